@@ -0,0 +1,104 @@
+// В этом файле описаны методы клиента для управления аккаунтом:
+// смена пароля, удаление и восстановление аккаунта,
+// подтверждение email и сброс пароля по почте.
+package api
+
+import "time"
+
+// ChangePasswordRequest описывает тело запроса смены пароля.
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+// ChangePassword меняет пароль аккаунта.
+//
+// Метод отправляет POST запрос на /auth/password с access токеном.
+// Сервер отзывает все сессии пользователя и возвращает новую пару токенов
+// для текущего устройства.
+func (c *Client) ChangePassword(accessToken, oldPassword, newPassword string) (LoginResponse, error) {
+	var resp LoginResponse
+	err := c.PostJSON("/auth/password", ChangePasswordRequest{OldPassword: oldPassword, NewPassword: newPassword}, &resp, accessToken)
+	return resp, err
+}
+
+// DeleteMeRequest описывает тело запроса удаления аккаунта.
+type DeleteMeRequest struct {
+	Password string `json:"password"`
+}
+
+// DeleteMeResponse описывает ответ на удаление аккаунта:
+// до DeleteAfter аккаунт ещё можно восстановить.
+type DeleteMeResponse struct {
+	DeleteAfter time.Time `json:"delete_after"`
+}
+
+// RestoreAccountRequest описывает тело запроса восстановления аккаунта.
+type RestoreAccountRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// DeleteMe помечает аккаунт к удалению.
+//
+// Метод отправляет DELETE запрос на /me с паролем и access токеном.
+// Сервер сразу отзывает все сессии, а данные удаляет после отсрочки.
+func (c *Client) DeleteMe(accessToken, password string) (DeleteMeResponse, error) {
+	var resp DeleteMeResponse
+	err := c.DeleteJSONWithBody("/me", DeleteMeRequest{Password: password}, &resp, accessToken)
+	return resp, err
+}
+
+// RestoreAccount отменяет удаление аккаунта в течение отсрочки.
+//
+// Метод отправляет POST запрос на /auth/restore. После восстановления нужен login.
+func (c *Client) RestoreAccount(email, password string) error {
+	return c.PostJSON("/auth/restore", RestoreAccountRequest{Email: email, Password: password}, nil, "")
+}
+
+// EmailTokenRequest описывает тело запроса подтверждения email.
+type EmailTokenRequest struct {
+	Token string `json:"token"`
+}
+
+// EmailRequest описывает тело запросов, отправляющих письмо на email.
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+// ResetPasswordRequest описывает тело запроса сброса пароля.
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// VerifyEmail подтверждает email токеном из письма.
+//
+// Метод отправляет POST запрос на /auth/email/verify.
+func (c *Client) VerifyEmail(token string) error {
+	return c.PostJSON("/auth/email/verify", EmailTokenRequest{Token: token}, nil, "")
+}
+
+// ResendVerification просит повторно отправить письмо подтверждения email.
+//
+// Метод отправляет POST запрос на /auth/email/verify/resend.
+// Ответ не зависит от того, зарегистрирован ли адрес.
+func (c *Client) ResendVerification(email string) error {
+	return c.PostJSON("/auth/email/verify/resend", EmailRequest{Email: email}, nil, "")
+}
+
+// RequestPasswordReset просит отправить на email токен сброса пароля.
+//
+// Метод отправляет POST запрос на /auth/password/reset/request.
+// Ответ не зависит от того, зарегистрирован ли адрес.
+func (c *Client) RequestPasswordReset(email string) error {
+	return c.PostJSON("/auth/password/reset/request", EmailRequest{Email: email}, nil, "")
+}
+
+// ResetPassword задаёт новый пароль аккаунта токеном из письма.
+//
+// Метод отправляет POST запрос на /auth/password/reset. Сервер отзывает
+// все сессии пользователя; секреты, зашифрованные мастер-паролем, не меняются.
+func (c *Client) ResetPassword(token, newPassword string) error {
+	return c.PostJSON("/auth/password/reset", ResetPasswordRequest{Token: token, NewPassword: newPassword}, nil, "")
+}
@@ -0,0 +1,98 @@
+// В этом файле описаны методы клиента для администрирования сервера:
+// список пользователей, блокировка аккаунтов, отзыв сессий и статистика.
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// AdminUser описывает пользователя в списке администратора.
+type AdminUser struct {
+	ID          string     `json:"id"`
+	Email       string     `json:"email"`
+	IsAdmin     bool       `json:"is_admin"`
+	Disabled    bool       `json:"disabled"`
+	DisabledAt  *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	SecretCount int        `json:"secret_count"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+// ListAdminUsersResponse описывает ответ GET /admin/users.
+type ListAdminUsersResponse struct {
+	Users []AdminUser `json:"users"`
+}
+
+// AdminStatsResponse описывает ответ GET /admin/stats.
+type AdminStatsResponse struct {
+	Users          int64 `json:"users"`
+	DisabledUsers  int64 `json:"disabled_users"`
+	Secrets        int64 `json:"secrets"`
+	SecretBytes    int64 `json:"secret_bytes"`
+	ActiveSessions int64 `json:"active_sessions"`
+}
+
+// AdminUsers возвращает страницу пользователей сервера (нулевые limit и offset не передаются).
+//
+// Выполняет запрос:
+//
+//	GET /admin/users?limit=&offset=
+func (c *Client) AdminUsers(accessToken string, limit, offset int) (ListAdminUsersResponse, error) {
+	params := url.Values{}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		params.Set("offset", strconv.Itoa(offset))
+	}
+
+	path := "/admin/users"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	var resp ListAdminUsersResponse
+	err := c.GetJSON(path, &resp, accessToken)
+	return resp, err
+}
+
+// DisableUser блокирует аккаунт и отзывает все его сессии.
+//
+// Выполняет запрос:
+//
+//	POST /admin/users/{id}/disable
+func (c *Client) DisableUser(accessToken, id string) error {
+	return c.PostJSON(fmt.Sprintf("/admin/users/%s/disable", id), nil, nil, accessToken)
+}
+
+// EnableUser снимает блокировку аккаунта.
+//
+// Выполняет запрос:
+//
+//	POST /admin/users/{id}/enable
+func (c *Client) EnableUser(accessToken, id string) error {
+	return c.PostJSON(fmt.Sprintf("/admin/users/%s/enable", id), nil, nil, accessToken)
+}
+
+// RevokeUserSessions отзывает все сессии пользователя.
+//
+// Выполняет запрос:
+//
+//	DELETE /admin/users/{id}/sessions
+func (c *Client) RevokeUserSessions(accessToken, id string) error {
+	return c.DeleteJSON(fmt.Sprintf("/admin/users/%s/sessions", id), nil, accessToken)
+}
+
+// AdminStats возвращает сводную статистику хранилища.
+//
+// Выполняет запрос:
+//
+//	GET /admin/stats
+func (c *Client) AdminStats(accessToken string) (AdminStatsResponse, error) {
+	var resp AdminStatsResponse
+	err := c.GetJSON("/admin/stats", &resp, accessToken)
+	return resp, err
+}
@@ -0,0 +1,69 @@
+// В этом файле описаны методы клиента для персональных API-токенов:
+// создание, список и отзыв.
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+// CreateAPITokenRequest описывает тело запроса создания API-токена.
+type CreateAPITokenRequest struct {
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`
+	Types     []string `json:"types,omitempty"`
+	ExpiresIn int64    `json:"expires_in,omitempty"` // секунды, 0 — срок по умолчанию
+}
+
+// APIToken описывает персональный API-токен (без самого токена).
+type APIToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	Types      []string   `json:"types,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// CreateAPITokenResponse содержит созданный токен — сервер показывает его один раз.
+type CreateAPITokenResponse struct {
+	Token string `json:"token"`
+	APIToken
+}
+
+// ListAPITokensResponse описывает ответ GET /auth/tokens.
+type ListAPITokensResponse struct {
+	Tokens []APIToken `json:"tokens"`
+}
+
+// CreateAPIToken выпускает персональный API-токен.
+//
+// Выполняет запрос:
+//
+//	POST /auth/tokens
+func (c *Client) CreateAPIToken(accessToken string, req CreateAPITokenRequest) (CreateAPITokenResponse, error) {
+	var resp CreateAPITokenResponse
+	err := c.PostJSON("/auth/tokens", req, &resp, accessToken)
+	return resp, err
+}
+
+// ListAPITokens возвращает действующие API-токены пользователя.
+//
+// Выполняет запрос:
+//
+//	GET /auth/tokens
+func (c *Client) ListAPITokens(accessToken string) (ListAPITokensResponse, error) {
+	var resp ListAPITokensResponse
+	err := c.GetJSON("/auth/tokens", &resp, accessToken)
+	return resp, err
+}
+
+// RevokeAPIToken отзывает API-токен по ID.
+//
+// Выполняет запрос:
+//
+//	DELETE /auth/tokens/{id}
+func (c *Client) RevokeAPIToken(accessToken, id string) error {
+	return c.DeleteJSON(fmt.Sprintf("/auth/tokens/%s", id), nil, accessToken)
+}
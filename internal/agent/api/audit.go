@@ -0,0 +1,66 @@
+package api
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// AuditEvent описывает событие журнала аудита пользователя.
+//
+// SecretID заполнен только для событий секретов.
+type AuditEvent struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"type"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	SecretID  string    `json:"secret_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListAuditResponse описывает ответ GET /audit.
+//
+// NextCursor передаётся в следующий запрос для получения более старых событий,
+// 0 — страниц больше нет.
+type ListAuditResponse struct {
+	Events     []AuditEvent `json:"events"`
+	NextCursor int64        `json:"next_cursor,omitempty"`
+}
+
+// AuditQuery — параметры выборки журнала аудита (нулевые значения не передаются).
+type AuditQuery struct {
+	Since  time.Time
+	Type   string
+	Limit  int
+	Cursor int64
+}
+
+// Audit возвращает страницу журнала аудита пользователя, от новых событий к старым.
+//
+// Выполняет запрос:
+//
+//	GET /audit?since=&type=&limit=&cursor=
+func (c *Client) Audit(accessToken string, q AuditQuery) (ListAuditResponse, error) {
+	params := url.Values{}
+	if !q.Since.IsZero() {
+		params.Set("since", q.Since.UTC().Format(time.RFC3339))
+	}
+	if q.Type != "" {
+		params.Set("type", q.Type)
+	}
+	if q.Limit > 0 {
+		params.Set("limit", strconv.Itoa(q.Limit))
+	}
+	if q.Cursor > 0 {
+		params.Set("cursor", strconv.FormatInt(q.Cursor, 10))
+	}
+
+	path := "/audit"
+	if len(params) > 0 {
+		path += "?" + params.Encode()
+	}
+
+	var resp ListAuditResponse
+	err := c.GetJSON(path, &resp, accessToken)
+	return resp, err
+}
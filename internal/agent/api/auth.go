@@ -3,12 +3,16 @@
 // информации о текущем пользователе.
 package api
 
+import "time"
+
 // RegisterRequest описывает тело запроса регистрации пользователя.
 //
 // Email и Password передаются в JSON формате в эндпоинт /auth/register.
+// Invite — код приглашения, если сервер принимает регистрацию только по приглашениям.
 type RegisterRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	Invite   string `json:"invite,omitempty"`
 }
 
 // RegisterResponse описывает ответ сервера при успешной регистрации.
@@ -30,9 +34,16 @@ type LoginRequest struct {
 //
 // AccessToken используется для авторизации запросов к защищённым эндпоинтам.
 // RefreshToken используется для обновления пары токенов через /auth/refresh.
+//
+// Если у пользователя подключён TOTP, токены пустые, MFARequired=true,
+// а MFAToken нужно вместе с кодом передать в VerifyMFA до MFAExpiresAt.
 type LoginResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
+
+	MFARequired  bool      `json:"mfa_required,omitempty"`
+	MFAToken     string    `json:"mfa_token,omitempty"`
+	MFAExpiresAt time.Time `json:"expires_at"`
 }
 
 // RefreshRequest описывает тело запроса обновления токенов.
@@ -54,23 +65,41 @@ type RefreshResponse struct {
 //
 // Метод отправляет POST запрос на /auth/register и возвращает RegisterResponse.
 // В случае ошибки возвращает непустую ошибку и пустой ответ.
-func (c *Client) Register(email, password string) (RegisterResponse, error) {
+func (c *Client) Register(email, password, invite string) (RegisterResponse, error) {
 	var resp RegisterResponse
-	err := c.PostJSON("/auth/register", RegisterRequest{Email: email, Password: password}, &resp, "")
+	err := c.PostJSON("/auth/register", RegisterRequest{Email: email, Password: password, Invite: invite}, &resp, "")
 	return resp, err
 }
 
 // Login выполняет вход пользователя и получает пару токенов.
 //
 // Метод отправляет POST запрос на /auth/login и возвращает LoginResponse
-// с AccessToken и RefreshToken. В случае ошибки возвращает непустую ошибку
-// и пустой ответ.
+// с AccessToken и RefreshToken (или challenge второго фактора, см. MFARequired).
+// В случае ошибки возвращает непустую ошибку и пустой ответ.
 func (c *Client) Login(email, password string) (LoginResponse, error) {
 	var resp LoginResponse
 	err := c.PostJSON("/auth/login", LoginRequest{Email: email, Password: password}, &resp, "")
 	return resp, err
 }
 
+// OIDCLoginRequest описывает тело запроса входа по ID-токену провайдера.
+//
+// IDToken передаётся в JSON формате в эндпоинт /auth/oidc.
+type OIDCLoginRequest struct {
+	IDToken string `json:"id_token"`
+}
+
+// LoginOIDC обменивает ID-токен внешнего OpenID Connect провайдера на пару токенов.
+//
+// Метод отправляет POST запрос на /auth/oidc и возвращает LoginResponse
+// (или challenge второго фактора, см. MFARequired).
+// В случае ошибки возвращает непустую ошибку и пустой ответ.
+func (c *Client) LoginOIDC(idToken string) (LoginResponse, error) {
+	var resp LoginResponse
+	err := c.PostJSON("/auth/oidc", OIDCLoginRequest{IDToken: idToken}, &resp, "")
+	return resp, err
+}
+
 // Refresh обновляет пару токенов по refresh токену.
 //
 // Метод отправляет POST запрос на /auth/refresh и возвращает новую пару токенов.
@@ -81,11 +110,60 @@ func (c *Client) Refresh(refreshToken string) (RefreshResponse, error) {
 	return resp, err
 }
 
+// ReauthRequest описывает тело запроса повторной аутентификации.
+//
+// Code нужен, если подключён TOTP (код из приложения или код восстановления).
+type ReauthRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code,omitempty"`
+}
+
+// ReauthResponse описывает ответ сервера на повторную аутентификацию.
+//
+// AccessToken той же сессии до ElevatedUntil разрешает чувствительные операции
+// (удаление секретов, выпуск API-токенов, привязку сертификатов и т.д.).
+type ReauthResponse struct {
+	AccessToken   string    `json:"access_token"`
+	ElevatedUntil time.Time `json:"elevated_until"`
+}
+
+// Reauth повторно подтверждает пароль и получает access токен с повышенными правами.
+//
+// Метод отправляет POST запрос на /auth/reauth с текущим access токеном.
+// Refresh токен при этом не меняется.
+func (c *Client) Reauth(accessToken, password, code string) (ReauthResponse, error) {
+	var resp ReauthResponse
+	err := c.PostJSON("/auth/reauth", ReauthRequest{Password: password, Code: code}, &resp, accessToken)
+	return resp, err
+}
+
+// LogoutRequest описывает тело запроса выхода.
+//
+// All=true просит сервер отозвать все сессии пользователя.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	All          bool   `json:"all"`
+}
+
+// Logout отзывает refresh-сессию на сервере.
+//
+// Метод отправляет POST запрос на /auth/logout. Если all=true, сервер
+// отзывает все сессии пользователя (выход со всех устройств).
+func (c *Client) Logout(refreshToken string, all bool) error {
+	return c.PostJSON("/auth/logout", LogoutRequest{RefreshToken: refreshToken, All: all}, nil, "")
+}
+
 // MeResponse описывает ответ сервера с информацией о текущем пользователе.
 //
-// UserID содержит идентификатор пользователя, ассоциированного с переданным access токеном.
+// UserID содержит идентификатор пользователя, ассоциированного с переданным access токеном,
+// остальные поля — сводку по аккаунту (секреты, занятое место, активные сессии).
 type MeResponse struct {
-	UserID string `json:"user_id"`
+	UserID         string    `json:"user_id"`
+	Email          string    `json:"email"`
+	CreatedAt      time.Time `json:"created_at"`
+	SecretCount    int       `json:"secret_count"`
+	StorageBytes   int64     `json:"storage_bytes"`
+	ActiveSessions int       `json:"active_sessions"`
 }
 
 // Me запрашивает информацию о текущем пользователе.
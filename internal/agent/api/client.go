@@ -8,10 +8,16 @@
 //   - baseURL нормализуется (обрезаются завершающие "/").
 //   - По умолчанию добавляется заголовок Accept: application/json.
 //   - Заголовок Content-Type: application/json добавляется только при наличии тела запроса.
+//   - Заголовок X-Device-Name (имя хоста) передаётся, чтобы сервер показывал устройство в списке сессий.
+//   - Клиентский сертификат (mTLS) подключается через UseClientCertificate.
+//   - На ответ 428 (сервер под нагрузкой требует proof-of-work) POST-запрос
+//     повторяется один раз с решением challenge из GET /auth/challenge.
 //   - При ответах 204 No Content тело не читается и это считается успехом.
 //   - Пустое тело ответа (EOF при декодировании) не считается ошибкой.
 //   - При ошибочных ответах (не 2xx) возвращается ошибка с текстом тела ответа
 //     (если тело пустое — используется res.Status).
+//   - Если операция требует повторного ввода пароля (401 с WWW-Authenticate
+//     error="insufficient_user_authentication"), возвращается serr.ErrReauthRequired.
 //
 // ВНИМАНИЕ: NewClient включает InsecureSkipVerify=true (TLS сертификат не проверяется).
 // Это допустимо только для разработки и локального окружения. Для production следует
@@ -23,10 +29,14 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
 	"time"
+
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
 )
 
 // Client реализует HTTP-клиент для общения с сервером GophKeeper.
@@ -34,12 +44,14 @@ import (
 // Поля:
 //   - baseURL: базовый адрес сервера без завершающего слэша.
 //   - http: настроенный http.Client (таймаут, транспорт, TLS).
+//   - deviceName: имя устройства для заголовка X-Device-Name.
 //
 // Client предоставляет методы PostJSON/GetJSON/PutJSON/DeleteJSON,
 // которые отправляют HTTP-запросы и (при необходимости) декодируют JSON-ответ.
 type Client struct {
-	baseURL string
-	http    *http.Client
+	baseURL    string
+	http       *http.Client
+	deviceName string
 }
 
 // NewClient создаёт новый HTTP-клиент для общения с сервером.
@@ -50,6 +62,7 @@ type Client struct {
 // Поведение:
 //   - обрезает завершающий "/" у baseURL;
 //   - создаёт http.Client с таймаутом 10 секунд;
+//   - в качестве имени устройства берёт имя хоста (os.Hostname);
 //
 // ВНИМАНИЕ: InsecureSkipVerify=true отключает проверку сертификата и делает TLS
 // уязвимым для MITM. Использовать только для локальной разработки/тестов.
@@ -58,12 +71,47 @@ func NewClient(baseURL string) *Client {
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, // только для dev
 	}
 
+	deviceName, _ := os.Hostname()
+
 	return &Client{
 		baseURL: strings.TrimRight(baseURL, "/"),
 		http: &http.Client{
 			Timeout:   10 * time.Second,
 			Transport: tr,
 		},
+		deviceName: deviceName,
+	}
+}
+
+// LoadClientCertificate читает клиентский сертификат и его закрытый ключ (PEM) для mTLS.
+func LoadClientCertificate(certFile, keyFile string) (tls.Certificate, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("load client certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// UseClientCertificate включает mTLS: сертификат предъявляется серверу при TLS-рукопожатии.
+//
+// Если к аккаунту привязан сертификат, сервер без него не выдаёт токены
+// и не принимает выданные для него access-токены.
+func (c *Client) UseClientCertificate(cert tls.Certificate) {
+	tr, ok := c.http.Transport.(*http.Transport)
+	if !ok {
+		return
+	}
+	tr.TLSClientConfig.Certificates = []tls.Certificate{cert}
+}
+
+// setCommonHeaders проставляет заголовки, общие для всех запросов.
+func (c *Client) setCommonHeaders(r *http.Request, authToken string) {
+	r.Header.Set("Accept", "application/json")
+	if c.deviceName != "" {
+		r.Header.Set("X-Device-Name", c.deviceName)
+	}
+	if authToken != "" {
+		r.Header.Set("Authorization", "Bearer "+authToken)
 	}
 }
 
@@ -72,10 +120,15 @@ func NewClient(baseURL string) *Client {
 // Используется в случае HTTP-ошибок (не 2xx).
 //
 // Поведение:
+//   - 401 с error="insufficient_user_authentication" — serr.ErrReauthRequired;
 //   - читает res.Body полностью;
 //   - если тело непустое — возвращает error с этим текстом (trim пробелов);
 //   - если тело пустое — возвращает error со строкой res.Status.
 func readAPIErrorBody(res *http.Response) error {
+	if res.StatusCode == http.StatusUnauthorized &&
+		strings.Contains(res.Header.Get("WWW-Authenticate"), "insufficient_user_authentication") {
+		return serr.ErrReauthRequired
+	}
 	raw, _ := io.ReadAll(res.Body)
 	msg := strings.TrimSpace(string(raw))
 	if msg == "" {
@@ -125,6 +178,7 @@ func decodeJSONOrOK(r io.Reader, resp any) error {
 //   - 2xx: успех
 //   - 204 No Content: успех без попытки декодирования тела
 //   - прочие 2xx: декодирует JSON в resp (если resp != nil); EOF не ошибка
+//   - 428 Precondition Required: решает challenge proof-of-work и повторяет запрос
 //   - не 2xx: возвращает ошибку с текстом тела ответа (или res.Status)
 func (c *Client) PostJSON(path string, req any, resp any, authToken string) error {
 	var buf bytes.Buffer
@@ -134,21 +188,19 @@ func (c *Client) PostJSON(path string, req any, resp any, authToken string) erro
 		}
 	}
 
-	r, err := http.NewRequest(http.MethodPost, c.baseURL+path, &buf)
+	res, err := c.post(path, buf.Bytes(), req != nil, authToken, nil)
 	if err != nil {
 		return err
 	}
-	r.Header.Set("Accept", "application/json")
-	if req != nil {
-		r.Header.Set("Content-Type", "application/json")
-	}
-	if authToken != "" {
-		r.Header.Set("Authorization", "Bearer "+authToken)
-	}
-
-	res, err := c.http.Do(r)
-	if err != nil {
-		return err
+	if res.StatusCode == http.StatusPreconditionRequired {
+		res.Body.Close()
+		proof, err := c.solveChallenge()
+		if err != nil {
+			return err
+		}
+		if res, err = c.post(path, buf.Bytes(), req != nil, authToken, proof); err != nil {
+			return err
+		}
 	}
 	defer res.Body.Close()
 
@@ -164,6 +216,22 @@ func (c *Client) PostJSON(path string, req any, resp any, authToken string) erro
 	return decodeJSONOrOK(res.Body, resp)
 }
 
+// post отправляет POST-запрос с телом body и заголовками extra (решение proof-of-work).
+func (c *Client) post(path string, body []byte, isJSON bool, authToken string, extra map[string]string) (*http.Response, error) {
+	r, err := http.NewRequest(http.MethodPost, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	c.setCommonHeaders(r, authToken)
+	if isJSON {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range extra {
+		r.Header.Set(k, v)
+	}
+	return c.http.Do(r)
+}
+
 // GetJSON выполняет GET-запрос к серверу и (опционально) декодирует JSON-ответ.
 //
 // Параметры:
@@ -186,10 +254,7 @@ func (c *Client) GetJSON(path string, resp any, authToken string) error {
 	if err != nil {
 		return err
 	}
-	r.Header.Set("Accept", "application/json")
-	if authToken != "" {
-		r.Header.Set("Authorization", "Bearer "+authToken)
-	}
+	c.setCommonHeaders(r, authToken)
 
 	res, err := c.http.Do(r)
 	if err != nil {
@@ -241,13 +306,10 @@ func (c *Client) PutJSON(path string, req any, resp any, authToken string) error
 	if err != nil {
 		return err
 	}
-	r.Header.Set("Accept", "application/json")
+	c.setCommonHeaders(r, authToken)
 	if req != nil {
 		r.Header.Set("Content-Type", "application/json")
 	}
-	if authToken != "" {
-		r.Header.Set("Authorization", "Bearer "+authToken)
-	}
 
 	res, err := c.http.Do(r)
 	if err != nil {
@@ -289,9 +351,44 @@ func (c *Client) DeleteJSON(path string, resp any, authToken string) error {
 	if err != nil {
 		return err
 	}
-	r.Header.Set("Accept", "application/json")
-	if authToken != "" {
-		r.Header.Set("Authorization", "Bearer "+authToken)
+	c.setCommonHeaders(r, authToken)
+
+	res, err := c.http.Do(r)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return readAPIErrorBody(res)
+	}
+
+	if res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	return decodeJSONOrOK(res.Body, resp)
+}
+
+// DeleteJSONWithBody выполняет DELETE-запрос с JSON-телом
+// (например, DELETE /me с подтверждением паролем).
+//
+// Параметры и обработка ответа — как у PostJSON.
+func (c *Client) DeleteJSONWithBody(path string, req any, resp any, authToken string) error {
+	var buf bytes.Buffer
+	if req != nil {
+		if err := json.NewEncoder(&buf).Encode(req); err != nil {
+			return err
+		}
+	}
+
+	r, err := http.NewRequest(http.MethodDelete, c.baseURL+path, &buf)
+	if err != nil {
+		return err
+	}
+	c.setCommonHeaders(r, authToken)
+	if req != nil {
+		r.Header.Set("Content-Type", "application/json")
 	}
 
 	res, err := c.http.Do(r)
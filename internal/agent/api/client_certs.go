@@ -0,0 +1,60 @@
+// В этом файле описаны методы клиента для клиентских сертификатов (mTLS):
+// привязка к аккаунту, список и отвязка.
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+// BindClientCertRequest описывает тело запроса привязки сертификата.
+type BindClientCertRequest struct {
+	Certificate string `json:"certificate"` // PEM, закрытый ключ не отправляется
+	Name        string `json:"name,omitempty"`
+}
+
+// ClientCert описывает привязанный к аккаунту сертификат.
+type ClientCert struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Subject     string    `json:"subject"`
+	Fingerprint string    `json:"fingerprint"` // SHA-256 в hex
+	NotAfter    time.Time `json:"not_after"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ListClientCertsResponse описывает ответ GET /auth/certs.
+type ListClientCertsResponse struct {
+	Certificates []ClientCert `json:"certificates"`
+}
+
+// BindClientCert привязывает клиентский сертификат к аккаунту.
+//
+// Выполняет запрос:
+//
+//	POST /auth/certs
+func (c *Client) BindClientCert(accessToken string, req BindClientCertRequest) (ClientCert, error) {
+	var resp ClientCert
+	err := c.PostJSON("/auth/certs", req, &resp, accessToken)
+	return resp, err
+}
+
+// ListClientCerts возвращает сертификаты, привязанные к аккаунту.
+//
+// Выполняет запрос:
+//
+//	GET /auth/certs
+func (c *Client) ListClientCerts(accessToken string) (ListClientCertsResponse, error) {
+	var resp ListClientCertsResponse
+	err := c.GetJSON("/auth/certs", &resp, accessToken)
+	return resp, err
+}
+
+// UnbindClientCert отвязывает сертификат по ID.
+//
+// Выполняет запрос:
+//
+//	DELETE /auth/certs/{id}
+func (c *Client) UnbindClientCert(accessToken, id string) error {
+	return c.DeleteJSON(fmt.Sprintf("/auth/certs/%s", id), nil, accessToken)
+}
@@ -0,0 +1,104 @@
+// В этом файле описаны методы клиента для входа по коду устройства (RFC 8628):
+// получение кода, опрос токенов и подтверждение кода с другого устройства.
+package api
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var (
+	// ErrAuthorizationPending — код ещё не подтверждён, опрос нужно продолжать.
+	ErrAuthorizationPending = errors.New("authorization pending")
+	// ErrSlowDown — опрос слишком частый, интервал нужно увеличить на 5 секунд.
+	ErrSlowDown = errors.New("slow down")
+)
+
+// DeviceCodeResponse описывает ответ сервера на начало входа по коду устройства.
+type DeviceCodeResponse struct {
+	DeviceCode string `json:"device_code"`
+	UserCode   string `json:"user_code"`
+	ExpiresIn  int    `json:"expires_in"` // секунды
+	Interval   int    `json:"interval"`   // секунды
+}
+
+// DeviceTokenRequest описывает тело запроса опроса токенов.
+type DeviceTokenRequest struct {
+	DeviceCode string `json:"device_code"`
+}
+
+// DeviceDecisionRequest описывает тело запроса подтверждения или отказа.
+type DeviceDecisionRequest struct {
+	UserCode string `json:"user_code"`
+}
+
+// StartDeviceAuthorization начинает вход по коду устройства.
+//
+// Выполняет запрос:
+//
+//	POST /auth/device/code
+func (c *Client) StartDeviceAuthorization() (DeviceCodeResponse, error) {
+	var resp DeviceCodeResponse
+	err := c.PostJSON("/auth/device/code", nil, &resp, "")
+	return resp, err
+}
+
+// PollDeviceToken один раз спрашивает сервер, подтверждён ли код.
+//
+// Пока код не подтверждён, возвращает ErrAuthorizationPending,
+// при слишком частом опросе — ErrSlowDown.
+//
+// Выполняет запрос:
+//
+//	POST /auth/device/token
+func (c *Client) PollDeviceToken(deviceCode string) (LoginResponse, error) {
+	var resp LoginResponse
+	err := c.PostJSON("/auth/device/token", DeviceTokenRequest{DeviceCode: deviceCode}, &resp, "")
+	if err != nil {
+		return LoginResponse{}, deviceError(err)
+	}
+	return resp, nil
+}
+
+// ApproveDevice подтверждает вход устройства с кодом userCode.
+//
+// Выполняет запрос:
+//
+//	POST /auth/device/approve
+func (c *Client) ApproveDevice(accessToken, userCode string) error {
+	return c.PostJSON("/auth/device/approve", DeviceDecisionRequest{UserCode: userCode}, nil, accessToken)
+}
+
+// DenyDevice отклоняет вход устройства с кодом userCode.
+//
+// Выполняет запрос:
+//
+//	POST /auth/device/deny
+func (c *Client) DenyDevice(accessToken, userCode string) error {
+	return c.PostJSON("/auth/device/deny", DeviceDecisionRequest{UserCode: userCode}, nil, accessToken)
+}
+
+// deviceError переводит коды ошибок RFC 8628 из тела ответа в ошибки клиента.
+func deviceError(err error) error {
+	var body struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal([]byte(err.Error()), &body) != nil {
+		return err
+	}
+
+	switch body.Error {
+	case "authorization_pending":
+		return ErrAuthorizationPending
+	case "slow_down":
+		return ErrSlowDown
+	case "access_denied":
+		return errors.New("device login denied")
+	case "expired_token":
+		return errors.New("device code expired, start again")
+	case "invalid_grant":
+		return errors.New("device code is invalid or already used")
+	default:
+		return err
+	}
+}
@@ -0,0 +1,66 @@
+// В этом файле описаны методы клиента для кодов приглашений:
+// выпуск, список и отзыв.
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+// CreateInviteRequest описывает тело запроса выпуска приглашения.
+type CreateInviteRequest struct {
+	MaxUses   int   `json:"max_uses,omitempty"`   // 0 — одно использование
+	ExpiresIn int64 `json:"expires_in,omitempty"` // секунды, 0 — срок по умолчанию
+}
+
+// Invite описывает приглашение (без самого кода).
+type Invite struct {
+	ID        string    `json:"id"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	MaxUses   int       `json:"max_uses"`
+	Uses      int       `json:"uses"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateInviteResponse содержит код приглашения — сервер показывает его один раз.
+type CreateInviteResponse struct {
+	Code string `json:"code"`
+	Invite
+}
+
+// ListInvitesResponse описывает ответ GET /auth/invites.
+type ListInvitesResponse struct {
+	Invites []Invite `json:"invites"`
+}
+
+// CreateInvite выпускает код приглашения.
+//
+// Выполняет запрос:
+//
+//	POST /auth/invites
+func (c *Client) CreateInvite(accessToken string, req CreateInviteRequest) (CreateInviteResponse, error) {
+	var resp CreateInviteResponse
+	err := c.PostJSON("/auth/invites", req, &resp, accessToken)
+	return resp, err
+}
+
+// ListInvites возвращает действующие приглашения.
+//
+// Выполняет запрос:
+//
+//	GET /auth/invites
+func (c *Client) ListInvites(accessToken string) (ListInvitesResponse, error) {
+	var resp ListInvitesResponse
+	err := c.GetJSON("/auth/invites", &resp, accessToken)
+	return resp, err
+}
+
+// RevokeInvite отзывает приглашение по ID.
+//
+// Выполняет запрос:
+//
+//	DELETE /auth/invites/{id}
+func (c *Client) RevokeInvite(accessToken, id string) error {
+	return c.DeleteJSON(fmt.Sprintf("/auth/invites/%s", id), nil, accessToken)
+}
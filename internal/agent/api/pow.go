@@ -0,0 +1,51 @@
+// В этом файле описано получение и решение challenge proof-of-work,
+// который сервер под нагрузкой требует на регистрации и входе.
+package api
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/pow"
+)
+
+// ChallengeResponse описывает ответ GET /auth/challenge.
+type ChallengeResponse struct {
+	Challenge  string    `json:"challenge"`
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Challenge запрашивает одноразовый challenge proof-of-work.
+//
+// Выполняет запрос:
+//
+//	GET /auth/challenge
+func (c *Client) Challenge() (ChallengeResponse, error) {
+	var resp ChallengeResponse
+	err := c.GetJSON("/auth/challenge", &resp, "")
+	return resp, err
+}
+
+// solveChallenge получает challenge и перебором находит решение до истечения его срока.
+//
+// Возвращает заголовки X-PoW-Challenge и X-PoW-Solution для повтора запроса.
+func (c *Client) solveChallenge() (map[string]string, error) {
+	ch, err := c.Challenge()
+	if err != nil {
+		return nil, fmt.Errorf("get proof-of-work challenge: %w", err)
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), ch.ExpiresAt)
+	defer cancel()
+	solution, err := pow.Solve(ctx, ch.Challenge, ch.Difficulty)
+	if err != nil {
+		return nil, fmt.Errorf("solve proof-of-work challenge: %w", err)
+	}
+
+	return map[string]string{
+		pow.HeaderChallenge: ch.Challenge,
+		pow.HeaderSolution:  solution,
+	}, nil
+}
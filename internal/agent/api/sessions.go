@@ -0,0 +1,41 @@
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+// Session описывает активную сессию (устройство) пользователя на сервере.
+type Session struct {
+	ID         string    `json:"id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// ListSessionsResponse описывает ответ GET /auth/sessions.
+type ListSessionsResponse struct {
+	Sessions []Session `json:"sessions"`
+}
+
+// ListSessions возвращает активные сессии пользователя.
+//
+// Выполняет запрос:
+//
+//	GET /auth/sessions
+func (c *Client) ListSessions(accessToken string) (ListSessionsResponse, error) {
+	var resp ListSessionsResponse
+	err := c.GetJSON("/auth/sessions", &resp, accessToken)
+	return resp, err
+}
+
+// RevokeSession отзывает сессию пользователя по ID.
+//
+// Выполняет запрос:
+//
+//	DELETE /auth/sessions/{id}
+func (c *Client) RevokeSession(accessToken, id string) error {
+	return c.DeleteJSON(fmt.Sprintf("/auth/sessions/%s", id), nil, accessToken)
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ChangePassword(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/password", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+
+		var req api.ChangePasswordRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "OldPass123", req.OldPassword)
+		require.Equal(t, "NewPass123", req.NewPassword)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.LoginResponse{AccessToken: "access-2", RefreshToken: "refresh-2"})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	resp, err := c.ChangePassword("access-1", "OldPass123", "NewPass123")
+	require.NoError(t, err)
+	require.Equal(t, "access-2", resp.AccessToken)
+	require.Equal(t, "refresh-2", resp.RefreshToken)
+}
+
+func TestClient_DeleteMe_SendsPassword(t *testing.T) {
+	deleteAfter := time.Now().Add(7 * 24 * time.Hour).UTC().Truncate(time.Second)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodDelete, r.Method)
+		require.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+
+		var req api.DeleteMeRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "StrongPass123", req.Password)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(api.DeleteMeResponse{DeleteAfter: deleteAfter})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	resp, err := c.DeleteMe("access-1", "StrongPass123")
+	require.NoError(t, err)
+	require.True(t, resp.DeleteAfter.Equal(deleteAfter))
+}
+
+func TestClient_RestoreAccount(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/restore", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+
+		var req api.RestoreAccountRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "test@example.com", req.Email)
+		require.Equal(t, "StrongPass123", req.Password)
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+	require.NoError(t, c.RestoreAccount("test@example.com", "StrongPass123"))
+}
+
+func TestClient_EmailFlows(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/email/verify", func(w http.ResponseWriter, r *http.Request) {
+		var req api.EmailTokenRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "verify-token", req.Token)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/auth/email/verify/resend", func(w http.ResponseWriter, r *http.Request) {
+		var req api.EmailRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "test@example.com", req.Email)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("/auth/password/reset/request", func(w http.ResponseWriter, r *http.Request) {
+		var req api.EmailRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "test@example.com", req.Email)
+		w.WriteHeader(http.StatusAccepted)
+	})
+	mux.HandleFunc("/auth/password/reset", func(w http.ResponseWriter, r *http.Request) {
+		var req api.ResetPasswordRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "reset-token", req.Token)
+		require.Equal(t, "NewPass123", req.NewPassword)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	require.NoError(t, c.VerifyEmail("verify-token"))
+	require.NoError(t, c.ResendVerification("test@example.com"))
+	require.NoError(t, c.RequestPasswordReset("test@example.com"))
+	require.NoError(t, c.ResetPassword("reset-token", "NewPass123"))
+}
+
+func TestClient_ResetPassword_InvalidToken(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/password/reset", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid or expired token", http.StatusBadRequest)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	err := api.NewClient(srv.URL).ResetPassword("used", "NewPass123")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid or expired token")
+}
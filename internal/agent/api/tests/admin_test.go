@@ -0,0 +1,72 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_AdminUsers(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/admin/users", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+		require.Equal(t, "20", r.URL.Query().Get("limit"))
+		require.Equal(t, "40", r.URL.Query().Get("offset"))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ListAdminUsersResponse{
+			Users: []api.AdminUser{{ID: "id-1", Email: "a@example.com", SecretCount: 3, CreatedAt: time.Now()}},
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	resp, err := c.AdminUsers("access-1", 20, 40)
+	require.NoError(t, err)
+	require.Len(t, resp.Users, 1)
+	require.Equal(t, 3, resp.Users[0].SecretCount)
+}
+
+func TestClient_AdminUserActionsAndStats(t *testing.T) {
+	var calls []string
+	mux := http.NewServeMux()
+	for _, p := range []string{"/admin/users/id-1/disable", "/admin/users/id-1/enable", "/admin/users/id-1/sessions"} {
+		mux.HandleFunc(p, func(w http.ResponseWriter, r *http.Request) {
+			calls = append(calls, r.Method+" "+r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+	mux.HandleFunc("/admin/stats", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.AdminStatsResponse{Users: 10, DisabledUsers: 1, Secrets: 42})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	require.NoError(t, c.DisableUser("access-1", "id-1"))
+	require.NoError(t, c.EnableUser("access-1", "id-1"))
+	require.NoError(t, c.RevokeUserSessions("access-1", "id-1"))
+	require.Equal(t, []string{
+		"POST /admin/users/id-1/disable",
+		"POST /admin/users/id-1/enable",
+		"DELETE /admin/users/id-1/sessions",
+	}, calls)
+
+	st, err := c.AdminStats("access-1")
+	require.NoError(t, err)
+	require.Equal(t, int64(10), st.Users)
+	require.Equal(t, int64(42), st.Secrets)
+}
@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_CreateAPIToken(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+
+		var req api.CreateAPITokenRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "ci", req.Name)
+		require.Equal(t, []string{"secrets:read"}, req.Scopes)
+		require.Equal(t, int64(3600), req.ExpiresIn)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(api.CreateAPITokenResponse{
+			Token:    "gkp_secret",
+			APIToken: api.APIToken{ID: "id-1", Name: "ci", ExpiresAt: time.Now().Add(time.Hour)},
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	resp, err := c.CreateAPIToken("access-1", api.CreateAPITokenRequest{
+		Name:      "ci",
+		Scopes:    []string{"secrets:read"},
+		ExpiresIn: 3600,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "gkp_secret", resp.Token)
+	require.Equal(t, "id-1", resp.ID)
+}
+
+func TestClient_ListAndRevokeAPITokens(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ListAPITokensResponse{
+			Tokens: []api.APIToken{{ID: "id-1", Name: "ci", Scopes: []string{"secrets:read"}}},
+		})
+	})
+	mux.HandleFunc("/auth/tokens/id-1", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodDelete, r.Method)
+		require.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	list, err := c.ListAPITokens("access-1")
+	require.NoError(t, err)
+	require.Len(t, list.Tokens, 1)
+	require.Equal(t, "ci", list.Tokens[0].Name)
+
+	require.NoError(t, c.RevokeAPIToken("access-1", "id-1"))
+}
@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Audit_SendsQuery(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/audit", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+
+		q := r.URL.Query()
+		require.Equal(t, "2026-01-02T03:04:05Z", q.Get("since"))
+		require.Equal(t, "login_failed", q.Get("type"))
+		require.Equal(t, "20", q.Get("limit"))
+		require.Equal(t, "99", q.Get("cursor"))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ListAuditResponse{
+			Events:     []api.AuditEvent{{ID: 98, Type: "login_failed"}},
+			NextCursor: 98,
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	resp, err := c.Audit("access-1", api.AuditQuery{
+		Since:  time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Type:   "login_failed",
+		Limit:  20,
+		Cursor: 99,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Events, 1)
+	require.Equal(t, int64(98), resp.NextCursor)
+}
+
+func TestClient_Audit_NoQuery(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/audit", func(w http.ResponseWriter, r *http.Request) {
+		require.Empty(t, r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ListAuditResponse{})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	_, err := api.NewClient(srv.URL).Audit("access-1", api.AuditQuery{})
+	require.NoError(t, err)
+}
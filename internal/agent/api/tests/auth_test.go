@@ -7,8 +7,10 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -32,7 +34,7 @@ func TestClient_Register_Success(t *testing.T) {
 
 	c := api.NewClient(srv.URL)
 
-	resp, err := c.Register("test@example.com", "StrongPass123")
+	resp, err := c.Register("test@example.com", "StrongPass123", "")
 	require.NoError(t, err)
 	require.Equal(t, "u1", resp.UserID)
 }
@@ -101,7 +103,7 @@ func TestClient_Me_Success_UsesBearerToken(t *testing.T) {
 		require.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(api.MeResponse{UserID: "u1"})
+		json.NewEncoder(w).Encode(api.MeResponse{UserID: "u1", Email: "test@example.com", SecretCount: 2})
 	})
 
 	srv := httptest.NewTLSServer(mux)
@@ -112,6 +114,8 @@ func TestClient_Me_Success_UsesBearerToken(t *testing.T) {
 	resp, err := c.Me("access-1")
 	require.NoError(t, err)
 	require.Equal(t, "u1", resp.UserID)
+	require.Equal(t, "test@example.com", resp.Email)
+	require.Equal(t, 2, resp.SecretCount)
 }
 
 func TestClient_Non2xx_ReturnsBodyAsError(t *testing.T) {
@@ -130,3 +134,64 @@ func TestClient_Non2xx_ReturnsBodyAsError(t *testing.T) {
 	require.Error(t, err)
 	require.True(t, strings.Contains(err.Error(), "invalid credentials"))
 }
+
+func TestClient_Logout_SendsRefreshAndAll(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/logout", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+
+		var req api.LogoutRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "refresh-1", req.RefreshToken)
+		require.True(t, req.All)
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+	require.NoError(t, c.Logout("refresh-1", true))
+}
+
+func TestClient_Reauth_Success(t *testing.T) {
+	until := time.Now().Add(5 * time.Minute).UTC().Truncate(time.Second)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/reauth", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "Bearer access", r.Header.Get("Authorization"))
+
+		var req api.ReauthRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "StrongPass123", req.Password)
+		require.Equal(t, "123456", req.Code)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ReauthResponse{AccessToken: "elevated", ElevatedUntil: until})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	resp, err := api.NewClient(srv.URL).Reauth("access", "StrongPass123", "123456")
+	require.NoError(t, err)
+	require.Equal(t, "elevated", resp.AccessToken)
+	require.True(t, until.Equal(resp.ElevatedUntil))
+}
+
+// 401 insufficient_user_authentication превращается в ErrReauthRequired
+func TestClient_ReauthRequired(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/secrets/s1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_user_authentication"`)
+		http.Error(w, "reauthentication required", http.StatusUnauthorized)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	err := api.NewClient(srv.URL).DeleteSecret("access", "s1", 1)
+	require.ErrorIs(t, err, serr.ErrReauthRequired)
+}
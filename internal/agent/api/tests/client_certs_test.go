@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+)
+
+// writeClientCert создаёт самоподписанный клиентский сертификат и ключ в dir
+func writeClientCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "laptop"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create cert: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+
+	certFile = filepath.Join(dir, "client.crt")
+	keyFile = filepath.Join(dir, "client.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write cert: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("write key: %v", err)
+	}
+	return certFile, keyFile
+}
+
+// сервер требует клиентский сертификат: без него соединение не устанавливается
+func TestClient_UseClientCertificate(t *testing.T) {
+	certFile, keyFile := writeClientCert(t, t.TempDir())
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) != 1 || r.TLS.PeerCertificates[0].Subject.CommonName != "laptop" {
+			t.Fatalf("unexpected peer certificates: %v", r.TLS.PeerCertificates)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{"ok": true})
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	if err := api.NewClient(srv.URL).GetJSON("/x", nil, ""); err == nil {
+		t.Fatal("expected handshake error without client certificate")
+	}
+
+	cert, err := api.LoadClientCertificate(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("LoadClientCertificate: %v", err)
+	}
+	c := api.NewClient(srv.URL)
+	c.UseClientCertificate(cert)
+
+	var resp map[string]any
+	if err := c.GetJSON("/x", &resp, ""); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if resp["ok"] != true {
+		t.Fatalf("unexpected response: %v", resp)
+	}
+}
+
+func TestLoadClientCertificate_Missing(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := api.LoadClientCertificate(filepath.Join(dir, "a.crt"), filepath.Join(dir, "a.key")); err == nil {
+		t.Fatal("expected error for missing files")
+	}
+}
+
+func TestClient_BindClientCert(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/certs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Fatalf("expected POST, got %s", r.Method)
+		}
+		var req api.BindClientCertRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Certificate != "PEM" || req.Name != "laptop" {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(api.ClientCert{ID: "id-1", Name: "laptop", Fingerprint: "abcd"})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	cert, err := api.NewClient(srv.URL).BindClientCert("access", api.BindClientCertRequest{Certificate: "PEM", Name: "laptop"})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if cert.ID != "id-1" || cert.Fingerprint != "abcd" {
+		t.Fatalf("unexpected response: %+v", cert)
+	}
+}
@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_StartDeviceAuthorization(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/device/code", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Empty(t, r.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.DeviceCodeResponse{DeviceCode: "dc", UserCode: "BCDF-GHJK", ExpiresIn: 600, Interval: 5})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	resp, err := api.NewClient(srv.URL).StartDeviceAuthorization()
+	require.NoError(t, err)
+	require.Equal(t, "dc", resp.DeviceCode)
+	require.Equal(t, "BCDF-GHJK", resp.UserCode)
+	require.Equal(t, 5, resp.Interval)
+}
+
+// коды ошибок RFC 8628 превращаются в ошибки клиента
+func TestClient_PollDeviceToken_Errors(t *testing.T) {
+	var code string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/device/token", func(w http.ResponseWriter, r *http.Request) {
+		var req api.DeviceTokenRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "dc", req.DeviceCode)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": code})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	code = "authorization_pending"
+	_, err := c.PollDeviceToken("dc")
+	require.ErrorIs(t, err, api.ErrAuthorizationPending)
+
+	code = "slow_down"
+	_, err = c.PollDeviceToken("dc")
+	require.ErrorIs(t, err, api.ErrSlowDown)
+
+	code = "access_denied"
+	_, err = c.PollDeviceToken("dc")
+	require.EqualError(t, err, "device login denied")
+}
+
+func TestClient_ApproveDevice(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/device/approve", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+
+		var req api.DeviceDecisionRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "BCDF-GHJK", req.UserCode)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	require.NoError(t, api.NewClient(srv.URL).ApproveDevice("access-1", "BCDF-GHJK"))
+}
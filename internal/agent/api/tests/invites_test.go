@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_CreateInvite(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/invites", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+
+		var req api.CreateInviteRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, 3, req.MaxUses)
+		require.Equal(t, int64(3600), req.ExpiresIn)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(api.CreateInviteResponse{
+			Code:   "invite-code",
+			Invite: api.Invite{ID: "id-1", MaxUses: 3, ExpiresAt: time.Now().Add(time.Hour)},
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	resp, err := c.CreateInvite("access-1", api.CreateInviteRequest{MaxUses: 3, ExpiresIn: 3600})
+	require.NoError(t, err)
+	require.Equal(t, "invite-code", resp.Code)
+	require.Equal(t, "id-1", resp.ID)
+}
+
+func TestClient_ListAndRevokeInvites(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/invites", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ListInvitesResponse{
+			Invites: []api.Invite{{ID: "id-1", MaxUses: 3, Uses: 1}},
+		})
+	})
+	mux.HandleFunc("/auth/invites/id-1", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodDelete, r.Method)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	list, err := c.ListInvites("access-1")
+	require.NoError(t, err)
+	require.Len(t, list.Invites, 1)
+	require.Equal(t, 1, list.Invites[0].Uses)
+
+	require.NoError(t, c.RevokeInvite("access-1", "id-1"))
+}
@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/pow"
+)
+
+// На 428 клиент сам решает challenge и повторяет запрос с тем же телом
+func TestClient_PostJSON_SolvesProofOfWork(t *testing.T) {
+	const challenge = "nonce.10.9999999999.sig"
+	attempts := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/challenge", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ChallengeResponse{
+			Challenge:  challenge,
+			Difficulty: 10,
+			ExpiresAt:  time.Now().Add(time.Minute),
+		})
+	})
+	mux.HandleFunc("/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		var req api.LoginRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "test@example.com", req.Email)
+
+		if r.Header.Get(pow.HeaderChallenge) == "" {
+			http.Error(w, "proof of work required", http.StatusPreconditionRequired)
+			return
+		}
+		require.Equal(t, challenge, r.Header.Get(pow.HeaderChallenge))
+		require.True(t, pow.Valid(challenge, r.Header.Get(pow.HeaderSolution), 10))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.LoginResponse{AccessToken: "a", RefreshToken: "r"})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	resp, err := api.NewClient(srv.URL).Login("test@example.com", "StrongPass123")
+
+	require.NoError(t, err)
+	require.Equal(t, "a", resp.AccessToken)
+	require.Equal(t, 2, attempts)
+}
+
+// Повторный 428 (например, challenge уже использован) возвращается как ошибка
+func TestClient_PostJSON_ProofOfWorkRejected(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/challenge", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ChallengeResponse{Challenge: "c", ExpiresAt: time.Now().Add(time.Minute)})
+	})
+	mux.HandleFunc("/auth/register", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid proof of work", http.StatusPreconditionRequired)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	_, err := api.NewClient(srv.URL).Register("test@example.com", "StrongPass123", "")
+
+	require.ErrorContains(t, err, "invalid proof of work")
+}
@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ListSessions_SendsDeviceName(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/sessions", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodGet, r.Method)
+		require.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+		require.NotEmpty(t, r.Header.Get("X-Device-Name"))
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ListSessionsResponse{
+			Sessions: []api.Session{{ID: "s1", DeviceName: "laptop"}},
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	resp, err := c.ListSessions("access-1")
+	require.NoError(t, err)
+	require.Len(t, resp.Sessions, 1)
+	require.Equal(t, "laptop", resp.Sessions[0].DeviceName)
+}
+
+func TestClient_RevokeSession(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/sessions/s1", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodDelete, r.Method)
+		require.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+	require.NoError(t, c.RevokeSession("access-1", "s1"))
+}
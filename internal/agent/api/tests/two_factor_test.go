@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Login_MFARequired(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]any{
+			"mfa_required": true,
+			"mfa_token":    "mfa-1",
+			"expires_at":   time.Now().Add(5 * time.Minute),
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	resp, err := c.Login("test@example.com", "StrongPass123")
+	require.NoError(t, err)
+	require.True(t, resp.MFARequired)
+	require.Equal(t, "mfa-1", resp.MFAToken)
+	require.Empty(t, resp.AccessToken)
+}
+
+func TestClient_VerifyMFA(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/2fa/verify", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+
+		var req api.VerifyMFARequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "mfa-1", req.MFAToken)
+		require.Equal(t, "123456", req.Code)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.LoginResponse{AccessToken: "a", RefreshToken: "r"})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	resp, err := c.VerifyMFA("mfa-1", "123456")
+	require.NoError(t, err)
+	require.Equal(t, "a", resp.AccessToken)
+	require.Equal(t, "r", resp.RefreshToken)
+}
+
+func TestClient_VerifyMFA_InvalidCode(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/2fa/verify", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"invalid one-time code"}`))
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	_, err := c.VerifyMFA("mfa-1", "000000")
+	require.Error(t, err)
+}
+
+func TestClient_EnrollAndConfirmTOTP(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/2fa/enroll", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.EnrollTOTPResponse{Secret: "S", OTPAuthURI: "otpauth://totp/x"})
+	})
+	mux.HandleFunc("/auth/2fa/confirm", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer access-1", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ConfirmTOTPResponse{RecoveryCodes: []string{"c1"}})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	c := api.NewClient(srv.URL)
+
+	enr, err := c.EnrollTOTP("access-1")
+	require.NoError(t, err)
+	require.Equal(t, "S", enr.Secret)
+
+	conf, err := c.ConfirmTOTP("access-1", "123456")
+	require.NoError(t, err)
+	require.Equal(t, []string{"c1"}, conf.RecoveryCodes)
+}
@@ -0,0 +1,56 @@
+// В этом файле описаны методы клиента для двухфакторной аутентификации (TOTP):
+// подключение TOTP и второй шаг входа.
+package api
+
+// EnrollTOTPResponse описывает ответ сервера на начало подключения TOTP.
+//
+// OTPAuthURI добавляется в приложение-аутентификатор, Secret — для ручного ввода.
+type EnrollTOTPResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// ConfirmTOTPRequest описывает тело запроса подтверждения TOTP.
+type ConfirmTOTPRequest struct {
+	Code string `json:"code"`
+}
+
+// ConfirmTOTPResponse содержит одноразовые коды восстановления.
+type ConfirmTOTPResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// VerifyMFARequest описывает тело запроса второго шага входа.
+type VerifyMFARequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+// EnrollTOTP начинает подключение TOTP.
+//
+// Метод отправляет POST запрос на /auth/2fa/enroll с access токеном.
+func (c *Client) EnrollTOTP(accessToken string) (EnrollTOTPResponse, error) {
+	var resp EnrollTOTPResponse
+	err := c.PostJSON("/auth/2fa/enroll", nil, &resp, accessToken)
+	return resp, err
+}
+
+// ConfirmTOTP подтверждает подключение TOTP кодом из приложения
+// и возвращает коды восстановления.
+//
+// Метод отправляет POST запрос на /auth/2fa/confirm с access токеном.
+func (c *Client) ConfirmTOTP(accessToken, code string) (ConfirmTOTPResponse, error) {
+	var resp ConfirmTOTPResponse
+	err := c.PostJSON("/auth/2fa/confirm", ConfirmTOTPRequest{Code: code}, &resp, accessToken)
+	return resp, err
+}
+
+// VerifyMFA завершает вход: обменивает mfaToken из ответа Login
+// и TOTP-код (или код восстановления) на пару токенов.
+//
+// Метод отправляет POST запрос на /auth/2fa/verify.
+func (c *Client) VerifyMFA(mfaToken, code string) (LoginResponse, error) {
+	var resp LoginResponse
+	err := c.PostJSON("/auth/2fa/verify", VerifyMFARequest{MFAToken: mfaToken, Code: code}, &resp, "")
+	return resp, err
+}
@@ -0,0 +1,424 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+)
+
+// NewAccountCmd создаёт группу CLI-команд управления аккаунтом.
+//
+// Подкоманды:
+//   - passwd — сменить пароль аккаунта;
+//   - delete — удалить аккаунт (с отсрочкой, в течение которой его можно восстановить);
+//   - restore — отменить удаление аккаунта;
+//   - verify-email — подтвердить email токеном из письма;
+//   - reset-password — сбросить забытый пароль аккаунта по почте.
+//
+// Пример использования:
+//
+//	gophkeeper account passwd
+//	gophkeeper account delete
+//	gophkeeper account restore --email test@example.com
+//	gophkeeper account verify-email --token <token>
+//	gophkeeper account reset-password --email test@example.com
+func NewAccountCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "account",
+		Short: "Управление аккаунтом",
+		Long: `Управление аккаунтом пользователя.
+
+Примеры:
+  gophkeeper account passwd
+  gophkeeper account delete
+  gophkeeper account restore --email test@example.com
+  gophkeeper account verify-email --token <token>
+  gophkeeper account verify-email --resend --email test@example.com
+  gophkeeper account reset-password --email test@example.com
+  gophkeeper account reset-password --token <token>
+
+Сброс пароля возвращает доступ к аккаунту, но не к секретам:
+они зашифрованы мастер-паролем, и сервер не может их расшифровать.
+`,
+	}
+
+	cmd.AddCommand(newAccountPasswdCmd(app))
+	cmd.AddCommand(newAccountDeleteCmd(app))
+	cmd.AddCommand(newAccountRestoreCmd(app))
+	cmd.AddCommand(newAccountVerifyEmailCmd(app))
+	cmd.AddCommand(newAccountResetPasswordCmd(app))
+
+	return cmd
+}
+
+// newAccountPasswdCmd меняет пароль аккаунта.
+//
+// Пароли запрашиваются в терминале без эха (новый — дважды).
+// Для скриптов есть --password-stdin: старый и новый пароль
+// читаются из первых двух строк stdin.
+//
+// Сервер отзывает все сессии пользователя, поэтому новые токены
+// текущего устройства сразу сохраняются в локальный конфиг.
+func newAccountPasswdCmd(app *App) *cobra.Command {
+	var fromStdin bool
+
+	cmd := &cobra.Command{
+		Use:          "passwd",
+		Short:        "Сменить пароль аккаунта",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			oldPassword, newPassword, err := readPasswordChange(cmd, fromStdin)
+			if err != nil {
+				return err
+			}
+
+			c := app.apiClient()
+			resp, err := c.ChangePassword(app.Creds.AccessToken, oldPassword, newPassword)
+			if err != nil {
+				return err
+			}
+
+			app.Creds.AccessToken = resp.AccessToken
+			app.Creds.RefreshToken = resp.RefreshToken
+			if err := config.Save(app.CredsPath, app.Creds); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "password changed (other sessions revoked, tokens saved)")
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&fromStdin, "password-stdin", false, "read old and new password from the first two lines of stdin")
+
+	return cmd
+}
+
+// newAccountDeleteCmd удаляет аккаунт.
+//
+// Команда просит подтвердить удаление вводом слова "delete" (или флаг --yes),
+// затем запрашивает пароль без эха (или читает его из stdin с --password-stdin).
+//
+// Сервер блокирует вход и отзывает все сессии, поэтому локальные токены
+// и кэш секретов удаляются сразу.
+func newAccountDeleteCmd(app *App) *cobra.Command {
+	var yes, fromStdin bool
+
+	cmd := &cobra.Command{
+		Use:          "delete",
+		Short:        "Удалить аккаунт",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+			if fromStdin && !yes {
+				return errors.New("--password-stdin requires --yes")
+			}
+
+			if !yes {
+				fmt.Fprintln(cmd.ErrOrStderr(), "All secrets of this account will be deleted after the grace period.")
+				answer, err := readLine(cmd, "Type 'delete' to confirm: ")
+				if err != nil || answer != "delete" {
+					fmt.Fprintln(cmd.OutOrStdout(), "aborted")
+					return nil
+				}
+			}
+
+			password, err := readPassword(cmd, fromStdin, "Password: ")
+			if err != nil {
+				return err
+			}
+
+			c := app.apiClient()
+			resp, err := c.DeleteMe(app.Creds.AccessToken, password)
+			if err != nil {
+				return err
+			}
+
+			// сессии на сервере уже отозваны — чистим всё локальное
+			app.Creds = &config.Credentials{}
+			if err := config.Save(app.CredsPath, app.Creds); err != nil {
+				return err
+			}
+			if app.Secrets != nil {
+				app.Secrets.ReplaceAll(nil)
+			}
+			if app.SecretsPath != "" {
+				if err := os.Remove(app.SecretsPath); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "account scheduled for deletion at %s (local credentials and secrets wiped)\n",
+				resp.DeleteAfter.Local().Format("2006-01-02 15:04:05"))
+			fmt.Fprintln(out, "to undo before then, run: gophkeeper account restore --email <email>")
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&yes, "yes", false, "do not ask for confirmation")
+	cmd.Flags().BoolVar(&fromStdin, "password-stdin", false, "read password from the first line of stdin (requires --yes)")
+
+	return cmd
+}
+
+// newAccountRestoreCmd отменяет удаление аккаунта в течение отсрочки.
+func newAccountRestoreCmd(app *App) *cobra.Command {
+	var (
+		email     string
+		fromStdin bool
+	)
+
+	cmd := &cobra.Command{
+		Use:          "restore",
+		Short:        "Отменить удаление аккаунта",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			password, err := readPassword(cmd, fromStdin, "Password: ")
+			if err != nil {
+				return err
+			}
+
+			c := app.apiClient()
+			if err := c.RestoreAccount(email, password); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "account restored, run: gophkeeper login")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&email, "email", "", "account email")
+	cmd.Flags().BoolVar(&fromStdin, "password-stdin", false, "read password from the first line of stdin")
+	cmd.MarkFlagRequired("email")
+
+	return cmd
+}
+
+// newAccountVerifyEmailCmd подтверждает email токеном из письма
+// или (--resend) просит отправить письмо ещё раз.
+func newAccountVerifyEmailCmd(app *App) *cobra.Command {
+	var (
+		token, email string
+		resend       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:          "verify-email",
+		Short:        "Подтвердить email",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := app.apiClient()
+			out := cmd.OutOrStdout()
+
+			if resend {
+				if email == "" {
+					return errors.New("--resend requires --email")
+				}
+				if err := c.ResendVerification(email); err != nil {
+					return err
+				}
+				fmt.Fprintln(out, "if the address belongs to an unverified account, a new email has been sent")
+				return nil
+			}
+
+			if token == "" {
+				return errors.New("--token is required (or use --resend --email)")
+			}
+			if err := c.VerifyEmail(token); err != nil {
+				return err
+			}
+			fmt.Fprintln(out, "email verified")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&token, "token", "", "token from the verification email")
+	cmd.Flags().BoolVar(&resend, "resend", false, "send the verification email again")
+	cmd.Flags().StringVar(&email, "email", "", "account email (with --resend)")
+	cmd.MarkFlagsMutuallyExclusive("token", "resend")
+
+	return cmd
+}
+
+// newAccountResetPasswordCmd сбрасывает забытый пароль аккаунта в два шага:
+// --email отправляет письмо с токеном, --token задаёт новый пароль.
+//
+// Новый пароль запрашивается в терминале без эха дважды
+// (или читается из первой строки stdin с --password-stdin).
+// Сервер отзывает все сессии, поэтому локальные токены удаляются.
+// Мастер-пароль и зашифрованные им секреты сброс не затрагивает.
+func newAccountResetPasswordCmd(app *App) *cobra.Command {
+	var (
+		token, email string
+		fromStdin    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:          "reset-password",
+		Short:        "Сбросить забытый пароль аккаунта по почте",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := app.apiClient()
+			out := cmd.OutOrStdout()
+
+			if token == "" {
+				if email == "" {
+					return errors.New("either --email or --token is required")
+				}
+				if err := c.RequestPasswordReset(email); err != nil {
+					return err
+				}
+				fmt.Fprintln(out, "if the address belongs to an account, a reset token has been sent")
+				fmt.Fprintln(out, "then run: gophkeeper account reset-password --token <token>")
+				return nil
+			}
+
+			newPassword, err := readNewPassword(cmd, fromStdin)
+			if err != nil {
+				return err
+			}
+			if err := c.ResetPassword(token, newPassword); err != nil {
+				return err
+			}
+
+			// сессии на сервере отозваны — локальные токены больше не действуют
+			if app.Creds != nil && (app.Creds.AccessToken != "" || app.Creds.RefreshToken != "") {
+				app.Creds.AccessToken = ""
+				app.Creds.RefreshToken = ""
+				if err := config.Save(app.CredsPath, app.Creds); err != nil {
+					return err
+				}
+			}
+
+			fmt.Fprintln(out, "password reset (all sessions revoked), run: gophkeeper login")
+			fmt.Fprintln(out, "note: secrets are still encrypted with your master password; the reset does not recover them")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&email, "email", "", "account email: request a reset token")
+	cmd.Flags().StringVar(&token, "token", "", "token from the reset email: set a new password")
+	cmd.Flags().BoolVar(&fromStdin, "password-stdin", false, "read the new password from the first line of stdin")
+	cmd.MarkFlagsMutuallyExclusive("email", "token")
+
+	return cmd
+}
+
+// readNewPassword читает новый пароль: первую строку stdin (fromStdin=true)
+// или дважды из терминала без эха.
+func readNewPassword(cmd *cobra.Command, fromStdin bool) (string, error) {
+	if fromStdin {
+		return readPassword(cmd, true, "")
+	}
+
+	password, err := readHiddenLine(cmd, "New password: ")
+	if err != nil {
+		return "", err
+	}
+	repeat, err := readHiddenLine(cmd, "Repeat new password: ")
+	if err != nil {
+		return "", err
+	}
+	if password != repeat {
+		return "", errors.New("new passwords do not match")
+	}
+	return password, nil
+}
+
+// readPassword читает один пароль: первую строку stdin (fromStdin=true)
+// или из терминала без эха.
+func readPassword(cmd *cobra.Command, fromStdin bool, prompt string) (string, error) {
+	if !fromStdin {
+		return readHiddenLine(cmd, prompt)
+	}
+
+	b, err := io.ReadAll(cmd.InOrStdin())
+	if err != nil {
+		return "", fmt.Errorf("read password from stdin: %w", err)
+	}
+	line, _, _ := strings.Cut(string(b), "\n")
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", errors.New("empty password on stdin")
+	}
+	return line, nil
+}
+
+// readPasswordChange читает старый и новый пароль.
+//
+// Режимы:
+//   - fromStdin=true: первые две строки stdin (старый, новый);
+//   - fromStdin=false: терминал со скрытым вводом, новый пароль вводится дважды.
+func readPasswordChange(cmd *cobra.Command, fromStdin bool) (string, string, error) {
+	if fromStdin {
+		b, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return "", "", fmt.Errorf("read passwords from stdin: %w", err)
+		}
+		lines := strings.Split(string(bytes.ReplaceAll(b, []byte("\r\n"), []byte("\n"))), "\n")
+		if len(lines) < 2 || lines[0] == "" || lines[1] == "" {
+			return "", "", errors.New("expected old and new password on separate lines of stdin")
+		}
+		return lines[0], lines[1], nil
+	}
+
+	oldPassword, err := readHiddenLine(cmd, "Current password: ")
+	if err != nil {
+		return "", "", err
+	}
+	newPassword, err := readHiddenLine(cmd, "New password: ")
+	if err != nil {
+		return "", "", err
+	}
+	repeat, err := readHiddenLine(cmd, "Repeat new password: ")
+	if err != nil {
+		return "", "", err
+	}
+	if newPassword != repeat {
+		return "", "", errors.New("new passwords do not match")
+	}
+	return oldPassword, newPassword, nil
+}
+
+// readHiddenLine читает строку из терминала без эха.
+// Если stdin не терминал — возвращает ошибку с подсказкой про --password-stdin.
+func readHiddenLine(cmd *cobra.Command, prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.New("stdin is not a terminal; use --password-stdin")
+	}
+
+	fmt.Fprint(cmd.ErrOrStderr(), prompt)
+	b, err := term.ReadPassword(fd)
+	fmt.Fprintln(cmd.ErrOrStderr())
+	if err != nil {
+		return "", fmt.Errorf("read password: %w", err)
+	}
+
+	s := strings.TrimSpace(string(b))
+	if s == "" {
+		return "", errors.New("empty password")
+	}
+	return s, nil
+}
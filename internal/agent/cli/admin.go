@@ -0,0 +1,169 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// NewAdminCmd создаёт группу CLI-команд администрирования сервера.
+//
+// Подкоманды:
+//   - users — список пользователей с числом секретов и последним входом;
+//   - disable <id> / enable <id> — заблокировать или разблокировать аккаунт;
+//   - revoke-sessions <id> — выйти из аккаунта пользователя на всех устройствах;
+//   - stats — сводная статистика хранилища.
+//
+// Доступно администраторам сервера (users.is_admin). Блокировка, разблокировка
+// и отзыв сессий требуют недавнего gophkeeper reauth.
+//
+// Пример использования:
+//
+//	gophkeeper admin users --limit 20
+//	gophkeeper admin disable 7a0a4a6a-a7bf-42c0-8cdf-2be8583d180e
+//	gophkeeper admin stats
+func NewAdminCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Администрирование сервера (для администраторов)",
+		Long: `Администрирование сервера.
+
+Доступно пользователям с ролью администратора (users.is_admin).
+Заблокированный аккаунт не может войти, обновить токены и пользоваться
+API-токенами; его сессии отзываются сразу, секреты сохраняются.
+Блокировка, разблокировка и отзыв сессий требуют gophkeeper reauth.
+
+Примеры:
+  gophkeeper admin users --limit 20 --offset 40
+  gophkeeper admin disable <uuid>
+  gophkeeper admin enable <uuid>
+  gophkeeper admin revoke-sessions <uuid>
+  gophkeeper admin stats
+`,
+	}
+
+	cmd.AddCommand(newAdminUsersCmd(app))
+	cmd.AddCommand(newAdminUserActionCmd(app, "disable", "Заблокировать аккаунт и отозвать его сессии", "disabled"))
+	cmd.AddCommand(newAdminUserActionCmd(app, "enable", "Разблокировать аккаунт", "enabled"))
+	cmd.AddCommand(newAdminUserActionCmd(app, "revoke-sessions", "Отозвать все сессии пользователя", "sessions revoked"))
+	cmd.AddCommand(newAdminStatsCmd(app))
+
+	return cmd
+}
+
+// newAdminUsersCmd печатает страницу пользователей сервера.
+func newAdminUsersCmd(app *App) *cobra.Command {
+	var limit, offset int
+
+	cmd := &cobra.Command{
+		Use:          "users",
+		Short:        "Список пользователей",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+			if limit < 0 || offset < 0 {
+				return fmt.Errorf("--limit and --offset must not be negative")
+			}
+
+			c := app.apiClient()
+			resp, err := c.AdminUsers(app.Creds.AccessToken, limit, offset)
+			if err != nil {
+				return err
+			}
+
+			if len(resp.Users) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "no users")
+				return nil
+			}
+
+			for _, u := range resp.Users {
+				lastLogin := "never"
+				if u.LastLoginAt != nil {
+					lastLogin = u.LastLoginAt.Format("2006-01-02 15:04:05")
+				}
+				status := "active"
+				if u.Disabled {
+					status = "disabled"
+				}
+				if u.IsAdmin {
+					status += ",admin"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(),
+					"%s\t%s\t%s\tsecrets=%d\tcreated=%s\tlast_login=%s\n",
+					u.ID, u.Email, status, u.SecretCount,
+					u.CreatedAt.Format("2006-01-02 15:04:05"), lastLogin,
+				)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 0, "page size (default 50, max 200)")
+	cmd.Flags().IntVar(&offset, "offset", 0, "number of users to skip")
+
+	return cmd
+}
+
+// newAdminUserActionCmd создаёт команду действия над аккаунтом по ID.
+func newAdminUserActionCmd(app *App, use, short, done string) *cobra.Command {
+	return &cobra.Command{
+		Use:          use + " <id>",
+		Short:        short,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			c := app.apiClient()
+			var err error
+			switch use {
+			case "disable":
+				err = c.DisableUser(app.Creds.AccessToken, args[0])
+			case "enable":
+				err = c.EnableUser(app.Creds.AccessToken, args[0])
+			default:
+				err = c.RevokeUserSessions(app.Creds.AccessToken, args[0])
+			}
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "user %s: %s\n", args[0], done)
+			return nil
+		},
+	}
+}
+
+// newAdminStatsCmd печатает статистику хранилища.
+func newAdminStatsCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:          "stats",
+		Short:        "Статистика хранилища",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			c := app.apiClient()
+			st, err := c.AdminStats(app.Creds.AccessToken)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "users=%d\n", st.Users)
+			fmt.Fprintf(out, "disabled_users=%d\n", st.DisabledUsers)
+			fmt.Fprintf(out, "secrets=%d\n", st.Secrets)
+			fmt.Fprintf(out, "secret_bytes=%d\n", st.SecretBytes)
+			fmt.Fprintf(out, "active_sessions=%d\n", st.ActiveSessions)
+			return nil
+		},
+	}
+}
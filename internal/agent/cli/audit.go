@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+)
+
+// NewAuditCmd создаёт CLI-команду просмотра журнала аудита.
+//
+// Журнал показывает входы, неудачные попытки входа, повторное
+// использование refresh-токена, отзыв сессий и изменения секретов —
+// от новых событий к старым, постранично.
+//
+// Флаги:
+//   - --since — только события не старше длительности (24h) или момента (RFC 3339);
+//   - --type — только события указанного типа (например, login_failed);
+//   - --limit — размер страницы;
+//   - --cursor — курсор следующей страницы из предыдущего вывода.
+//
+// Пример использования:
+//
+//	gophkeeper audit --since 24h --type login_failed
+func NewAuditCmd(app *App) *cobra.Command {
+	var (
+		since  string
+		typ    string
+		limit  int
+		cursor int64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Журнал аудита аккаунта",
+		Long: `Показать журнал аудита: входы, неудачные попытки входа, повторное
+использование refresh-токена, отзыв сессий, создание, изменение и удаление секретов.
+
+Типы событий: login, login_failed, logout, refresh_reuse, session_revoked,
+password_changed, reauth, secret_created, secret_updated, secret_deleted.
+
+Примеры:
+  gophkeeper audit
+  gophkeeper audit --since 24h --type login_failed
+  gophkeeper audit --since 2026-01-01T00:00:00Z --limit 20 --cursor 1234
+`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			q := api.AuditQuery{Type: strings.TrimSpace(typ), Limit: limit, Cursor: cursor}
+			if since != "" {
+				t, err := parseSince(since, time.Now())
+				if err != nil {
+					return err
+				}
+				q.Since = t
+			}
+
+			c := app.apiClient()
+			resp, err := c.Audit(app.Creds.AccessToken, q)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(resp.Events) == 0 {
+				fmt.Fprintln(out, "no audit events")
+				return nil
+			}
+
+			for _, e := range resp.Events {
+				line := fmt.Sprintf("%s\t%s\t%s\t%s",
+					e.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+					e.Type, orDash(e.IP), orDash(e.UserAgent),
+				)
+				if e.SecretID != "" {
+					line += "\tsecret=" + e.SecretID
+				}
+				fmt.Fprintln(out, line)
+			}
+			if resp.NextCursor != 0 {
+				fmt.Fprintf(out, "more events: repeat with --cursor %d\n", resp.NextCursor)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&since, "since", "", "только события не старше длительности (24h) или момента (RFC 3339)")
+	cmd.Flags().StringVar(&typ, "type", "", "тип события (например, login_failed)")
+	cmd.Flags().IntVar(&limit, "limit", 0, "размер страницы (по умолчанию 50, не больше 200)")
+	cmd.Flags().Int64Var(&cursor, "cursor", 0, "курсор следующей страницы")
+
+	return cmd
+}
+
+// parseSince разбирает --since: длительность назад от now или момент в RFC 3339.
+func parseSince(v string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(v); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("invalid --since %q: duration must be positive", v)
+		}
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid --since %q: want duration (24h) or RFC 3339 time", v)
+	}
+	return t, nil
+}
+
+// orDash подставляет "-" вместо пустого значения в табличном выводе.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+)
+
+// NewCertCmd создаёт группу CLI-команд клиентских сертификатов (mTLS).
+//
+// Подкоманды:
+//   - bind — привязать к аккаунту сертификат из --client-cert;
+//   - list — показать привязанные сертификаты;
+//   - unbind <id> — отвязать сертификат.
+//
+// После привязки сервер выдаёт и обновляет токены только клиенту,
+// предъявившему привязанный сертификат, поэтому дальше нужны
+// --client-cert/--client-key (или GOPHKEEPER_CLIENT_CERT/GOPHKEEPER_CLIENT_KEY).
+//
+// Пример использования:
+//
+//	gophkeeper cert bind --client-cert laptop.crt --client-key laptop.key --name laptop
+//	gophkeeper cert list
+//	gophkeeper cert unbind 7a0a4a6a-a7bf-42c0-8cdf-2be8583d180e
+func NewCertCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cert",
+		Short: "Привязка клиентского сертификата (mTLS) к аккаунту",
+		Long: `Клиентские сертификаты (mTLS).
+
+Привязанный сертификат — второй фактор: без него сервер не выдаёт
+и не обновляет токены, а access-токен принимается только вместе с ним.
+Сертификат должен быть выпущен CA, которому доверяет сервер.
+Привязывается только сертификат, с которым выполнен запрос (--client-cert/--client-key):
+так сервер убеждается, что у клиента есть его закрытый ключ.
+
+Примеры:
+  gophkeeper cert bind --client-cert laptop.crt --client-key laptop.key --name laptop
+  gophkeeper cert list
+  gophkeeper cert unbind <uuid>
+`,
+	}
+
+	cmd.AddCommand(newCertBindCmd(app))
+	cmd.AddCommand(newCertListCmd(app))
+	cmd.AddCommand(newCertUnbindCmd(app))
+
+	return cmd
+}
+
+// newCertBindCmd отправляет на привязку сертификат из --client-cert.
+//
+// Запрос идёт по mTLS с этим же сертификатом: сервер привязывает только предъявленный.
+func newCertBindCmd(app *App) *cobra.Command {
+	var name string
+
+	cmd := &cobra.Command{
+		Use:          "bind",
+		Short:        "Привязать клиентский сертификат к аккаунту",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+			if app.ClientCertFile == "" || app.ClientKeyFile == "" {
+				return fmt.Errorf("certificate is required: use --client-cert and --client-key")
+			}
+
+			data, err := os.ReadFile(app.ClientCertFile)
+			if err != nil {
+				return fmt.Errorf("read certificate: %w", err)
+			}
+
+			c := app.apiClient()
+			cert, err := c.BindClientCert(app.Creds.AccessToken, api.BindClientCertRequest{
+				Certificate: string(data),
+				Name:        name,
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "certificate %s (%s) bound, fingerprint %s\n", cert.ID, cert.Name, cert.Fingerprint)
+			fmt.Fprintf(cmd.ErrOrStderr(),
+				"from now on pass --client-cert/--client-key (or %s/%s) to log in and refresh tokens\n",
+				config.ClientCertEnv, config.ClientKeyEnv,
+			)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "certificate name (default: certificate CN)")
+
+	return cmd
+}
+
+// newCertListCmd печатает привязанные сертификаты.
+func newCertListCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "Показать привязанные сертификаты",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			c := app.apiClient()
+			resp, err := c.ListClientCerts(app.Creds.AccessToken)
+			if err != nil {
+				return err
+			}
+
+			if len(resp.Certificates) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "no client certificates")
+				return nil
+			}
+
+			for _, cert := range resp.Certificates {
+				fmt.Fprintf(cmd.OutOrStdout(),
+					"%s\t%s\t%s\tfingerprint=%s\texpires=%s\n",
+					cert.ID, cert.Name, cert.Subject, cert.Fingerprint,
+					cert.NotAfter.Format("2006-01-02 15:04:05"),
+				)
+			}
+			return nil
+		},
+	}
+}
+
+// newCertUnbindCmd отвязывает сертификат по ID.
+func newCertUnbindCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:          "unbind <id>",
+		Short:        "Отвязать сертификат по ID",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			c := app.apiClient()
+			if err := c.UnbindClientCert(app.Creds.AccessToken, args[0]); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "certificate %s unbound\n", args[0])
+			return nil
+		},
+	}
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+)
+
+// defaultDevicePollInterval — интервал опроса, если сервер его не прислал (RFC 8628, 3.2).
+const defaultDevicePollInterval = 5 * time.Second
+
+// NewDeviceCmd создаёт группу CLI-команд входа по коду устройства (RFC 8628).
+//
+// Подкоманды:
+//   - login — вход без пароля на машине без браузера (например, по SSH):
+//     печатает код и ждёт, пока его подтвердят;
+//   - approve <code> — подтвердить код с уже авторизованной машины;
+//   - deny <code> — отклонить код.
+//
+// Пример использования:
+//
+//	gophkeeper device login
+//	gophkeeper device approve BCDF-GHJK
+func NewDeviceCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "device",
+		Short: "Вход по коду устройства (без ввода пароля)",
+		Long: `Вход по коду устройства.
+
+На машине без браузера (пароль не попадёт в историю shell):
+  gophkeeper device login
+
+На уже авторизованной машине:
+  gophkeeper device approve BCDF-GHJK
+  gophkeeper device deny BCDF-GHJK
+`,
+	}
+
+	cmd.AddCommand(newDeviceLoginCmd(app))
+	cmd.AddCommand(newDeviceDecisionCmd(app, true))
+	cmd.AddCommand(newDeviceDecisionCmd(app, false))
+
+	return cmd
+}
+
+// newDeviceLoginCmd получает код устройства и опрашивает сервер до подтверждения.
+func newDeviceLoginCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:          "login",
+		Short:        "Войти, подтвердив код с другого устройства",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := app.apiClient()
+
+			code, err := c.StartDeviceAuthorization()
+			if err != nil {
+				return err
+			}
+
+			interval := time.Duration(code.Interval) * time.Second
+			if interval <= 0 {
+				interval = defaultDevicePollInterval
+			}
+			deadline := time.Now().Add(time.Duration(code.ExpiresIn) * time.Second)
+
+			fmt.Fprintf(cmd.ErrOrStderr(),
+				"To sign in, run on a logged-in device:\n  gophkeeper device approve %s\nThe code expires in %s. Waiting for approval...\n",
+				code.UserCode, time.Until(deadline).Round(time.Second),
+			)
+
+			for {
+				select {
+				case <-cmd.Context().Done():
+					return cmd.Context().Err()
+				case <-time.After(interval):
+				}
+
+				resp, err := c.PollDeviceToken(code.DeviceCode)
+				switch {
+				case errors.Is(err, api.ErrAuthorizationPending):
+				case errors.Is(err, api.ErrSlowDown):
+					interval += 5 * time.Second
+				case err != nil:
+					return err
+				default:
+					app.Creds.AccessToken = resp.AccessToken
+					app.Creds.RefreshToken = resp.RefreshToken
+					if err := config.Save(app.CredsPath, app.Creds); err != nil {
+						return err
+					}
+					fmt.Fprintln(cmd.OutOrStdout(), "login ok (tokens saved)")
+					return nil
+				}
+
+				if time.Now().After(deadline) {
+					return errors.New("device code expired, start again")
+				}
+			}
+		},
+	}
+}
+
+// newDeviceDecisionCmd подтверждает (approve) или отклоняет (deny) код устройства.
+func newDeviceDecisionCmd(app *App, approve bool) *cobra.Command {
+	use, short, done := "approve <code>", "Подтвердить вход устройства по коду", "approved"
+	if !approve {
+		use, short, done = "deny <code>", "Отклонить вход устройства по коду", "denied"
+	}
+
+	return &cobra.Command{
+		Use:          use,
+		Short:        short,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			c := app.apiClient()
+			decide := c.ApproveDevice
+			if !approve {
+				decide = c.DenyDevice
+			}
+			if err := decide(app.Creds.AccessToken, args[0]); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "device %s %s\n", args[0], done)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+)
+
+// NewInviteCmd создаёт группу CLI-команд кодов приглашений.
+//
+// Подкоманды:
+//   - create — выпустить код на одну или несколько регистраций;
+//   - list — показать действующие приглашения;
+//   - revoke <id> — отозвать приглашение.
+//
+// Доступно пользователям из auth.invites.admins и администраторам сервера. Код печатается один раз
+// и передаётся новому пользователю для gophkeeper register --invite.
+//
+// Пример использования:
+//
+//	gophkeeper invite create --uses 5 --ttl 72h
+//	gophkeeper invite list
+//	gophkeeper invite revoke 7a0a4a6a-a7bf-42c0-8cdf-2be8583d180e
+func NewInviteCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "invite",
+		Short: "Коды приглашений для регистрации (для администраторов)",
+		Long: `Коды приглашений.
+
+Нужны, когда сервер принимает регистрацию только по приглашениям
+(auth.registration: invite). Выпускать коды могут пользователи из auth.invites.admins
+и администраторы сервера (users.is_admin).
+Использование: gophkeeper register --email <email> --password <password> --invite <code>
+
+Примеры:
+  gophkeeper invite create --uses 5 --ttl 72h
+  gophkeeper invite list
+  gophkeeper invite revoke <uuid>
+`,
+	}
+
+	cmd.AddCommand(newInviteCreateCmd(app))
+	cmd.AddCommand(newInviteListCmd(app))
+	cmd.AddCommand(newInviteRevokeCmd(app))
+
+	return cmd
+}
+
+// newInviteCreateCmd выпускает код приглашения и печатает его.
+func newInviteCreateCmd(app *App) *cobra.Command {
+	var (
+		uses int
+		ttl  time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:          "create",
+		Short:        "Выпустить код приглашения",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+			if uses < 0 {
+				return fmt.Errorf("--uses must not be negative")
+			}
+			if ttl < 0 {
+				return fmt.Errorf("--ttl must not be negative")
+			}
+
+			c := app.apiClient()
+			resp, err := c.CreateInvite(app.Creds.AccessToken, api.CreateInviteRequest{
+				MaxUses:   uses,
+				ExpiresIn: int64(ttl / time.Second),
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), resp.Code)
+			fmt.Fprintf(cmd.ErrOrStderr(),
+				"invite %s created for %d registration(s), expires %s; it is shown only once\n",
+				resp.ID, resp.MaxUses, resp.ExpiresAt.Format("2006-01-02 15:04:05"),
+			)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&uses, "uses", 1, "how many accounts may register with the code")
+	cmd.Flags().DurationVar(&ttl, "ttl", 0, "invite lifetime, e.g. 72h (default: server setting)")
+
+	return cmd
+}
+
+// newInviteListCmd печатает действующие приглашения.
+func newInviteListCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "Показать действующие приглашения",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			c := app.apiClient()
+			resp, err := c.ListInvites(app.Creds.AccessToken)
+			if err != nil {
+				return err
+			}
+
+			if len(resp.Invites) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "no active invites")
+				return nil
+			}
+
+			for _, inv := range resp.Invites {
+				fmt.Fprintf(cmd.OutOrStdout(),
+					"%s\tuses=%d/%d\texpires=%s\n",
+					inv.ID, inv.Uses, inv.MaxUses, inv.ExpiresAt.Format("2006-01-02 15:04:05"),
+				)
+			}
+			return nil
+		},
+	}
+}
+
+// newInviteRevokeCmd отзывает приглашение по ID.
+func newInviteRevokeCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:          "revoke <id>",
+		Short:        "Отозвать приглашение по ID",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			c := app.apiClient()
+			if err := c.RevokeInvite(app.Creds.AccessToken, args[0]); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "invite %s revoked\n", args[0])
+			return nil
+		},
+	}
+}
@@ -15,17 +15,21 @@ import (
 // получает пару access/refresh токенов и сохраняет их в локальный
 // конфигурационный файл.
 //
-// Для выполнения команды требуется указать обязательные флаги
-// --email и --password.
+// Для выполнения команды требуется указать флаги --email и --password
+// либо --id-token — ID-токен корпоративного OpenID Connect провайдера.
 //
 // Пример использования:
 //
 //	gophkeeper login --email test@example.com --password StrongPass123
 //
+// Если у пользователя подключена двухфакторная аутентификация, сервер
+// вместо токенов возвращает challenge: команда запрашивает код из
+// приложения-аутентификатора (или код восстановления) либо берёт его из --code.
+//
 // В случае успешного выполнения токены сохраняются локально, а пользователю
 // выводится сообщение об успешном входе.
 func NewLoginCmd(app *App) *cobra.Command {
-	var email, password string
+	var email, password, code, idToken string
 
 	cmd := &cobra.Command{
 		Use:   "login",
@@ -34,15 +38,53 @@ func NewLoginCmd(app *App) *cobra.Command {
 
 Пример:
   gophkeeper login --email test@example.com --password StrongPass123
+
+При подключённой 2FA код будет запрошен интерактивно, либо:
+  gophkeeper login --email test@example.com --password StrongPass123 --code 123456
+
+Без ввода пароля (например, по SSH): gophkeeper device login
+
+Через корпоративный провайдер (ID-токен получен у провайдера):
+  gophkeeper login --id-token "$ID_TOKEN"
 `,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if idToken == "" && (email == "" || password == "") {
+				return fmt.Errorf("--email and --password (or --id-token) are required")
+			}
+			if idToken != "" && (email != "" || password != "") {
+				return fmt.Errorf("--id-token cannot be combined with --email/--password")
+			}
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// создаём API-клиент для общения с сервером
-			c := api.NewClient(app.ServerURL)
-			// выполняем логин пользователя
-			resp, err := c.Login(email, password)
+			c := app.apiClient()
+			// выполняем логин пользователя: по паролю или по ID-токену провайдера
+			var (
+				resp api.LoginResponse
+				err  error
+			)
+			if idToken != "" {
+				resp, err = c.LoginOIDC(idToken)
+			} else {
+				resp, err = c.Login(email, password)
+			}
 			if err != nil {
 				return err
 			}
+			// второй фактор: обмениваем challenge и код на токены
+			if resp.MFARequired {
+				if code == "" {
+					code, err = readLine(cmd, "Two-factor code (or recovery code): ")
+					if err != nil {
+						return err
+					}
+				}
+				resp, err = c.VerifyMFA(resp.MFAToken, code)
+				if err != nil {
+					return err
+				}
+			}
 
 			// сохраняем полученные токены в состоянии приложения
 			app.Creds.AccessToken = resp.AccessToken
@@ -60,8 +102,8 @@ func NewLoginCmd(app *App) *cobra.Command {
 
 	cmd.Flags().StringVar(&email, "email", "", "email for login")
 	cmd.Flags().StringVar(&password, "password", "", "password for login")
-	cmd.MarkFlagRequired("email")
-	cmd.MarkFlagRequired("password")
+	cmd.Flags().StringVar(&code, "code", "", "TOTP or recovery code (if two-factor auth is enabled)")
+	cmd.Flags().StringVar(&idToken, "id-token", "", "ID token from the company OpenID Connect provider (instead of email/password)")
 
 	return cmd
 }
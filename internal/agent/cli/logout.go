@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+)
+
+// NewLogoutCmd создаёт CLI-команду выхода пользователя.
+//
+// Команда отзывает текущую refresh-сессию на сервере и очищает
+// локально сохранённые токены.
+//
+// Флаги:
+//   - --all: отозвать все сессии пользователя (выход со всех устройств);
+//   - --wipe: дополнительно удалить локальный кэш секретов (secrets.json).
+//
+// Пример использования:
+//
+//	gophkeeper logout
+//	gophkeeper logout --all --wipe
+//
+// Если сервер недоступен или токен уже недействителен, обычный logout
+// всё равно очищает локальные токены (с предупреждением). С --all ошибка
+// сервера возвращается: пользователь явно просил отозвать сессии на сервере.
+func NewLogoutCmd(app *App) *cobra.Command {
+	var all, wipe bool
+
+	cmd := &cobra.Command{
+		Use:   "logout",
+		Short: "Выход (отозвать refresh токен и очистить локальные токены)",
+		Long: `Выход пользователя.
+
+Отзывает текущую сессию на сервере и удаляет токены из локального конфига.
+
+Примеры:
+  gophkeeper logout
+  gophkeeper logout --all          # выйти на всех устройствах
+  gophkeeper logout --wipe         # удалить также локальный кэш секретов
+`,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds != nil && app.Creds.RefreshToken != "" {
+				c := app.apiClient()
+				if err := c.Logout(app.Creds.RefreshToken, all); err != nil {
+					if all {
+						return err
+					}
+					fmt.Fprintf(cmd.ErrOrStderr(), "warning: server logout failed: %v\n", err)
+				}
+			} else if all {
+				return fmt.Errorf("no refresh_token in config, run: gophkeeper login")
+			}
+
+			// очищаем токены локально
+			app.Creds = &config.Credentials{}
+			if err := config.Save(app.CredsPath, app.Creds); err != nil {
+				return err
+			}
+
+			if wipe {
+				if app.Secrets != nil {
+					app.Secrets.ReplaceAll(nil)
+				}
+				if err := os.Remove(app.SecretsPath); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+			}
+
+			switch {
+			case all && wipe:
+				fmt.Fprintln(cmd.OutOrStdout(), "logout ok (all sessions revoked, local secrets wiped)")
+			case all:
+				fmt.Fprintln(cmd.OutOrStdout(), "logout ok (all sessions revoked)")
+			case wipe:
+				fmt.Fprintln(cmd.OutOrStdout(), "logout ok (local secrets wiped)")
+			default:
+				fmt.Fprintln(cmd.OutOrStdout(), "logout ok")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "revoke sessions on all devices")
+	cmd.Flags().BoolVar(&wipe, "wipe", false, "also remove local secrets cache")
+
+	return cmd
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// NewReauthCmd создаёт CLI-команду повторной аутентификации.
+//
+// Удаление секретов, выпуск API-токенов, привязка сертификатов, подключение 2FA,
+// подтверждение входа по коду устройства и выпуск приглашений требуют недавнего
+// ввода пароля. Команда запрашивает пароль без эха (или читает его из stdin
+// с --password-stdin), при подключённой 2FA — код, и сохраняет полученный
+// access токен с повышенными правами в локальный конфиг.
+//
+// Пример использования:
+//
+//	gophkeeper reauth
+//	gophkeeper delete <id>
+func NewReauthCmd(app *App) *cobra.Command {
+	var code string
+	var fromStdin bool
+
+	cmd := &cobra.Command{
+		Use:   "reauth",
+		Short: "Подтвердить пароль для чувствительных операций",
+		Long: `Повторно подтверждает пароль и на несколько минут открывает
+чувствительные операции: удаление секретов, выпуск API-токенов,
+привязку сертификатов, подключение 2FA, подтверждение входа устройства,
+блокировку аккаунтов и отзыв сессий администратором.
+
+Пример:
+  gophkeeper reauth
+  gophkeeper delete <uuid>
+
+При подключённой 2FA код будет запрошен интерактивно, либо:
+  gophkeeper reauth --code 123456
+`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			password, err := readPassword(cmd, fromStdin, "Password: ")
+			if err != nil {
+				return err
+			}
+
+			c := app.apiClient()
+			resp, err := c.Reauth(app.Creds.AccessToken, password, code)
+			// второй фактор: пароль верен, нужен код из приложения
+			if err != nil && code == "" && strings.Contains(err.Error(), serr.ErrMFARequired.Error()) {
+				if fromStdin {
+					return fmt.Errorf("two-factor code required, pass --code")
+				}
+				code, err = readLine(cmd, "Two-factor code (or recovery code): ")
+				if err != nil {
+					return err
+				}
+				resp, err = c.Reauth(app.Creds.AccessToken, password, code)
+			}
+			if err != nil {
+				return err
+			}
+
+			app.Creds.AccessToken = resp.AccessToken
+			if err := config.Save(app.CredsPath, app.Creds); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "reauthenticated until %s\n", resp.ElevatedUntil.Local().Format(time.TimeOnly))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&code, "code", "", "two-factor code (or recovery code) if 2FA is enabled")
+	cmd.Flags().BoolVar(&fromStdin, "password-stdin", false, "read password from the first line of stdin")
+
+	return cmd
+}
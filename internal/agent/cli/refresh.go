@@ -5,7 +5,6 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
 )
 
@@ -38,7 +37,7 @@ func NewRefreshCmd(app *App) *cobra.Command {
 				return fmt.Errorf("no refresh_token in config, run: gophkeeper login")
 			}
 
-			c := api.NewClient(app.ServerURL)
+			c := app.apiClient()
 			// генерирует новый jwt по refresh
 			resp, err := c.Refresh(app.Creds.RefreshToken)
 			if err != nil {
@@ -4,8 +4,6 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
-
-	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
 )
 
 // NewRegisterCmd создаёт CLI-команду для регистрации нового пользователя.
@@ -13,28 +11,35 @@ import (
 // Команда выполняет регистрацию пользователя на сервере GophKeeper
 // с использованием email и пароля. Для выполнения команды необходимо
 // указать обязательные флаги --email и --password.
+// Если сервер принимает регистрацию только по приглашениям,
+// код передаётся флагом --invite.
 //
 // Пример использования:
 //
 //	gophkeeper register --email test@example.com --password StrongPass123
+//	gophkeeper register --email test@example.com --password StrongPass123 --invite <code>
 //
 // В случае успешной регистрации пользователю выводится сообщение
 // об успешном завершении операции.
 func NewRegisterCmd(app *App) *cobra.Command {
-	var email, password string
+	var email, password, invite string
 
 	cmd := &cobra.Command{
 		Use:   "register",
 		Short: "Регистрация нового пользователя",
 		Long: `Регистрация нового пользователя на сервере.
 
+Если сервер принимает регистрацию только по приглашениям,
+передайте код приглашения флагом --invite.
+
 Пример:
   gophkeeper register --email test@example.com --password StrongPass123
+  gophkeeper register --email test@example.com --password StrongPass123 --invite <code>
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c := api.NewClient(app.ServerURL)
+			c := app.apiClient()
 			// выполняет добавление нового пользователя в бд
-			_, err := c.Register(email, password)
+			_, err := c.Register(email, password, invite)
 			if err != nil {
 				return err
 			}
@@ -45,6 +50,7 @@ func NewRegisterCmd(app *App) *cobra.Command {
 
 	cmd.Flags().StringVar(&email, "email", "", "email for registration")
 	cmd.Flags().StringVar(&password, "password", "", "password for registration")
+	cmd.Flags().StringVar(&invite, "invite", "", "invite code (if registration is invite-only)")
 	cmd.MarkFlagRequired("email")
 	cmd.MarkFlagRequired("password")
 
@@ -10,13 +10,17 @@
 package cli
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/memory"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
 )
 
 // App содержит состояние CLI-приложения, разделяемое между командами.
@@ -35,6 +39,37 @@ type App struct {
 
 	Secrets     *memory.SecretsStore
 	SecretsPath string
+
+	// ClientCertFile и ClientKeyFile — клиентский сертификат и ключ (PEM) для mTLS.
+	ClientCertFile string
+	ClientKeyFile  string
+	// ClientCert — загруженный сертификат; nil — запросы без клиентского сертификата.
+	ClientCert *tls.Certificate
+}
+
+// apiClient создаёт HTTP-клиент сервера с клиентским сертификатом, если он задан.
+func (a *App) apiClient() *api.Client {
+	c := NewAPIClient(a.ServerURL)
+	if a.ClientCert != nil {
+		c.UseClientCertificate(*a.ClientCert)
+	}
+	return c
+}
+
+// loadClientCert загружает клиентский сертификат из --client-cert/--client-key.
+func (a *App) loadClientCert() error {
+	if a.ClientCertFile == "" && a.ClientKeyFile == "" {
+		return nil
+	}
+	if a.ClientCertFile == "" || a.ClientKeyFile == "" {
+		return errors.New("--client-cert and --client-key must be set together")
+	}
+	cert, err := api.LoadClientCertificate(a.ClientCertFile, a.ClientKeyFile)
+	if err != nil {
+		return err
+	}
+	a.ClientCert = &cert
+	return nil
 }
 
 // NewRootCmd создаёт root-команду CLI и регистрирует подкоманды.
@@ -55,6 +90,16 @@ func NewRootCmd(buildVersion, buildDate string) *cobra.Command {
   register    Регистрация нового пользователя
   login       Логин (получить access/refresh токены)
   refresh     Обновить access токен по refresh токену
+  logout      Выход (отозвать сессию и удалить локальные токены)
+  sessions    Список активных сессий и отзыв устройства
+  2fa         Подключение двухфакторной аутентификации (TOTP)
+  account     Управление аккаунтом (смена пароля, удаление, сброс пароля по почте)
+  token       Персональные API-токены для CI и автоматизации
+  device      Вход по коду устройства (без ввода пароля)
+  cert        Привязка клиентского сертификата (mTLS) к аккаунту
+  invite      Коды приглашений для регистрации (для администраторов)
+  admin       Администрирование сервера: пользователи, блокировка, статистика
+  whoami      Сведения о текущем пользователе
   version     Версия и дата сборки
 
 Команды работы с секретами:
@@ -71,16 +116,108 @@ func NewRootCmd(buildVersion, buildDate string) *cobra.Command {
 
 Регистрация:
   Регистрирует нового пользователя в системе.
+  Если сервер принимает регистрацию только по приглашениям, нужен --invite.
   gophkeeper register --email test@example.com --password StrongPass123
+  gophkeeper register --email test@example.com --password StrongPass123 --invite <code>
 
 Логин:
   Выполняет аутентификацию и сохраняет access/refresh токены в локальном конфиге.
+  При подключённой 2FA запрашивает код из приложения (или --code).
   gophkeeper login --email test@example.com --password StrongPass123
 
 Refresh:
   Обновляет access токен, используя refresh токен из локального конфига.
   gophkeeper refresh
 
+Logout:
+  Отзывает текущую сессию на сервере и удаляет локальные токены.
+  --all  — выйти на всех устройствах, --wipe — удалить локальный кэш секретов.
+  gophkeeper logout --all --wipe
+
+Reauth:
+  Повторно подтверждает пароль (и код 2FA) перед чувствительными операциями:
+  удалением секретов, выпуском API-токенов, привязкой сертификатов,
+  подключением 2FA, подтверждением входа устройства и выпуском приглашений.
+  Права действуют несколько минут.
+  gophkeeper reauth
+
+Sessions:
+  Показывает активные сессии (устройства) и позволяет отозвать потерянное устройство.
+  gophkeeper sessions list
+  gophkeeper sessions revoke <id>
+
+Audit:
+  Журнал аудита: входы, неудачные попытки входа, отзыв сессий,
+  создание, изменение и удаление секретов (от новых к старым).
+  gophkeeper audit --since 24h --type login_failed
+
+2FA:
+  Подключает TOTP: печатает otpauth URI для приложения-аутентификатора,
+  запрашивает код и выводит одноразовые коды восстановления.
+  gophkeeper 2fa enroll
+  gophkeeper 2fa confirm --code 123456
+
+Account:
+  Меняет пароль аккаунта. Пароли вводятся без отображения,
+  все остальные сессии отзываются, новые токены сохраняются локально.
+  gophkeeper account passwd
+  Удаление аккаунта: вход блокируется сразу, данные удаляются после отсрочки,
+  до этого момента аккаунт можно восстановить. Локальные токены и кэш удаляются.
+  gophkeeper account delete
+  gophkeeper account restore --email test@example.com
+  Подтверждение email и сброс забытого пароля по токену из письма (если на сервере
+  настроена почта). После сброса все сессии отзываются. Секреты сброс не восстанавливает:
+  они зашифрованы мастер-паролем, который сервер не знает.
+  gophkeeper account verify-email --token <token>
+  gophkeeper account reset-password --email test@example.com
+  gophkeeper account reset-password --token <token>
+
+Token:
+  Выпускает персональный API-токен со scopes (secrets:read, secrets:write)
+  и, при необходимости, ограничением по типам секретов. Токен печатается один раз.
+  В CI задайте переменную окружения GOPHKEEPER_TOKEN — логин не понадобится.
+  gophkeeper token create --name ci --scope secrets:read --type text --ttl 720h
+  gophkeeper token list
+  gophkeeper token revoke <id>
+
+Device:
+  Вход на машине без браузера (например, по SSH) без ввода пароля:
+  команда печатает код и ждёт, пока его подтвердят с авторизованной машины.
+  gophkeeper device login
+  gophkeeper device approve BCDF-GHJK
+  gophkeeper device deny BCDF-GHJK
+
+Cert:
+  Привязывает клиентский сертификат (например, корпоративного устройства) к аккаунту.
+  После привязки вход и обновление токенов возможны только с этим сертификатом:
+  передавайте --client-cert/--client-key (или GOPHKEEPER_CLIENT_CERT/GOPHKEEPER_CLIENT_KEY).
+  gophkeeper cert bind --client-cert laptop.crt --client-key laptop.key --name laptop
+  gophkeeper cert list
+  gophkeeper cert unbind <id>
+
+Invite:
+  Выпускает коды приглашений, когда сервер принимает регистрацию только по ним.
+  Доступно пользователям из auth.invites.admins и администраторам сервера.
+  Код печатается один раз.
+  gophkeeper invite create --uses 5 --ttl 72h
+  gophkeeper invite list
+  gophkeeper invite revoke <id>
+
+Admin:
+  Для администраторов сервера (users.is_admin): список пользователей,
+  блокировка аккаунтов, отзыв сессий и статистика хранилища.
+  Блокировка, разблокировка и отзыв сессий требуют gophkeeper reauth.
+  gophkeeper admin users --limit 20
+  gophkeeper admin disable <id>
+  gophkeeper admin enable <id>
+  gophkeeper admin revoke-sessions <id>
+  gophkeeper admin stats
+
+Whoami:
+  Показывает ID, email, число секретов, занятое место, активные сессии
+  и срок действия локального access токена.
+  gophkeeper whoami
+
 Version:
   Отображает версию и дату сборки клиента.
   gophkeeper version
@@ -139,11 +276,21 @@ Delete <id>:
 			}
 			app.CredsPath = p
 
-			creds, err := config.Load(app.CredsPath)
-			if err != nil {
+			// клиентский сертификат для mTLS
+			if err := app.loadClientCert(); err != nil {
 				return err
 			}
-			app.Creds = creds
+
+			// API-токен из окружения важнее сохранённых токенов
+			if creds := config.FromEnv(); creds != nil {
+				app.Creds = creds
+			} else {
+				creds, err := config.Load(app.CredsPath)
+				if err != nil {
+					return err
+				}
+				app.Creds = creds
+			}
 
 			app.Secrets = memory.NewSecrets()
 
@@ -166,10 +313,24 @@ Delete <id>:
 	cmd.SetErr(os.Stderr)
 
 	cmd.PersistentFlags().StringVar(&app.ServerURL, "server", "https://127.0.0.1:8080", "server base URL")
+	cmd.PersistentFlags().StringVar(&app.ClientCertFile, "client-cert", os.Getenv(config.ClientCertEnv), "client certificate (PEM) for mTLS")
+	cmd.PersistentFlags().StringVar(&app.ClientKeyFile, "client-key", os.Getenv(config.ClientKeyEnv), "client certificate private key (PEM) for mTLS")
 
 	cmd.AddCommand(NewRegisterCmd(app))
 	cmd.AddCommand(NewLoginCmd(app))
 	cmd.AddCommand(NewRefreshCmd(app))
+	cmd.AddCommand(NewLogoutCmd(app))
+	cmd.AddCommand(NewReauthCmd(app))
+	cmd.AddCommand(NewSessionsCmd(app))
+	cmd.AddCommand(NewAuditCmd(app))
+	cmd.AddCommand(NewTwoFactorCmd(app))
+	cmd.AddCommand(NewAccountCmd(app))
+	cmd.AddCommand(NewTokenCmd(app))
+	cmd.AddCommand(NewDeviceCmd(app))
+	cmd.AddCommand(NewCertCmd(app))
+	cmd.AddCommand(NewInviteCmd(app))
+	cmd.AddCommand(NewAdminCmd(app))
+	cmd.AddCommand(NewWhoamiCmd(app))
 	cmd.AddCommand(NewVersionCmd(buildVersion, buildDate))
 
 	cmd.AddCommand(SecretSync(app))
@@ -185,9 +346,13 @@ Delete <id>:
 //
 // При ошибке выполнения команды сообщение выводится в stderr, после чего процесс
 // завершается с кодом 1 (os.Exit(1)).
+// Если сервер требует повторного ввода пароля, печатается подсказка про reauth.
 func Execute(buildVersion, buildDate string) {
 	if err := NewRootCmd(buildVersion, buildDate).Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
+		if errors.Is(err, serr.ErrReauthRequired) {
+			fmt.Fprintln(os.Stderr, "run: gophkeeper reauth, then repeat the command")
+		}
 		os.Exit(1)
 	}
 }
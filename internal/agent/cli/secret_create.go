@@ -99,7 +99,7 @@ Master password не передаётся флагом (чтобы не утек
 				metaPtr = &meta
 			}
 
-			c := NewAPIClient(app.ServerURL)
+			c := app.apiClient()
 
 			created, err := c.CreateSecret(app.Creds.AccessToken, sharedModels.CreateSecretRequest{
 				Type:    typ,
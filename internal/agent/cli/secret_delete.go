@@ -56,7 +56,7 @@ func SecretDelete(app *App) *cobra.Command {
 				return fmt.Errorf("secret %s not found locally (run: gophkeeper sync): %w", id, err)
 			}
 
-			c := NewAPIClient(app.ServerURL)
+			c := app.apiClient()
 			if err := c.DeleteSecret(app.Creds.AccessToken, id, sec.Version); err != nil {
 				return err
 			}
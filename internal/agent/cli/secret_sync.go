@@ -58,7 +58,7 @@ func SecretSync(app *App) *cobra.Command {
 				return fmt.Errorf("no access_token, run: gophkeeper login")
 			}
 
-			c := NewAPIClient(app.ServerURL)
+			c := app.apiClient()
 			result, err := c.Sync(app.Creds.AccessToken)
 			if err != nil {
 				return err
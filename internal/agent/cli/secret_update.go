@@ -113,7 +113,7 @@ Optimistic locking:
 			}
 
 			// Запрос на сервер
-			c := NewAPIClient(app.ServerURL)
+			c := app.apiClient()
 			if _, err := c.UpdateSecret(app.Creds.AccessToken, id, models.UpdateSecretRequest{
 				Type:    typePtr,
 				Title:   titlePtr,
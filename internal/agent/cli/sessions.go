@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// NewSessionsCmd создаёт группу CLI-команд управления сессиями (устройствами).
+//
+// Подкоманды:
+//   - list — показать активные сессии (ID, устройство, IP, даты);
+//   - revoke <id> — отозвать сессию, например потерянного ноутбука.
+//
+// Пример использования:
+//
+//	gophkeeper sessions list
+//	gophkeeper sessions revoke 7a0a4a6a-a7bf-42c0-8cdf-2be8583d180e
+func NewSessionsCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "Управление сессиями (устройствами)",
+		Long: `Управление активными сессиями пользователя.
+
+Примеры:
+  gophkeeper sessions list
+  gophkeeper sessions revoke <uuid>
+`,
+	}
+
+	cmd.AddCommand(newSessionsListCmd(app))
+	cmd.AddCommand(newSessionsRevokeCmd(app))
+
+	return cmd
+}
+
+// newSessionsListCmd печатает активные сессии пользователя.
+func newSessionsListCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "Показать активные сессии",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			c := app.apiClient()
+			resp, err := c.ListSessions(app.Creds.AccessToken)
+			if err != nil {
+				return err
+			}
+
+			if len(resp.Sessions) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "no active sessions")
+				return nil
+			}
+
+			for _, s := range resp.Sessions {
+				device := s.DeviceName
+				if device == "" {
+					device = "unknown"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(),
+					"%s\t%s\t%s\tcreated=%s\texpires=%s\n",
+					s.ID, device, s.IP,
+					s.CreatedAt.Format("2006-01-02 15:04:05"),
+					s.ExpiresAt.Format("2006-01-02 15:04:05"),
+				)
+			}
+			return nil
+		},
+	}
+}
+
+// newSessionsRevokeCmd отзывает сессию по ID.
+func newSessionsRevokeCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:          "revoke <id>",
+		Short:        "Отозвать сессию по ID",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			c := app.apiClient()
+			if err := c.RevokeSession(app.Creds.AccessToken, args[0]); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "session %s revoked\n", args[0])
+			return nil
+		},
+	}
+}
@@ -0,0 +1,356 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/cli"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/memory"
+)
+
+func TestNewAccountCmd_Passwd_FromStdin_SavesNewTokens(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/password", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			t.Fatalf("unexpected auth header: %q", r.Header.Get("Authorization"))
+		}
+		var req api.ChangePasswordRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.OldPassword != "OldPass123" || req.NewPassword != "NewPass123" {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token":  "access-2",
+			"refresh_token": "refresh-2",
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	credsPath := filepath.Join(t.TempDir(), "creds.json")
+	app := &cli.App{
+		ServerURL: srv.URL,
+		CredsPath: credsPath,
+		Creds:     &config.Credentials{AccessToken: "access-1", RefreshToken: "refresh-1"},
+	}
+
+	cmd := cli.NewAccountCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetIn(strings.NewReader("OldPass123\nNewPass123\n"))
+	cmd.SetArgs([]string{"passwd", "--password-stdin"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "password changed") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	loaded, err := config.Load(credsPath)
+	if err != nil {
+		t.Fatalf("load creds: %v", err)
+	}
+	if loaded.AccessToken != "access-2" || loaded.RefreshToken != "refresh-2" {
+		t.Fatalf("unexpected creds: %+v", loaded)
+	}
+}
+
+func TestNewAccountCmd_Passwd_StdinMissingNewPassword(t *testing.T) {
+	app := &cli.App{ServerURL: "https://127.0.0.1:1", Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewAccountCmd(app)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetIn(strings.NewReader("OldPass123\n"))
+	cmd.SetArgs([]string{"passwd", "--password-stdin"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "old and new password") {
+		t.Fatalf("expected stdin format error, got %v", err)
+	}
+}
+
+func TestNewAccountCmd_Passwd_NoToken(t *testing.T) {
+	app := &cli.App{ServerURL: "https://127.0.0.1:1", Creds: &config.Credentials{}}
+
+	cmd := cli.NewAccountCmd(app)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"passwd"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "no access_token") {
+		t.Fatalf("expected no access_token error, got %v", err)
+	}
+}
+
+func TestNewAccountCmd_Delete_WipesLocalState(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Fatalf("expected DELETE, got %s", r.Method)
+		}
+		var req api.DeleteMeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Password != "StrongPass123" {
+			t.Fatalf("unexpected password %q", req.Password)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(api.DeleteMeResponse{DeleteAfter: time.Now().Add(7 * 24 * time.Hour)})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	tmpDir := t.TempDir()
+	app := &cli.App{
+		ServerURL:   srv.URL,
+		CredsPath:   filepath.Join(tmpDir, "creds.json"),
+		Creds:       &config.Credentials{AccessToken: "access-1", RefreshToken: "refresh-1"},
+		Secrets:     memory.NewSecrets(),
+		SecretsPath: filepath.Join(tmpDir, "secrets.json"),
+	}
+	app.Secrets.ReplaceAll([]memory.Secret{{ID: "s1", Title: "t"}})
+	if err := memory.SaveToFile(app.SecretsPath, app.Secrets); err != nil {
+		t.Fatalf("save secrets: %v", err)
+	}
+
+	cmd := cli.NewAccountCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetIn(strings.NewReader("StrongPass123\n"))
+	cmd.SetArgs([]string{"delete", "--yes", "--password-stdin"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "account scheduled for deletion") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	loaded, err := config.Load(app.CredsPath)
+	if err != nil {
+		t.Fatalf("load creds: %v", err)
+	}
+	if loaded.AccessToken != "" || loaded.RefreshToken != "" {
+		t.Fatalf("expected creds wiped, got %+v", loaded)
+	}
+	if _, err := os.Stat(app.SecretsPath); !os.IsNotExist(err) {
+		t.Fatalf("expected secrets file removed, stat err=%v", err)
+	}
+	if len(app.Secrets.List()) != 0 {
+		t.Fatalf("expected in-memory secrets wiped")
+	}
+}
+
+func TestNewAccountCmd_Delete_NotConfirmed(t *testing.T) {
+	// сервер не должен вызываться
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewAccountCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetIn(strings.NewReader("no\n"))
+	cmd.SetArgs([]string{"delete"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "aborted") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+	if app.Creds.AccessToken != "access-1" {
+		t.Fatalf("expected creds untouched")
+	}
+}
+
+func TestNewAccountCmd_Delete_PasswordStdinRequiresYes(t *testing.T) {
+	app := &cli.App{ServerURL: "https://127.0.0.1:1", Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewAccountCmd(app)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"delete", "--password-stdin"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--yes") {
+		t.Fatalf("expected --yes error, got %v", err)
+	}
+}
+
+func TestNewAccountCmd_Restore(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/restore", func(w http.ResponseWriter, r *http.Request) {
+		var req api.RestoreAccountRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Email != "test@example.com" || req.Password != "StrongPass123" {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, Creds: &config.Credentials{}}
+
+	cmd := cli.NewAccountCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetIn(strings.NewReader("StrongPass123\n"))
+	cmd.SetArgs([]string{"restore", "--email", "test@example.com", "--password-stdin"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "account restored") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestNewAccountCmd_VerifyEmail(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/email/verify", func(w http.ResponseWriter, r *http.Request) {
+		var req api.EmailTokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Token != "verify-token" {
+			t.Fatalf("unexpected token: %q", req.Token)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	cmd := cli.NewAccountCmd(&cli.App{ServerURL: srv.URL})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"verify-email", "--token", "verify-token"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "email verified") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestNewAccountCmd_VerifyEmail_ResendRequiresEmail(t *testing.T) {
+	cmd := cli.NewAccountCmd(&cli.App{ServerURL: "https://127.0.0.1:1"})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"verify-email", "--resend"})
+
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--email") {
+		t.Fatalf("expected --email error, got %v", err)
+	}
+}
+
+func TestNewAccountCmd_ResetPassword_Request(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/password/reset/request", func(w http.ResponseWriter, r *http.Request) {
+		var req api.EmailRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Email != "test@example.com" {
+			t.Fatalf("unexpected email: %q", req.Email)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	cmd := cli.NewAccountCmd(&cli.App{ServerURL: srv.URL})
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"reset-password", "--email", "test@example.com"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "reset-password --token") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+// После сброса сессии отозваны — локальные токены удаляются, секреты остаются
+func TestNewAccountCmd_ResetPassword_WithToken_ClearsTokens(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/password/reset", func(w http.ResponseWriter, r *http.Request) {
+		var req api.ResetPasswordRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Token != "reset-token" || req.NewPassword != "NewPass123" {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	credsPath := filepath.Join(t.TempDir(), "creds.json")
+	app := &cli.App{
+		ServerURL: srv.URL,
+		CredsPath: credsPath,
+		Creds:     &config.Credentials{AccessToken: "access-1", RefreshToken: "refresh-1"},
+	}
+
+	cmd := cli.NewAccountCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetIn(strings.NewReader("NewPass123\n"))
+	cmd.SetArgs([]string{"reset-password", "--token", "reset-token", "--password-stdin"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "does not recover") {
+		t.Fatalf("expected master password note, got %q", out.String())
+	}
+
+	saved, err := config.Load(credsPath)
+	if err != nil {
+		t.Fatalf("load creds: %v", err)
+	}
+	if saved.AccessToken != "" || saved.RefreshToken != "" {
+		t.Fatalf("expected tokens cleared, got %+v", saved)
+	}
+}
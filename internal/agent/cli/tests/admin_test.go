@@ -0,0 +1,113 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/cli"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+func TestNewAdminCmd_Users(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/admin/users", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") != "20" {
+			t.Fatalf("unexpected query: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ListAdminUsersResponse{
+			Users: []api.AdminUser{
+				{ID: "id-1", Email: "admin@example.com", IsAdmin: true, SecretCount: 2, CreatedAt: time.Now()},
+				{ID: "id-2", Email: "user@example.com", Disabled: true, CreatedAt: time.Now()},
+			},
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewAdminCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"users", "--limit", "20"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	for _, want := range []string{"admin@example.com\tactive,admin\tsecrets=2", "user@example.com\tdisabled", "last_login=never"} {
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("output %q does not contain %q", out.String(), want)
+		}
+	}
+}
+
+func TestNewAdminCmd_DisableAndStats(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/admin/users/id-2/disable", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Fatalf("expected POST, got %s", r.Method)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/admin/stats", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.AdminStatsResponse{Users: 2, DisabledUsers: 1, Secrets: 5, SecretBytes: 1024})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewAdminCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"disable", "id-2"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "user id-2: disabled") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	cmd = cli.NewAdminCmd(app)
+	out.Reset()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"stats"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "disabled_users=1") || !strings.Contains(out.String(), "secret_bytes=1024") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestNewAdminCmd_NoAccessToken(t *testing.T) {
+	app := &cli.App{ServerURL: "https://127.0.0.1:8080", Creds: &config.Credentials{}}
+
+	cmd := cli.NewAdminCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"stats"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatalf("%s, got nil", serr.ErrExpectedError.Error())
+	}
+	if !strings.Contains(err.Error(), "gophkeeper login") {
+		t.Fatalf("%s: %v", serr.ErrUnexpectedError.Error(), err)
+	}
+}
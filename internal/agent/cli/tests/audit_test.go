@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/cli"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+)
+
+func TestNewAuditCmd_PrintsEvents(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/audit", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			t.Fatalf("unexpected auth header: %q", r.Header.Get("Authorization"))
+		}
+		if r.URL.Query().Get("type") != "secret_deleted" || r.URL.Query().Get("since") == "" {
+			t.Fatalf("unexpected query: %q", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ListAuditResponse{
+			Events: []api.AuditEvent{{
+				ID:        7,
+				Type:      "secret_deleted",
+				IP:        "10.0.0.1",
+				SecretID:  "sec-1",
+				CreatedAt: time.Now(),
+			}},
+			NextCursor: 7,
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewAuditCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--since", "24h", "--type", "secret_deleted"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "secret_deleted\t10.0.0.1\t-\tsecret=sec-1") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+	if !strings.Contains(out.String(), "--cursor 7") {
+		t.Fatalf("expected next page hint, got %q", out.String())
+	}
+}
+
+func TestNewAuditCmd_InvalidSince(t *testing.T) {
+	app := &cli.App{ServerURL: "https://127.0.0.1:1", Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewAuditCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--since", "yesterday"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected error for invalid --since")
+	}
+}
+
+func TestNewAuditCmd_NoToken(t *testing.T) {
+	app := &cli.App{ServerURL: "https://127.0.0.1:1", Creds: &config.Credentials{}}
+
+	cmd := cli.NewAuditCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected error without access token")
+	}
+}
@@ -0,0 +1,127 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/cli"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+)
+
+// bind отправляет файл из --client-cert
+func TestNewCertCmd_Bind_UsesClientCert(t *testing.T) {
+	certFile := filepath.Join(t.TempDir(), "laptop.crt")
+	if err := os.WriteFile(certFile, []byte("-----BEGIN CERTIFICATE-----\n"), 0o600); err != nil {
+		t.Fatalf("write cert: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/certs", func(w http.ResponseWriter, r *http.Request) {
+		var req api.BindClientCertRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Certificate != "-----BEGIN CERTIFICATE-----\n" || req.Name != "laptop" {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(api.ClientCert{ID: "id-1", Name: "laptop", Fingerprint: "abcd"})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{
+		ServerURL:      srv.URL,
+		Creds:          &config.Credentials{AccessToken: "access-1"},
+		ClientCertFile: certFile,
+		ClientKeyFile:  filepath.Join(filepath.Dir(certFile), "laptop.key"),
+	}
+
+	cmd := cli.NewCertCmd(app)
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"bind", "--name", "laptop"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "id-1") || !strings.Contains(out.String(), "abcd") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), config.ClientCertEnv) {
+		t.Fatalf("expected hint about %s, got %q", config.ClientCertEnv, errOut.String())
+	}
+}
+
+func TestNewCertCmd_Bind_NoCertificate(t *testing.T) {
+	app := &cli.App{ServerURL: "https://127.0.0.1:1", Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewCertCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"bind"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("expected error without certificate")
+	}
+}
+
+// без ключа запрос не пройдёт mTLS, и сервер откажет в привязке
+func TestNewCertCmd_Bind_NoKey(t *testing.T) {
+	app := &cli.App{
+		ServerURL:      "https://127.0.0.1:1",
+		Creds:          &config.Credentials{AccessToken: "access-1"},
+		ClientCertFile: "laptop.crt",
+	}
+
+	cmd := cli.NewCertCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"bind"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--client-key") {
+		t.Fatalf("expected --client-key error, got %v", err)
+	}
+}
+
+func TestNewCertCmd_List(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/certs", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ListClientCertsResponse{
+			Certificates: []api.ClientCert{{ID: "id-1", Name: "laptop", Subject: "CN=laptop", NotAfter: time.Now()}},
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewCertCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"list"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "id-1\tlaptop\tCN=laptop") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
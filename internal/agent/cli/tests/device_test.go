@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/cli"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+)
+
+// опрос продолжается, пока код не подтвердят; токены сохраняются
+func TestNewDeviceCmd_Login_PollsUntilApproved(t *testing.T) {
+	var polls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/device/code", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.DeviceCodeResponse{DeviceCode: "dc", UserCode: "BCDF-GHJK", ExpiresIn: 60, Interval: 1})
+	})
+	mux.HandleFunc("/auth/device/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if polls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "authorization_pending"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token":  "access-1",
+			"refresh_token": "refresh-1",
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	credsPath := filepath.Join(t.TempDir(), "creds.json")
+	app := &cli.App{ServerURL: srv.URL, CredsPath: credsPath, Creds: &config.Credentials{}}
+
+	cmd := cli.NewDeviceCmd(app)
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"login"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(errOut.String(), "gophkeeper device approve BCDF-GHJK") {
+		t.Fatalf("expected approve hint, got %q", errOut.String())
+	}
+	if !strings.Contains(out.String(), "login ok") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+	if polls.Load() != 2 {
+		t.Fatalf("expected 2 polls, got %d", polls.Load())
+	}
+
+	loaded, err := config.Load(credsPath)
+	if err != nil {
+		t.Fatalf("load creds: %v", err)
+	}
+	if loaded.AccessToken != "access-1" || loaded.RefreshToken != "refresh-1" {
+		t.Fatalf("unexpected creds: %+v", loaded)
+	}
+}
+
+// отказ на другом устройстве прерывает опрос
+func TestNewDeviceCmd_Login_Denied(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/device/code", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.DeviceCodeResponse{DeviceCode: "dc", UserCode: "BCDF-GHJK", ExpiresIn: 60, Interval: 1})
+	})
+	mux.HandleFunc("/auth/device/token", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "access_denied"})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, CredsPath: filepath.Join(t.TempDir(), "creds.json"), Creds: &config.Credentials{}}
+
+	cmd := cli.NewDeviceCmd(app)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"login"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Fatalf("expected denied error, got %v", err)
+	}
+}
+
+func TestNewDeviceCmd_Approve(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/device/approve", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			t.Fatalf("unexpected auth header: %q", r.Header.Get("Authorization"))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewDeviceCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"approve", "BCDF-GHJK"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "device BCDF-GHJK approved") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestNewDeviceCmd_Deny_NoAccessToken(t *testing.T) {
+	app := &cli.App{ServerURL: "https://127.0.0.1:1", Creds: &config.Credentials{}}
+
+	cmd := cli.NewDeviceCmd(app)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"deny", "BCDF-GHJK"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "no access_token") {
+		t.Fatalf("expected no access_token error, got %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/cli"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+func TestNewInviteCmd_Create_PrintsCode(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/invites", func(w http.ResponseWriter, r *http.Request) {
+		var req api.CreateInviteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.MaxUses != 5 || req.ExpiresIn != 3*3600 {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(api.CreateInviteResponse{
+			Code:   "invite-code",
+			Invite: api.Invite{ID: "id-1", MaxUses: 5, ExpiresAt: time.Now().Add(3 * time.Hour)},
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewInviteCmd(app)
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"create", "--uses", "5", "--ttl", "3h"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	// в stdout только код
+	if out.String() != "invite-code\n" {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestNewInviteCmd_ListAndRevoke(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/invites", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ListInvitesResponse{
+			Invites: []api.Invite{{ID: "id-1", MaxUses: 5, Uses: 2}},
+		})
+	})
+	mux.HandleFunc("/auth/invites/id-1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Fatalf("expected DELETE, got %s", r.Method)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewInviteCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"list"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "uses=2/5") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	cmd = cli.NewInviteCmd(app)
+	out.Reset()
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"revoke", "id-1"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "invite id-1 revoked") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestNewInviteCmd_NoAccessToken(t *testing.T) {
+	app := &cli.App{ServerURL: "https://127.0.0.1:8080", Creds: &config.Credentials{}}
+
+	cmd := cli.NewInviteCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"create"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatalf("%s, got nil", serr.ErrExpectedError.Error())
+	}
+	if !strings.Contains(err.Error(), "gophkeeper login") {
+		t.Fatalf("%s: %v", serr.ErrUnexpectedError.Error(), err)
+	}
+}
@@ -155,3 +155,65 @@ func TestNewLoginCmd_ServerReturnsError_DoesNotWriteCredsFile(t *testing.T) {
 		t.Fatalf("creds file should not be created on login error")
 	}
 }
+
+func TestNewLoginCmd_IDToken_UsesOIDCEndpoint(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/oidc", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			IDToken string `json:"id_token"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.IDToken != "id-token-1" {
+			t.Fatalf("expected id-token-1, got %q", req.IDToken)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token":  "access-1",
+			"refresh_token": "refresh-1",
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	credsPath := filepath.Join(t.TempDir(), "creds.json")
+	app := &cli.App{
+		ServerURL: srv.URL,
+		CredsPath: credsPath,
+		Creds:     &config.Credentials{},
+	}
+
+	cmd := cli.NewLoginCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--id-token", "id-token-1"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if app.Creds.AccessToken != "access-1" {
+		t.Fatalf("expected AccessToken=access-1, got %q", app.Creds.AccessToken)
+	}
+}
+
+func TestNewLoginCmd_IDTokenWithPassword_ReturnsError(t *testing.T) {
+	app := &cli.App{
+		ServerURL: "https://127.0.0.1:8080",
+		CredsPath: filepath.Join(t.TempDir(), "creds.json"),
+		Creds:     &config.Credentials{},
+	}
+
+	cmd := cli.NewLoginCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--id-token", "id-token-1", "--email", "test@example.com"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("%s, got nil", serr.ErrExpectedError.Error())
+	}
+}
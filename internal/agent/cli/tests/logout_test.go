@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/cli"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/memory"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+func newLogoutApp(t *testing.T, serverURL string) *cli.App {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	app := &cli.App{
+		ServerURL:   serverURL,
+		CredsPath:   filepath.Join(tmpDir, "creds.json"),
+		Creds:       &config.Credentials{AccessToken: "access-1", RefreshToken: "refresh-1"},
+		Secrets:     memory.NewSecrets(),
+		SecretsPath: filepath.Join(tmpDir, "secrets.json"),
+	}
+	if err := config.Save(app.CredsPath, app.Creds); err != nil {
+		t.Fatalf("save creds: %v", err)
+	}
+	return app
+}
+
+func TestNewLogoutCmd_AllAndWipe_ClearsCredsAndSecrets(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/logout", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			RefreshToken string `json:"refresh_token"`
+			All          bool   `json:"all"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.RefreshToken != "refresh-1" || !req.All {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := newLogoutApp(t, srv.URL)
+	app.Secrets.ReplaceAll([]memory.Secret{{ID: "s1", Title: "t"}})
+	if err := memory.SaveToFile(app.SecretsPath, app.Secrets); err != nil {
+		t.Fatalf("save secrets: %v", err)
+	}
+
+	cmd := cli.NewLogoutCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"--all", "--wipe"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "logout ok") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	loaded, err := config.Load(app.CredsPath)
+	if err != nil {
+		t.Fatalf("load creds: %v", err)
+	}
+	if loaded.AccessToken != "" || loaded.RefreshToken != "" {
+		t.Fatalf("expected empty creds, got %+v", loaded)
+	}
+	if _, err := os.Stat(app.SecretsPath); !os.IsNotExist(err) {
+		t.Fatalf("expected secrets file removed, stat err=%v", err)
+	}
+	if len(app.Secrets.List()) != 0 {
+		t.Fatalf("expected in-memory secrets wiped")
+	}
+}
+
+func TestNewLogoutCmd_ServerError_StillClearsLocalCreds(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/logout", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("unauthorized"))
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := newLogoutApp(t, srv.URL)
+
+	cmd := cli.NewLogoutCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "warning") {
+		t.Fatalf("expected warning in output, got %q", out.String())
+	}
+
+	loaded, _ := config.Load(app.CredsPath)
+	if loaded.RefreshToken != "" {
+		t.Fatalf("expected refresh token cleared")
+	}
+}
+
+func TestNewLogoutCmd_All_ServerError_ReturnsError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/logout", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("unauthorized"))
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := newLogoutApp(t, srv.URL)
+
+	cmd := cli.NewLogoutCmd(app)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"--all"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("%s, got nil", serr.ErrExpectedError.Error())
+	}
+
+	loaded, _ := config.Load(app.CredsPath)
+	if loaded.RefreshToken != "refresh-1" {
+		t.Fatalf("expected creds untouched on failed --all")
+	}
+}
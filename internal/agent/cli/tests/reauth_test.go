@@ -0,0 +1,118 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/cli"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+func TestNewReauthCmd_Success_SavesElevatedToken(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/reauth", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer access-old" {
+			t.Fatalf("unexpected Authorization: %q", got)
+		}
+		var req struct {
+			Password string `json:"password"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Password != "StrongPass123" {
+			t.Fatalf("expected password StrongPass123, got %q", req.Password)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token":   "access-elevated",
+			"elevated_until": time.Now().Add(5 * time.Minute),
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	credsPath := filepath.Join(t.TempDir(), "creds.json")
+	app := &cli.App{
+		ServerURL: srv.URL,
+		CredsPath: credsPath,
+		Creds:     &config.Credentials{AccessToken: "access-old", RefreshToken: "refresh-old"},
+	}
+
+	cmd := cli.NewReauthCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetIn(strings.NewReader("StrongPass123\n"))
+	cmd.SetArgs([]string{"--password-stdin"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "reauthenticated until") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	saved, err := config.Load(credsPath)
+	if err != nil {
+		t.Fatalf("load creds: %v", err)
+	}
+	if saved.AccessToken != "access-elevated" || saved.RefreshToken != "refresh-old" {
+		t.Fatalf("unexpected saved creds: %+v", saved)
+	}
+}
+
+// при подключённой 2FA и пароле из stdin код передаётся флагом
+func TestNewReauthCmd_MFARequired_AsksForCode(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/reauth", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		json.NewEncoder(w).Encode(map[string]string{"error": serr.ErrMFARequired.Error()})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{
+		ServerURL: srv.URL,
+		CredsPath: filepath.Join(t.TempDir(), "creds.json"),
+		Creds:     &config.Credentials{AccessToken: "access-old"},
+	}
+
+	cmd := cli.NewReauthCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetIn(strings.NewReader("StrongPass123\n"))
+	cmd.SetArgs([]string{"--password-stdin"})
+
+	err := cmd.Execute()
+	if err == nil {
+		t.Fatalf("%s, got nil", serr.ErrExpectedError.Error())
+	}
+	if !strings.Contains(err.Error(), "--code") {
+		t.Fatalf("%s: %v", serr.ErrUnexpectedError.Error(), err)
+	}
+}
+
+func TestNewReauthCmd_NoAccessToken_ReturnsError(t *testing.T) {
+	app := &cli.App{ServerURL: "https://127.0.0.1:8080", Creds: &config.Credentials{}}
+
+	cmd := cli.NewReauthCmd(app)
+	cmd.SetArgs([]string{"--password-stdin"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "no access_token") {
+		t.Fatalf("%s: %v", serr.ErrUnexpectedError.Error(), err)
+	}
+}
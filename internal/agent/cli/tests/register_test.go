@@ -123,3 +123,40 @@ func TestNewRegisterCmd_ServerReturnsError_ReturnsError(t *testing.T) {
 		t.Fatalf("%s: %v", serr.ErrUnexpectedError.Error(), err)
 	}
 }
+
+func TestNewRegisterCmd_Invite_SendsCode(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/register", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Invite string `json:"invite"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Invite != "invite-code" {
+			t.Fatalf("expected invite invite-code, got %q", req.Invite)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"user_id": "u1"})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, Creds: &config.Credentials{}}
+
+	cmd := cli.NewRegisterCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{
+		"--email", "test@example.com",
+		"--password", "StrongPass123",
+		"--invite", "invite-code",
+	})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+}
@@ -19,7 +19,7 @@ func TestNewRootCmd_HasExpectedSubcommands(t *testing.T) {
 		names[c.Name()] = true
 	}
 
-	want := []string{"register", "login", "refresh", "version"}
+	want := []string{"register", "login", "refresh", "logout", "reauth", "sessions", "audit", "2fa", "account", "token", "device", "cert", "invite", "admin", "whoami", "version"}
 	for _, w := range want {
 		if !names[w] {
 			t.Fatalf("expected subcommand %q to exist", w)
@@ -96,3 +96,18 @@ func TestNewRootCmd_PersistentPreRunE_ReturnsErrorOnBadCredsFile(t *testing.T) {
 		t.Fatalf("expected error, got nil")
 	}
 }
+
+// сертификат без ключа (и наоборот) — ошибка до выполнения команды
+func TestNewRootCmd_ClientCertWithoutKey(t *testing.T) {
+	root := cli.NewRootCmd("1.0.0", "2026-01-16")
+
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs([]string{"version", "--client-cert", "client.crt", "--client-key", ""})
+
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--client-key") {
+		t.Fatalf("expected client key error, got %v", err)
+	}
+}
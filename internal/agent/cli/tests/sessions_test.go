@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/cli"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+)
+
+func TestNewSessionsCmd_List_PrintsSessions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/sessions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			t.Fatalf("unexpected auth header: %q", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ListSessionsResponse{Sessions: []api.Session{{
+			ID:         "s1",
+			DeviceName: "laptop",
+			IP:         "10.0.0.1",
+			CreatedAt:  time.Now(),
+			ExpiresAt:  time.Now().Add(time.Hour),
+		}}})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewSessionsCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"list"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(out.String(), "s1\tlaptop\t10.0.0.1") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestNewSessionsCmd_Revoke(t *testing.T) {
+	called := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/sessions/s1", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Fatalf("expected DELETE, got %s", r.Method)
+		}
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewSessionsCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"revoke", "s1"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !called {
+		t.Fatalf("expected server to be called")
+	}
+	if !strings.Contains(out.String(), "session s1 revoked") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+}
+
+func TestNewSessionsCmd_NoToken(t *testing.T) {
+	app := &cli.App{ServerURL: "https://127.0.0.1:1", Creds: &config.Credentials{}}
+
+	cmd := cli.NewSessionsCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"list"})
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatalf("expected error without access token")
+	}
+}
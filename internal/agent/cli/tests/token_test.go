@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/cli"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+)
+
+func TestNewTokenCmd_Create_PrintsToken(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		var req api.CreateAPITokenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Name != "ci" || strings.Join(req.Scopes, ",") != "secrets:read,secrets:write" ||
+			strings.Join(req.Types, ",") != "text" || req.ExpiresIn != 7200 {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(api.CreateAPITokenResponse{
+			Token:    "gkp_secret",
+			APIToken: api.APIToken{ID: "id-1", ExpiresAt: time.Now().Add(2 * time.Hour)},
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewTokenCmd(app)
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetArgs([]string{"create", "--name", "ci", "--scope", "secrets:read,secrets:write", "--type", "text", "--ttl", "2h"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	// в stdout только токен — удобно для $(gophkeeper token create ...)
+	if out.String() != "gkp_secret\n" {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), config.TokenEnv) {
+		t.Fatalf("expected hint about %s, got %q", config.TokenEnv, errOut.String())
+	}
+}
+
+func TestNewTokenCmd_List(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/tokens", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ListAPITokensResponse{
+			Tokens: []api.APIToken{{ID: "id-1", Name: "ci", Scopes: []string{"secrets:read"}}},
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewTokenCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetArgs([]string{"list"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	got := out.String()
+	if !strings.Contains(got, "id-1\tci\tscopes=secrets:read\ttypes=all") || !strings.Contains(got, "last_used=never") {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+func TestNewTokenCmd_Revoke_NoAccessToken(t *testing.T) {
+	app := &cli.App{ServerURL: "https://127.0.0.1:1", Creds: &config.Credentials{}}
+
+	cmd := cli.NewTokenCmd(app)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"revoke", "id-1"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "no access_token") {
+		t.Fatalf("expected no access_token error, got %v", err)
+	}
+}
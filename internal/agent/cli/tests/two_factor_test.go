@@ -0,0 +1,141 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/cli"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+)
+
+func TestNewLoginCmd_MFA_ReadsCodeFromStdin(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/login", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]any{
+			"mfa_required": true,
+			"mfa_token":    "mfa-1",
+			"expires_at":   time.Now().Add(5 * time.Minute),
+		})
+	})
+	mux.HandleFunc("/auth/2fa/verify", func(w http.ResponseWriter, r *http.Request) {
+		var req api.VerifyMFARequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.MFAToken != "mfa-1" || req.Code != "123456" {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"access_token":  "access-1",
+			"refresh_token": "refresh-1",
+		})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	credsPath := filepath.Join(t.TempDir(), "creds.json")
+	app := &cli.App{ServerURL: srv.URL, CredsPath: credsPath, Creds: &config.Credentials{}}
+
+	cmd := cli.NewLoginCmd(app)
+	var out, errOut bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&errOut)
+	cmd.SetIn(strings.NewReader("123456\n"))
+	cmd.SetArgs([]string{"--email", "test@example.com", "--password", "StrongPass123"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if !strings.Contains(errOut.String(), "Two-factor code") {
+		t.Fatalf("expected prompt, got %q", errOut.String())
+	}
+	if !strings.Contains(out.String(), "login ok (tokens saved)") {
+		t.Fatalf("unexpected output: %q", out.String())
+	}
+
+	loaded, err := config.Load(credsPath)
+	if err != nil {
+		t.Fatalf("load creds: %v", err)
+	}
+	if loaded.AccessToken != "access-1" || loaded.RefreshToken != "refresh-1" {
+		t.Fatalf("unexpected creds: %+v", loaded)
+	}
+}
+
+func TestNewTwoFactorCmd_Enroll_ConfirmsAndPrintsRecoveryCodes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/2fa/enroll", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-1" {
+			t.Fatalf("unexpected auth header: %q", r.Header.Get("Authorization"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.EnrollTOTPResponse{
+			Secret:     "SECRET",
+			OTPAuthURI: "otpauth://totp/GophKeeper:test@example.com?secret=SECRET",
+		})
+	})
+	mux.HandleFunc("/auth/2fa/confirm", func(w http.ResponseWriter, r *http.Request) {
+		var req api.ConfirmTOTPRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		if req.Code != "654321" {
+			t.Fatalf("unexpected code %q", req.Code)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(api.ConfirmTOTPResponse{RecoveryCodes: []string{"aaaaa-bbbbb", "ccccc-ddddd"}})
+	})
+
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	app := &cli.App{ServerURL: srv.URL, Creds: &config.Credentials{AccessToken: "access-1"}}
+
+	cmd := cli.NewTwoFactorCmd(app)
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&out)
+	cmd.SetIn(strings.NewReader("654321\n"))
+	cmd.SetArgs([]string{"enroll"})
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"otpauth://totp/GophKeeper:test@example.com",
+		"two-factor authentication enabled",
+		"aaaaa-bbbbb",
+		"ccccc-ddddd",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("expected %q in output, got %q", want, got)
+		}
+	}
+}
+
+func TestNewTwoFactorCmd_Confirm_NoToken(t *testing.T) {
+	app := &cli.App{ServerURL: "https://127.0.0.1:1", Creds: &config.Credentials{}}
+
+	cmd := cli.NewTwoFactorCmd(app)
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"confirm", "--code", "123456"})
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "no access_token") {
+		t.Fatalf("expected no access_token error, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/agent/config"
+)
+
+// NewTokenCmd создаёт группу CLI-команд персональных API-токенов.
+//
+// Подкоманды:
+//   - create — выпустить токен со scopes (secrets:read, secrets:write) и типами секретов;
+//   - list — показать действующие токены;
+//   - revoke <id> — отозвать токен.
+//
+// Токен печатается один раз. В CI его передают через переменную окружения
+// GOPHKEEPER_TOKEN — тогда логин не нужен.
+//
+// Пример использования:
+//
+//	gophkeeper token create --name ci --scope secrets:read --type text --ttl 720h
+//	gophkeeper token list
+//	gophkeeper token revoke 7a0a4a6a-a7bf-42c0-8cdf-2be8583d180e
+func NewTokenCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "token",
+		Short: "Персональные API-токены для CI и автоматизации",
+		Long: `Персональные API-токены.
+
+Токен даёт доступ только к секретам в пределах scopes и типов.
+Использование: GOPHKEEPER_TOKEN=<token> gophkeeper sync
+
+Примеры:
+  gophkeeper token create --name ci --scope secrets:read --type text --ttl 720h
+  gophkeeper token list
+  gophkeeper token revoke <uuid>
+`,
+	}
+
+	cmd.AddCommand(newTokenCreateCmd(app))
+	cmd.AddCommand(newTokenListCmd(app))
+	cmd.AddCommand(newTokenRevokeCmd(app))
+
+	return cmd
+}
+
+// newTokenCreateCmd выпускает API-токен и печатает его.
+func newTokenCreateCmd(app *App) *cobra.Command {
+	var (
+		name   string
+		scopes []string
+		types  []string
+		ttl    time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:          "create",
+		Short:        "Выпустить API-токен",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+			if ttl < 0 {
+				return fmt.Errorf("--ttl must not be negative")
+			}
+
+			c := app.apiClient()
+			resp, err := c.CreateAPIToken(app.Creds.AccessToken, api.CreateAPITokenRequest{
+				Name:      name,
+				Scopes:    scopes,
+				Types:     types,
+				ExpiresIn: int64(ttl / time.Second),
+			})
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintln(out, resp.Token)
+			fmt.Fprintf(cmd.ErrOrStderr(),
+				"token %s created, expires %s; it is shown only once, use it as %s\n",
+				resp.ID, resp.ExpiresAt.Format("2006-01-02 15:04:05"), config.TokenEnv,
+			)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&name, "name", "", "token name, e.g. github-actions")
+	cmd.Flags().StringSliceVar(&scopes, "scope", []string{"secrets:read"}, "scopes: secrets:read, secrets:write")
+	cmd.Flags().StringSliceVar(&types, "type", nil, "secret types the token may access (default: all)")
+	cmd.Flags().DurationVar(&ttl, "ttl", 0, "token lifetime, e.g. 720h (default: server setting)")
+	cmd.MarkFlagRequired("name")
+
+	return cmd
+}
+
+// newTokenListCmd печатает действующие API-токены.
+func newTokenListCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:          "list",
+		Short:        "Показать API-токены",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			c := app.apiClient()
+			resp, err := c.ListAPITokens(app.Creds.AccessToken)
+			if err != nil {
+				return err
+			}
+
+			if len(resp.Tokens) == 0 {
+				fmt.Fprintln(cmd.OutOrStdout(), "no api tokens")
+				return nil
+			}
+
+			for _, t := range resp.Tokens {
+				types := "all"
+				if len(t.Types) > 0 {
+					types = strings.Join(t.Types, ",")
+				}
+				lastUsed := "never"
+				if t.LastUsedAt != nil {
+					lastUsed = t.LastUsedAt.Format("2006-01-02 15:04:05")
+				}
+				fmt.Fprintf(cmd.OutOrStdout(),
+					"%s\t%s\tscopes=%s\ttypes=%s\texpires=%s\tlast_used=%s\n",
+					t.ID, t.Name, strings.Join(t.Scopes, ","), types,
+					t.ExpiresAt.Format("2006-01-02 15:04:05"), lastUsed,
+				)
+			}
+			return nil
+		},
+	}
+}
+
+// newTokenRevokeCmd отзывает API-токен по ID.
+func newTokenRevokeCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:          "revoke <id>",
+		Short:        "Отозвать API-токен по ID",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			c := app.apiClient()
+			if err := c.RevokeAPIToken(app.Creds.AccessToken, args[0]); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "token %s revoked\n", args[0])
+			return nil
+		},
+	}
+}
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// NewTwoFactorCmd создаёт группу CLI-команд двухфакторной аутентификации (TOTP).
+//
+// Подкоманды:
+//   - enroll — получить otpauth URI для приложения-аутентификатора и сразу подтвердить код;
+//   - confirm --code — подтвердить подключение кодом (если enroll прерван).
+//
+// После подтверждения печатаются одноразовые коды восстановления:
+// сервер хранит только их хэши, повторно показать их нельзя.
+//
+// Пример использования:
+//
+//	gophkeeper 2fa enroll
+//	gophkeeper 2fa confirm --code 123456
+func NewTwoFactorCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "2fa",
+		Short: "Двухфакторная аутентификация (TOTP)",
+		Long: `Подключение двухфакторной аутентификации.
+
+Примеры:
+  gophkeeper 2fa enroll
+  gophkeeper 2fa confirm --code 123456
+`,
+	}
+
+	cmd.AddCommand(newTwoFactorEnrollCmd(app))
+	cmd.AddCommand(newTwoFactorConfirmCmd(app))
+
+	return cmd
+}
+
+// newTwoFactorEnrollCmd начинает подключение TOTP и запрашивает код для подтверждения.
+func newTwoFactorEnrollCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:          "enroll",
+		Short:        "Подключить TOTP",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			c := app.apiClient()
+			resp, err := c.EnrollTOTP(app.Creds.AccessToken)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintln(out, "Add this account to your authenticator app:")
+			fmt.Fprintln(out, resp.OTPAuthURI)
+			fmt.Fprintf(out, "secret=%s\n", resp.Secret)
+
+			code, err := readLine(cmd, "Code from the app: ")
+			if err != nil || code == "" {
+				fmt.Fprintln(out, "not confirmed yet, run: gophkeeper 2fa confirm --code <code>")
+				return nil
+			}
+			return confirmTOTP(cmd, app, code)
+		},
+	}
+}
+
+// newTwoFactorConfirmCmd подтверждает подключение TOTP кодом.
+func newTwoFactorConfirmCmd(app *App) *cobra.Command {
+	var code string
+
+	cmd := &cobra.Command{
+		Use:          "confirm",
+		Short:        "Подтвердить подключение TOTP кодом",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+			return confirmTOTP(cmd, app, code)
+		},
+	}
+
+	cmd.Flags().StringVar(&code, "code", "", "code from the authenticator app")
+	cmd.MarkFlagRequired("code")
+
+	return cmd
+}
+
+// confirmTOTP подтверждает TOTP и печатает коды восстановления.
+func confirmTOTP(cmd *cobra.Command, app *App, code string) error {
+	c := app.apiClient()
+	resp, err := c.ConfirmTOTP(app.Creds.AccessToken, code)
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintln(out, "two-factor authentication enabled")
+	fmt.Fprintln(out, "Recovery codes (each works once, store them safely):")
+	for _, rc := range resp.RecoveryCodes {
+		fmt.Fprintln(out, rc)
+	}
+	return nil
+}
+
+// readLine печатает приглашение в stderr и читает одну строку из stdin команды.
+func readLine(cmd *cobra.Command, prompt string) (string, error) {
+	fmt.Fprint(cmd.ErrOrStderr(), prompt)
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read input: %w", err)
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return "", errors.New("empty input")
+	}
+	return line, nil
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/spf13/cobra"
+)
+
+// NewWhoamiCmd создаёт CLI-команду для вывода сведений о текущем пользователе.
+//
+// Команда запрашивает GET /me и печатает ID, email, дату регистрации,
+// количество секретов, занятое место и число активных сессий.
+// Дополнительно выводится срок действия локально сохранённого access токена
+// (exp декодируется из JWT без проверки подписи — только для информации).
+//
+// Пример использования:
+//
+//	gophkeeper whoami
+func NewWhoamiCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:          "whoami",
+		Short:        "Показать текущего пользователя",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if app.Creds == nil || app.Creds.AccessToken == "" {
+				return fmt.Errorf("no access_token, run: gophkeeper login")
+			}
+
+			c := app.apiClient()
+			me, err := c.Me(app.Creds.AccessToken)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "user_id=%s\n", me.UserID)
+			fmt.Fprintf(out, "email=%s\n", me.Email)
+			fmt.Fprintf(out, "created_at=%s\n", me.CreatedAt.Format("2006-01-02 15:04:05"))
+			fmt.Fprintf(out, "secrets=%d\n", me.SecretCount)
+			fmt.Fprintf(out, "storage_bytes=%d\n", me.StorageBytes)
+			fmt.Fprintf(out, "active_sessions=%d\n", me.ActiveSessions)
+
+			exp, err := accessTokenExpiry(app.Creds.AccessToken)
+			if err != nil {
+				fmt.Fprintln(out, "access_token_expires=unknown")
+				return nil
+			}
+			fmt.Fprintf(out, "access_token_expires=%s (in %s)\n",
+				exp.Format("2006-01-02 15:04:05"),
+				time.Until(exp).Round(time.Second),
+			)
+			return nil
+		},
+	}
+}
+
+// accessTokenExpiry извлекает exp из access токена без проверки подписи.
+// Ключа подписи у клиента нет, поэтому результат носит справочный характер.
+func accessTokenExpiry(token string) (time.Time, error) {
+	var claims jwt.RegisteredClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil {
+		return time.Time{}, err
+	}
+	if claims.ExpiresAt == nil {
+		return time.Time{}, fmt.Errorf("token has no exp claim")
+	}
+	return claims.ExpiresAt.Time, nil
+}
@@ -13,6 +13,20 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
+)
+
+// TokenEnv — переменная окружения с персональным API-токеном.
+//
+// Если она задана, CLI работает с этим токеном вместо сохранённых
+// access/refresh токенов (удобно в CI, где интерактивный логин невозможен).
+const TokenEnv = "GOPHKEEPER_TOKEN"
+
+// Переменные окружения с путями к клиентскому сертификату и ключу (mTLS)
+// — значения по умолчанию для --client-cert и --client-key.
+const (
+	ClientCertEnv = "GOPHKEEPER_CLIENT_CERT"
+	ClientKeyEnv  = "GOPHKEEPER_CLIENT_KEY"
 )
 
 // Credentials содержит учётные данные, используемые CLI-клиентом.
@@ -72,3 +86,14 @@ func Save(path string, c *Credentials) error {
 	}
 	return os.WriteFile(path, b, 0o600)
 }
+
+// FromEnv возвращает учётные данные из переменной окружения GOPHKEEPER_TOKEN.
+//
+// Если переменная не задана, возвращает nil.
+func FromEnv() *Credentials {
+	token := strings.TrimSpace(os.Getenv(TokenEnv))
+	if token == "" {
+		return nil
+	}
+	return &Credentials{AccessToken: token}
+}
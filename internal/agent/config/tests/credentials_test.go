@@ -95,3 +95,16 @@ func TestLoad_BadJSON_ReturnsError(t *testing.T) {
 		t.Fatalf("expected error, got nil")
 	}
 }
+
+func TestFromEnv(t *testing.T) {
+	t.Setenv(config.TokenEnv, "")
+	if creds := config.FromEnv(); creds != nil {
+		t.Fatalf("expected nil creds, got %+v", *creds)
+	}
+
+	t.Setenv(config.TokenEnv, " gkp_secret \n")
+	creds := config.FromEnv()
+	if creds == nil || creds.AccessToken != "gkp_secret" || creds.RefreshToken != "" {
+		t.Fatalf("unexpected creds: %+v", creds)
+	}
+}
@@ -0,0 +1,134 @@
+// HTTP-хендлеры удаления и восстановления аккаунта
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// DeleteMeRequest — тело DELETE /me.
+type DeleteMeRequest struct {
+	Password string `json:"password"`
+}
+
+// DeleteMeResponse — ответ DELETE /me: момент окончательного удаления.
+type DeleteMeResponse struct {
+	DeleteAfter time.Time `json:"delete_after"`
+}
+
+// RestoreAccountRequest — тело POST /auth/restore.
+type RestoreAccountRequest struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// DeleteMe помечает аккаунт к удалению.
+//
+// @Summary      Delete account
+// @Description  Requires the current password. Blocks logins and revokes all sessions immediately; the account and its secrets are purged after the grace period unless restored via POST /auth/restore.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body DeleteMeRequest true "Current password"
+// @Success      202 {object} DeleteMeResponse
+// @Failure      400 {object} ErrorResponse "Invalid input or bad JSON"
+// @Failure      401 {object} ErrorResponse "Unauthorized or wrong password"
+// @Failure      429 {object} ErrorResponse "Too many failed attempts, see Retry-After"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Failure      503 {object} ErrorResponse "Password hashing queue is full, see Retry-After"
+// @Router       /me [delete]
+func (h *Handler) DeleteMe(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	var req DeleteMeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+
+	deleteAfter, err := h.Svc.Auth.RequestDeletion(r.Context(), userID, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, serr.ErrInvalidInput):
+			WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		case errors.Is(err, serr.ErrInvalidCredentials):
+			WriteError(w, http.StatusUnauthorized, serr.ErrInvalidCredentials)
+		case errors.Is(err, serr.ErrNotFound):
+			WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		// после неудачных попыток проверка пароля временно недоступна
+		case errors.Is(err, serr.ErrTooManyAttempts):
+			setRetryAfter(w, err)
+			WriteError(w, http.StatusTooManyRequests, serr.ErrTooManyAttempts)
+		case errors.Is(err, serr.ErrServerBusy):
+			setRetryAfter(w, err)
+			WriteError(w, http.StatusServiceUnavailable, serr.ErrServerBusy)
+		default:
+			h.Log.Logger.Sugar().Errorw(
+				"delete account failed",
+				"error", err,
+				"user_id", userID.String(),
+			)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(DeleteMeResponse{DeleteAfter: deleteAfter})
+}
+
+// RestoreAccount отменяет удаление аккаунта в течение отсрочки.
+//
+// @Summary      Restore account
+// @Description  Cancels a pending account deletion by email and password. Log in again afterwards.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body RestoreAccountRequest true "Account credentials"
+// @Success      204 "Account restored"
+// @Failure      400 {object} ErrorResponse "Invalid input or bad JSON"
+// @Failure      401 {object} ErrorResponse "Invalid credentials or grace period expired"
+// @Failure      429 {object} ErrorResponse "Too many failed attempts, see Retry-After"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Failure      503 {object} ErrorResponse "Password hashing queue is full, see Retry-After"
+// @Router       /auth/restore [post]
+func (h *Handler) RestoreAccount(w http.ResponseWriter, r *http.Request) {
+	var req RestoreAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+
+	if err := h.Svc.Auth.RestoreAccount(r.Context(), req.Email, req.Password); err != nil {
+		switch {
+		case errors.Is(err, serr.ErrInvalidInput):
+			WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		case errors.Is(err, serr.ErrInvalidCredentials):
+			WriteError(w, http.StatusUnauthorized, serr.ErrInvalidCredentials)
+		// после неудачных попыток проверка пароля временно недоступна
+		case errors.Is(err, serr.ErrTooManyAttempts):
+			setRetryAfter(w, err)
+			WriteError(w, http.StatusTooManyRequests, serr.ErrTooManyAttempts)
+		case errors.Is(err, serr.ErrServerBusy):
+			setRetryAfter(w, err)
+			WriteError(w, http.StatusServiceUnavailable, serr.ErrServerBusy)
+		default:
+			h.Log.Logger.Sugar().Errorw("restore account failed", "error", err)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
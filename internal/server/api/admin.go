@@ -0,0 +1,286 @@
+// HTTP-хендлеры администрирования сервера
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// AdminUser — swagger-схема пользователя в списке администратора.
+type AdminUser struct {
+	ID          string     `json:"id"`
+	Email       string     `json:"email"`
+	IsAdmin     bool       `json:"is_admin"`
+	Disabled    bool       `json:"disabled"`
+	DisabledAt  *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	SecretCount int        `json:"secret_count"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+// ListAdminUsersResponse — ответ GET /admin/users.
+type ListAdminUsersResponse struct {
+	Users []AdminUser `json:"users"`
+}
+
+// AdminStatsResponse — ответ GET /admin/stats.
+type AdminStatsResponse struct {
+	Users          int64 `json:"users"`
+	DisabledUsers  int64 `json:"disabled_users"`
+	Secrets        int64 `json:"secrets"`
+	SecretBytes    int64 `json:"secret_bytes"`
+	ActiveSessions int64 `json:"active_sessions"`
+}
+
+// RequireAdmin пропускает только администраторов сервера (users.is_admin).
+//
+// Ставится после проверки access-токена. Если администрирование
+// не подключено, /admin отвечает 404.
+func (h *Handler) RequireAdmin() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if h.Svc.Admin == nil {
+				WriteError(w, http.StatusNotFound, serr.ErrNotFound)
+				return
+			}
+			userID, ok := middleware.UserIDFromContext(r.Context())
+			if !ok {
+				WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+				return
+			}
+
+			if err := h.Svc.Admin.RequireAdmin(r.Context(), userID); err != nil {
+				switch {
+				case errors.Is(err, serr.ErrForbidden):
+					WriteError(w, http.StatusForbidden, serr.ErrForbidden)
+				case errors.Is(err, serr.ErrUnauthorized):
+					WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+				default:
+					h.Log.Logger.Sugar().Errorw(
+						"check admin role failed",
+						"error", err,
+						"user_id", userID.String(),
+					)
+					WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+				}
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ListAdminUsers возвращает пользователей сервера.
+//
+// @Summary      List users
+// @Description  Returns users in registration order with secret count and last login time. Admins only.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        limit  query int false "Page size (default 50, max 200)"
+// @Param        offset query int false "Number of users to skip"
+// @Success      200 {object} ListAdminUsersResponse
+// @Failure      400 {object} ErrorResponse "Invalid input"
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      403 {object} ErrorResponse "Not an administrator"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /admin/users [get]
+func (h *Handler) ListAdminUsers(w http.ResponseWriter, r *http.Request) {
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		return
+	}
+	offset, err := queryInt(r, "offset")
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		return
+	}
+
+	users, err := h.Svc.Admin.ListUsers(r.Context(), limit, offset)
+	if err != nil {
+		if errors.Is(err, serr.ErrInvalidInput) {
+			WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+			return
+		}
+		h.Log.Logger.Sugar().Errorw("list users failed", "error", err)
+		WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		return
+	}
+
+	resp := ListAdminUsersResponse{Users: make([]AdminUser, 0, len(users))}
+	for _, u := range users {
+		resp.Users = append(resp.Users, AdminUser{
+			ID:          u.ID.String(),
+			Email:       u.Email,
+			IsAdmin:     u.IsAdmin,
+			Disabled:    u.DisabledAt != nil,
+			DisabledAt:  u.DisabledAt,
+			CreatedAt:   u.CreatedAt,
+			SecretCount: u.SecretCount,
+			LastLoginAt: u.LastLoginAt,
+		})
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// DisableUser блокирует аккаунт.
+//
+// @Summary      Disable user
+// @Description  Blocks login, token refresh and API tokens of the account and revokes all its sessions. Secrets are kept. Admins only; requires a recent POST /auth/reauth.
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        id path string true "User ID" format(uuid)
+// @Success      204 "Disabled"
+// @Failure      400 {object} ErrorResponse "Invalid user ID or own account"
+// @Failure      401 {object} ErrorResponse "Unauthorized or reauthentication required"
+// @Failure      403 {object} ErrorResponse "Not an administrator"
+// @Failure      404 {object} ErrorResponse "User not found"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /admin/users/{id}/disable [post]
+func (h *Handler) DisableUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, true)
+}
+
+// EnableUser снимает блокировку аккаунта.
+//
+// @Summary      Enable user
+// @Description  Lifts the block set by POST /admin/users/{id}/disable. Admins only; requires a recent POST /auth/reauth.
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        id path string true "User ID" format(uuid)
+// @Success      204 "Enabled"
+// @Failure      400 {object} ErrorResponse "Invalid user ID"
+// @Failure      401 {object} ErrorResponse "Unauthorized or reauthentication required"
+// @Failure      403 {object} ErrorResponse "Not an administrator"
+// @Failure      404 {object} ErrorResponse "User not found"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /admin/users/{id}/enable [post]
+func (h *Handler) EnableUser(w http.ResponseWriter, r *http.Request) {
+	h.setUserDisabled(w, r, false)
+}
+
+func (h *Handler) setUserDisabled(w http.ResponseWriter, r *http.Request, disabled bool) {
+	adminID, targetID, ok := h.adminTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Svc.Admin.SetDisabled(r.Context(), adminID, targetID, disabled); err != nil {
+		h.writeAdminError(w, "set user disabled failed", adminID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RevokeUserSessions отзывает все сессии пользователя.
+//
+// @Summary      Revoke user sessions
+// @Description  Signs the user out on all devices. Access tokens of the revoked sessions stop working immediately. Admins only; requires a recent POST /auth/reauth.
+// @Tags         admin
+// @Security     BearerAuth
+// @Param        id path string true "User ID" format(uuid)
+// @Success      204 "Revoked"
+// @Failure      400 {object} ErrorResponse "Invalid user ID"
+// @Failure      401 {object} ErrorResponse "Unauthorized or reauthentication required"
+// @Failure      403 {object} ErrorResponse "Not an administrator"
+// @Failure      404 {object} ErrorResponse "User not found"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /admin/users/{id}/sessions [delete]
+func (h *Handler) RevokeUserSessions(w http.ResponseWriter, r *http.Request) {
+	adminID, targetID, ok := h.adminTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.Svc.Admin.RevokeSessions(r.Context(), adminID, targetID); err != nil {
+		h.writeAdminError(w, "revoke user sessions failed", adminID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AdminStats возвращает сводную статистику хранилища.
+//
+// @Summary      Storage stats
+// @Description  Returns the number of users (and disabled users), secrets, total size of encrypted secret data and active sessions. Admins only.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} AdminStatsResponse
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      403 {object} ErrorResponse "Not an administrator"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /admin/stats [get]
+func (h *Handler) AdminStats(w http.ResponseWriter, r *http.Request) {
+	st, err := h.Svc.Admin.Stats(r.Context())
+	if err != nil {
+		h.Log.Logger.Sugar().Errorw("storage stats failed", "error", err)
+		WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		return
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(AdminStatsResponse{
+		Users:          st.Users,
+		DisabledUsers:  st.DisabledUsers,
+		Secrets:        st.Secrets,
+		SecretBytes:    st.SecretBytes,
+		ActiveSessions: st.ActiveSessions,
+	})
+}
+
+// adminTarget достаёт ID администратора из контекста и ID пользователя из пути.
+func (h *Handler) adminTarget(w http.ResponseWriter, r *http.Request) (adminID, targetID uuid.UUID, ok bool) {
+	targetID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		return uuid.Nil, uuid.Nil, false
+	}
+	adminID, ok = middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return uuid.Nil, uuid.Nil, false
+	}
+	return adminID, targetID, true
+}
+
+func (h *Handler) writeAdminError(w http.ResponseWriter, msg string, adminID uuid.UUID, err error) {
+	switch {
+	case errors.Is(err, serr.ErrInvalidInput):
+		WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+	case errors.Is(err, serr.ErrNotFound):
+		WriteError(w, http.StatusNotFound, serr.ErrNotFound)
+	default:
+		h.Log.Logger.Sugar().Errorw(
+			msg,
+			"error", err,
+			"user_id", adminID.String(),
+		)
+		WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+	}
+}
+
+// queryInt разбирает целый параметр запроса; пустой — 0.
+func queryInt(r *http.Request, name string) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(v)
+}
@@ -0,0 +1,254 @@
+// HTTP-хендлеры персональных API-токенов и проверка их scopes
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// CreateAPITokenRequest — тело POST /auth/tokens.
+type CreateAPITokenRequest struct {
+	Name      string   `json:"name"`
+	Scopes    []string `json:"scopes"`               // secrets:read, secrets:write
+	Types     []string `json:"types,omitempty"`      // типы секретов (пусто — любые)
+	ExpiresIn int64    `json:"expires_in,omitempty"` // срок действия в секундах (0 — по умолчанию)
+}
+
+// APIToken — swagger-схема персонального API-токена (без самого токена).
+type APIToken struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	Types      []string   `json:"types,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// CreateAPITokenResponse — ответ POST /auth/tokens.
+//
+// Token показывается только один раз.
+type CreateAPITokenResponse struct {
+	Token string `json:"token"`
+	APIToken
+}
+
+// ListAPITokensResponse — ответ GET /auth/tokens.
+type ListAPITokensResponse struct {
+	Tokens []APIToken `json:"tokens"`
+}
+
+func apiTokenResponse(t models.APIToken) APIToken {
+	return APIToken{
+		ID:         t.ID.String(),
+		Name:       t.Name,
+		Scopes:     t.Scopes,
+		Types:      t.Types,
+		CreatedAt:  t.CreatedAt,
+		ExpiresAt:  t.ExpiresAt,
+		LastUsedAt: t.LastUsedAt,
+	}
+}
+
+// CreateAPIToken выпускает персональный API-токен.
+//
+// @Summary      Create API token
+// @Description  Issues a personal API token for CI/automation. The token is returned once and stored hashed.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body CreateAPITokenRequest true "Token name, scopes, secret types and lifetime"
+// @Success      201 {object} CreateAPITokenResponse
+// @Failure      400 {object} ErrorResponse "Invalid input or bad JSON"
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      403 {object} ErrorResponse "API tokens cannot manage tokens"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/tokens [post]
+func (h *Handler) CreateAPIToken(w http.ResponseWriter, r *http.Request) {
+	var req CreateAPITokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+	if req.ExpiresIn < 0 {
+		WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		return
+	}
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	created, err := h.Svc.Auth.CreateAPIToken(r.Context(), userID, req.Name, req.Scopes, req.Types, time.Duration(req.ExpiresIn)*time.Second)
+	if err != nil {
+		switch {
+		case errors.Is(err, serr.ErrInvalidInput):
+			WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		default:
+			h.Log.Logger.Sugar().Errorw(
+				"create api token failed",
+				"error", err,
+				"user_id", userID.String(),
+			)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateAPITokenResponse{
+		Token:    created.Token,
+		APIToken: apiTokenResponse(created.APIToken),
+	})
+}
+
+// ListAPITokens возвращает действующие API-токены пользователя.
+//
+// @Summary      List API tokens
+// @Description  Returns active personal API tokens (without token values).
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListAPITokensResponse
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      403 {object} ErrorResponse "API tokens cannot manage tokens"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/tokens [get]
+func (h *Handler) ListAPITokens(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	list, err := h.Svc.Auth.ListAPITokens(r.Context(), userID)
+	if err != nil {
+		h.Log.Logger.Sugar().Errorw(
+			"list api tokens failed",
+			"error", err,
+			"user_id", userID.String(),
+		)
+		WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		return
+	}
+
+	resp := ListAPITokensResponse{Tokens: make([]APIToken, 0, len(list))}
+	for _, t := range list {
+		resp.Tokens = append(resp.Tokens, apiTokenResponse(t))
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RevokeAPIToken отзывает API-токен пользователя по ID.
+//
+// @Summary      Revoke API token
+// @Description  Revokes a personal API token. Requests with it are rejected immediately.
+// @Tags         auth
+// @Security     BearerAuth
+// @Param        id path string true "Token ID" format(uuid)
+// @Success      204 "Revoked"
+// @Failure      400 {object} ErrorResponse "Invalid token id"
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      403 {object} ErrorResponse "API tokens cannot manage tokens"
+// @Failure      404 {object} ErrorResponse "Token not found"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/tokens/{id} [delete]
+func (h *Handler) RevokeAPIToken(w http.ResponseWriter, r *http.Request) {
+	tokenID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		return
+	}
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	if err := h.Svc.Auth.RevokeAPIToken(r.Context(), userID, tokenID); err != nil {
+		switch {
+		case errors.Is(err, serr.ErrNotFound):
+			WriteError(w, http.StatusNotFound, err)
+		default:
+			h.Log.Logger.Sugar().Errorw(
+				"revoke api token failed",
+				"error", err,
+				"user_id", userID.String(),
+				"token_id", tokenID.String(),
+			)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// allowSecrets проверяет, что API-токен запроса имеет scope и доступ к типу секрета.
+//
+// Запросы с JWT access-токеном ограничений не имеют.
+// typ пустой — проверяется только scope.
+func allowSecrets(r *http.Request, scope, typ string) bool {
+	token, ok := middleware.APITokenFromContext(r.Context())
+	if !ok {
+		return true
+	}
+	return token.HasScope(scope) && (typ == "" || token.AllowsType(typ))
+}
+
+// allowSecretWrite проверяет право API-токена изменить существующий секрет.
+//
+// Если токен ограничен типами, тип секрета берётся из хранилища.
+// При отказе сам пишет ответ (403/404/500) и возвращает false.
+func (h *Handler) allowSecretWrite(w http.ResponseWriter, r *http.Request, userID, secretID uuid.UUID) bool {
+	token, ok := middleware.APITokenFromContext(r.Context())
+	if !ok {
+		return true
+	}
+	if !token.HasScope(models.ScopeSecretsWrite) {
+		WriteError(w, http.StatusForbidden, serr.ErrForbidden)
+		return false
+	}
+	if len(token.Types) == 0 {
+		return true
+	}
+
+	typ, err := h.Svc.Secrets.SecretType(r.Context(), userID, secretID)
+	if err != nil {
+		if errors.Is(err, serr.ErrNotFound) {
+			WriteError(w, http.StatusNotFound, err)
+			return false
+		}
+		h.Log.Logger.Sugar().Errorw(
+			"get secret type failed",
+			"error", err,
+			"user_id", userID.String(),
+			"secret_id", secretID.String(),
+		)
+		WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		return false
+	}
+	// чужие типы не раскрываем: для токена такого секрета нет
+	if !token.AllowsType(typ) {
+		WriteError(w, http.StatusNotFound, serr.ErrNotFound)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,129 @@
+// HTTP-хендлер журнала аудита пользователя
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// AuditEvent — swagger-схема события журнала аудита.
+type AuditEvent struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"type"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	SecretID  string    `json:"secret_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListAuditResponse — ответ GET /audit.
+//
+// NextCursor передаётся в параметре cursor для следующей страницы,
+// 0 — страниц больше нет.
+type ListAuditResponse struct {
+	Events     []AuditEvent `json:"events"`
+	NextCursor int64        `json:"next_cursor,omitempty"`
+}
+
+// ListAudit возвращает журнал аудита пользователя.
+//
+// @Summary      Audit log
+// @Description  Returns security-relevant events of the authenticated user (logins, failed logins, refresh token reuse, session revocation, secret changes), newest first.
+// @Tags         audit
+// @Produce      json
+// @Security     BearerAuth
+// @Param        since  query string false "Only events at or after this time (RFC 3339)"
+// @Param        type   query string false "Event type, e.g. login_failed"
+// @Param        limit  query int    false "Page size (default 50, max 200)"
+// @Param        cursor query int    false "next_cursor of the previous page"
+// @Success      200 {object} ListAuditResponse
+// @Failure      400 {object} ErrorResponse "Invalid input"
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      404 {object} ErrorResponse "Audit log is disabled"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /audit [get]
+func (h *Handler) ListAudit(w http.ResponseWriter, r *http.Request) {
+	if h.Svc.Audit == nil {
+		WriteError(w, http.StatusNotFound, serr.ErrNotFound)
+		return
+	}
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	filter, err := parseAuditFilter(r)
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		return
+	}
+
+	events, next, err := h.Svc.Audit.List(r.Context(), userID, filter)
+	if err != nil {
+		if errors.Is(err, serr.ErrInvalidInput) {
+			WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+			return
+		}
+		h.Log.Logger.Sugar().Errorw(
+			"list audit events failed",
+			"error", err,
+			"user_id", userID.String(),
+		)
+		WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		return
+	}
+
+	resp := ListAuditResponse{Events: make([]AuditEvent, 0, len(events)), NextCursor: next}
+	for _, e := range events {
+		ev := AuditEvent{
+			ID:        e.ID,
+			Type:      e.Type,
+			IP:        e.IP,
+			UserAgent: e.UserAgent,
+			CreatedAt: e.CreatedAt,
+		}
+		if e.SecretID != uuid.Nil {
+			ev.SecretID = e.SecretID.String()
+		}
+		resp.Events = append(resp.Events, ev)
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// parseAuditFilter разбирает параметры since, type, limit и cursor.
+func parseAuditFilter(r *http.Request) (models.AuditFilter, error) {
+	q := r.URL.Query()
+	f := models.AuditFilter{Type: q.Get("type")}
+
+	var err error
+	if v := q.Get("since"); v != "" {
+		if f.Since, err = time.Parse(time.RFC3339, v); err != nil {
+			return f, err
+		}
+	}
+	if v := q.Get("limit"); v != "" {
+		if f.Limit, err = strconv.Atoi(v); err != nil {
+			return f, err
+		}
+	}
+	if v := q.Get("cursor"); v != "" {
+		if f.Before, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
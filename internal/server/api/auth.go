@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"time"
 
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
 	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
 )
 
@@ -17,9 +19,12 @@ const (
 )
 
 // RegisterRequest описывает тело запроса регистрации пользователя.
+//
+// Invite обязателен, если сервер работает в режиме auth.registration=invite.
 type RegisterRequest struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
+	Invite   string `json:"invite,omitempty"`
 }
 
 // RegisterResponse описывает успешный ответ регистрации.
@@ -39,6 +44,16 @@ type LoginResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// MFAChallengeResponse — ответ на вход, когда у пользователя подключён TOTP.
+//
+// Токены не выдаются: клиент должен отправить MFAToken и код
+// из приложения (или код восстановления) в POST /auth/2fa/verify до ExpiresAt.
+type MFAChallengeResponse struct {
+	MFARequired bool      `json:"mfa_required"`
+	MFAToken    string    `json:"mfa_token"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
 // RefreshRequest описывает тело запроса обновления токенов.
 type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token"`
@@ -50,6 +65,14 @@ type RefreshResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// LogoutRequest описывает тело запроса выхода.
+//
+// All=true отзывает все сессии пользователя (выход со всех устройств).
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	All          bool   `json:"all"`
+}
+
 // Register обрабатывает регистрацию пользователя.
 //
 // @Summary      Register user
@@ -60,8 +83,10 @@ type RefreshResponse struct {
 // @Param        request body RegisterRequest true "Register request"
 // @Success      201 {object} RegisterResponse
 // @Failure      400 {object} ErrorResponse "Invalid input or bad JSON"
+// @Failure      403 {object} ErrorResponse "Registration is closed or invite code is invalid"
 // @Failure      409 {object} ErrorResponse "User already exists"
 // @Failure      500 {object} ErrorResponse "Internal server error"
+// @Failure      503 {object} ErrorResponse "Password hashing queue is full, see Retry-After"
 // @Router       /auth/register [post]
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	var req RegisterRequest
@@ -70,13 +95,20 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := h.Svc.Auth.Register(r.Context(), req.Email, req.Password)
+	id, err := h.Svc.Auth.Register(r.Context(), req.Email, req.Password, req.Invite)
 	if err != nil {
 		switch {
 		case errors.Is(err, serr.ErrInvalidInput):
 			http.Error(w, serr.ErrInvalidInput.Error(), http.StatusBadRequest)
+		case errors.Is(err, serr.ErrRegistrationClosed):
+			http.Error(w, serr.ErrRegistrationClosed.Error(), http.StatusForbidden)
+		case errors.Is(err, serr.ErrInvalidInvite):
+			http.Error(w, serr.ErrInvalidInvite.Error(), http.StatusForbidden)
 		case errors.Is(err, serr.ErrAlreadyExists):
 			http.Error(w, serr.ErrAlreadyExists.Error(), http.StatusConflict)
+		case errors.Is(err, serr.ErrServerBusy):
+			setRetryAfter(w, err)
+			http.Error(w, serr.ErrServerBusy.Error(), http.StatusServiceUnavailable)
 		default:
 			h.Log.Logger.Sugar().Error("register failed")
 			http.Error(w, serr.ErrInternal.Error(), http.StatusInternalServerError)
@@ -98,9 +130,14 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 // @Produce      json
 // @Param        request body LoginRequest true "Login request"
 // @Success      200 {object} LoginResponse
+// @Success      202 {object} MFAChallengeResponse "Second factor required"
 // @Failure      400 {object} ErrorResponse "Invalid input or bad JSON"
-// @Failure      401 {object} ErrorResponse "Invalid credentials"
+// @Failure      401 {object} ErrorResponse "Invalid credentials or bound client certificate not presented"
+// @Failure      403 {object} ErrorResponse "Email not verified (mail.require_verified) or account disabled"
+// @Failure      409 {object} ErrorResponse "Too many active sessions"
+// @Failure      429 {object} ErrorResponse "Too many failed attempts, see Retry-After"
 // @Failure      500 {object} ErrorResponse "Internal server error"
+// @Failure      503 {object} ErrorResponse "Password hashing queue is full, see Retry-After"
 // @Router       /auth/login [post]
 func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 	var req LoginRequest
@@ -111,11 +148,41 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 
 	pair, err := h.Svc.Auth.Login(r.Context(), req.Email, req.Password)
 	if err != nil {
+		// пароль верный, но нужен второй фактор
+		var mfaErr *service.MFARequiredError
+		if errors.As(err, &mfaErr) {
+			w.Header().Set(ContentType, JsonContentType)
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(MFAChallengeResponse{
+				MFARequired: true,
+				MFAToken:    mfaErr.Challenge.Token,
+				ExpiresAt:   mfaErr.Challenge.ExpiresAt,
+			})
+			return
+		}
 		switch {
 		case errors.Is(err, serr.ErrInvalidInput):
 			http.Error(w, serr.ErrInvalidInput.Error(), http.StatusBadRequest)
 		case errors.Is(err, serr.ErrInvalidCredentials):
 			http.Error(w, serr.ErrInvalidCredentials.Error(), http.StatusUnauthorized)
+		case errors.Is(err, serr.ErrTooManySessions):
+			http.Error(w, serr.ErrTooManySessions.Error(), http.StatusConflict)
+		// к аккаунту привязан сертификат, а запрос пришёл без него
+		case errors.Is(err, serr.ErrClientCertRequired):
+			http.Error(w, serr.ErrClientCertRequired.Error(), http.StatusUnauthorized)
+		// аккаунт заблокирован администратором
+		case errors.Is(err, serr.ErrAccountDisabled):
+			http.Error(w, serr.ErrAccountDisabled.Error(), http.StatusForbidden)
+		// сервер требует подтверждённый email
+		case errors.Is(err, serr.ErrEmailNotVerified):
+			http.Error(w, serr.ErrEmailNotVerified.Error(), http.StatusForbidden)
+		// после неудачных попыток вход по email временно недоступен
+		case errors.Is(err, serr.ErrTooManyAttempts):
+			setRetryAfter(w, err)
+			http.Error(w, serr.ErrTooManyAttempts.Error(), http.StatusTooManyRequests)
+		case errors.Is(err, serr.ErrServerBusy):
+			setRetryAfter(w, err)
+			http.Error(w, serr.ErrServerBusy.Error(), http.StatusServiceUnavailable)
 		default:
 			h.Log.Logger.Sugar().Error("login failed")
 			http.Error(w, serr.ErrInternal.Error(), http.StatusInternalServerError)
@@ -140,7 +207,8 @@ func (h *Handler) Login(w http.ResponseWriter, r *http.Request) {
 // @Param        request body RefreshRequest true "Refresh request"
 // @Success      200 {object} RefreshResponse
 // @Failure      400 {object} ErrorResponse "Invalid input or bad JSON"
-// @Failure      401 {object} ErrorResponse "Unauthorized or token revoked"
+// @Failure      401 {object} ErrorResponse "Unauthorized, token revoked or bound client certificate not presented"
+// @Failure      403 {object} ErrorResponse "Account disabled by an administrator"
 // @Failure      500 {object} ErrorResponse "Internal server error"
 // @Router       /auth/refresh [post]
 func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
@@ -158,6 +226,11 @@ func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
 
 		case errors.Is(err, serr.ErrUnauthorized):
 			http.Error(w, serr.ErrUnauthorized.Error(), http.StatusUnauthorized)
+		case errors.Is(err, serr.ErrClientCertRequired):
+			http.Error(w, serr.ErrClientCertRequired.Error(), http.StatusUnauthorized)
+		// аккаунт заблокирован администратором
+		case errors.Is(err, serr.ErrAccountDisabled):
+			http.Error(w, serr.ErrAccountDisabled.Error(), http.StatusForbidden)
 		default:
 			h.Log.Logger.Sugar().Error("refresh failed")
 			http.Error(w, serr.ErrInternal.Error(), http.StatusInternalServerError)
@@ -171,3 +244,39 @@ func (h *Handler) Refresh(w http.ResponseWriter, r *http.Request) {
 		RefreshToken: pair.RefreshToken,
 	})
 }
+
+// Logout отзывает refresh-сессию (или все сессии пользователя).
+//
+// @Summary      Logout
+// @Description  Revokes the refresh session. With all=true revokes every session of the user.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body LogoutRequest true "Logout request"
+// @Success      204 "No Content"
+// @Failure      400 {object} ErrorResponse "Invalid input or bad JSON"
+// @Failure      401 {object} ErrorResponse "Unauthorized or token revoked"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/logout [post]
+func (h *Handler) Logout(w http.ResponseWriter, r *http.Request) {
+	var req LogoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, serr.ErrBadJSON.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Svc.Auth.Logout(r.Context(), req.RefreshToken, req.All); err != nil {
+		switch {
+		case errors.Is(err, serr.ErrInvalidInput):
+			http.Error(w, serr.ErrInvalidInput.Error(), http.StatusBadRequest)
+		case errors.Is(err, serr.ErrUnauthorized):
+			http.Error(w, serr.ErrUnauthorized.Error(), http.StatusUnauthorized)
+		default:
+			h.Log.Logger.Sugar().Error("logout failed")
+			http.Error(w, serr.ErrInternal.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,192 @@
+// HTTP-хендлеры привязки клиентских сертификатов (mTLS) к аккаунту
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// BindClientCertRequest — тело POST /auth/certs.
+type BindClientCertRequest struct {
+	Certificate string `json:"certificate"`    // сертификат в PEM (без закрытого ключа)
+	Name        string `json:"name,omitempty"` // имя для списка (пусто — CN сертификата)
+}
+
+// ClientCert — swagger-схема привязанного клиентского сертификата.
+type ClientCert struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Subject     string    `json:"subject"`
+	Fingerprint string    `json:"fingerprint"` // SHA-256 DER в hex
+	NotAfter    time.Time `json:"not_after"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ListClientCertsResponse — ответ GET /auth/certs.
+type ListClientCertsResponse struct {
+	Certificates []ClientCert `json:"certificates"`
+}
+
+func clientCertResponse(c models.ClientCert) ClientCert {
+	return ClientCert{
+		ID:          c.ID.String(),
+		Name:        c.Name,
+		Subject:     c.Subject,
+		Fingerprint: hex.EncodeToString(c.Fingerprint),
+		NotAfter:    c.NotAfter,
+		CreatedAt:   c.CreatedAt,
+	}
+}
+
+// BindClientCert привязывает клиентский сертификат к аккаунту.
+//
+// @Summary      Bind client certificate
+// @Description  Binds a client certificate issued by the server's client CA to the account. The request must be made over mTLS with the same certificate. Afterwards login, refresh and device login require mTLS with a bound certificate, and access tokens are accepted only together with it.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body BindClientCertRequest true "PEM certificate and optional name"
+// @Success      201 {object} ClientCert
+// @Failure      400 {object} ErrorResponse "Invalid or untrusted certificate, bad JSON"
+// @Failure      401 {object} ErrorResponse "Unauthorized, or the request was not made over mTLS with this certificate"
+// @Failure      403 {object} ErrorResponse "API tokens cannot manage certificates"
+// @Failure      404 {object} ErrorResponse "Client certificates are not enabled on the server"
+// @Failure      409 {object} ErrorResponse "Certificate is already bound"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/certs [post]
+func (h *Handler) BindClientCert(w http.ResponseWriter, r *http.Request) {
+	var req BindClientCertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	cert, err := h.Svc.Auth.BindClientCert(r.Context(), userID, req.Certificate, req.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, serr.ErrInvalidInput):
+			WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		case errors.Is(err, serr.ErrClientCertRequired):
+			WriteError(w, http.StatusUnauthorized, serr.ErrClientCertRequired)
+		case errors.Is(err, serr.ErrNotFound):
+			WriteError(w, http.StatusNotFound, serr.ErrNotFound)
+		case errors.Is(err, serr.ErrAlreadyExists):
+			WriteError(w, http.StatusConflict, serr.ErrAlreadyExists)
+		default:
+			h.Log.Logger.Sugar().Errorw(
+				"bind client cert failed",
+				"error", err,
+				"user_id", userID.String(),
+			)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(clientCertResponse(cert))
+}
+
+// ListClientCerts возвращает сертификаты, привязанные к аккаунту.
+//
+// @Summary      List client certificates
+// @Description  Returns client certificates bound to the account.
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListClientCertsResponse
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      403 {object} ErrorResponse "API tokens cannot manage certificates"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/certs [get]
+func (h *Handler) ListClientCerts(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	list, err := h.Svc.Auth.ListClientCerts(r.Context(), userID)
+	if err != nil {
+		h.Log.Logger.Sugar().Errorw(
+			"list client certs failed",
+			"error", err,
+			"user_id", userID.String(),
+		)
+		WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		return
+	}
+
+	resp := ListClientCertsResponse{Certificates: make([]ClientCert, 0, len(list))}
+	for _, c := range list {
+		resp.Certificates = append(resp.Certificates, clientCertResponse(c))
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// UnbindClientCert отвязывает сертификат от аккаунта по ID.
+//
+// @Summary      Unbind client certificate
+// @Description  Removes a bound client certificate. When the last one is removed, login no longer requires mTLS.
+// @Tags         auth
+// @Security     BearerAuth
+// @Param        id path string true "Certificate ID" format(uuid)
+// @Success      204 "Unbound"
+// @Failure      400 {object} ErrorResponse "Invalid certificate id"
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      403 {object} ErrorResponse "API tokens cannot manage certificates"
+// @Failure      404 {object} ErrorResponse "Certificate not found"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/certs/{id} [delete]
+func (h *Handler) UnbindClientCert(w http.ResponseWriter, r *http.Request) {
+	certID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		return
+	}
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	if err := h.Svc.Auth.UnbindClientCert(r.Context(), userID, certID); err != nil {
+		switch {
+		case errors.Is(err, serr.ErrNotFound):
+			WriteError(w, http.StatusNotFound, err)
+		default:
+			h.Log.Logger.Sugar().Errorw(
+				"unbind client cert failed",
+				"error", err,
+				"user_id", userID.String(),
+				"cert_id", certID.String(),
+			)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
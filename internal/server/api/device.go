@@ -0,0 +1,192 @@
+// HTTP-хендлеры входа по коду устройства (RFC 8628)
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// DeviceCodeResponse — ответ POST /auth/device/code.
+//
+// UserCode показывается пользователю, DeviceCode клиент оставляет себе
+// и опрашивает /auth/device/token не чаще раза в Interval секунд.
+type DeviceCodeResponse struct {
+	DeviceCode string `json:"device_code"`
+	UserCode   string `json:"user_code"`
+	ExpiresIn  int    `json:"expires_in"` // секунды
+	Interval   int    `json:"interval"`   // секунды
+}
+
+// DeviceTokenRequest — тело POST /auth/device/token.
+type DeviceTokenRequest struct {
+	DeviceCode string `json:"device_code"`
+}
+
+// DeviceDecisionRequest — тело POST /auth/device/approve и /auth/device/deny.
+type DeviceDecisionRequest struct {
+	UserCode string `json:"user_code"`
+}
+
+// DeviceCode начинает вход по коду устройства.
+//
+// @Summary      Start device authorization
+// @Description  Issues a device_code for polling and a short user_code to approve from a logged-in device (RFC 8628).
+// @Tags         auth
+// @Produce      json
+// @Success      200 {object} DeviceCodeResponse
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/device/code [post]
+func (h *Handler) DeviceCode(w http.ResponseWriter, r *http.Request) {
+	auth, err := h.Svc.Auth.StartDeviceAuthorization(r.Context())
+	if err != nil {
+		h.Log.Logger.Sugar().Errorw("start device authorization failed", "error", err)
+		WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		return
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(DeviceCodeResponse{
+		DeviceCode: auth.DeviceCode,
+		UserCode:   auth.UserCode,
+		ExpiresIn:  int(time.Until(auth.ExpiresAt).Round(time.Second) / time.Second),
+		Interval:   int(auth.Interval / time.Second),
+	})
+}
+
+// DeviceToken обменивает подтверждённый device_code на пару токенов.
+//
+// Пока код не подтверждён, отвечает 400 с error=authorization_pending;
+// при слишком частом опросе — slow_down (интервал нужно увеличить на 5 секунд).
+//
+// @Summary      Poll device authorization
+// @Description  Exchanges an approved device_code for access/refresh tokens. Errors follow RFC 8628: authorization_pending, slow_down, access_denied, expired_token, invalid_grant.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body DeviceTokenRequest true "Device code"
+// @Success      200 {object} LoginResponse
+// @Failure      400 {object} ErrorResponse "authorization_pending, slow_down, access_denied, expired_token, invalid_grant or bad JSON"
+// @Failure      401 {object} ErrorResponse "Bound client certificate not presented, or the account was deleted"
+// @Failure      403 {object} ErrorResponse "Account disabled by an administrator"
+// @Failure      409 {object} ErrorResponse "Too many active sessions"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/device/token [post]
+func (h *Handler) DeviceToken(w http.ResponseWriter, r *http.Request) {
+	var req DeviceTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+
+	pair, err := h.Svc.Auth.PollDeviceToken(r.Context(), req.DeviceCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, serr.ErrAuthorizationPending),
+			errors.Is(err, serr.ErrSlowDown),
+			errors.Is(err, serr.ErrAccessDenied),
+			errors.Is(err, serr.ErrExpiredToken),
+			errors.Is(err, serr.ErrInvalidGrant):
+			WriteError(w, http.StatusBadRequest, err)
+		case errors.Is(err, serr.ErrClientCertRequired):
+			WriteError(w, http.StatusUnauthorized, serr.ErrClientCertRequired)
+		// аккаунт удалён или помечен к удалению после подтверждения кода
+		case errors.Is(err, serr.ErrUnauthorized):
+			WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		case errors.Is(err, serr.ErrAccountDisabled):
+			WriteError(w, http.StatusForbidden, serr.ErrAccountDisabled)
+		case errors.Is(err, serr.ErrTooManySessions):
+			WriteError(w, http.StatusConflict, serr.ErrTooManySessions)
+		default:
+			h.Log.Logger.Sugar().Errorw("device token failed", "error", err)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(LoginResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+	})
+}
+
+// ApproveDevice подтверждает вход по user_code от имени текущего пользователя.
+//
+// @Summary      Approve device login
+// @Description  Approves a pending device authorization; the polling device receives tokens for the current user.
+// @Tags         auth
+// @Accept       json
+// @Security     BearerAuth
+// @Param        request body DeviceDecisionRequest true "User code shown on the device"
+// @Success      204 "Approved"
+// @Failure      400 {object} ErrorResponse "Invalid code format or bad JSON"
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      403 {object} ErrorResponse "API tokens cannot approve devices"
+// @Failure      404 {object} ErrorResponse "Code not found, expired or already used"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/device/approve [post]
+func (h *Handler) ApproveDevice(w http.ResponseWriter, r *http.Request) {
+	h.decideDevice(w, r, true)
+}
+
+// DenyDevice отклоняет вход по user_code.
+//
+// @Summary      Deny device login
+// @Description  Denies a pending device authorization; the polling device receives access_denied.
+// @Tags         auth
+// @Accept       json
+// @Security     BearerAuth
+// @Param        request body DeviceDecisionRequest true "User code shown on the device"
+// @Success      204 "Denied"
+// @Failure      400 {object} ErrorResponse "Invalid code format or bad JSON"
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      403 {object} ErrorResponse "API tokens cannot approve devices"
+// @Failure      404 {object} ErrorResponse "Code not found, expired or already used"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/device/deny [post]
+func (h *Handler) DenyDevice(w http.ResponseWriter, r *http.Request) {
+	h.decideDevice(w, r, false)
+}
+
+// decideDevice — общая часть ApproveDevice и DenyDevice.
+func (h *Handler) decideDevice(w http.ResponseWriter, r *http.Request, approve bool) {
+	var req DeviceDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	if err := h.Svc.Auth.DecideDevice(r.Context(), userID, req.UserCode, approve); err != nil {
+		switch {
+		case errors.Is(err, serr.ErrInvalidInput):
+			WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		case errors.Is(err, serr.ErrNotFound):
+			WriteError(w, http.StatusNotFound, serr.ErrNotFound)
+		default:
+			h.Log.Logger.Sugar().Errorw(
+				"device decision failed",
+				"error", err,
+				"user_id", userID.String(),
+			)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
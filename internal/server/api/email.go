@@ -0,0 +1,161 @@
+// HTTP-хендлеры подтверждения email и сброса пароля по почте
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// EmailTokenRequest — тело POST /auth/email/verify.
+type EmailTokenRequest struct {
+	Token string `json:"token"`
+}
+
+// EmailRequest — тело POST /auth/email/verify/resend и POST /auth/password/reset/request.
+type EmailRequest struct {
+	Email string `json:"email"`
+}
+
+// ResetPasswordRequest — тело POST /auth/password/reset.
+type ResetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+// VerifyEmail подтверждает email по токену из письма.
+//
+// @Summary      Verify email
+// @Description  Marks the account email as verified using the one-time token from the verification email.
+// @Tags         auth
+// @Accept       json
+// @Param        request body EmailTokenRequest true "Token from the email"
+// @Success      204 "Verified"
+// @Failure      400 {object} ErrorResponse "Invalid input, bad JSON or invalid/expired token"
+// @Failure      404 {object} ErrorResponse "Mail is not configured on the server"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/email/verify [post]
+func (h *Handler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req EmailTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+
+	if err := h.Svc.Auth.VerifyEmail(r.Context(), req.Token); err != nil {
+		h.writeEmailError(w, "verify email failed", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ResendVerification повторно отправляет письмо подтверждения email.
+//
+// Ответ не раскрывает, зарегистрирован ли адрес.
+//
+// @Summary      Resend verification email
+// @Description  Sends a new verification email if the address belongs to an unverified account. The response is the same, and takes the same time, whether the account exists or not: the email is sent in the background.
+// @Tags         auth
+// @Accept       json
+// @Param        request body EmailRequest true "Account email"
+// @Success      202 "Accepted"
+// @Failure      400 {object} ErrorResponse "Invalid input or bad JSON"
+// @Failure      404 {object} ErrorResponse "Mail is not configured on the server"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/email/verify/resend [post]
+func (h *Handler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	var req EmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+
+	if err := h.Svc.Auth.ResendVerification(r.Context(), req.Email); err != nil {
+		h.writeEmailError(w, "resend verification failed", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// RequestPasswordReset отправляет письмо с токеном сброса пароля.
+//
+// Ответ не раскрывает, зарегистрирован ли адрес.
+//
+// @Summary      Request password reset
+// @Description  Emails a short-lived single-use reset token if the address belongs to an account. The response is the same whether the account exists or not. Resetting the password does not recover secrets encrypted with the master password.
+// @Tags         auth
+// @Accept       json
+// @Param        request body EmailRequest true "Account email"
+// @Success      202 "Accepted"
+// @Failure      400 {object} ErrorResponse "Invalid input or bad JSON"
+// @Failure      404 {object} ErrorResponse "Mail is not configured on the server"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/password/reset/request [post]
+func (h *Handler) RequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req EmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+
+	if err := h.Svc.Auth.RequestPasswordReset(r.Context(), req.Email); err != nil {
+		h.writeEmailError(w, "request password reset failed", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// ResetPassword задаёт новый пароль по токену из письма.
+//
+// Все сессии пользователя отзываются. Зашифрованные секреты
+// сброс не восстанавливает: для них нужен мастер-пароль.
+//
+// @Summary      Reset password
+// @Description  Sets a new account password using the token from the reset email and revokes all sessions. Secrets stay encrypted with the master password; the server cannot recover them.
+// @Tags         auth
+// @Accept       json
+// @Param        request body ResetPasswordRequest true "Token from the email and new password"
+// @Success      204 "Password reset"
+// @Failure      400 {object} ErrorResponse "Invalid input, bad JSON or invalid/expired token"
+// @Failure      404 {object} ErrorResponse "Mail is not configured on the server"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Failure      503 {object} ErrorResponse "Password hashing queue is full, see Retry-After"
+// @Router       /auth/password/reset [post]
+func (h *Handler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+
+	if err := h.Svc.Auth.ResetPassword(r.Context(), req.Token, req.NewPassword); err != nil {
+		h.writeEmailError(w, "reset password failed", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// writeEmailError переводит ошибки почтовых сценариев в HTTP-ответ.
+func (h *Handler) writeEmailError(w http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, serr.ErrInvalidInput):
+		WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+	case errors.Is(err, serr.ErrInvalidEmailToken):
+		WriteError(w, http.StatusBadRequest, serr.ErrInvalidEmailToken)
+	// письма на сервере выключены
+	case errors.Is(err, serr.ErrNotFound):
+		WriteError(w, http.StatusNotFound, serr.ErrNotFound)
+	case errors.Is(err, serr.ErrServerBusy):
+		setRetryAfter(w, err)
+		WriteError(w, http.StatusServiceUnavailable, serr.ErrServerBusy)
+	default:
+		h.Log.Logger.Sugar().Errorw(msg, "error", err)
+		WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+	}
+}
@@ -9,10 +9,16 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/logger"
 )
 
@@ -21,13 +27,20 @@ import (
 // Handler содержит:
 //   - Svc: сервисный слой (бизнес-логика);
 //   - Log: логгер для записи событий и ошибок;
-//   - Verifier: компонент проверки JWT и middleware авторизации.
+//   - Verifier: компонент проверки JWT и middleware авторизации;
+//   - Limiter: rate limit запросов (nil — лимит выключен);
+//   - PoW: proof-of-work на регистрации и входе под нагрузкой (nil — выключен);
+//   - TrustProxy: доверять ли X-Forwarded-For при определении IP клиента.
 //
 // Методы Handler используются роутером для обработки HTTP-запросов.
 type Handler struct {
 	Svc      *service.Services
 	Log      *logger.HTTPLogger
 	Verifier *middleware.JWTVerifier
+	Limiter  *middleware.RateLimiter
+	PoW      *middleware.ProofOfWork
+
+	TrustProxy bool
 }
 
 // NewHandler создаёт экземпляр Handler с переданными зависимостями.
@@ -51,3 +64,54 @@ func WriteError(w http.ResponseWriter, status int, err error) {
 		Error: err.Error(),
 	})
 }
+
+// setRetryAfter выставляет заголовок Retry-After, если ошибка сервиса
+// сообщает, когда запрос можно повторить (блокировка входа, перегрузка).
+// Значение — целые секунды с округлением вверх.
+func setRetryAfter(w http.ResponseWriter, err error) {
+	var d time.Duration
+	var lockErr *service.LoginLockedError
+	var busyErr *service.BusyError
+	switch {
+	case errors.As(err, &lockErr):
+		d = lockErr.RetryAfter
+	case errors.As(err, &busyErr):
+		d = busyErr.RetryAfter
+	default:
+		return
+	}
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+}
+
+// DeviceNameHeader — заголовок, в котором клиент передаёт имя устройства.
+const DeviceNameHeader = "X-Device-Name"
+
+// maxClientFieldLen — ограничение длины имени устройства и user agent,
+// чтобы в sessions не попадали мегабайтные строки.
+const maxClientFieldLen = 256
+
+// ClientInfoMiddleware сохраняет в контексте сведения о клиенте
+// (имя устройства, user agent, IP), которые сервисы записывают в сессии,
+// и отпечаток клиентского сертификата для проверки привязки к аккаунту.
+func (h *Handler) ClientInfoMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			info := models.ClientInfo{
+				DeviceName: truncate(strings.TrimSpace(r.Header.Get(DeviceNameHeader)), maxClientFieldLen),
+				UserAgent:  truncate(r.UserAgent(), maxClientFieldLen),
+				IP:         middleware.ClientIP(r, h.TrustProxy),
+
+				CertFingerprint: middleware.PeerCertFingerprint(r),
+			}
+			next.ServeHTTP(w, r.WithContext(service.WithClientInfo(r.Context(), info)))
+		})
+	}
+}
+
+// truncate обрезает строку до n байт.
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
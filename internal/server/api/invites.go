@@ -0,0 +1,210 @@
+// HTTP-хендлеры кодов приглашений для регистрации
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// CreateInviteRequest — тело POST /auth/invites.
+type CreateInviteRequest struct {
+	MaxUses   int   `json:"max_uses,omitempty"`   // число регистраций по коду (0 — одна)
+	ExpiresIn int64 `json:"expires_in,omitempty"` // срок действия в секундах (0 — по умолчанию)
+}
+
+// Invite — swagger-схема приглашения (без самого кода).
+type Invite struct {
+	ID        string    `json:"id"`
+	CreatedBy string    `json:"created_by,omitempty"`
+	MaxUses   int       `json:"max_uses"`
+	Uses      int       `json:"uses"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateInviteResponse — ответ POST /auth/invites.
+//
+// Code показывается только один раз.
+type CreateInviteResponse struct {
+	Code string `json:"code"`
+	Invite
+}
+
+// ListInvitesResponse — ответ GET /auth/invites.
+type ListInvitesResponse struct {
+	Invites []Invite `json:"invites"`
+}
+
+func inviteResponse(inv models.Invite) Invite {
+	resp := Invite{
+		ID:        inv.ID.String(),
+		MaxUses:   inv.MaxUses,
+		Uses:      inv.Uses,
+		ExpiresAt: inv.ExpiresAt,
+		CreatedAt: inv.CreatedAt,
+	}
+	// автор мог удалить аккаунт
+	if inv.CreatedBy != uuid.Nil {
+		resp.CreatedBy = inv.CreatedBy.String()
+	}
+	return resp
+}
+
+// CreateInvite выпускает код приглашения.
+//
+// @Summary      Create invite
+// @Description  Issues an invite code for registration in auth.registration=invite mode. Only auth.invites.admins and server administrators may issue codes. The code is returned once and stored hashed.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body CreateInviteRequest true "Number of uses and lifetime"
+// @Success      201 {object} CreateInviteResponse
+// @Failure      400 {object} ErrorResponse "Invalid input or bad JSON"
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      403 {object} ErrorResponse "Not an invite admin or API token used"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/invites [post]
+func (h *Handler) CreateInvite(w http.ResponseWriter, r *http.Request) {
+	var req CreateInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+	if req.ExpiresIn < 0 {
+		WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		return
+	}
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	created, err := h.Svc.Auth.CreateInvite(r.Context(), userID, req.MaxUses, time.Duration(req.ExpiresIn)*time.Second)
+	if err != nil {
+		switch {
+		case errors.Is(err, serr.ErrInvalidInput):
+			WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		case errors.Is(err, serr.ErrForbidden):
+			WriteError(w, http.StatusForbidden, serr.ErrForbidden)
+		default:
+			h.Log.Logger.Sugar().Errorw(
+				"create invite failed",
+				"error", err,
+				"user_id", userID.String(),
+			)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(CreateInviteResponse{
+		Code:   created.Code,
+		Invite: inviteResponse(created.Invite),
+	})
+}
+
+// ListInvites возвращает действующие приглашения.
+//
+// @Summary      List invites
+// @Description  Returns unexpired invites with remaining uses (without codes).
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListInvitesResponse
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      403 {object} ErrorResponse "Not an invite admin or API token used"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/invites [get]
+func (h *Handler) ListInvites(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	list, err := h.Svc.Auth.ListInvites(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, serr.ErrForbidden) {
+			WriteError(w, http.StatusForbidden, serr.ErrForbidden)
+			return
+		}
+		h.Log.Logger.Sugar().Errorw(
+			"list invites failed",
+			"error", err,
+			"user_id", userID.String(),
+		)
+		WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		return
+	}
+
+	resp := ListInvitesResponse{Invites: make([]Invite, 0, len(list))}
+	for _, inv := range list {
+		resp.Invites = append(resp.Invites, inviteResponse(inv))
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RevokeInvite отзывает приглашение по ID.
+//
+// @Summary      Revoke invite
+// @Description  Revokes an invite code. Registration with it is rejected immediately.
+// @Tags         auth
+// @Security     BearerAuth
+// @Param        id path string true "Invite ID" format(uuid)
+// @Success      204 "Revoked"
+// @Failure      400 {object} ErrorResponse "Invalid invite id"
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      403 {object} ErrorResponse "Not an invite admin or API token used"
+// @Failure      404 {object} ErrorResponse "Invite not found"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/invites/{id} [delete]
+func (h *Handler) RevokeInvite(w http.ResponseWriter, r *http.Request) {
+	inviteID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		return
+	}
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	if err := h.Svc.Auth.RevokeInvite(r.Context(), userID, inviteID); err != nil {
+		switch {
+		case errors.Is(err, serr.ErrForbidden):
+			WriteError(w, http.StatusForbidden, serr.ErrForbidden)
+		case errors.Is(err, serr.ErrNotFound):
+			WriteError(w, http.StatusNotFound, err)
+		default:
+			h.Log.Logger.Sugar().Errorw(
+				"revoke invite failed",
+				"error", err,
+				"user_id", userID.String(),
+				"invite_id", inviteID.String(),
+			)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
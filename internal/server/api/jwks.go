@@ -0,0 +1,34 @@
+// HTTP-хендлер публикации публичных ключей JWT
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+)
+
+// JWKS отдаёт публичные ключи проверки access-токенов (RFC 7517).
+//
+// Сторонние сервисы проверяют токены GophKeeper по этим ключам,
+// не имея доступа к секрету подписи. Ключ выбирается по kid из заголовка токена.
+// При HS256 публичных ключей нет — возвращается пустой список.
+//
+// @Summary      JSON Web Key Set
+// @Description  Returns public keys (active and retired) used to verify access tokens.
+// @Tags         auth
+// @Produce      json
+// @Success      200 {object} crypto.JWKS
+// @Router       /.well-known/jwks.json [get]
+func (h *Handler) JWKS(w http.ResponseWriter, r *http.Request) {
+	set := crypto.JWKS{Keys: []crypto.JWK{}}
+	if h.Verifier != nil && h.Verifier.Keys != nil {
+		set = h.Verifier.Keys.JWKS()
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	// ключи меняются только при рестарте с новым конфигом
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(set)
+}
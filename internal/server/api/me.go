@@ -0,0 +1,69 @@
+// HTTP-хендлер сведений о текущем пользователе
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// MeResponse — ответ GET /me.
+type MeResponse struct {
+	UserID         string    `json:"user_id"`
+	Email          string    `json:"email"`
+	CreatedAt      time.Time `json:"created_at"`
+	SecretCount    int       `json:"secret_count"`
+	StorageBytes   int64     `json:"storage_bytes"`
+	ActiveSessions int       `json:"active_sessions"`
+}
+
+// Me возвращает сведения об аккаунте аутентифицированного пользователя.
+//
+// @Summary      Current user
+// @Description  Returns user id, email, creation date, secret count, storage usage and active session count.
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} MeResponse
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      404 {object} ErrorResponse "User not found"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /me [get]
+func (h *Handler) Me(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	p, err := h.Svc.Auth.Me(r.Context(), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, serr.ErrNotFound):
+			WriteError(w, http.StatusNotFound, err)
+		default:
+			h.Log.Logger.Sugar().Errorw(
+				"get profile failed",
+				"error", err,
+				"user_id", userID.String(),
+			)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(MeResponse{
+		UserID:         p.ID.String(),
+		Email:          p.Email,
+		CreatedAt:      p.CreatedAt,
+		SecretCount:    p.SecretCount,
+		StorageBytes:   p.StorageBytes,
+		ActiveSessions: p.ActiveSessions,
+	})
+}
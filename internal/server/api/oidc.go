@@ -0,0 +1,88 @@
+// HTTP-хендлер входа через внешний OpenID Connect провайдер
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// OIDCLoginRequest — тело POST /auth/oidc.
+type OIDCLoginRequest struct {
+	IDToken string `json:"id_token"` // ID-токен, выданный провайдером клиенту
+}
+
+// LoginOIDC обменивает ID-токен провайдера на пару токенов.
+//
+// @Summary      Login with OpenID Connect
+// @Description  Exchanges an ID token issued by the configured OpenID Connect provider for access/refresh tokens. The account is found by issuer and subject; on first login it is created (or linked by email when enabled) from the provider's verified email. Returns 202 with an MFA challenge if TOTP is enabled for the account.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body OIDCLoginRequest true "ID token"
+// @Success      200 {object} LoginResponse
+// @Success      202 {object} MFAChallengeResponse
+// @Failure      400 {object} ErrorResponse "Invalid input or bad JSON"
+// @Failure      401 {object} ErrorResponse "Invalid ID token, unverified email or bound client certificate not presented"
+// @Failure      403 {object} ErrorResponse "Account disabled by an administrator"
+// @Failure      404 {object} ErrorResponse "OpenID Connect login is not enabled"
+// @Failure      409 {object} ErrorResponse "Email belongs to an account not linked to the provider, or too many active sessions"
+// @Failure      503 {object} ErrorResponse "Password hashing queue is full"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/oidc [post]
+func (h *Handler) LoginOIDC(w http.ResponseWriter, r *http.Request) {
+	var req OIDCLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+
+	pair, err := h.Svc.Auth.LoginOIDC(r.Context(), req.IDToken)
+	if err != nil {
+		var mfaErr *service.MFARequiredError
+		if errors.As(err, &mfaErr) {
+			w.Header().Set(ContentType, JsonContentType)
+			w.WriteHeader(http.StatusAccepted)
+			json.NewEncoder(w).Encode(MFAChallengeResponse{
+				MFARequired: true,
+				MFAToken:    mfaErr.Challenge.Token,
+				ExpiresAt:   mfaErr.Challenge.ExpiresAt,
+			})
+			return
+		}
+		switch {
+		case errors.Is(err, serr.ErrInvalidInput):
+			WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		case errors.Is(err, serr.ErrUnauthorized):
+			WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		case errors.Is(err, serr.ErrClientCertRequired):
+			WriteError(w, http.StatusUnauthorized, serr.ErrClientCertRequired)
+		case errors.Is(err, serr.ErrAccountDisabled):
+			WriteError(w, http.StatusForbidden, serr.ErrAccountDisabled)
+		case errors.Is(err, serr.ErrNotFound):
+			WriteError(w, http.StatusNotFound, serr.ErrNotFound)
+		case errors.Is(err, serr.ErrAlreadyExists):
+			WriteError(w, http.StatusConflict, serr.ErrAlreadyExists)
+		case errors.Is(err, serr.ErrTooManySessions):
+			WriteError(w, http.StatusConflict, serr.ErrTooManySessions)
+		case errors.Is(err, serr.ErrServerBusy):
+			setRetryAfter(w, err)
+			WriteError(w, http.StatusServiceUnavailable, serr.ErrServerBusy)
+		default:
+			h.Log.Logger.Sugar().Errorw("oidc login failed", "error", err)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(LoginResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+	})
+}
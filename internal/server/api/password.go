@@ -0,0 +1,90 @@
+// HTTP-хендлер смены пароля аккаунта
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// ChangePasswordRequest — тело POST /auth/password.
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password"`
+	NewPassword string `json:"new_password"`
+}
+
+// ChangePassword меняет пароль аккаунта.
+//
+// Все сессии пользователя отзываются, текущему устройству
+// выдаётся новая пара токенов.
+//
+// @Summary      Change account password
+// @Description  Checks the old password, stores the new one and revokes all sessions. Returns a fresh token pair for the current device.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body ChangePasswordRequest true "Old and new password"
+// @Success      200 {object} LoginResponse
+// @Failure      400 {object} ErrorResponse "Invalid input or bad JSON"
+// @Failure      401 {object} ErrorResponse "Unauthorized, wrong old password or bound client certificate not presented"
+// @Failure      403 {object} ErrorResponse "Account disabled by an administrator"
+// @Failure      429 {object} ErrorResponse "Too many failed attempts, see Retry-After"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Failure      503 {object} ErrorResponse "Password hashing queue is full, see Retry-After"
+// @Router       /auth/password [post]
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+
+	pair, err := h.Svc.Auth.ChangePassword(r.Context(), userID, req.OldPassword, req.NewPassword)
+	if err != nil {
+		switch {
+		case errors.Is(err, serr.ErrInvalidInput):
+			WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		case errors.Is(err, serr.ErrInvalidCredentials):
+			WriteError(w, http.StatusUnauthorized, serr.ErrInvalidCredentials)
+		case errors.Is(err, serr.ErrNotFound):
+			WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		// пароль сменён, но новые токены без привязанного сертификата не выдаются
+		case errors.Is(err, serr.ErrClientCertRequired):
+			WriteError(w, http.StatusUnauthorized, serr.ErrClientCertRequired)
+		case errors.Is(err, serr.ErrAccountDisabled):
+			WriteError(w, http.StatusForbidden, serr.ErrAccountDisabled)
+		// после неудачных попыток проверка пароля временно недоступна
+		case errors.Is(err, serr.ErrTooManyAttempts):
+			setRetryAfter(w, err)
+			WriteError(w, http.StatusTooManyRequests, serr.ErrTooManyAttempts)
+		case errors.Is(err, serr.ErrServerBusy):
+			setRetryAfter(w, err)
+			WriteError(w, http.StatusServiceUnavailable, serr.ErrServerBusy)
+		default:
+			h.Log.Logger.Sugar().Errorw(
+				"change password failed",
+				"error", err,
+				"user_id", userID.String(),
+			)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(LoginResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+	})
+}
@@ -0,0 +1,54 @@
+// HTTP-хендлер выдачи challenge proof-of-work
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// ChallengeResponse — ответ GET /auth/challenge.
+//
+// Клиент ищет решение — строку n, при которой SHA-256(challenge + ":" + n)
+// начинается с Difficulty нулевых бит, — и передаёт challenge и n
+// в заголовках X-PoW-Challenge и X-PoW-Solution запроса регистрации или входа.
+type ChallengeResponse struct {
+	Challenge  string    `json:"challenge"`
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Challenge выдаёт одноразовый challenge proof-of-work.
+//
+// @Summary      Proof-of-work challenge
+// @Description  Returns a signed single-use hashcash challenge. Under load /auth/register and /auth/login answer 428 until a solution is sent in X-PoW-Challenge/X-PoW-Solution. Difficulty 0 means no solution is required right now.
+// @Tags         auth
+// @Produce      json
+// @Success      200 {object} ChallengeResponse
+// @Failure      404 {object} ErrorResponse "Proof-of-work is disabled"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/challenge [get]
+func (h *Handler) Challenge(w http.ResponseWriter, r *http.Request) {
+	if h.PoW == nil {
+		WriteError(w, http.StatusNotFound, serr.ErrNotFound)
+		return
+	}
+
+	challenge, difficulty, expiresAt, err := h.PoW.Challenge()
+	if err != nil {
+		h.Log.Logger.Sugar().Errorw("issue pow challenge failed", "error", err)
+		WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		return
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ChallengeResponse{
+		Challenge:  challenge,
+		Difficulty: difficulty,
+		ExpiresAt:  expiresAt,
+	})
+}
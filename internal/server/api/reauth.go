@@ -0,0 +1,105 @@
+// HTTP-хендлер повторной аутентификации перед чувствительными операциями
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// ReauthRequest — тело POST /auth/reauth.
+//
+// Code нужен, если у пользователя подключён TOTP (код из приложения или код восстановления).
+type ReauthRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code,omitempty"`
+}
+
+// ReauthResponse — access-токен с повышенными правами до ElevatedUntil.
+type ReauthResponse struct {
+	AccessToken   string    `json:"access_token"`
+	ElevatedUntil time.Time `json:"elevated_until"`
+}
+
+// Reauth повторно проверяет пароль владельца сессии и выдаёт
+// access-токен с повышенными правами.
+//
+// Refresh-токен не меняется: после обновления пары права снова обычные.
+//
+// @Summary      Re-authenticate for sensitive operations
+// @Description  Checks the password (and TOTP code if enabled) and returns a new access token for the same session with a short-lived elevated claim. Deleting secrets, creating API tokens, binding client certificates, enrolling 2FA, approving device logins and issuing invites require it.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body ReauthRequest true "Password and optional TOTP code"
+// @Success      200 {object} ReauthResponse
+// @Failure      400 {object} ErrorResponse "Invalid input or bad JSON"
+// @Failure      401 {object} ErrorResponse "Unauthorized, wrong password, TOTP code required or invalid, bound client certificate not presented"
+// @Failure      429 {object} ErrorResponse "Too many failed attempts, see Retry-After"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Failure      503 {object} ErrorResponse "Password hashing queue is full, see Retry-After"
+// @Router       /auth/reauth [post]
+func (h *Handler) Reauth(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+	// токены без sid выданы до появления claim: нужен новый вход
+	sessionID, ok := middleware.SessionIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	var req ReauthRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+
+	elev, err := h.Svc.Auth.Reauthenticate(r.Context(), userID, sessionID, req.Password, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, serr.ErrInvalidInput):
+			WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		case errors.Is(err, serr.ErrInvalidCredentials):
+			WriteError(w, http.StatusUnauthorized, serr.ErrInvalidCredentials)
+		case errors.Is(err, serr.ErrMFARequired):
+			WriteError(w, http.StatusUnauthorized, serr.ErrMFARequired)
+		case errors.Is(err, serr.ErrInvalidOTP):
+			WriteError(w, http.StatusUnauthorized, serr.ErrInvalidOTP)
+		case errors.Is(err, serr.ErrClientCertRequired):
+			WriteError(w, http.StatusUnauthorized, serr.ErrClientCertRequired)
+		case errors.Is(err, serr.ErrNotFound), errors.Is(err, serr.ErrUnauthorized):
+			WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		// после неудачных попыток проверка пароля временно недоступна
+		case errors.Is(err, serr.ErrTooManyAttempts):
+			setRetryAfter(w, err)
+			WriteError(w, http.StatusTooManyRequests, serr.ErrTooManyAttempts)
+		case errors.Is(err, serr.ErrServerBusy):
+			setRetryAfter(w, err)
+			WriteError(w, http.StatusServiceUnavailable, serr.ErrServerBusy)
+		default:
+			h.Log.Logger.Sugar().Errorw(
+				"reauth failed",
+				"error", err,
+				"user_id", userID.String(),
+			)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ReauthResponse{
+		AccessToken:   elev.AccessToken,
+		ElevatedUntil: elev.ElevatedUntil,
+	})
+}
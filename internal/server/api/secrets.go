@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
@@ -71,6 +72,7 @@ type ErrorResponse struct {
 // @Success      201 {object} CreateSecretResponse
 // @Failure      400 {object} ErrorResponse "Invalid input or bad JSON"
 // @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      403 {object} ErrorResponse "API token lacks secrets:write or the type"
 // @Failure      413 {object} ErrorResponse "Payload too large"
 // @Failure      500 {object} ErrorResponse "Internal server error"
 // @Router       /secrets [post]
@@ -86,6 +88,10 @@ func (h *Handler) CreateSecret(w http.ResponseWriter, r *http.Request) {
 		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
 		return
 	}
+	if !allowSecrets(r, models.ScopeSecretsWrite, req.Type) {
+		WriteError(w, http.StatusForbidden, serr.ErrForbidden)
+		return
+	}
 
 	id, version, updatedAt, err := h.Svc.Secrets.Create(
 		r.Context(),
@@ -134,6 +140,7 @@ func (h *Handler) CreateSecret(w http.ResponseWriter, r *http.Request) {
 // @Security     BearerAuth
 // @Success      200 {object} GetAllSecretsResponse
 // @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      403 {object} ErrorResponse "API token lacks secrets:read"
 // @Failure      500 {object} ErrorResponse "Internal server error"
 // @Router       /secrets [get]
 func (h *Handler) ListSecrets(w http.ResponseWriter, r *http.Request) {
@@ -142,12 +149,22 @@ func (h *Handler) ListSecrets(w http.ResponseWriter, r *http.Request) {
 		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
 		return
 	}
+	if !allowSecrets(r, models.ScopeSecretsRead, "") {
+		WriteError(w, http.StatusForbidden, serr.ErrForbidden)
+		return
+	}
 
 	secret, err := h.Svc.Secrets.ListSecrets(r.Context(), userID)
 	if err != nil {
 		WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
 		return
 	}
+	// API-токен видит только разрешённые ему типы
+	if token, ok := middleware.APITokenFromContext(r.Context()); ok {
+		secret = slices.DeleteFunc(secret, func(s sharedModels.Secret) bool {
+			return !token.AllowsType(s.Type)
+		})
+	}
 
 	data := sharedModels.GetAllSecretsResponse{
 		Secrets: secret,
@@ -171,6 +188,7 @@ func (h *Handler) ListSecrets(w http.ResponseWriter, r *http.Request) {
 // @Success      204 "No Content"
 // @Failure      400 {object} ErrorResponse "Bad request"
 // @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      403 {object} ErrorResponse "API token lacks secrets:write or the type"
 // @Failure      404 {object} ErrorResponse "Not found"
 // @Failure      409 {object} ErrorResponse "Conflict"
 // @Failure      500 {object} ErrorResponse "Internal server error"
@@ -194,6 +212,14 @@ func (h *Handler) UpdateSecret(w http.ResponseWriter, r *http.Request) {
 		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
 		return
 	}
+	if !h.allowSecretWrite(w, r, userID, secretID) {
+		return
+	}
+	// смена типа тоже должна остаться в пределах токена
+	if req.Type != nil && !allowSecrets(r, models.ScopeSecretsWrite, *req.Type) {
+		WriteError(w, http.StatusForbidden, serr.ErrForbidden)
+		return
+	}
 
 	err = h.Svc.Secrets.UpdateSecret(
 		r.Context(),
@@ -234,6 +260,7 @@ func (h *Handler) UpdateSecret(w http.ResponseWriter, r *http.Request) {
 // @Success      204 "Секрет успешно удалён"
 // @Failure      400 {object} ErrorResponse "Некорректный ID или версия"
 // @Failure      401 {object} ErrorResponse "Не авторизован"
+// @Failure      403 {object} ErrorResponse "Удаление по API-токену недоступно"
 // @Failure      404 {object} ErrorResponse "Секрет не найден"
 // @Failure      409 {object} ErrorResponse "Конфликт версий"
 // @Failure      500 {object} ErrorResponse "Внутренняя ошибка"
@@ -259,6 +286,9 @@ func (h *Handler) DeleteSecret(w http.ResponseWriter, r *http.Request) {
 		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
 		return
 	}
+	if !h.allowSecretWrite(w, r, userID, secretID) {
+		return
+	}
 
 	err = h.Svc.Secrets.DeleteSecret(
 		r.Context(),
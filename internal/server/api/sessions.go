@@ -0,0 +1,122 @@
+// HTTP-хендлеры управления сессиями (устройствами) пользователя
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// Session — swagger-схема активной сессии пользователя.
+type Session struct {
+	ID         string    `json:"id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// ListSessionsResponse — ответ GET /auth/sessions.
+type ListSessionsResponse struct {
+	Sessions []Session `json:"sessions"`
+}
+
+// ListSessions возвращает активные сессии пользователя.
+//
+// @Summary      List sessions
+// @Description  Returns active refresh sessions (devices) of the authenticated user.
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} ListSessionsResponse
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/sessions [get]
+func (h *Handler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	list, err := h.Svc.Auth.ListSessions(r.Context(), userID)
+	if err != nil {
+		h.Log.Logger.Sugar().Errorw(
+			"list sessions failed",
+			"error", err,
+			"user_id", userID.String(),
+		)
+		WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		return
+	}
+
+	resp := ListSessionsResponse{Sessions: make([]Session, 0, len(list))}
+	for _, s := range list {
+		resp.Sessions = append(resp.Sessions, Session{
+			ID:         s.ID.String(),
+			DeviceName: s.DeviceName,
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt,
+			ExpiresAt:  s.ExpiresAt,
+		})
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// RevokeSession отзывает сессию пользователя по ID.
+//
+// @Summary      Revoke session
+// @Description  Revokes one refresh session (device) of the authenticated user.
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        id  path  string  true  "Session ID (UUID)"
+// @Success      204 "No Content"
+// @Failure      400 {object} ErrorResponse "Invalid session id"
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      404 {object} ErrorResponse "Session not found"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/sessions/{id} [delete]
+func (h *Handler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		return
+	}
+
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	if err := h.Svc.Auth.RevokeSession(r.Context(), userID, sessionID); err != nil {
+		switch {
+		case errors.Is(err, serr.ErrNotFound):
+			WriteError(w, http.StatusNotFound, err)
+		default:
+			h.Log.Logger.Sugar().Errorw(
+				"revoke session failed",
+				"error", err,
+				"user_id", userID.String(),
+				"session_id", sessionID.String(),
+			)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
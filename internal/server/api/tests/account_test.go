@@ -0,0 +1,135 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+func TestHandler_DeleteMe_Accepted(t *testing.T) {
+	t.Parallel()
+
+	h, users, sessions := NewTestHandler(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetPasswordHash(gomock.Any(), userID).
+		Return(hashForHandlerTest(t, "StrongPass123"), nil)
+	users.EXPECT().
+		ScheduleDeletion(gomock.Any(), userID, gomock.Any()).
+		Return(nil)
+	sessions.EXPECT().
+		RevokeAllForUser(gomock.Any(), userID).
+		Return(nil)
+
+	body, _ := json.Marshal(api.DeleteMeRequest{Password: "StrongPass123"})
+	req := httptest.NewRequest(http.MethodDelete, "/me", bytes.NewReader(body))
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	h.DeleteMe(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusAccepted, rec.Code, rec.Body.String())
+	}
+
+	var resp api.DeleteMeResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.DeleteAfter.IsZero() {
+		t.Fatalf("expected delete_after, got %+v", resp)
+	}
+}
+
+func TestHandler_DeleteMe_WrongPassword(t *testing.T) {
+	t.Parallel()
+
+	h, users, _ := NewTestHandler(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetPasswordHash(gomock.Any(), userID).
+		Return(hashForHandlerTest(t, "StrongPass123"), nil)
+
+	body, _ := json.Marshal(api.DeleteMeRequest{Password: "WrongPass123"})
+	req := httptest.NewRequest(http.MethodDelete, "/me", bytes.NewReader(body))
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	h.DeleteMe(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestHandler_DeleteMe_BadJSON(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodDelete, "/me", bytes.NewBufferString("{bad"))
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), uuid.New()))
+	rec := httptest.NewRecorder()
+
+	h.DeleteMe(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestHandler_RestoreAccount_NoContent(t *testing.T) {
+	t.Parallel()
+
+	h, users, _ := NewTestHandler(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetPendingDeletion(gomock.Any(), "test@example.com").
+		Return(userID, hashForHandlerTest(t, "StrongPass123"), time.Now().Add(time.Hour), nil)
+	users.EXPECT().
+		CancelDeletion(gomock.Any(), userID).
+		Return(nil)
+
+	body, _ := json.Marshal(api.RestoreAccountRequest{Email: "test@example.com", Password: "StrongPass123"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/restore", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.RestoreAccount(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandler_RestoreAccount_Unauthorized(t *testing.T) {
+	t.Parallel()
+
+	h, users, _ := NewTestHandler(t)
+
+	users.EXPECT().
+		GetPendingDeletion(gomock.Any(), "test@example.com").
+		Return(uuid.Nil, "", time.Time{}, serr.ErrNotFound)
+
+	body, _ := json.Marshal(api.RestoreAccountRequest{Email: "test@example.com", Password: "StrongPass123"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/restore", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.RestoreAccount(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
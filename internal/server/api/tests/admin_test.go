@@ -0,0 +1,218 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	svcmocks "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// newAdminTestHandler возвращает хендлер с подключённым администрированием.
+func newAdminTestHandler(t *testing.T) (*api.Handler, *svcmocks.MockUsersRepo, *svcmocks.MockSessionsRepo, *svcmocks.MockAdminRepo) {
+	t.Helper()
+	h, users, sessions := NewTestHandler(t)
+	repo := svcmocks.NewMockAdminRepo(gomock.NewController(t))
+	h.Svc.Auth.UseAdmin(repo)
+	h.Svc.Admin = service.NewAdminService(repo, sessions, h.Svc.Auth)
+	return h, users, sessions, repo
+}
+
+// adminRouter повторяет /admin без проверки токена и reauth.
+func adminRouter(h *api.Handler, userID uuid.UUID) http.Handler {
+	r := chi.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			next.ServeHTTP(w, req.WithContext(middleware.ContextWithUserID(req.Context(), userID)))
+		})
+	})
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(h.RequireAdmin())
+		r.Get("/users", h.ListAdminUsers)
+		r.Get("/stats", h.AdminStats)
+		r.Post("/users/{id}/disable", h.DisableUser)
+		r.Post("/users/{id}/enable", h.EnableUser)
+		r.Delete("/users/{id}/sessions", h.RevokeUserSessions)
+	})
+	return r
+}
+
+func TestHandler_RequireAdmin(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, repo := newAdminTestHandler(t)
+
+	userID := uuid.New()
+	repo.EXPECT().GetStatus(gomock.Any(), userID).Return(models.UserStatus{}, nil)
+
+	rec := httptest.NewRecorder()
+	adminRouter(h, userID).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/stats", nil))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusForbidden, rec.Code, rec.Body.String())
+	}
+}
+
+// Без подключённого администрирования /admin не существует
+func TestHandler_RequireAdmin_Disabled(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewTestHandler(t)
+
+	rec := httptest.NewRecorder()
+	adminRouter(h, uuid.New()).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/stats", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestHandler_ListAdminUsers(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, repo := newAdminTestHandler(t)
+
+	adminID, userID := uuid.New(), uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+	repo.EXPECT().GetStatus(gomock.Any(), adminID).Return(models.UserStatus{IsAdmin: true}, nil)
+	repo.EXPECT().ListUsers(gomock.Any(), 10, 20).Return([]models.AdminUser{
+		{ID: userID, Email: "user@example.com", CreatedAt: now, SecretCount: 3, DisabledAt: &now},
+	}, nil)
+
+	rec := httptest.NewRecorder()
+	adminRouter(h, adminID).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/users?limit=10&offset=20", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var resp api.ListAdminUsersResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Users) != 1 || resp.Users[0].ID != userID.String() || !resp.Users[0].Disabled ||
+		resp.Users[0].SecretCount != 3 || resp.Users[0].LastLoginAt != nil {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestHandler_ListAdminUsers_BadLimit(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, repo := newAdminTestHandler(t)
+
+	adminID := uuid.New()
+	repo.EXPECT().GetStatus(gomock.Any(), adminID).Return(models.UserStatus{IsAdmin: true}, nil)
+
+	rec := httptest.NewRecorder()
+	adminRouter(h, adminID).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/users?limit=abc", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestHandler_DisableUser(t *testing.T) {
+	t.Parallel()
+
+	h, _, sessions, repo := newAdminTestHandler(t)
+
+	adminID, userID := uuid.New(), uuid.New()
+	repo.EXPECT().GetStatus(gomock.Any(), adminID).Return(models.UserStatus{IsAdmin: true}, nil)
+	repo.EXPECT().SetDisabled(gomock.Any(), userID, true).Return(nil)
+	sessions.EXPECT().RevokeAllForUser(gomock.Any(), userID).Return(nil)
+
+	rec := httptest.NewRecorder()
+	adminRouter(h, adminID).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/users/"+userID.String()+"/disable", nil))
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandler_DisableUser_Self(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, repo := newAdminTestHandler(t)
+
+	adminID := uuid.New()
+	repo.EXPECT().GetStatus(gomock.Any(), adminID).Return(models.UserStatus{IsAdmin: true}, nil)
+
+	rec := httptest.NewRecorder()
+	adminRouter(h, adminID).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/users/"+adminID.String()+"/disable", nil))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestHandler_RevokeUserSessions_NotFound(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, repo := newAdminTestHandler(t)
+
+	adminID, userID := uuid.New(), uuid.New()
+	repo.EXPECT().GetStatus(gomock.Any(), adminID).Return(models.UserStatus{IsAdmin: true}, nil)
+	repo.EXPECT().GetStatus(gomock.Any(), userID).Return(models.UserStatus{}, serr.ErrNotFound)
+
+	rec := httptest.NewRecorder()
+	adminRouter(h, adminID).ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/admin/users/"+userID.String()+"/sessions", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestHandler_AdminStats(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, repo := newAdminTestHandler(t)
+
+	adminID := uuid.New()
+	repo.EXPECT().GetStatus(gomock.Any(), adminID).Return(models.UserStatus{IsAdmin: true}, nil)
+	repo.EXPECT().StorageStats(gomock.Any()).Return(models.StorageStats{Users: 5, Secrets: 12, SecretBytes: 2048, ActiveSessions: 4}, nil)
+
+	rec := httptest.NewRecorder()
+	adminRouter(h, adminID).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/stats", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+	var resp api.AdminStatsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Users != 5 || resp.Secrets != 12 || resp.SecretBytes != 2048 || resp.ActiveSessions != 4 {
+		t.Fatalf("unexpected stats: %+v", resp)
+	}
+}
+
+// Заблокированный аккаунт получает 403, а не 401: пароль верный
+func TestHandler_Login_AccountDisabled(t *testing.T) {
+	t.Parallel()
+
+	h, users, _, repo := newAdminTestHandler(t)
+
+	userID := uuid.New()
+	users.EXPECT().GetByEmail(gomock.Any(), "user@example.com").Return(userID, hashForHandlerTest(t, "StrongPass123"), nil)
+	repo.EXPECT().GetStatus(gomock.Any(), userID).Return(models.UserStatus{Disabled: true}, nil)
+
+	body, _ := json.Marshal(api.LoginRequest{Email: "user@example.com", Password: "StrongPass123"})
+	rec := httptest.NewRecorder()
+	h.Login(rec, httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewReader(body)))
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusForbidden, rec.Code, rec.Body.String())
+	}
+}
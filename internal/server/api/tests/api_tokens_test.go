@@ -0,0 +1,263 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	svcmocks "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+	sharedModels "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/models"
+)
+
+// NewAPITokensTestHandler — Handler с хранилищами API-токенов и секретов
+func NewAPITokensTestHandler(t *testing.T) (*api.Handler, *svcmocks.MockAPITokensRepo, *svcmocks.MockSecretsRepo) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	cfg := &config.Config{
+		Auth: config.AuthConfig{
+			APITokens: config.APITokensConfig{DefaultTTL: 24 * time.Hour, MaxTTL: 48 * time.Hour},
+		},
+		Secrets: config.SecretsConfig{AllowedTypes: []string{"text", "binary"}},
+	}
+
+	tokens := svcmocks.NewMockAPITokensRepo(ctrl)
+	secrets := svcmocks.NewMockSecretsRepo(ctrl)
+
+	auth := service.NewAuthService(svcmocks.NewMockUsersRepo(ctrl), svcmocks.NewMockSessionsRepo(ctrl), cfg)
+	auth.UseAPITokens(tokens)
+
+	h := api.NewHandler(&service.Services{
+		Auth:    auth,
+		Secrets: service.NewSecretsService(secrets, cfg.Secrets),
+	}, nil, nil)
+	return h, tokens, secrets
+}
+
+// withAPIToken кладёт в контекст пользователя и API-токен, как это делает middleware
+func withAPIToken(r *http.Request, token models.APIToken) *http.Request {
+	ctx := middleware.ContextWithUserID(r.Context(), token.UserID)
+	return r.WithContext(middleware.ContextWithAPIToken(ctx, token))
+}
+
+func TestHandler_CreateAPIToken_OK(t *testing.T) {
+	t.Parallel()
+
+	h, tokens, _ := NewAPITokensTestHandler(t)
+
+	userID := uuid.New()
+	tokenID := uuid.New()
+	tokens.EXPECT().
+		Create(gomock.Any(), userID, "ci", gomock.Any(), []string{"secrets:read"}, []string{"text"}, gomock.Any()).
+		Return(tokenID, time.Now(), nil)
+
+	body, _ := json.Marshal(api.CreateAPITokenRequest{
+		Name:      "ci",
+		Scopes:    []string{"secrets:read"},
+		Types:     []string{"text"},
+		ExpiresIn: 3600,
+	})
+	req := httptest.NewRequest(http.MethodPost, "/auth/tokens", bytes.NewReader(body))
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	h.CreateAPIToken(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	var resp api.CreateAPITokenResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !strings.HasPrefix(resp.Token, "gkp_") || resp.ID != tokenID.String() {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if time.Until(resp.ExpiresAt) > time.Hour {
+		t.Fatalf("expected expiry within an hour, got %v", resp.ExpiresAt)
+	}
+}
+
+func TestHandler_CreateAPIToken_InvalidInput(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewAPITokensTestHandler(t)
+
+	cases := []api.CreateAPITokenRequest{
+		{Name: "ci", Scopes: []string{"admin"}},
+		{Name: "ci", Scopes: []string{"secrets:read"}, Types: []string{"otp"}},
+		{Name: "ci", Scopes: []string{"secrets:read"}, ExpiresIn: int64((72 * time.Hour).Seconds())},
+		{Name: "ci", Scopes: []string{"secrets:read"}, ExpiresIn: -1},
+		{Scopes: []string{"secrets:read"}},
+	}
+	for _, c := range cases {
+		body, _ := json.Marshal(c)
+		req := httptest.NewRequest(http.MethodPost, "/auth/tokens", bytes.NewReader(body))
+		req = req.WithContext(middleware.ContextWithUserID(req.Context(), uuid.New()))
+		rec := httptest.NewRecorder()
+
+		h.CreateAPIToken(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%+v: expected %d, got %d", c, http.StatusBadRequest, rec.Code)
+		}
+	}
+}
+
+func TestHandler_RevokeAPIToken_NotFound(t *testing.T) {
+	t.Parallel()
+
+	h, tokens, _ := NewAPITokensTestHandler(t)
+
+	userID := uuid.New()
+	tokenID := uuid.New()
+	tokens.EXPECT().
+		Revoke(gomock.Any(), userID, tokenID).
+		Return(serr.ErrNotFound)
+
+	r := chi.NewRouter()
+	r.Delete("/auth/tokens/{id}", h.RevokeAPIToken)
+
+	req := httptest.NewRequest(http.MethodDelete, "/auth/tokens/"+tokenID.String(), nil)
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+// токен только на чтение не может создавать секреты
+func TestHandler_CreateSecret_APITokenWithoutWriteScope(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewAPITokensTestHandler(t)
+
+	token := models.APIToken{UserID: uuid.New(), Scopes: []string{models.ScopeSecretsRead}}
+
+	body, _ := json.Marshal(api.CreateSecretRequest{Type: "text", Title: "t", Payload: "p"})
+	req := withAPIToken(httptest.NewRequest(http.MethodPost, "/secrets", bytes.NewReader(body)), token)
+	rec := httptest.NewRecorder()
+
+	h.CreateSecret(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, rec.Code)
+	}
+}
+
+// токен, ограниченный типами, видит только свои секреты
+func TestHandler_ListSecrets_APITokenFiltersTypes(t *testing.T) {
+	t.Parallel()
+
+	h, _, secrets := NewAPITokensTestHandler(t)
+
+	token := models.APIToken{
+		UserID: uuid.New(),
+		Scopes: []string{models.ScopeSecretsRead},
+		Types:  []string{"text"},
+	}
+	secrets.EXPECT().
+		ListSecrets(gomock.Any(), token.UserID).
+		Return([]sharedModels.Secret{
+			{ID: "1", Type: "text"},
+			{ID: "2", Type: "binary"},
+		}, nil)
+
+	req := withAPIToken(httptest.NewRequest(http.MethodGet, "/secrets", nil), token)
+	rec := httptest.NewRecorder()
+
+	h.ListSecrets(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var resp sharedModels.GetAllSecretsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Secrets) != 1 || resp.Secrets[0].ID != "1" {
+		t.Fatalf("unexpected secrets: %+v", resp.Secrets)
+	}
+}
+
+// секрет чужого для токена типа выглядит как отсутствующий
+func TestHandler_DeleteSecret_APITokenForeignType(t *testing.T) {
+	t.Parallel()
+
+	h, _, secrets := NewAPITokensTestHandler(t)
+
+	token := models.APIToken{
+		UserID: uuid.New(),
+		Scopes: []string{models.ScopeSecretsWrite},
+		Types:  []string{"text"},
+	}
+	secretID := uuid.New()
+	secrets.EXPECT().
+		GetType(gomock.Any(), token.UserID, secretID).
+		Return(service.SecretType("binary"), nil)
+
+	r := chi.NewRouter()
+	r.Delete("/secrets/{id}", h.DeleteSecret)
+
+	req := withAPIToken(httptest.NewRequest(http.MethodDelete, "/secrets/"+secretID.String()+"?version=1", nil), token)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+// смена типа на недоступный токену запрещена
+func TestHandler_UpdateSecret_APITokenTypeChange(t *testing.T) {
+	t.Parallel()
+
+	h, _, secrets := NewAPITokensTestHandler(t)
+
+	token := models.APIToken{
+		UserID: uuid.New(),
+		Scopes: []string{models.ScopeSecretsWrite},
+		Types:  []string{"text"},
+	}
+	secretID := uuid.New()
+	secrets.EXPECT().
+		GetType(gomock.Any(), token.UserID, secretID).
+		Return(service.SecretType("text"), nil)
+
+	typ := "binary"
+	body, _ := json.Marshal(api.UpdateSecretRequest{Type: &typ, Version: 1})
+
+	r := chi.NewRouter()
+	r.Put("/secrets/{id}", h.UpdateSecret)
+
+	req := withAPIToken(httptest.NewRequest(http.MethodPut, "/secrets/"+secretID.String(), bytes.NewReader(body)), token)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, rec.Code)
+	}
+}
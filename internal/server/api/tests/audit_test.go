@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	svcmocks "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+)
+
+// newAuditTestHandler возвращает хендлер с подключённым журналом аудита.
+func newAuditTestHandler(t *testing.T) (*api.Handler, *svcmocks.MockAuditRepo) {
+	t.Helper()
+	h, _, _ := NewTestHandler(t)
+	repo := svcmocks.NewMockAuditRepo(gomock.NewController(t))
+	h.Svc.Audit = service.NewAuditService(repo, config.AuditConfig{})
+	return h, repo
+}
+
+func TestHandler_ListAudit_Success(t *testing.T) {
+	t.Parallel()
+
+	h, repo := newAuditTestHandler(t)
+
+	userID, secretID := uuid.New(), uuid.New()
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	repo.EXPECT().
+		List(gomock.Any(), userID, models.AuditFilter{Since: since, Type: models.AuditSecretDeleted, Before: 50, Limit: 2}).
+		Return([]models.AuditEvent{
+			{ID: 42, Type: models.AuditSecretDeleted, IP: "10.0.0.1", UserAgent: "gophkeeper-cli", SecretID: secretID, CreatedAt: now},
+			{ID: 41, Type: models.AuditSecretDeleted, CreatedAt: now},
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/audit?since=2026-01-02T03:04:05Z&type=secret_deleted&limit=1&cursor=50", nil)
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	h.ListAudit(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var resp api.ListAuditResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Events) != 1 || resp.Events[0].SecretID != secretID.String() || resp.NextCursor != 42 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestHandler_ListAudit_BadQuery(t *testing.T) {
+	t.Parallel()
+
+	h, _ := newAuditTestHandler(t)
+
+	for _, q := range []string{"since=yesterday", "limit=abc", "cursor=-1", "type=unknown"} {
+		req := httptest.NewRequest(http.MethodGet, "/audit?"+q, nil)
+		req = req.WithContext(middleware.ContextWithUserID(req.Context(), uuid.New()))
+		rec := httptest.NewRecorder()
+
+		h.ListAudit(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected %d, got %d", q, http.StatusBadRequest, rec.Code)
+		}
+	}
+}
+
+func TestHandler_ListAudit_Disabled(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/audit", nil)
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), uuid.New()))
+	rec := httptest.NewRecorder()
+
+	h.ListAudit(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
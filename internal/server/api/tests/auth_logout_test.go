@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+func TestHandler_Logout_BadJSON(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", bytes.NewBufferString("{bad json"))
+	rec := httptest.NewRecorder()
+
+	h.Logout(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestHandler_Logout_Success(t *testing.T) {
+	t.Parallel()
+
+	h, _, sessions := NewTestHandler(t)
+
+	sessID := uuid.New()
+	userID := uuid.New()
+
+	sessions.EXPECT().
+		GetByRefreshHash(gomock.Any(), gomock.Any()).
+		Return(sessID, userID, time.Now().Add(time.Hour), nil, nil, uuid.New(), nil)
+	sessions.EXPECT().
+		Revoke(gomock.Any(), userID, sessID).
+		Return(nil)
+
+	body, _ := json.Marshal(api.LogoutRequest{RefreshToken: "refresh"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.Logout(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandler_Logout_UnknownToken(t *testing.T) {
+	t.Parallel()
+
+	h, _, sessions := NewTestHandler(t)
+
+	sessions.EXPECT().
+		GetByRefreshHash(gomock.Any(), gomock.Any()).
+		Return(uuid.Nil, uuid.Nil, time.Time{}, nil, nil, uuid.Nil, serr.ErrUnauthorized)
+
+	body, _ := json.Marshal(api.LogoutRequest{RefreshToken: "refresh", All: true})
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.Logout(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
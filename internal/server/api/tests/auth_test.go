@@ -192,9 +192,13 @@ func TestHandler_Login_Success(t *testing.T) {
 		Return(userID, hash, nil)
 
 	sessions.EXPECT().
-		Create(gomock.Any(), userID, gomock.Any(), gomock.Any()).
+		Create(gomock.Any(), userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(uuid.New(), nil)
 
+	sessions.EXPECT().
+		RevokeOldestActive(gomock.Any(), userID, 5).
+		Return(nil)
+
 	body, _ := json.Marshal(api.LoginRequest{Email: email, Password: password})
 	req := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewReader(body))
 	req.Header.Set(api.ContentType, api.JsonContentType)
@@ -262,7 +266,7 @@ func TestHandler_Refresh_Unauthorized_Expired(t *testing.T) {
 
 	sessions.EXPECT().
 		GetByRefreshHash(gomock.Any(), gomock.Any()).
-		Return(uuid.New(), uuid.New(), time.Now().Add(-1*time.Minute), nil, nil, nil)
+		Return(uuid.New(), uuid.New(), time.Now().Add(-1*time.Minute), nil, nil, uuid.New(), nil)
 
 	body, _ := json.Marshal(api.RefreshRequest{RefreshToken: refreshToken})
 	req := httptest.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewReader(body))
@@ -283,20 +287,25 @@ func TestHandler_Refresh_Success_RotateEnabled(t *testing.T) {
 	refreshToken := "some-refresh-token"
 	userID := uuid.New()
 	oldSessionID := uuid.New()
+	familyID := uuid.New()
 
 	sessions.EXPECT().
 		GetByRefreshHash(gomock.Any(), gomock.Any()).
-		Return(oldSessionID, userID, time.Now().Add(10*time.Minute), nil, nil, nil)
+		Return(oldSessionID, userID, time.Now().Add(10*time.Minute), nil, nil, familyID, nil)
 
 	newSessionID := uuid.New()
 	sessions.EXPECT().
-		Create(gomock.Any(), userID, gomock.Any(), gomock.Any()).
+		Create(gomock.Any(), userID, familyID, gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(newSessionID, nil)
 
 	sessions.EXPECT().
 		RevokeAndReplace(gomock.Any(), oldSessionID, newSessionID).
 		Return(nil)
 
+	sessions.EXPECT().
+		RevokeOldestActive(gomock.Any(), userID, 5).
+		Return(nil)
+
 	body, _ := json.Marshal(api.RefreshRequest{RefreshToken: refreshToken})
 	req := httptest.NewRequest(http.MethodPost, "/auth/refresh", bytes.NewReader(body))
 	rec := httptest.NewRecorder()
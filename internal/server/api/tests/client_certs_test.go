@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	svcmocks "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// NewClientCertsTestHandler — NewTestHandler с подключёнными клиентскими сертификатами
+func NewClientCertsTestHandler(t *testing.T) (*api.Handler, *svcmocks.MockUsersRepo, *svcmocks.MockSessionsRepo, *svcmocks.MockClientCertsRepo) {
+	t.Helper()
+
+	h, users, sessions := NewTestHandler(t)
+	certs := svcmocks.NewMockClientCertsRepo(gomock.NewController(t))
+	h.Svc.Auth.UseClientCerts(certs, x509.NewCertPool())
+	return h, users, sessions, certs
+}
+
+func TestHandler_ListClientCerts_OK(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, certs := NewClientCertsTestHandler(t)
+
+	userID := uuid.New()
+	certs.EXPECT().
+		ListByUser(gomock.Any(), userID).
+		Return([]models.ClientCert{{
+			ID:          uuid.New(),
+			UserID:      userID,
+			Name:        "laptop",
+			Subject:     "CN=laptop",
+			Fingerprint: []byte{0xab, 0xcd},
+			NotAfter:    time.Now().Add(time.Hour),
+		}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/certs", nil)
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	h.ListClientCerts(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+	var resp api.ListClientCertsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Certificates) != 1 || resp.Certificates[0].Fingerprint != "abcd" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+// сертификат не от CA сервера
+func TestHandler_BindClientCert_Invalid(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, _ := NewClientCertsTestHandler(t)
+
+	body, _ := json.Marshal(api.BindClientCertRequest{Certificate: "not a pem", Name: "laptop"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/certs", bytes.NewReader(body))
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), uuid.New()))
+	rec := httptest.NewRecorder()
+
+	h.BindClientCert(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+// mTLS на сервере выключен
+func TestHandler_BindClientCert_Disabled(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewTestHandler(t)
+
+	body, _ := json.Marshal(api.BindClientCertRequest{Certificate: "pem"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/certs", bytes.NewReader(body))
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), uuid.New()))
+	rec := httptest.NewRecorder()
+
+	h.BindClientCert(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestHandler_UnbindClientCert(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, certs := NewClientCertsTestHandler(t)
+
+	userID, certID := uuid.New(), uuid.New()
+	certs.EXPECT().
+		Delete(gomock.Any(), userID, certID).
+		Return(nil)
+	certs.EXPECT().
+		Delete(gomock.Any(), userID, gomock.Any()).
+		Return(serr.ErrNotFound)
+
+	r := chi.NewRouter()
+	r.Delete("/auth/certs/{id}", h.UnbindClientCert)
+
+	for _, tc := range []struct {
+		id   string
+		want int
+	}{
+		{certID.String(), http.StatusNoContent},
+		{uuid.NewString(), http.StatusNotFound},
+		{"not-a-uuid", http.StatusBadRequest},
+	} {
+		req := httptest.NewRequest(http.MethodDelete, "/auth/certs/"+tc.id, nil)
+		req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+		rec := httptest.NewRecorder()
+
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != tc.want {
+			t.Fatalf("%s: expected %d, got %d", tc.id, tc.want, rec.Code)
+		}
+	}
+}
+
+// отпечаток сертификата из TLS-соединения доходит до сервиса, без привязанного — 401
+func TestHandler_Login_ClientCertRequired(t *testing.T) {
+	t.Parallel()
+
+	h, users, _, certs := NewClientCertsTestHandler(t)
+
+	userID := uuid.New()
+	hash, err := crypto.HashPassword("StrongPass123", crypto.Argon2Params{
+		Time:      1,
+		MemoryKiB: 64 * 1024,
+		Threads:   1,
+		KeyLen:    32,
+		SaltLen:   16,
+	})
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	peer := &x509.Certificate{Raw: []byte("other-cert")}
+
+	users.EXPECT().
+		GetByEmail(gomock.Any(), "test@example.com").
+		Return(userID, hash, nil)
+	certs.EXPECT().
+		Match(gomock.Any(), userID, crypto.CertFingerprint(peer)).
+		Return(true, false, nil)
+
+	body, _ := json.Marshal(api.LoginRequest{Email: "test@example.com", Password: "StrongPass123"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewReader(body))
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{peer}}
+	rec := httptest.NewRecorder()
+
+	h.ClientInfoMiddleware()(http.HandlerFunc(h.Login)).ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusUnauthorized, rec.Code, rec.Body.String())
+	}
+	if !bytes.Contains(rec.Body.Bytes(), []byte(serr.ErrClientCertRequired.Error())) {
+		t.Fatalf("unexpected body %q", rec.Body.String())
+	}
+}
@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	svcmocks "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// NewDeviceTestHandler — NewTestHandler с подключённым хранилищем кодов устройств
+func NewDeviceTestHandler(t *testing.T) (*api.Handler, *svcmocks.MockSessionsRepo, *svcmocks.MockDeviceCodesRepo) {
+	t.Helper()
+
+	h, _, sessions := NewTestHandler(t)
+	devices := svcmocks.NewMockDeviceCodesRepo(gomock.NewController(t))
+	h.Svc.Auth.UseDeviceCodes(devices)
+	return h, sessions, devices
+}
+
+func TestHandler_DeviceCode_OK(t *testing.T) {
+	t.Parallel()
+
+	h, _, devices := NewDeviceTestHandler(t)
+
+	devices.EXPECT().
+		Create(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/device/code", nil)
+	rec := httptest.NewRecorder()
+
+	h.DeviceCode(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var resp api.DeviceCodeResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.DeviceCode == "" || len(resp.UserCode) != 9 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+// пока код не подтверждён — 400 с кодом ошибки из RFC 8628
+func TestHandler_DeviceToken_Pending(t *testing.T) {
+	t.Parallel()
+
+	h, _, devices := NewDeviceTestHandler(t)
+
+	devices.EXPECT().
+		Poll(gomock.Any(), gomock.Any()).
+		Return(models.DeviceCode{ID: uuid.New(), ExpiresAt: time.Now().Add(time.Minute)}, nil)
+
+	body, _ := json.Marshal(api.DeviceTokenRequest{DeviceCode: "device-code"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/device/token", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.DeviceToken(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	var resp api.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Error != "authorization_pending" {
+		t.Fatalf("expected authorization_pending, got %q", resp.Error)
+	}
+}
+
+func TestHandler_DeviceToken_Approved(t *testing.T) {
+	t.Parallel()
+
+	h, sessions, devices := NewDeviceTestHandler(t)
+
+	id := uuid.New()
+	userID := uuid.New()
+	devices.EXPECT().
+		Poll(gomock.Any(), gomock.Any()).
+		Return(models.DeviceCode{ID: id, UserID: userID, ExpiresAt: time.Now().Add(time.Minute), Approved: true}, nil)
+	devices.EXPECT().
+		Consume(gomock.Any(), id).
+		Return(nil)
+	sessions.EXPECT().
+		Create(gomock.Any(), userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+	sessions.EXPECT().
+		RevokeOldestActive(gomock.Any(), userID, 5).
+		Return(nil)
+
+	body, _ := json.Marshal(api.DeviceTokenRequest{DeviceCode: "device-code"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/device/token", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.DeviceToken(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var resp api.LoginResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.AccessToken == "" || resp.RefreshToken == "" {
+		t.Fatalf("expected non-empty tokens, got %+v", resp)
+	}
+}
+
+func TestHandler_ApproveDevice(t *testing.T) {
+	t.Parallel()
+
+	h, _, devices := NewDeviceTestHandler(t)
+
+	userID := uuid.New()
+	devices.EXPECT().
+		Decide(gomock.Any(), "BCDF-GHJK", userID, true).
+		Return(nil)
+
+	body, _ := json.Marshal(api.DeviceDecisionRequest{UserCode: "bcdf-ghjk"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/device/approve", bytes.NewReader(body))
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	h.ApproveDevice(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+}
+
+// истёкший или уже использованный код
+func TestHandler_DenyDevice_NotFound(t *testing.T) {
+	t.Parallel()
+
+	h, _, devices := NewDeviceTestHandler(t)
+
+	userID := uuid.New()
+	devices.EXPECT().
+		Decide(gomock.Any(), "BCDF-GHJK", userID, false).
+		Return(serr.ErrNotFound)
+
+	body, _ := json.Marshal(api.DeviceDecisionRequest{UserCode: "BCDF-GHJK"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/device/deny", bytes.NewReader(body))
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	h.DenyDevice(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+func TestHandler_ApproveDevice_Unauthorized(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewDeviceTestHandler(t)
+
+	body, _ := json.Marshal(api.DeviceDecisionRequest{UserCode: "BCDF-GHJK"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/device/approve", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.ApproveDevice(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
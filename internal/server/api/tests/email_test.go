@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/mailer"
+	svcmocks "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// discardMailer принимает письма и никуда их не отправляет.
+type discardMailer struct{ sent int }
+
+func (m *discardMailer) Send(context.Context, mailer.Message) error {
+	m.sent++
+	return nil
+}
+
+// newMailTestHandler возвращает хендлер с подключёнными письмами.
+func newMailTestHandler(t *testing.T) (*api.Handler, *svcmocks.MockUsersRepo, *svcmocks.MockSessionsRepo, *svcmocks.MockEmailTokensRepo, *discardMailer) {
+	t.Helper()
+	h, users, sessions := NewTestHandler(t)
+	tokens := svcmocks.NewMockEmailTokensRepo(gomock.NewController(t))
+	m := &discardMailer{}
+	h.Svc.Auth.UseMailer(tokens, m, nil)
+	return h, users, sessions, tokens, m
+}
+
+func postJSON(t *testing.T, handler http.HandlerFunc, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	raw, _ := json.Marshal(body)
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(raw)))
+	return rec
+}
+
+func TestHandler_VerifyEmail_Success(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, tokens, _ := newMailTestHandler(t)
+
+	userID := uuid.New()
+	tokens.EXPECT().Consume(gomock.Any(), models.EmailTokenVerify, gomock.Any()).Return(userID, nil)
+	tokens.EXPECT().MarkVerified(gomock.Any(), userID).Return(nil)
+
+	rec := postJSON(t, h.VerifyEmail, "/auth/email/verify", api.EmailTokenRequest{Token: "tok"})
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandler_VerifyEmail_InvalidToken(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, tokens, _ := newMailTestHandler(t)
+
+	tokens.EXPECT().Consume(gomock.Any(), models.EmailTokenVerify, gomock.Any()).Return(uuid.Nil, serr.ErrNotFound)
+
+	rec := postJSON(t, h.VerifyEmail, "/auth/email/verify", api.EmailTokenRequest{Token: "used"})
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusBadRequest, rec.Code, rec.Body.String())
+	}
+}
+
+// Без mail.driver эндпоинты недоступны
+func TestHandler_VerifyEmail_MailDisabled(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewTestHandler(t)
+
+	rec := postJSON(t, h.VerifyEmail, "/auth/email/verify", api.EmailTokenRequest{Token: "tok"})
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusNotFound, rec.Code, rec.Body.String())
+	}
+}
+
+// Неизвестный адрес неотличим от зарегистрированного
+func TestHandler_RequestPasswordReset_UnknownEmail(t *testing.T) {
+	t.Parallel()
+
+	h, users, _, _, m := newMailTestHandler(t)
+
+	users.EXPECT().GetByEmail(gomock.Any(), "nobody@example.com").Return(uuid.Nil, "", serr.ErrNotFound)
+
+	rec := postJSON(t, h.RequestPasswordReset, "/auth/password/reset/request", api.EmailRequest{Email: "nobody@example.com"})
+	h.Svc.Auth.WaitMail()
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusAccepted, rec.Code, rec.Body.String())
+	}
+	if m.sent != 0 {
+		t.Fatalf("expected no mail, got %d", m.sent)
+	}
+}
+
+func TestHandler_ResetPassword_Success(t *testing.T) {
+	t.Parallel()
+
+	h, users, sessions, tokens, _ := newMailTestHandler(t)
+
+	userID := uuid.New()
+	tokens.EXPECT().Consume(gomock.Any(), models.EmailTokenReset, gomock.Any()).Return(userID, nil)
+	users.EXPECT().UpdatePasswordHash(gomock.Any(), userID, gomock.Any()).Return(nil)
+	sessions.EXPECT().RevokeAllForUser(gomock.Any(), userID).Return(nil)
+	tokens.EXPECT().MarkVerified(gomock.Any(), userID).Return(nil)
+	tokens.EXPECT().GetEmail(gomock.Any(), userID).Return("user@example.com", true, nil)
+
+	rec := postJSON(t, h.ResetPassword, "/auth/password/reset", api.ResetPasswordRequest{Token: "tok", NewPassword: "NewPass123"})
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandler_ResetPassword_BadJSON(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, _, _ := newMailTestHandler(t)
+
+	rec := httptest.NewRecorder()
+	h.ResetPassword(rec, httptest.NewRequest(http.MethodPost, "/auth/password/reset", bytes.NewReader([]byte("{"))))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
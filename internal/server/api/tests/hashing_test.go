@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+)
+
+// Очередь хэширования переполнена — 503 с Retry-After
+func TestHandler_Register_HashingBusy(t *testing.T) {
+	t.Parallel()
+
+	h, users, sessions := NewTestHandler(t)
+
+	cfg := &config.Config{
+		Password: config.PasswordConfig{
+			Hasher: "argon2id",
+			Argon2: config.Argon2Config{Time: 10, MemoryKiB: 64 * 1024, Threads: 1, KeyLen: 32, SaltLen: 16},
+			Limits: config.PasswordLimitsConfig{MemoryBudgetMiB: 64, QueueTimeout: 10 * time.Millisecond},
+		},
+	}
+	auth := service.NewAuthService(users, sessions, cfg)
+	h.Svc = &service.Services{Auth: auth}
+
+	// первая регистрация занимает весь бюджет
+	users.EXPECT().
+		Create(gomock.Any(), "first@example.com", gomock.Any()).
+		Return(uuid.New(), nil)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = auth.Register(context.Background(), "first@example.com", "StrongPass123", "")
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for auth.HashingStats().InUseKiB == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("hashing did not start")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	body, _ := json.Marshal(api.RegisterRequest{Email: "second@example.com", Password: "StrongPass123"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/register", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.Register(rec, req)
+	<-done
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusServiceUnavailable, rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Retry-After"); got != "1" {
+		t.Fatalf("expected Retry-After 1, got %q", got)
+	}
+}
@@ -0,0 +1,191 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	svcmocks "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// NewInvitesTestHandler — Handler в режиме регистрации по приглашениям,
+// администратор приглашений — admin@example.com
+func NewInvitesTestHandler(t *testing.T, registration string) (*api.Handler, *svcmocks.MockUsersRepo, *svcmocks.MockInvitesRepo) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+
+	cfg := &config.Config{
+		Auth: config.AuthConfig{
+			Registration: registration,
+			Invites: config.InvitesConfig{
+				Admins:     []string{"Admin@example.com"},
+				DefaultTTL: 24 * time.Hour,
+				MaxTTL:     48 * time.Hour,
+				MaxUses:    10,
+			},
+		},
+		Password: config.PasswordConfig{
+			Argon2: config.Argon2Config{Time: 1, MemoryKiB: 8 * 1024, Threads: 1, KeyLen: 32, SaltLen: 16},
+		},
+	}
+
+	users := svcmocks.NewMockUsersRepo(ctrl)
+	invites := svcmocks.NewMockInvitesRepo(ctrl)
+
+	auth := service.NewAuthService(users, svcmocks.NewMockSessionsRepo(ctrl), cfg)
+	auth.UseInvites(invites)
+
+	return api.NewHandler(&service.Services{Auth: auth}, nil, nil), users, invites
+}
+
+func postRegister(h *api.Handler, req api.RegisterRequest) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(req)
+	rec := httptest.NewRecorder()
+	h.Register(rec, httptest.NewRequest(http.MethodPost, "/auth/register", bytes.NewReader(body)))
+	return rec
+}
+
+func TestHandler_Register_WithInvite(t *testing.T) {
+	t.Parallel()
+
+	h, _, invites := NewInvitesTestHandler(t, config.RegistrationInvite)
+
+	userID := uuid.New()
+	invites.EXPECT().Check(gomock.Any(), gomock.Any()).Return(nil)
+	invites.EXPECT().
+		CreateUser(gomock.Any(), gomock.Any(), "new@example.com", gomock.Any()).
+		Return(userID, nil)
+
+	rec := postRegister(h, api.RegisterRequest{Email: "new@example.com", Password: "StrongPass123", Invite: "code"})
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+}
+
+func TestHandler_Register_InviteRejected(t *testing.T) {
+	t.Parallel()
+
+	h, _, invites := NewInvitesTestHandler(t, config.RegistrationInvite)
+
+	invites.EXPECT().Check(gomock.Any(), gomock.Any()).Return(serr.ErrInvalidInvite)
+
+	// неверный код
+	if rec := postRegister(h, api.RegisterRequest{Email: "new@example.com", Password: "StrongPass123", Invite: "bad"}); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, rec.Code)
+	}
+	// без кода
+	if rec := postRegister(h, api.RegisterRequest{Email: "new@example.com", Password: "StrongPass123"}); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, rec.Code)
+	}
+}
+
+func TestHandler_Register_Closed(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewInvitesTestHandler(t, config.RegistrationClosed)
+
+	rec := postRegister(h, api.RegisterRequest{Email: "new@example.com", Password: "StrongPass123", Invite: "code"})
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, rec.Code)
+	}
+}
+
+func TestHandler_CreateInvite_OK(t *testing.T) {
+	t.Parallel()
+
+	h, users, invites := NewInvitesTestHandler(t, config.RegistrationInvite)
+
+	adminID := uuid.New()
+	users.EXPECT().
+		GetProfile(gomock.Any(), adminID).
+		Return(models.Profile{ID: adminID, Email: "admin@example.com"}, nil)
+	invites.EXPECT().
+		Create(gomock.Any(), adminID, gomock.Any(), 3, gomock.Any()).
+		DoAndReturn(func(_ any, _ uuid.UUID, _ []byte, maxUses int, expiresAt time.Time) (models.Invite, error) {
+			return models.Invite{ID: uuid.New(), CreatedBy: adminID, MaxUses: maxUses, ExpiresAt: expiresAt}, nil
+		})
+
+	body, _ := json.Marshal(api.CreateInviteRequest{MaxUses: 3, ExpiresIn: 3600})
+	req := httptest.NewRequest(http.MethodPost, "/auth/invites", bytes.NewReader(body))
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), adminID))
+	rec := httptest.NewRecorder()
+
+	h.CreateInvite(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	var resp api.CreateInviteResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Code == "" || resp.MaxUses != 3 || resp.CreatedBy != adminID.String() {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestHandler_CreateInvite_NotAdmin(t *testing.T) {
+	t.Parallel()
+
+	h, users, _ := NewInvitesTestHandler(t, config.RegistrationInvite)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetProfile(gomock.Any(), userID).
+		Return(models.Profile{ID: userID, Email: "user@example.com"}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/invites", bytes.NewBufferString(`{}`))
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	h.CreateInvite(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected %d, got %d", http.StatusForbidden, rec.Code)
+	}
+}
+
+func TestHandler_RevokeInvite_NotFound(t *testing.T) {
+	t.Parallel()
+
+	h, users, invites := NewInvitesTestHandler(t, config.RegistrationInvite)
+
+	adminID := uuid.New()
+	inviteID := uuid.New()
+	users.EXPECT().
+		GetProfile(gomock.Any(), adminID).
+		Return(models.Profile{ID: adminID, Email: "admin@example.com"}, nil)
+	invites.EXPECT().
+		Delete(gomock.Any(), inviteID).
+		Return(serr.ErrNotFound)
+
+	r := chi.NewRouter()
+	r.Delete("/auth/invites/{id}", h.RevokeInvite)
+
+	req := httptest.NewRequest(http.MethodDelete, "/auth/invites/"+inviteID.String(), nil)
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), adminID))
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
@@ -0,0 +1,61 @@
+package tests
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+)
+
+func TestHandler_JWKS_PublishesPublicKeys(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	ks, err := crypto.NewKeySet(&crypto.SigningKey{ID: "k1", Alg: crypto.AlgEdDSA, Private: priv, Public: pub})
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+
+	h := api.NewHandler(nil, nil, middleware.NewKeySetVerifier(ks, "", ""))
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+
+	h.JWKS(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var set crypto.JWKS
+	if err := json.NewDecoder(rec.Body).Decode(&set); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(set.Keys) != 1 || set.Keys[0].Kid != "k1" || set.Keys[0].Alg != "EdDSA" {
+		t.Fatalf("unexpected jwks: %+v", set)
+	}
+}
+
+func TestHandler_JWKS_EmptyForHS256(t *testing.T) {
+	t.Parallel()
+
+	h := api.NewHandler(nil, nil, middleware.NewJWTVerifier("secret", "", ""))
+
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+
+	h.JWKS(rec, req)
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "{\"keys\":[]}\n" {
+		t.Fatalf("unexpected response: %d %q", rec.Code, rec.Body.String())
+	}
+}
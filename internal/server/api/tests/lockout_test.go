@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	svcmocks "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+)
+
+// Во время блокировки логин отвечает 429 с Retry-After в секундах
+func TestHandler_Login_LockedOut(t *testing.T) {
+	t.Parallel()
+
+	h, users, sessions := NewTestHandler(t)
+
+	cfg := &config.Config{
+		Auth: config.AuthConfig{
+			Lockout: config.LockoutConfig{
+				Enabled:     true,
+				MaxFailures: 5,
+				BaseDelay:   time.Second,
+				MaxDelay:    30 * time.Second,
+				Duration:    15 * time.Minute,
+			},
+		},
+	}
+	auth := service.NewAuthService(users, sessions, cfg)
+	attempts := svcmocks.NewMockLoginAttemptsRepo(gomock.NewController(t))
+	auth.UseLoginAttempts(attempts)
+	h.Svc = &service.Services{Auth: auth}
+
+	attempts.EXPECT().
+		Get(gomock.Any(), "test@example.com").
+		Return(5, time.Now(), nil)
+
+	body, _ := json.Marshal(api.LoginRequest{Email: "test@example.com", Password: "StrongPass123"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.Login(rec, req)
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected %d, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+	retry, err := strconv.Atoi(rec.Header().Get("Retry-After"))
+	if err != nil {
+		t.Fatalf("bad Retry-After %q: %v", rec.Header().Get("Retry-After"), err)
+	}
+	if retry < 899 || retry > 900 {
+		t.Fatalf("expected Retry-After ~900, got %d", retry)
+	}
+}
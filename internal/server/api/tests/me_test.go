@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+func TestHandler_Me_Success(t *testing.T) {
+	t.Parallel()
+
+	h, users, _ := NewTestHandler(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetProfile(gomock.Any(), userID).
+		Return(models.Profile{
+			ID:             userID,
+			Email:          "test@example.com",
+			CreatedAt:      time.Now(),
+			SecretCount:    3,
+			StorageBytes:   1024,
+			ActiveSessions: 2,
+		}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	h.Me(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var resp api.MeResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.UserID != userID.String() || resp.Email != "test@example.com" ||
+		resp.SecretCount != 3 || resp.StorageBytes != 1024 || resp.ActiveSessions != 2 {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestHandler_Me_Unauthorized(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	rec := httptest.NewRecorder()
+
+	h.Me(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestHandler_Me_NotFound(t *testing.T) {
+	t.Parallel()
+
+	h, users, _ := NewTestHandler(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetProfile(gomock.Any(), userID).
+		Return(models.Profile{}, serr.ErrNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	h.Me(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	svcmocks "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+)
+
+// NewOIDCTestHandler — хендлер со входом через локальный stub-провайдер.
+// Возвращает функцию, выпускающую ID-токен провайдера для subject.
+func NewOIDCTestHandler(t *testing.T) (*api.Handler, *svcmocks.MockSessionsRepo, *svcmocks.MockOIDCIdentitiesRepo, string, func(subject string) string) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	idp := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(crypto.JWKS{Keys: []crypto.JWK{{
+			Kty: "OKP", Crv: "Ed25519", Kid: "k1", Alg: "EdDSA", Use: "sig",
+			X: base64.RawURLEncoding.EncodeToString(pub),
+		}}})
+	}))
+	t.Cleanup(idp.Close)
+
+	h, _, sessions := NewTestHandler(t)
+	identities := svcmocks.NewMockOIDCIdentitiesRepo(gomock.NewController(t))
+	h.Svc.Auth.UseOIDC(identities, crypto.NewOIDCVerifier(crypto.OIDCConfig{
+		Issuer:     idp.URL,
+		ClientID:   "gophkeeper",
+		JWKSURL:    idp.URL,
+		CacheTTL:   time.Hour,
+		HTTPClient: idp.Client(),
+	}), false)
+
+	issue := func(subject string) string {
+		tok := jwt.NewWithClaims(jwt.SigningMethodEdDSA, crypto.IDTokenClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    idp.URL,
+				Subject:   subject,
+				Audience:  jwt.ClaimStrings{"gophkeeper"},
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+			Email:         "alice@corp.example",
+			EmailVerified: true,
+		})
+		tok.Header["kid"] = "k1"
+		raw, err := tok.SignedString(priv)
+		if err != nil {
+			t.Fatalf("sign: %v", err)
+		}
+		return raw
+	}
+	return h, sessions, identities, idp.URL, issue
+}
+
+func postOIDC(h *api.Handler, idToken string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(api.OIDCLoginRequest{IDToken: idToken})
+	rec := httptest.NewRecorder()
+	h.LoginOIDC(rec, httptest.NewRequest(http.MethodPost, "/auth/oidc", bytes.NewReader(body)))
+	return rec
+}
+
+func TestHandler_LoginOIDC_OK(t *testing.T) {
+	t.Parallel()
+
+	h, sessions, identities, issuer, issue := NewOIDCTestHandler(t)
+
+	userID := uuid.New()
+	identities.EXPECT().
+		GetUser(gomock.Any(), issuer, "sub-1").
+		Return(userID, nil)
+	sessions.EXPECT().
+		Create(gomock.Any(), userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+	sessions.EXPECT().
+		RevokeOldestActive(gomock.Any(), userID, 5).
+		Return(nil)
+
+	rec := postOIDC(h, issue("sub-1"))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var resp api.LoginResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.AccessToken == "" || resp.RefreshToken == "" {
+		t.Fatalf("expected tokens, got %+v", resp)
+	}
+}
+
+func TestHandler_LoginOIDC_InvalidToken(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, _, _ := NewOIDCTestHandler(t)
+
+	if rec := postOIDC(h, "not-a-jwt"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+	if rec := postOIDC(h, " "); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestHandler_LoginOIDC_Disabled(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewTestHandler(t)
+
+	if rec := postOIDC(h, "id-token"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+)
+
+func hashForHandlerTest(t *testing.T, password string) string {
+	t.Helper()
+
+	hash, err := crypto.HashPassword(password, crypto.Argon2Params{
+		Time:      1,
+		MemoryKiB: 64 * 1024,
+		Threads:   1,
+		KeyLen:    32,
+		SaltLen:   16,
+	})
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+	return hash
+}
+
+func TestHandler_ChangePassword_Success(t *testing.T) {
+	t.Parallel()
+
+	h, users, sessions := NewTestHandler(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetPasswordHash(gomock.Any(), userID).
+		Return(hashForHandlerTest(t, "OldPass123"), nil)
+	users.EXPECT().
+		UpdatePasswordHash(gomock.Any(), userID, gomock.Any()).
+		Return(nil)
+	sessions.EXPECT().
+		RevokeAllForUser(gomock.Any(), userID).
+		Return(nil)
+	sessions.EXPECT().
+		Create(gomock.Any(), userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+	sessions.EXPECT().
+		RevokeOldestActive(gomock.Any(), userID, 5).
+		Return(nil)
+
+	body, _ := json.Marshal(api.ChangePasswordRequest{OldPassword: "OldPass123", NewPassword: "NewPass123"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/password", bytes.NewReader(body))
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	h.ChangePassword(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var resp api.LoginResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.AccessToken == "" || resp.RefreshToken == "" {
+		t.Fatalf("expected non-empty tokens, got %+v", resp)
+	}
+}
+
+func TestHandler_ChangePassword_WrongOldPassword(t *testing.T) {
+	t.Parallel()
+
+	h, users, _ := NewTestHandler(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetPasswordHash(gomock.Any(), userID).
+		Return(hashForHandlerTest(t, "OldPass123"), nil)
+
+	body, _ := json.Marshal(api.ChangePasswordRequest{OldPassword: "WrongPass123", NewPassword: "NewPass123"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/password", bytes.NewReader(body))
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	h.ChangePassword(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestHandler_ChangePassword_WeakNewPassword(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewTestHandler(t)
+
+	body, _ := json.Marshal(api.ChangePasswordRequest{OldPassword: "OldPass123", NewPassword: "short"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/password", bytes.NewReader(body))
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), uuid.New()))
+	rec := httptest.NewRecorder()
+
+	h.ChangePassword(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestHandler_ChangePassword_Unauthorized(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/password", bytes.NewBufferString(`{}`))
+	rec := httptest.NewRecorder()
+
+	h.ChangePassword(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+)
+
+func TestHandler_Challenge_OK(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	h, _, _ := NewTestHandler(t)
+	h.PoW = middleware.NewProofOfWork(middleware.PoWOptions{
+		Key:            key,
+		ChallengeTTL:   time.Minute,
+		Window:         time.Minute,
+		Threshold:      1,
+		BaseDifficulty: 8,
+		MaxDifficulty:  8,
+	})
+	h.PoW.Record()
+
+	rec := httptest.NewRecorder()
+	h.Challenge(rec, httptest.NewRequest(http.MethodGet, "/auth/challenge", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+	var resp api.ChallengeResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Difficulty != 8 {
+		t.Fatalf("expected difficulty 8, got %d", resp.Difficulty)
+	}
+	if _, err := crypto.ParsePoWChallenge(key, resp.Challenge, time.Now()); err != nil {
+		t.Fatalf("challenge is not signed by server: %v", err)
+	}
+}
+
+func TestHandler_Challenge_Disabled(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewTestHandler(t)
+
+	rec := httptest.NewRecorder()
+	h.Challenge(rec, httptest.NewRequest(http.MethodGet, "/auth/challenge", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
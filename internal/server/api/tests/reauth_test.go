@@ -0,0 +1,101 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+)
+
+func reauthRequest(t *testing.T, userID uuid.UUID, withSession bool, body api.ReauthRequest) *http.Request {
+	t.Helper()
+
+	raw, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, "/auth/reauth", bytes.NewReader(raw))
+	ctx := middleware.ContextWithUserID(req.Context(), userID)
+	if withSession {
+		ctx = middleware.ContextWithSessionID(ctx, uuid.New())
+	}
+	return req.WithContext(ctx)
+}
+
+func TestHandler_Reauth_Success(t *testing.T) {
+	t.Parallel()
+
+	h, users, _ := NewTestHandler(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetPasswordHash(gomock.Any(), userID).
+		Return(hashForHandlerTest(t, "StrongPass123"), nil)
+
+	rec := httptest.NewRecorder()
+	h.Reauth(rec, reauthRequest(t, userID, true, api.ReauthRequest{Password: "StrongPass123"}))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var resp api.ReauthResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.AccessToken == "" {
+		t.Fatal("expected access token")
+	}
+	if !resp.ElevatedUntil.After(time.Now()) {
+		t.Fatalf("expected elevated_until in the future, got %v", resp.ElevatedUntil)
+	}
+}
+
+func TestHandler_Reauth_WrongPassword(t *testing.T) {
+	t.Parallel()
+
+	h, users, _ := NewTestHandler(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetPasswordHash(gomock.Any(), userID).
+		Return(hashForHandlerTest(t, "StrongPass123"), nil)
+
+	rec := httptest.NewRecorder()
+	h.Reauth(rec, reauthRequest(t, userID, true, api.ReauthRequest{Password: "WrongPass123"}))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+// токен без sid повысить нельзя
+func TestHandler_Reauth_NoSession(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewTestHandler(t)
+
+	rec := httptest.NewRecorder()
+	h.Reauth(rec, reauthRequest(t, uuid.New(), false, api.ReauthRequest{Password: "StrongPass123"}))
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestHandler_Reauth_EmptyPassword(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewTestHandler(t)
+
+	rec := httptest.NewRecorder()
+	h.Reauth(rec, reauthRequest(t, uuid.New(), true, api.ReauthRequest{}))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
@@ -0,0 +1,116 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+func TestHandler_ListSessions_Success(t *testing.T) {
+	t.Parallel()
+
+	h, _, sessions := NewTestHandler(t)
+
+	userID := uuid.New()
+	sessID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	sessions.EXPECT().
+		ListActive(gomock.Any(), userID).
+		Return([]models.Session{{
+			ID:         sessID,
+			DeviceName: "laptop",
+			UserAgent:  "gophkeeper-cli",
+			IP:         "10.0.0.1",
+			CreatedAt:  now,
+			ExpiresAt:  now.Add(time.Hour),
+		}}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/sessions", nil)
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	h.ListSessions(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var resp api.ListSessionsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Sessions) != 1 || resp.Sessions[0].ID != sessID.String() || resp.Sessions[0].DeviceName != "laptop" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestHandler_ListSessions_Unauthorized(t *testing.T) {
+	t.Parallel()
+
+	h, _, _ := NewTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/sessions", nil)
+	rec := httptest.NewRecorder()
+
+	h.ListSessions(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestHandler_RevokeSession(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	sessID := uuid.New()
+
+	tests := []struct {
+		name   string
+		id     string
+		repo   error
+		expect bool
+		want   int
+	}{
+		{name: "ok", id: sessID.String(), expect: true, want: http.StatusNoContent},
+		{name: "not found", id: sessID.String(), repo: serr.ErrNotFound, expect: true, want: http.StatusNotFound},
+		{name: "bad id", id: "not-a-uuid", want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, sessions := NewTestHandler(t)
+
+			if tt.expect {
+				sessions.EXPECT().
+					Revoke(gomock.Any(), userID, sessID).
+					Return(tt.repo)
+			}
+
+			r := chi.NewRouter()
+			r.Delete("/auth/sessions/{id}", h.RevokeSession)
+
+			req := httptest.NewRequest(http.MethodDelete, "/auth/sessions/"+tt.id, nil)
+			req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+			rec := httptest.NewRecorder()
+
+			r.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("expected %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	svcmocks "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// NewTwoFactorTestHandler — NewTestHandler с подключённым хранилищем 2FA
+func NewTwoFactorTestHandler(t *testing.T) (*api.Handler, *svcmocks.MockUsersRepo, *svcmocks.MockSessionsRepo, *svcmocks.MockTwoFactorRepo) {
+	t.Helper()
+
+	h, users, sessions := NewTestHandler(t)
+	twoFactor := svcmocks.NewMockTwoFactorRepo(gomock.NewController(t))
+	h.Svc.Auth.UseTwoFactor(twoFactor)
+	return h, users, sessions, twoFactor
+}
+
+func TestHandler_Login_MFARequired(t *testing.T) {
+	t.Parallel()
+
+	h, users, _, twoFactor := NewTwoFactorTestHandler(t)
+
+	email := "test@example.com"
+	password := "StrongPass123"
+	userID := uuid.New()
+
+	hash, err := crypto.HashPassword(password, crypto.Argon2Params{
+		Time:      1,
+		MemoryKiB: 64 * 1024,
+		Threads:   1,
+		KeyLen:    32,
+		SaltLen:   16,
+	})
+	if err != nil {
+		t.Fatalf("HashPassword: %v", err)
+	}
+
+	users.EXPECT().
+		GetByEmail(gomock.Any(), email).
+		Return(userID, hash, nil)
+	twoFactor.EXPECT().
+		GetTOTP(gomock.Any(), userID).
+		Return("SECRET", true, nil)
+	twoFactor.EXPECT().
+		CreateChallenge(gomock.Any(), userID, gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	body, _ := json.Marshal(api.LoginRequest{Email: email, Password: password})
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", bytes.NewReader(body))
+	req.Header.Set(api.ContentType, api.JsonContentType)
+	rec := httptest.NewRecorder()
+
+	h.Login(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusAccepted, rec.Code, rec.Body.String())
+	}
+
+	var resp api.MFAChallengeResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if !resp.MFARequired || resp.MFAToken == "" || resp.ExpiresAt.Before(time.Now()) {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestHandler_VerifyMFA_Success(t *testing.T) {
+	t.Parallel()
+
+	h, _, sessions, twoFactor := NewTwoFactorTestHandler(t)
+
+	userID := uuid.New()
+	secret, _ := crypto.NewTOTPSecret()
+	code, _ := crypto.TOTPCode(secret, crypto.TOTPStep(time.Now()))
+
+	twoFactor.EXPECT().
+		ChallengeAttempt(gomock.Any(), crypto.HashRefreshToken("mfa-token"), gomock.Any()).
+		Return(userID, nil)
+	twoFactor.EXPECT().
+		GetTOTP(gomock.Any(), userID).
+		Return(secret, true, nil)
+	twoFactor.EXPECT().
+		UseTOTPStep(gomock.Any(), userID, gomock.Any()).
+		Return(nil)
+	twoFactor.EXPECT().
+		CompleteChallenge(gomock.Any(), gomock.Any()).
+		Return(nil)
+	sessions.EXPECT().
+		Create(gomock.Any(), userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+	sessions.EXPECT().
+		RevokeOldestActive(gomock.Any(), userID, 5).
+		Return(nil)
+
+	body, _ := json.Marshal(api.VerifyMFARequest{MFAToken: "mfa-token", Code: code})
+	req := httptest.NewRequest(http.MethodPost, "/auth/2fa/verify", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.VerifyMFA(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d, body=%q", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var resp api.LoginResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.AccessToken == "" || resp.RefreshToken == "" {
+		t.Fatalf("expected non-empty tokens, got %+v", resp)
+	}
+}
+
+func TestHandler_VerifyMFA_InvalidCode(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, twoFactor := NewTwoFactorTestHandler(t)
+
+	userID := uuid.New()
+	twoFactor.EXPECT().
+		ChallengeAttempt(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(userID, nil)
+	twoFactor.EXPECT().
+		UseRecoveryCode(gomock.Any(), userID, gomock.Any()).
+		Return(serr.ErrNotFound)
+
+	body, _ := json.Marshal(api.VerifyMFARequest{MFAToken: "mfa-token", Code: "aaaaa-bbbbb"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/2fa/verify", bytes.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.VerifyMFA(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestHandler_EnrollTOTP_Unauthorized(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, _ := NewTwoFactorTestHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/2fa/enroll", nil)
+	rec := httptest.NewRecorder()
+
+	h.EnrollTOTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+}
+
+func TestHandler_EnrollTOTP_AlreadyEnabled(t *testing.T) {
+	t.Parallel()
+
+	h, users, _, twoFactor := NewTwoFactorTestHandler(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetProfile(gomock.Any(), userID).
+		Return(models.Profile{ID: userID, Email: "test@example.com"}, nil)
+	twoFactor.EXPECT().
+		SaveTOTPSecret(gomock.Any(), userID, gomock.Any()).
+		Return(serr.ErrAlreadyExists)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/2fa/enroll", nil)
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	h.EnrollTOTP(rec, req)
+
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected %d, got %d", http.StatusConflict, rec.Code)
+	}
+}
+
+func TestHandler_ConfirmTOTP_InvalidCode(t *testing.T) {
+	t.Parallel()
+
+	h, _, _, twoFactor := NewTwoFactorTestHandler(t)
+
+	userID := uuid.New()
+	secret, _ := crypto.NewTOTPSecret()
+	twoFactor.EXPECT().
+		GetTOTP(gomock.Any(), userID).
+		Return(secret, false, nil)
+
+	body, _ := json.Marshal(api.ConfirmTOTPRequest{Code: "12"})
+	req := httptest.NewRequest(http.MethodPost, "/auth/2fa/confirm", bytes.NewReader(body))
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), userID))
+	rec := httptest.NewRecorder()
+
+	h.ConfirmTOTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
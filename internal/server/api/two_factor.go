@@ -0,0 +1,192 @@
+// HTTP-хендлеры двухфакторной аутентификации (TOTP)
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// EnrollTOTPResponse — ответ POST /auth/2fa/enroll.
+//
+// OTPAuthURI добавляется в приложение-аутентификатор (обычно QR-кодом),
+// Secret — тот же секрет для ручного ввода.
+type EnrollTOTPResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// ConfirmTOTPRequest — тело POST /auth/2fa/confirm.
+type ConfirmTOTPRequest struct {
+	Code string `json:"code"`
+}
+
+// ConfirmTOTPResponse — одноразовые коды восстановления.
+// Показываются один раз, сервер хранит только их хэши.
+type ConfirmTOTPResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// VerifyMFARequest — тело POST /auth/2fa/verify.
+//
+// MFAToken — из ответа /auth/login, Code — TOTP-код или код восстановления.
+type VerifyMFARequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+// EnrollTOTP начинает подключение TOTP.
+//
+// @Summary      Enroll TOTP
+// @Description  Generates a TOTP secret and returns an otpauth URI. 2FA is enabled only after POST /auth/2fa/confirm.
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} EnrollTOTPResponse
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      409 {object} ErrorResponse "TOTP already enabled"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/2fa/enroll [post]
+func (h *Handler) EnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	enr, err := h.Svc.Auth.EnrollTOTP(r.Context(), userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, serr.ErrAlreadyExists):
+			WriteError(w, http.StatusConflict, serr.ErrAlreadyExists)
+		case errors.Is(err, serr.ErrNotFound):
+			WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		default:
+			h.Log.Logger.Sugar().Errorw(
+				"enroll totp failed",
+				"error", err,
+				"user_id", userID.String(),
+			)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(EnrollTOTPResponse{Secret: enr.Secret, OTPAuthURI: enr.URI})
+}
+
+// ConfirmTOTP подтверждает подключение TOTP кодом из приложения.
+//
+// @Summary      Confirm TOTP
+// @Description  Enables TOTP after checking a code from the authenticator app and returns one-time recovery codes.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body ConfirmTOTPRequest true "TOTP code"
+// @Success      200 {object} ConfirmTOTPResponse
+// @Failure      400 {object} ErrorResponse "Bad JSON or invalid code"
+// @Failure      401 {object} ErrorResponse "Unauthorized"
+// @Failure      404 {object} ErrorResponse "Enrollment not started"
+// @Failure      409 {object} ErrorResponse "TOTP already enabled"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/2fa/confirm [post]
+func (h *Handler) ConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		return
+	}
+
+	var req ConfirmTOTPRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+
+	codes, err := h.Svc.Auth.ConfirmTOTP(r.Context(), userID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, serr.ErrInvalidOTP):
+			WriteError(w, http.StatusBadRequest, serr.ErrInvalidOTP)
+		case errors.Is(err, serr.ErrNotFound):
+			WriteError(w, http.StatusNotFound, serr.ErrNotFound)
+		case errors.Is(err, serr.ErrAlreadyExists):
+			WriteError(w, http.StatusConflict, serr.ErrAlreadyExists)
+		default:
+			h.Log.Logger.Sugar().Errorw(
+				"confirm totp failed",
+				"error", err,
+				"user_id", userID.String(),
+			)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(ConfirmTOTPResponse{RecoveryCodes: codes})
+}
+
+// VerifyMFA завершает вход вторым фактором и выдаёт пару токенов.
+//
+// @Summary      Complete login with second factor
+// @Description  Exchanges the mfa_token from /auth/login and a TOTP or recovery code for access/refresh tokens.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        request body VerifyMFARequest true "MFA challenge and code"
+// @Success      200 {object} LoginResponse
+// @Failure      400 {object} ErrorResponse "Invalid input or bad JSON"
+// @Failure      401 {object} ErrorResponse "Invalid code, expired challenge or bound client certificate not presented"
+// @Failure      403 {object} ErrorResponse "Account disabled by an administrator"
+// @Failure      409 {object} ErrorResponse "Too many active sessions"
+// @Failure      429 {object} ErrorResponse "Too many failed attempts, see Retry-After"
+// @Failure      500 {object} ErrorResponse "Internal server error"
+// @Router       /auth/2fa/verify [post]
+func (h *Handler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	var req VerifyMFARequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteError(w, http.StatusBadRequest, serr.ErrBadJSON)
+		return
+	}
+
+	pair, err := h.Svc.Auth.CompleteMFA(r.Context(), req.MFAToken, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, serr.ErrInvalidInput):
+			WriteError(w, http.StatusBadRequest, serr.ErrInvalidInput)
+		case errors.Is(err, serr.ErrInvalidOTP):
+			WriteError(w, http.StatusUnauthorized, serr.ErrInvalidOTP)
+		case errors.Is(err, serr.ErrUnauthorized):
+			WriteError(w, http.StatusUnauthorized, serr.ErrUnauthorized)
+		case errors.Is(err, serr.ErrClientCertRequired):
+			WriteError(w, http.StatusUnauthorized, serr.ErrClientCertRequired)
+		case errors.Is(err, serr.ErrAccountDisabled):
+			WriteError(w, http.StatusForbidden, serr.ErrAccountDisabled)
+		case errors.Is(err, serr.ErrTooManySessions):
+			WriteError(w, http.StatusConflict, serr.ErrTooManySessions)
+		// после неудачных попыток вход временно недоступен
+		case errors.Is(err, serr.ErrTooManyAttempts):
+			setRetryAfter(w, err)
+			WriteError(w, http.StatusTooManyRequests, serr.ErrTooManyAttempts)
+		default:
+			h.Log.Logger.Sugar().Errorw("verify mfa failed", "error", err)
+			WriteError(w, http.StatusInternalServerError, serr.ErrInternal)
+		}
+		return
+	}
+
+	w.Header().Set(ContentType, JsonContentType)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(LoginResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+	})
+}
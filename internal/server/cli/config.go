@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// NewConfigCmd создаёт группу команд для конфига сервера.
+//
+// Подкоманды:
+//   - validate — проверить конфиг и напечатать действующие настройки без секретов.
+//
+// Пример использования:
+//
+//	server config validate --config ./configs/server.yaml
+func NewConfigCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Конфиг сервера",
+	}
+
+	cmd.AddCommand(newConfigValidateCmd(app))
+
+	return cmd
+}
+
+// newConfigValidateCmd проверяет конфиг и печатает его с дефолтами.
+func newConfigValidateCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Проверить конфиг и напечатать действующие настройки",
+		Long: `Читает конфиг так же, как serve: подставляет ${VAR}, проставляет
+дефолты и проверяет значения. Печатает получившийся конфиг в YAML;
+пароль в db.dsn, auth.jwt.signing_key, password.pepper,
+security.pow.secret и mail.smtp.password заменяются на xxxxx.
+
+Пример:
+  server config validate --config ./configs/server.yaml
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := app.loadConfig()
+			if err != nil {
+				return err
+			}
+
+			out, err := yaml.Marshal(cfg.Redacted())
+			if err != nil {
+				return err
+			}
+			cmd.OutOrStdout().Write(out)
+			fmt.Fprintf(cmd.ErrOrStderr(), "config %s is valid\n", app.ConfigPath)
+			return nil
+		},
+	}
+}
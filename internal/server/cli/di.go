@@ -0,0 +1,17 @@
+package cli
+
+import (
+	"database/sql"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+)
+
+// для тестов
+var (
+	OpenDB = func(cfg config.DBConfig) (*sql.DB, error) {
+		if err := config.Connect(cfg); err != nil {
+			return nil, err
+		}
+		return config.GetDB(), nil
+	}
+)
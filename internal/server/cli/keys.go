@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+)
+
+// NewKeysCmd создаёт группу команд для ключей подписи JWT.
+//
+// Подкоманды:
+//   - generate — создать ключ EdDSA или RS256 для auth.jwt.private_key_file.
+//
+// Пример использования:
+//
+//	server keys generate --alg EdDSA --out ./certs/jwt_ed25519.pem
+func NewKeysCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "keys",
+		Short: "Ключи подписи JWT",
+	}
+
+	cmd.AddCommand(newKeysGenerateCmd())
+
+	return cmd
+}
+
+// newKeysGenerateCmd создаёт ключ подписи и печатает его kid.
+func newKeysGenerateCmd() *cobra.Command {
+	var alg, out string
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Создать ключ подписи JWT (EdDSA или RS256)",
+		Long: `Создаёт приватный ключ подписи JWT в PEM (PKCS#8) и печатает его kid.
+
+Ротация: новый ключ указывается в auth.jwt.private_key_file, а прежний
+переносится в auth.jwt.retired_keys, пока не истекут выданные им токены.
+Существующий файл не перезаписывается.
+
+Пример:
+  server keys generate --alg EdDSA --out ./certs/jwt_ed25519.pem
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if out == "" {
+				return errors.New("--out is required")
+			}
+
+			raw, err := crypto.GenerateSigningKey(alg)
+			if err != nil {
+				return err
+			}
+			key, err := crypto.ParseSigningKey(raw, alg, "")
+			if err != nil {
+				return err
+			}
+
+			f, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+			if err != nil {
+				return err
+			}
+			if _, err := f.Write(raw); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "alg=%s kid=%s file=%s\n", alg, key.ID, out)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&alg, "alg", crypto.AlgEdDSA, "algorithm: EdDSA|RS256")
+	cmd.Flags().StringVar(&out, "out", "", "output PEM file (created with mode 0600)")
+
+	return cmd
+}
@@ -0,0 +1,195 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/spf13/cobra"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+)
+
+// NewMigrateCmd создаёт группу команд управления миграциями БД.
+//
+// Подкоманды:
+//   - up — применить новые миграции (все или --steps);
+//   - down — откатить миграции (по умолчанию одну, --all — все);
+//   - version — текущая версия схемы;
+//   - force <version> — записать версию без выполнения миграций (после сбоя, dirty=true);
+//     -1 — «ни одной миграции», если сбой случился на первой.
+//
+// Миграции берутся из migrations.path, ожидание блокировки — migrations.lock_timeout.
+// migrations.enabled на эти команды не влияет: он управляет миграциями при старте serve.
+//
+// Пример использования:
+//
+//	server migrate up
+//	server migrate down --steps 2
+//	server migrate force 16
+//	server migrate force -- -1
+func NewMigrateCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Миграции БД",
+		Long: `Миграции БД из migrations.path.
+
+Команды работают при любом migrations.enabled (он управляет только
+миграциями при старте serve). Если миграции применяет другой экземпляр,
+команда ждёт не дольше migrations.lock_timeout.
+
+Примеры:
+  server migrate up
+  server migrate down --steps 1
+  server migrate version
+  server migrate force 16
+`,
+	}
+
+	cmd.AddCommand(newMigrateUpCmd(app))
+	cmd.AddCommand(newMigrateDownCmd(app))
+	cmd.AddCommand(newMigrateVersionCmd(app))
+	cmd.AddCommand(newMigrateForceCmd(app))
+
+	return cmd
+}
+
+// newMigrateUpCmd применяет новые миграции.
+func newMigrateUpCmd(app *App) *cobra.Command {
+	var steps int
+
+	cmd := &cobra.Command{
+		Use:   "up",
+		Short: "Применить новые миграции",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if steps < 0 {
+				return errors.New("--steps must not be negative")
+			}
+			return app.withMigrator(cmd, func(m *migrate.Migrate) error {
+				if steps > 0 {
+					return m.Steps(steps)
+				}
+				return m.Up()
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&steps, "steps", 0, "number of migrations to apply (default: all)")
+
+	return cmd
+}
+
+// newMigrateDownCmd откатывает миграции.
+func newMigrateDownCmd(app *App) *cobra.Command {
+	var (
+		steps int
+		all   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "down",
+		Short: "Откатить миграции (по умолчанию последнюю)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if all && cmd.Flags().Changed("steps") {
+				return errors.New("--steps and --all are mutually exclusive")
+			}
+			if steps <= 0 {
+				return errors.New("--steps must be positive")
+			}
+			return app.withMigrator(cmd, func(m *migrate.Migrate) error {
+				if all {
+					return m.Down()
+				}
+				return m.Steps(-steps)
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&steps, "steps", 1, "number of migrations to roll back")
+	cmd.Flags().BoolVar(&all, "all", false, "roll back all migrations (drops all data)")
+
+	return cmd
+}
+
+// newMigrateVersionCmd печатает текущую версию схемы.
+func newMigrateVersionCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Текущая версия схемы",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// withMigrator сам печатает версию
+			return app.withMigrator(cmd, func(m *migrate.Migrate) error { return nil })
+		},
+	}
+}
+
+// newMigrateForceCmd записывает версию схемы без выполнения миграций.
+//
+// Версия -1 (database.NilVersion) означает, что ни одна миграция не применена:
+// так снимается dirty после сбоя первой миграции. Чтобы cobra не принял -1
+// за флаг, его передают после --.
+func newMigrateForceCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "force <version>",
+		Short: "Записать версию схемы без выполнения миграций (снимает dirty)",
+		Long: "Записывает версию схемы без выполнения миграций и снимает флаг dirty.\n" +
+			"Версия -1 — ни одной миграции (сбой на первой): server migrate force -- -1",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v, err := strconv.Atoi(args[0])
+			if err != nil || v < database.NilVersion {
+				return fmt.Errorf("invalid version %q", args[0])
+			}
+			return app.withMigrator(cmd, func(m *migrate.Migrate) error {
+				return m.Force(v)
+			})
+		},
+	}
+}
+
+// withMigrator открывает БД и мигратор из конфига, выполняет fn и печатает
+// итоговую версию схемы. migrate.ErrNoChange не считается ошибкой.
+func (a *App) withMigrator(cmd *cobra.Command, fn func(m *migrate.Migrate) error) error {
+	cfg, err := a.loadConfig()
+	if err != nil {
+		return err
+	}
+	db, err := a.openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	m, err := config.NewMigrator(cmd.Context(), db, cfg.Migrations)
+	if err != nil {
+		return fmt.Errorf("open migrations %s: %w", cfg.Migrations.Path, err)
+	}
+	defer m.Close()
+
+	if err := fn(m); err != nil {
+		if !errors.Is(err, migrate.ErrNoChange) {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "no change")
+	}
+	return printVersion(cmd, m)
+}
+
+// printVersion печатает текущую версию схемы.
+func printVersion(cmd *cobra.Command, m *migrate.Migrate) error {
+	v, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		fmt.Fprintln(cmd.OutOrStdout(), "no migrations applied")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "version=%d dirty=%t\n", v, dirty)
+	return nil
+}
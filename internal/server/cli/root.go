@@ -0,0 +1,184 @@
+// Package cli реализует командный интерфейс серверного приложения GophKeeper.
+//
+// Пакет отвечает за:
+//   - запуск HTTPS-сервера (serve);
+//   - управление миграциями БД (migrate up|down|version|force);
+//   - управление пользователями без HTTP API (user create|disable|enable|list);
+//   - обслуживание хранилища (session purge-expired);
+//   - выпуск ключей подписи JWT (keys generate);
+//   - проверку конфига (config validate).
+//
+// Точка входа пакета — функция Execute.
+package cli
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/mailer"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/repository"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/logger"
+)
+
+// DefaultConfigPath — конфиг сервера, если --config не задан.
+const DefaultConfigPath = "./configs/server.yaml"
+
+// App содержит состояние CLI сервера, разделяемое между командами.
+type App struct {
+	// ConfigPath — путь к server.yaml (--config).
+	ConfigPath string
+}
+
+// loadConfig читает конфиг из ConfigPath, применяет переопределения
+// из окружения (ApplyEnvOverrides) и валидирует его.
+func (a *App) loadConfig() (*config.Config, error) {
+	cfg, err := config.Load(a.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	// переопределения из окружения (SERVER_PORT) проверяются заново
+	cfg.ApplyEnvOverrides()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// openDB подключается к БД из конфига. Миграции не применяются.
+func (a *App) openDB(cfg *config.Config) (*sql.DB, error) {
+	return OpenDB(cfg.DB)
+}
+
+// newServices создаёт репозитории и сервисы поверх db, подключая журналы
+// безопасности и аудита и служебные письма (mail.driver).
+//
+// Настройки, нужные только работающему серверу (ключи подписи, кэш отзыва сессий,
+// mTLS, OIDC), подключает serve.
+func (a *App) newServices(cfg *config.Config, db *sql.DB, sugar *zap.SugaredLogger) (*service.Services, error) {
+	repos := service.Repositories{
+		Users:         repository.NewUsersRepository(db),
+		Sessions:      repository.NewSessionsRepository(db),
+		Secrets:       repository.NewSecretsRepository(db),
+		TwoFactor:     repository.NewTwoFactorRepository(db),
+		LoginAttempts: repository.NewLoginAttemptsRepository(db),
+		APITokens:     repository.NewAPITokensRepository(db),
+		DeviceCodes:   repository.NewDeviceCodesRepository(db),
+		Invites:       repository.NewInvitesRepository(db),
+		Audit:         repository.NewAuditRepository(db),
+		Admin:         repository.NewAdminRepository(db),
+	}
+	svc := service.NewServices(repos, cfg)
+
+	// события безопасности пишем в отдельный лог
+	svc.Auth.UseSecurityLog(logger.NewSecurityLogger())
+	// служебные письма: подтверждение email, сброс пароля, новые устройства
+	if cfg.Mail.Driver != "" {
+		var m mailer.Mailer
+		switch cfg.Mail.Driver {
+		case config.MailDriverSMTP:
+			m = mailer.NewSMTP(mailer.SMTPOptions{
+				Host:     cfg.Mail.SMTP.Host,
+				Port:     cfg.Mail.SMTP.Port,
+				Username: cfg.Mail.SMTP.Username,
+				Password: cfg.Mail.SMTP.Password,
+				From:     cfg.Mail.From,
+				Timeout:  cfg.Mail.SMTP.Timeout,
+			})
+		case config.MailDriverFile:
+			var err error
+			m, err = mailer.NewOutbox(cfg.Mail.OutboxDir, cfg.Mail.From)
+			if err != nil {
+				return nil, err
+			}
+		}
+		svc.Auth.UseMailer(repository.NewEmailTokensRepository(db), m, func(err error) {
+			sugar.Warnf("send mail: %v", err)
+		})
+	}
+	// сбой журнала аудита не отменяет операцию, но должен быть виден
+	svc.Audit.OnError = func(err error) {
+		sugar.Warnf("write audit event: %v", err)
+	}
+	return svc, nil
+}
+
+// NewRootCmd создаёт root-команду CLI сервера и регистрирует подкоманды.
+//
+// Без подкоманды root-команда запускает сервер (как serve).
+// В PersistentPreRunE загружаются переменные окружения из .env (если файл есть):
+// они подставляются в конфиг вместо ${VAR}.
+func NewRootCmd() *cobra.Command {
+	app := &App{}
+
+	cmd := &cobra.Command{
+		Use:   "server",
+		Short: "GophKeeper server",
+		Long: `GophKeeper server.
+
+Команды:
+  serve       Запустить HTTPS-сервер
+  migrate     Миграции БД: up, down, version, force
+  user        Пользователи: create, disable, enable, list
+  session     Обслуживание сессий: purge-expired
+  keys        Ключи подписи JWT: generate
+  config      Проверка конфига: validate
+
+Без команды сервер запускается так же, как serve.
+Все команды читают конфиг из --config (по умолчанию ./configs/server.yaml);
+переменные ${VAR} в нём подставляются из окружения и файла .env.
+
+Примеры:
+  server serve --config /etc/gophkeeper/server.yaml
+  server migrate up
+  server migrate down --steps 1
+  server user create --email admin@example.com --admin
+  server user disable user@example.com
+  server session purge-expired --older-than 720h
+  server keys generate --alg EdDSA --out ./certs/jwt_ed25519.pem
+  server config validate
+`,
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		// без подкоманды — как раньше, просто запускаем сервер
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.serve(cmd.Context())
+		},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// .env необязателен: переменные могут прийти из окружения
+			_ = godotenv.Load()
+			return nil
+		},
+	}
+
+	cmd.SetOut(os.Stdout)
+	cmd.SetErr(os.Stderr)
+
+	cmd.PersistentFlags().StringVar(&app.ConfigPath, "config", DefaultConfigPath, "path to server.yaml")
+
+	cmd.AddCommand(NewServeCmd(app))
+	cmd.AddCommand(NewMigrateCmd(app))
+	cmd.AddCommand(NewUserCmd(app))
+	cmd.AddCommand(NewSessionCmd(app))
+	cmd.AddCommand(NewKeysCmd())
+	cmd.AddCommand(NewConfigCmd(app))
+
+	return cmd
+}
+
+// Execute запускает обработку CLI-команд сервера.
+//
+// При ошибке выполнения команды сообщение выводится в stderr, после чего процесс
+// завершается с кодом 1 (os.Exit(1)).
+func Execute() {
+	if err := NewRootCmd().Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,318 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	h "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/net/http"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/repository"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/logger"
+)
+
+// NewServeCmd создаёт команду запуска HTTPS-сервера.
+//
+// Команда:
+//   - загружает конфиг из --config и проверяет, что TLS включён (сервер работает только по HTTPS)
+//     и, при заданном tls.client_ca_file, подключает проверку клиентских сертификатов (mTLS);
+//   - подключается к БД и, если migrations.enabled, применяет новые миграции;
+//   - создаёт репозитории, сервисы, middleware и HTTP-обработчики;
+//   - запускает HTTPS-сервер и фоновые задачи (синхронизация отзыва сессий,
+//     удаление аккаунтов, очистка журнала аудита);
+//   - по SIGINT, SIGTERM, SIGQUIT корректно (graceful) завершает работу с таймаутом.
+//
+// Пример использования:
+//
+//	server serve --config ./configs/server.yaml
+func NewServeCmd(app *App) *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Запустить HTTPS-сервер",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.serve(cmd.Context())
+		},
+	}
+}
+
+// serve запускает сервер и блокируется до его остановки.
+func (a *App) serve(ctx context.Context) error {
+	sugar := logger.NewHTTPLogger().Logger.Sugar()
+	httpLogger := logger.NewHTTPLogger()
+
+	cfg, err := a.loadConfig()
+	if err != nil {
+		return err
+	}
+	// хочу только https
+	if !cfg.TLS.Enabled {
+		return errors.New("tls must be enabled")
+	}
+	// подключаем базу данных
+	db, err := a.openDB(cfg)
+	if err != nil {
+		return err
+	}
+	// делаем отложенное закрытие бд
+	defer db.Close()
+
+	if err := config.Migrate(ctx, db, cfg.Migrations); err != nil {
+		return err
+	}
+
+	// создаём репы и сервисы
+	svc, err := a.newServices(cfg, db, sugar)
+	if err != nil {
+		return err
+	}
+	sessionsRepo := repository.NewSessionsRepository(db)
+	// создаём jwt
+	verifier := middleware.NewJWTVerifier(
+		cfg.Auth.JWT.SigningKey,
+		cfg.Auth.Issuer,
+		cfg.Auth.Audience,
+	)
+	// асимметричная подпись: ключи из PEM-файлов, проверка по kid
+	if cfg.Auth.JWT.Algorithm != "HS256" {
+		keys, err := loadJWTKeys(cfg.Auth.JWT)
+		if err != nil {
+			return err
+		}
+		svc.Auth.UseKeySet(keys)
+		verifier = middleware.NewKeySetVerifier(keys, cfg.Auth.Issuer, cfg.Auth.Audience)
+	}
+	// кэш отозванных сессий: access-токены отозванной сессии перестают приниматься сразу
+	revocations := service.NewSessionRevocations(sessionsRepo, cfg.Auth.AccessTTL)
+	if err := revocations.Refresh(ctx); err != nil {
+		return fmt.Errorf("load revoked sessions: %w", err)
+	}
+	svc.Auth.UseRevocations(revocations)
+	verifier.Revocations = revocations
+	// персональные API-токены принимаются наравне с JWT
+	verifier.APITokens = svc.Auth
+	// mTLS: клиентские сертификаты можно привязать к аккаунту как второй фактор
+	var clientCAs *x509.CertPool
+	if cfg.TLS.ClientAuth != "" {
+		clientCAs, err = crypto.LoadCertPool(cfg.TLS.ClientCAFile)
+		if err != nil {
+			return err
+		}
+		svc.Auth.UseClientCerts(repository.NewClientCertsRepository(db), clientCAs)
+	}
+	// вход через корпоративный OpenID Connect провайдер
+	if cfg.Auth.OIDC.Enabled {
+		svc.Auth.UseOIDC(
+			repository.NewOIDCIdentitiesRepository(db),
+			crypto.NewOIDCVerifier(crypto.OIDCConfig{
+				Issuer:   cfg.Auth.OIDC.Issuer,
+				ClientID: cfg.Auth.OIDC.ClientID,
+				JWKSURL:  cfg.Auth.OIDC.JWKSURL,
+				CacheTTL: cfg.Auth.OIDC.JWKSCacheTTL,
+			}),
+			cfg.Auth.OIDC.LinkByEmail,
+		)
+	}
+	// создаём хандлер
+	handler := api.NewHandler(svc, httpLogger, verifier)
+	handler.TrustProxy = cfg.Server.TrustProxy
+	// метрики: глубина и время ожидания очереди хэширования паролей
+	if cfg.Observability.Metrics.Enabled {
+		expvar.Publish("password_hashing", expvar.Func(func() any {
+			return svc.Auth.HashingStats()
+		}))
+	}
+	// подключаем rate limit
+	if rl := cfg.Security.RateLimit; rl.Enabled {
+		key := middleware.KeyByIP(cfg.Server.TrustProxy)
+		if rl.Key == "user" {
+			key = middleware.KeyByUser(cfg.Server.TrustProxy)
+		}
+		handler.Limiter = middleware.NewRateLimiter(rl.RPS, rl.Burst, key)
+	}
+	// proof-of-work на регистрации и входе под нагрузкой
+	if pw := cfg.Security.PoW; pw.Enabled {
+		key := []byte(pw.Secret)
+		if len(key) == 0 {
+			// challenge, выданные до рестарта, станут недействительны — это допустимо
+			key = make([]byte, 32)
+			if _, err := rand.Read(key); err != nil {
+				return err
+			}
+		}
+		handler.PoW = middleware.NewProofOfWork(middleware.PoWOptions{
+			Key:            key,
+			ChallengeTTL:   pw.ChallengeTTL,
+			Window:         pw.Window,
+			Threshold:      pw.Threshold,
+			BaseDifficulty: pw.BaseDifficulty,
+			MaxDifficulty:  pw.MaxDifficulty,
+		})
+		if cfg.Observability.Metrics.Enabled {
+			expvar.Publish("proof_of_work", expvar.Func(func() any {
+				return handler.PoW.Stats()
+			}))
+		}
+	}
+	// создаём роутер
+	router := h.NewRouter(handler)
+	//создаём сервер
+	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
+
+	server := &http.Server{
+		Addr:         addr,
+		Handler:      router,
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+		IdleTimeout:  cfg.Server.IdleTimeout,
+		TLSConfig:    serverTLSConfig(cfg.TLS, clientCAs),
+	}
+
+	// метрики — на отдельном листенере (по умолчанию только loopback)
+	var metricsServer *http.Server
+	if m := cfg.Observability.Metrics; m.Enabled {
+		metricsServer = &http.Server{
+			Addr:              m.Addr,
+			Handler:           h.NewMetricsRouter(m.Path),
+			ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		}
+	}
+
+	// создаём контекст и errgroup
+	ctx, stop := signal.NotifyContext(
+		ctx,
+		os.Interrupt,
+		syscall.SIGTERM,
+		syscall.SIGQUIT,
+	)
+	defer stop()
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	// запускаем сервер
+	g.Go(func() error {
+		sugar.Infof("server started on %s", addr)
+
+		if err := server.ListenAndServeTLS(
+			cfg.TLS.CertFile,
+			cfg.TLS.KeyFile,
+		); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+
+	if metricsServer != nil {
+		g.Go(func() error {
+			sugar.Infof("metrics served on http://%s%s", metricsServer.Addr, cfg.Observability.Metrics.Path)
+
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("metrics server: %w", err)
+			}
+			return nil
+		})
+	}
+
+	// периодически подгружаем сессии, отозванные другими экземплярами сервера
+	// (и командами server user disable)
+	g.Go(func() error {
+		revocations.Run(ctx, cfg.Auth.Sessions.RevocationSyncInterval, func(err error) {
+			sugar.Warnf("sync revoked sessions: %v", err)
+		})
+		return nil
+	})
+
+	// удаляем аккаунты, у которых истекла отсрочка удаления
+	g.Go(func() error {
+		svc.Auth.RunAccountPurge(ctx, cfg.Auth.AccountDeletion.PurgeInterval, func(err error) {
+			sugar.Warnf("purge deleted accounts: %v", err)
+		})
+		return nil
+	})
+
+	// удаляем события аудита старше audit.retention
+	g.Go(func() error {
+		svc.Audit.RunRetention(ctx, cfg.Audit.PruneInterval, func(err error) {
+			sugar.Warnf("prune audit events: %v", err)
+		})
+		return nil
+	})
+
+	// graceful shutdown с таймаутом из конфига
+	g.Go(func() error {
+		<-ctx.Done()
+
+		sugar.Info("shutdown signal received")
+
+		shutdownCtx, cancel := context.WithTimeout(
+			context.Background(),
+			cfg.Server.ShutdownTimeout,
+		)
+		defer cancel()
+
+		if metricsServer != nil {
+			_ = metricsServer.Shutdown(shutdownCtx)
+		}
+		err := server.Shutdown(shutdownCtx)
+		// дожидаемся писем, поставленных в фон до остановки
+		svc.Auth.WaitMail()
+		return err
+	})
+
+	// ожидание и единная обработка ошибок
+	if err := g.Wait(); err != nil {
+		return fmt.Errorf("server stopped with error: %w", err)
+	}
+	sugar.Info("server gracefully stopped")
+	return nil
+}
+
+// loadJWTKeys загружает активный и retired ключи подписи JWT из PEM-файлов.
+func loadJWTKeys(cfg config.JWTConfig) (*crypto.KeySet, error) {
+	active := crypto.KeyFile{ID: cfg.KeyID, Alg: cfg.Algorithm, Path: cfg.PrivateKeyFile}
+
+	retired := make([]crypto.KeyFile, 0, len(cfg.RetiredKeys))
+	for _, k := range cfg.RetiredKeys {
+		alg := k.Algorithm
+		if alg == "" {
+			alg = cfg.Algorithm
+		}
+		retired = append(retired, crypto.KeyFile{ID: k.KeyID, Alg: alg, Path: k.File})
+	}
+
+	return crypto.LoadKeySet(active, retired...)
+}
+
+// serverTLSConfig собирает настройки TLS сервера: минимальную версию протокола
+// и проверку клиентских сертификатов по tls.client_auth.
+func serverTLSConfig(cfg config.TLSConfig, clientCAs *x509.CertPool) *tls.Config {
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.MinVersion == "1.3" {
+		tlsCfg.MinVersion = tls.VersionTLS13
+	}
+
+	switch cfg.ClientAuth {
+	case config.ClientAuthOptional:
+		tlsCfg.ClientCAs = clientCAs
+		tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	case config.ClientAuthRequire:
+		tlsCfg.ClientCAs = clientCAs
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsCfg
+}
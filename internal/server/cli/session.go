@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+)
+
+// NewSessionCmd создаёт группу команд обслуживания refresh-сессий.
+//
+// Подкоманды:
+//   - purge-expired — удалить сессии с истёкшим refresh-токеном.
+//
+// Пример использования:
+//
+//	server session purge-expired --older-than 720h
+func NewSessionCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "session",
+		Short: "Обслуживание сессий",
+	}
+
+	cmd.AddCommand(newSessionPurgeExpiredCmd(app))
+
+	return cmd
+}
+
+// newSessionPurgeExpiredCmd удаляет истёкшие сессии.
+func newSessionPurgeExpiredCmd(app *App) *cobra.Command {
+	var olderThan time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "purge-expired",
+		Short: "Удалить сессии с истёкшим refresh-токеном",
+		Long: `Удаляет сессии, refresh-токен которых истёк больше --older-than назад.
+
+Отозванные, но ещё не истёкшие сессии остаются: по ним сервер
+распознаёт повторное использование refresh-токенов. После удаления
+вход с устройств этих сессий снова считается входом с нового
+устройства (mail.notify_new_device).
+
+Пример:
+  server session purge-expired --older-than 720h
+`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.withServices(cmd, func(svc *service.Services) error {
+				n, err := svc.Auth.PurgeExpiredSessions(cmd.Context(), olderThan)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "purged %d sessions\n", n)
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().DurationVar(&olderThan, "older-than", 0, "keep sessions expired less than this long ago")
+
+	return cmd
+}
@@ -0,0 +1,229 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/logger"
+)
+
+// NewUserCmd создаёт группу команд управления пользователями напрямую через БД.
+//
+// Подкоманды:
+//   - create — создать пользователя (в том числе первого администратора);
+//   - disable <id|email> / enable <id|email> — заблокировать или разблокировать аккаунт;
+//   - list — список пользователей.
+//
+// Пример использования:
+//
+//	server user create --email admin@example.com --admin
+//	server user disable user@example.com
+//	server user list --limit 20
+func NewUserCmd(app *App) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user",
+		Short: "Управление пользователями",
+		Long: `Управление пользователями напрямую через БД (без HTTP API).
+
+create создаёт аккаунт при любом auth.registration; email такого
+аккаунта считается подтверждённым. С --admin аккаунт получает роль
+администратора (GET /admin/..., gophkeeper admin).
+disable блокирует вход и отзывает все сессии пользователя; работающие
+экземпляры сервера перестают принимать его access-токены за
+auth.sessions.revocation_sync_interval.
+
+Примеры:
+  server user create --email admin@example.com --admin
+  echo "$PASSWORD" | server user create --email ci@example.com --password-stdin
+  server user disable user@example.com
+  server user enable 7a0a4a6a-a7bf-42c0-8cdf-2be8583d180e
+  server user list --limit 20 --offset 40
+`,
+	}
+
+	cmd.AddCommand(newUserCreateCmd(app))
+	cmd.AddCommand(newUserSetDisabledCmd(app, "disable", "Заблокировать аккаунт и отозвать его сессии", true))
+	cmd.AddCommand(newUserSetDisabledCmd(app, "enable", "Разблокировать аккаунт", false))
+	cmd.AddCommand(newUserListCmd(app))
+
+	return cmd
+}
+
+// newUserCreateCmd создаёт пользователя.
+func newUserCreateCmd(app *App) *cobra.Command {
+	var (
+		email     string
+		admin     bool
+		fromStdin bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Создать пользователя",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(email) == "" {
+				return errors.New("--email is required")
+			}
+			password, err := readPassword(cmd, fromStdin)
+			if err != nil {
+				return err
+			}
+
+			return app.withServices(cmd, func(svc *service.Services) error {
+				id, err := svc.Admin.CreateUser(cmd.Context(), email, password, admin)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), id.String())
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&email, "email", "", "user email")
+	cmd.Flags().BoolVar(&admin, "admin", false, "grant the administrator role")
+	cmd.Flags().BoolVar(&fromStdin, "password-stdin", false, "read password from the first line of stdin")
+
+	return cmd
+}
+
+// newUserSetDisabledCmd блокирует или разблокирует аккаунт.
+func newUserSetDisabledCmd(app *App, use, short string, disabled bool) *cobra.Command {
+	return &cobra.Command{
+		Use:   use + " <id|email>",
+		Short: short,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.withServices(cmd, func(svc *service.Services) error {
+				id, err := svc.Admin.ResolveUser(cmd.Context(), args[0])
+				if err != nil {
+					return fmt.Errorf("user %s: %w", args[0], err)
+				}
+				// действие оператора сервера, а не пользователя с ролью администратора
+				if err := svc.Admin.SetDisabled(cmd.Context(), uuid.Nil, id, disabled); err != nil {
+					return err
+				}
+				state := "enabled"
+				if disabled {
+					state = "disabled"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "user %s: %s\n", id, state)
+				return nil
+			})
+		},
+	}
+}
+
+// newUserListCmd печатает страницу пользователей.
+func newUserListCmd(app *App) *cobra.Command {
+	var limit, offset int
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "Список пользователей",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return app.withServices(cmd, func(svc *service.Services) error {
+				users, err := svc.Admin.ListUsers(cmd.Context(), limit, offset)
+				if err != nil {
+					return err
+				}
+				if len(users) == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "no users")
+					return nil
+				}
+
+				for _, u := range users {
+					lastLogin := "never"
+					if u.LastLoginAt != nil {
+						lastLogin = u.LastLoginAt.Format("2006-01-02 15:04:05")
+					}
+					status := "active"
+					if u.DisabledAt != nil {
+						status = "disabled"
+					}
+					if u.IsAdmin {
+						status += ",admin"
+					}
+					fmt.Fprintf(cmd.OutOrStdout(),
+						"%s\t%s\t%s\tsecrets=%d\tcreated=%s\tlast_login=%s\n",
+						u.ID, u.Email, status, u.SecretCount,
+						u.CreatedAt.Format("2006-01-02 15:04:05"), lastLogin,
+					)
+				}
+				return nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&limit, "limit", 0, "page size (default 50, max 200)")
+	cmd.Flags().IntVar(&offset, "offset", 0, "number of users to skip")
+
+	return cmd
+}
+
+// withServices открывает БД из конфига, создаёт сервисы и выполняет fn.
+func (a *App) withServices(cmd *cobra.Command, fn func(svc *service.Services) error) error {
+	cfg, err := a.loadConfig()
+	if err != nil {
+		return err
+	}
+	db, err := a.openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	svc, err := a.newServices(cfg, db, logger.NewHTTPLogger().Logger.Sugar())
+	if err != nil {
+		return err
+	}
+	return fn(svc)
+}
+
+// readPassword читает пароль нового пользователя: первую строку stdin
+// (fromStdin=true) или дважды из терминала без эха.
+func readPassword(cmd *cobra.Command, fromStdin bool) (string, error) {
+	if fromStdin {
+		b, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return "", fmt.Errorf("read password from stdin: %w", err)
+		}
+		line, _, _ := strings.Cut(string(b), "\n")
+		line = strings.TrimRight(line, "\r")
+		if line == "" {
+			return "", errors.New("empty password on stdin")
+		}
+		return line, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", errors.New("stdin is not a terminal; use --password-stdin")
+	}
+
+	var pw [2][]byte
+	for i, prompt := range []string{"Password: ", "Repeat password: "} {
+		fmt.Fprint(cmd.ErrOrStderr(), prompt)
+		b, err := term.ReadPassword(fd)
+		fmt.Fprintln(cmd.ErrOrStderr())
+		if err != nil {
+			return "", fmt.Errorf("read password: %w", err)
+		}
+		pw[i] = b
+	}
+	if !bytes.Equal(pw[0], pw[1]) {
+		return "", errors.New("passwords do not match")
+	}
+	return string(pw[0]), nil
+}
@@ -2,16 +2,19 @@
 // и доступ к глобальному экземпляру *sql.DB.
 //
 // Пакет выполняет:
-//   - открытие соединения с PostgreSQL (через драйвер pgx);
+//   - открытие соединения с PostgreSQL (через драйвер pgx) с настройками пула из db;
 //   - проверку доступности базы (Ping);
-//   - запуск миграций (golang-migrate) при старте сервера.
+//   - запуск миграций (golang-migrate) из каталога migrations.path.
 //
 // Примечание: пакет использует глобальную переменную DB. Инициализация должна
 // выполняться один раз при запуске сервера.
 package config
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"path/filepath"
 
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/logger"
 	"github.com/golang-migrate/migrate/v4"
@@ -23,50 +26,83 @@ import (
 
 // DB — глобальный экземпляр подключения к базе данных.
 //
-// Инициализируется функцией Init и используется другими пакетами через GetDB.
+// Инициализируется функцией Connect и используется другими пакетами через GetDB.
 var DB *sql.DB
 
-// Init открывает подключение к базе данных по DSN, проверяет его доступность
-// и применяет миграции.
-//
-// databaseDSN — строка подключения к PostgreSQL.
-// Миграции запускаются из каталога file://migrations/postgres.
-// Если миграции уже применены, ошибка migrate.ErrNoChange не считается ошибкой.
-func Init(databaseDSN string) error {
+// Connect открывает подключение к базе данных с настройками пула из cfg
+// и проверяет его доступность. Миграции не применяются: см. Migrate.
+func Connect(cfg DBConfig) error {
 	customLog := logger.NewHTTPLogger().Logger.Sugar()
 
 	var err error
-	DB, err = sql.Open("pgx", databaseDSN)
-
+	DB, err = sql.Open("pgx", cfg.DSN)
 	if err != nil {
 		customLog.Errorf("error to connect db: %v", err)
 		return err
 	}
 
+	if cfg.MaxOpenConns > 0 {
+		DB.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		DB.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	DB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	DB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
 	if err = DB.Ping(); err != nil {
 		customLog.Errorf("error check db connection: %v", err)
 		return err
 	}
+	return nil
+}
 
-	// Запуск миграций
-	driver, err := postgres.WithInstance(DB, &postgres.Config{})
+// NewMigrator создаёт golang-migrate для каталога cfg.Path на отдельном
+// соединении из db. Close мигратора закрывает только это соединение, db остаётся открытой.
+//
+// Пока миграции применяет другой экземпляр, операции ждут блокировку
+// не дольше cfg.LockTimeout и возвращают migrate.ErrLockTimeout.
+func NewMigrator(ctx context.Context, db *sql.DB, cfg MigrationsConfig) (*migrate.Migrate, error) {
+	conn, err := db.Conn(ctx)
 	if err != nil {
-		customLog.Errorf("error creating migration driver: %v", err)
-		return err
+		return nil, err
+	}
+
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	m, err := migrate.NewWithDatabaseInstance("file://"+filepath.ToSlash(cfg.Path), "postgres", driver)
+	if err != nil {
+		driver.Close()
+		return nil, err
+	}
+	if cfg.LockTimeout > 0 {
+		m.LockTimeout = cfg.LockTimeout
+	}
+	return m, nil
+}
+
+// Migrate применяет новые миграции, если migrations.enabled.
+// Если миграции уже применены, migrate.ErrNoChange не считается ошибкой.
+func Migrate(ctx context.Context, db *sql.DB, cfg MigrationsConfig) error {
+	customLog := logger.NewHTTPLogger().Logger.Sugar()
+
+	if !cfg.Enabled {
+		customLog.Info("migrations disabled, skipping")
+		return nil
 	}
 
-	// создаём миграции с выбранным драйвером
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://migrations/postgres",
-		"postgres", driver)
+	m, err := NewMigrator(ctx, db, cfg)
 	if err != nil {
 		customLog.Errorf("error creating migrations: %v", err)
 		return err
 	}
+	defer m.Close()
 
-	// запускаем создание миграций
-	err = m.Up()
-	if err != nil && err != migrate.ErrNoChange {
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
 		customLog.Errorf("error applying migrations: %v", err)
 		return err
 	}
@@ -77,7 +113,7 @@ func Init(databaseDSN string) error {
 
 // GetDB возвращает текущий глобальный экземпляр *sql.DB.
 //
-// Возвращаемое значение может быть nil, если Init ещё не вызывался
+// Возвращаемое значение может быть nil, если Connect ещё не вызывался
 // или завершился ошибкой.
 func GetDB() *sql.DB {
 	return DB
@@ -0,0 +1,22 @@
+package crypto
+
+import "strings"
+
+// APITokenPrefix отличает персональные API-токены от JWT в заголовке Authorization.
+const APITokenPrefix = "gkp_"
+
+// NewAPIToken генерирует персональный API-токен: префикс gkp_ и 256 случайных бит.
+//
+// Как и refresh-токен, в БД хранится только его SHA-256 (HashRefreshToken).
+func NewAPIToken() (string, error) {
+	raw, err := NewRefreshToken()
+	if err != nil {
+		return "", err
+	}
+	return APITokenPrefix + raw, nil
+}
+
+// IsAPIToken сообщает, что строка похожа на персональный API-токен (а не JWT).
+func IsAPIToken(token string) bool {
+	return strings.HasPrefix(token, APITokenPrefix)
+}
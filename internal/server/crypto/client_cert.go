@@ -0,0 +1,65 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrInvalidCertificate — сертификат не разобран или не выпущен доверенным CA клиентов.
+var ErrInvalidCertificate = errors.New("invalid client certificate")
+
+// LoadCertPool читает PEM-файл с CA клиентских сертификатов (tls.client_ca_file).
+func LoadCertPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// CertFingerprint возвращает SHA-256 DER-кодировки сертификата.
+func CertFingerprint(cert *x509.Certificate) []byte {
+	sum := sha256.Sum256(cert.Raw)
+	return sum[:]
+}
+
+// CertThumbprint кодирует отпечаток для claim cnf.x5t#S256 (RFC 8705):
+// base64url без выравнивания.
+func CertThumbprint(fingerprint []byte) string {
+	return base64.RawURLEncoding.EncodeToString(fingerprint)
+}
+
+// ParseClientCertPEM разбирает PEM-сертификат и проверяет, что он выпущен
+// одним из CA roots для аутентификации клиента и действителен на момент now.
+//
+// Возвращает ErrInvalidCertificate, если сертификат не разобран или не прошёл проверку.
+func ParseClientCertPEM(data []byte, roots *x509.CertPool, now time.Time) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, ErrInvalidCertificate
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidCertificate
+	}
+
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCertificate, err)
+	}
+	return cert, nil
+}
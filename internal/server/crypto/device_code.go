@@ -0,0 +1,55 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"strings"
+)
+
+// userCodeAlphabet — согласные без похожих друг на друга букв (RFC 8628, раздел 6.1):
+// код удобно продиктовать и набрать, и из него не складываются слова.
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+// userCodeLen — длина пользовательского кода без дефиса (20^8 ≈ 2.5·10^10 вариантов).
+const userCodeLen = 8
+
+// NewUserCode генерирует пользовательский код device flow вида "XXXX-XXXX".
+//
+// Код вводится на уже авторизованном устройстве (gophkeeper device approve),
+// а headless-клиент тем временем опрашивает сервер по device_code.
+func NewUserCode() (string, error) {
+	var sb strings.Builder
+	buf := make([]byte, 1)
+	for sb.Len() < userCodeLen+1 {
+		if sb.Len() == userCodeLen/2 {
+			sb.WriteByte('-')
+			continue
+		}
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		// отбрасываем хвост байта, чтобы все буквы были равновероятны
+		if int(buf[0]) >= 256-256%len(userCodeAlphabet) {
+			continue
+		}
+		sb.WriteByte(userCodeAlphabet[int(buf[0])%len(userCodeAlphabet)])
+	}
+	return sb.String(), nil
+}
+
+// NormalizeUserCode приводит введённый код к виду "XXXX-XXXX":
+// регистр, пробелы и дефисы не важны.
+//
+// Возвращает false, если код не может быть пользовательским кодом.
+func NormalizeUserCode(code string) (string, bool) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+	if len(code) != userCodeLen {
+		return "", false
+	}
+	for i := 0; i < len(code); i++ {
+		if strings.IndexByte(userCodeAlphabet, code[i]) < 0 {
+			return "", false
+		}
+	}
+	return code[:userCodeLen/2] + "-" + code[userCodeLen/2:], true
+}
@@ -4,13 +4,15 @@
 // В частности, пакет отвечает за:
 //   - генерацию и подпись JWT access-токенов;
 //   - настройку параметров токенов (issuer, audience, TTL);
-//   - соблюдение требований безопасности (HS256, срок жизни).
+//   - соблюдение требований безопасности (HS256/EdDSA/RS256, срок жизни);
+//   - хранение ключей подписи с kid и их ротацию (KeySet, JWKS).
 package crypto
 
 import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 // JWTConfig описывает параметры генерации JWT access-токена.
@@ -22,30 +24,96 @@ type JWTConfig struct {
 	// SigningKey — секретный ключ для подписи токена (HS256).
 	// Должен быть достаточно длинным и случайным.
 	SigningKey string
+	// Keys — набор асимметричных ключей (EdDSA/RS256).
+	// Если задан, токен подписывается активным ключом, а SigningKey не используется.
+	Keys *KeySet
 	// AccessTTL — срок жизни access-токена.
 	AccessTTL time.Duration
 }
 
+// AccessClaims — claims access-токена.
+//
+// Помимо стандартных полей содержит sid — ID refresh-сессии, при создании
+// или обновлении которой выдан токен. По нему сервер отклоняет access-токены
+// отозванных сессий, не дожидаясь истечения exp.
+//
+// Если к аккаунту привязаны клиентские сертификаты, токен содержит cnf
+// с отпечатком сертификата (RFC 8705) и принимается только по mTLS с ним.
+//
+// После повторного ввода пароля (POST /auth/reauth) токен содержит
+// elevated_until — до этого момента разрешены чувствительные операции.
+type AccessClaims struct {
+	jwt.RegisteredClaims
+	SessionID     string           `json:"sid,omitempty"`
+	Confirmation  *Confirmation    `json:"cnf,omitempty"`
+	ElevatedUntil *jwt.NumericDate `json:"elevated_until,omitempty"`
+}
+
+// Confirmation — claim cnf: чем клиент подтверждает владение токеном.
+type Confirmation struct {
+	// CertThumbprint — отпечаток клиентского сертификата (CertThumbprint).
+	CertThumbprint string `json:"x5t#S256,omitempty"`
+}
+
 // NewAccessToken создаёт и подписывает JWT access-токен для пользователя.
 //
 // Токен содержит стандартные RegisteredClaims:
 //   - iss (Issuer)
 //   - aud (Audience)
 //   - sub (userID)
+//   - jti (уникальный ID токена)
 //   - iat (IssuedAt)
 //   - exp (ExpiresAt)
 //
-// Используется алгоритм подписи HS256.
+// и sid — ID сессии, к которой привязан токен.
+//
+// Если задан cfg.Keys — токен подписывается активным ключом набора
+// (EdDSA или RS256), а в заголовок добавляется kid.
+// Иначе используется HS256 с cfg.SigningKey.
 // В случае ошибки подписи возвращается непустая ошибка.
-func NewAccessToken(userID string, cfg JWTConfig) (string, error) {
+func NewAccessToken(userID, sessionID string, cfg JWTConfig) (string, error) {
+	return NewBoundAccessToken(userID, sessionID, "", cfg)
+}
+
+// NewBoundAccessToken создаёт access-токен, привязанный к клиентскому сертификату:
+// certThumbprint попадает в claim cnf.x5t#S256.
+//
+// При пустом certThumbprint токен не привязан (как NewAccessToken).
+func NewBoundAccessToken(userID, sessionID, certThumbprint string, cfg JWTConfig) (string, error) {
+	return NewElevatedAccessToken(userID, sessionID, certThumbprint, time.Time{}, cfg)
+}
+
+// NewElevatedAccessToken создаёт access-токен с повышенными правами до момента until
+// (claim elevated_until), выдаваемый после повторной аутентификации.
+//
+// until позже exp не имеет смысла: токен к тому моменту уже истечёт.
+// При нулевом until токен обычный (как NewBoundAccessToken).
+func NewElevatedAccessToken(userID, sessionID, certThumbprint string, until time.Time, cfg JWTConfig) (string, error) {
 	now := time.Now()
 
-	claims := jwt.RegisteredClaims{
-		Issuer:    cfg.Issuer,
-		Audience:  []string{cfg.Audience},
-		Subject:   userID,
-		IssuedAt:  jwt.NewNumericDate(now),
-		ExpiresAt: jwt.NewNumericDate(now.Add(cfg.AccessTTL)),
+	claims := AccessClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    cfg.Issuer,
+			Audience:  []string{cfg.Audience},
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(cfg.AccessTTL)),
+		},
+		SessionID: sessionID,
+	}
+	if certThumbprint != "" {
+		claims.Confirmation = &Confirmation{CertThumbprint: certThumbprint}
+	}
+	if !until.IsZero() {
+		claims.ElevatedUntil = jwt.NewNumericDate(until)
+	}
+
+	if cfg.Keys != nil {
+		key := cfg.Keys.Active()
+		t := jwt.NewWithClaims(key.Method(), claims)
+		t.Header["kid"] = key.ID
+		return t.SignedString(key.Private)
 	}
 
 	t := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -0,0 +1,289 @@
+// Ключи подписи JWT (EdDSA/RS256), ротация и JWKS
+package crypto
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Поддерживаемые асимметричные алгоритмы подписи.
+const (
+	AlgEdDSA = "EdDSA"
+	AlgRS256 = "RS256"
+)
+
+// minRSABits — минимальный размер RSA-ключа.
+const minRSABits = 2048
+
+// ErrUnknownKeyID — в токене указан kid, которого нет в KeySet.
+var ErrUnknownKeyID = errors.New("unknown signing key id")
+
+// SigningKey — ключ подписи JWT.
+//
+// У активного ключа заполнен Private, у выведенных из оборота (retired)
+// — только Public: ими можно проверять ещё не истёкшие токены, но не подписывать новые.
+type SigningKey struct {
+	ID      string
+	Alg     string
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// Method возвращает jwt.SigningMethod для алгоритма ключа.
+func (k *SigningKey) Method() jwt.SigningMethod {
+	if k.Alg == AlgRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
+
+// KeySet — набор ключей подписи: один активный и несколько retired.
+//
+// Ротация:
+//  1. новый ключ становится активным, старый переносится в retired;
+//  2. токены, подписанные старым ключом, продолжают проверяться по kid;
+//  3. через access_ttl старый ключ можно удалить из конфига.
+type KeySet struct {
+	active *SigningKey
+	keys   []*SigningKey // активный первым, затем retired в порядке конфига
+	byID   map[string]*SigningKey
+}
+
+// NewKeySet создаёт KeySet из активного ключа и retired ключей.
+//
+// Ошибки:
+//   - у активного ключа нет приватной части
+//   - повторяющиеся kid
+func NewKeySet(active *SigningKey, retired ...*SigningKey) (*KeySet, error) {
+	if active == nil || active.Private == nil {
+		return nil, errors.New("active key must have private part")
+	}
+
+	ks := &KeySet{active: active, byID: make(map[string]*SigningKey, len(retired)+1)}
+	for _, k := range append([]*SigningKey{active}, retired...) {
+		if _, dup := ks.byID[k.ID]; dup {
+			return nil, fmt.Errorf("duplicate key id %q", k.ID)
+		}
+		ks.byID[k.ID] = k
+		ks.keys = append(ks.keys, k)
+	}
+	return ks, nil
+}
+
+// Active возвращает ключ, которым подписываются новые токены.
+func (ks *KeySet) Active() *SigningKey {
+	return ks.active
+}
+
+// Lookup возвращает ключ по kid (активный или retired).
+func (ks *KeySet) Lookup(kid string) (*SigningKey, bool) {
+	k, ok := ks.byID[kid]
+	return k, ok
+}
+
+// Algorithms возвращает алгоритмы, встречающиеся в наборе.
+// Нужен парсеру JWT, чтобы не принимать токены с чужим alg.
+func (ks *KeySet) Algorithms() []string {
+	seen := make(map[string]bool)
+	var algs []string
+	for _, k := range ks.keys {
+		if !seen[k.Alg] {
+			seen[k.Alg] = true
+			algs = append(algs, k.Alg)
+		}
+	}
+	return algs
+}
+
+// JWK — публичный ключ в формате RFC 7517.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+}
+
+// JWKS — набор публичных ключей (/.well-known/jwks.json).
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS возвращает публичные части всех ключей набора (активный первым).
+func (ks *KeySet) JWKS() JWKS {
+	out := JWKS{Keys: make([]JWK, 0, len(ks.keys))}
+	for _, k := range ks.keys {
+		out.Keys = append(out.Keys, toJWK(k))
+	}
+	return out
+}
+
+func toJWK(k *SigningKey) JWK {
+	jwk := JWK{Kid: k.ID, Alg: k.Alg, Use: "sig"}
+	switch pub := k.Public.(type) {
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	}
+	return jwk
+}
+
+// LoadSigningKey читает ключ из PEM-файла.
+//
+// Поддерживаются:
+//   - PRIVATE KEY (PKCS#8) и RSA PRIVATE KEY (PKCS#1) — ключ с приватной частью
+//   - PUBLIC KEY (PKIX) — только для проверки (retired ключи)
+//
+// alg — EdDSA|RS256, тип ключа в файле должен ему соответствовать.
+// kid — идентификатор ключа; если пустой, вычисляется из публичного ключа.
+func LoadSigningKey(path, alg, kid string) (*SigningKey, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key %s: %w", path, err)
+	}
+	return ParseSigningKey(raw, alg, kid)
+}
+
+// ParseSigningKey разбирает PEM-ключ (см. LoadSigningKey).
+func ParseSigningKey(pemBytes []byte, alg, kid string) (*SigningKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse key: %w", err)
+	}
+
+	k := &SigningKey{Alg: alg}
+	switch key := parsed.(type) {
+	case ed25519.PrivateKey:
+		k.Private, k.Public = key, key.Public()
+	case *rsa.PrivateKey:
+		k.Private, k.Public = key, key.Public()
+	case ed25519.PublicKey, *rsa.PublicKey:
+		k.Public = key
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+
+	switch pub := k.Public.(type) {
+	case ed25519.PublicKey:
+		if alg != AlgEdDSA {
+			return nil, fmt.Errorf("ed25519 key cannot be used with %s", alg)
+		}
+	case *rsa.PublicKey:
+		if alg != AlgRS256 {
+			return nil, fmt.Errorf("rsa key cannot be used with %s", alg)
+		}
+		if pub.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("rsa key too short: %d bits, need >= %d", pub.N.BitLen(), minRSABits)
+		}
+	}
+
+	k.ID = kid
+	if k.ID == "" {
+		k.ID, err = KeyID(k.Public)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+// GenerateSigningKey создаёт новый ключ подписи для alg (EdDSA — ed25519,
+// RS256 — RSA 3072) и возвращает его в PEM (PKCS#8), пригодном для
+// auth.jwt.private_key_file.
+func GenerateSigningKey(alg string) ([]byte, error) {
+	var key crypto.Signer
+	var err error
+	switch alg {
+	case AlgEdDSA:
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	case AlgRS256:
+		key, err = rsa.GenerateKey(rand.Reader, 3072)
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", alg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("marshal key: %w", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// KeyID вычисляет kid по публичному ключу:
+// первые 16 байт SHA-256 от PKIX DER в base64url.
+func KeyID(pub crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return "", fmt.Errorf("marshal public key: %w", err)
+	}
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:16]), nil
+}
+
+// KeyFile — ключ в PEM-файле, как он задан в конфиге.
+type KeyFile struct {
+	ID   string // kid, пустой — вычисляется из ключа
+	Alg  string // EdDSA|RS256
+	Path string
+}
+
+// LoadKeySet загружает активный и retired ключи из PEM-файлов.
+func LoadKeySet(active KeyFile, retired ...KeyFile) (*KeySet, error) {
+	a, err := LoadSigningKey(active.Path, active.Alg, active.ID)
+	if err != nil {
+		return nil, fmt.Errorf("active key: %w", err)
+	}
+
+	old := make([]*SigningKey, 0, len(retired))
+	for _, f := range retired {
+		k, err := LoadSigningKey(f.Path, f.Alg, f.ID)
+		if err != nil {
+			return nil, fmt.Errorf("retired key: %w", err)
+		}
+		// retired ключ нужен только для проверки подписи
+		k.Private = nil
+		old = append(old, k)
+	}
+
+	return NewKeySet(a, old...)
+}
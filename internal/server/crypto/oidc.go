@@ -0,0 +1,239 @@
+// Проверка ID-токенов внешнего OpenID Connect провайдера
+package crypto
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidIDToken — ID-токен не прошёл проверку (подпись, iss, aud, срок).
+var ErrInvalidIDToken = errors.New("invalid id token")
+
+// Ограничения при работе с JWKS провайдера.
+const (
+	maxJWKSBytes = 1 << 20 // ответ jwks_url больше этого не читаем
+	// jwksMinRefresh — не чаще этого JWKS перезапрашивается из-за неизвестного kid,
+	// чтобы токены с выдуманным kid не превращались в поток запросов к провайдеру.
+	jwksMinRefresh = time.Minute
+	idTokenLeeway  = 30 * time.Second // допуск расхождения часов с провайдером
+)
+
+// idTokenAlgorithms — алгоритмы подписи ID-токенов, которые принимаем.
+var idTokenAlgorithms = []string{"RS256", "ES256", AlgEdDSA}
+
+// OIDCConfig — параметры доверенного провайдера.
+type OIDCConfig struct {
+	Issuer   string // ожидаемый iss
+	ClientID string // ожидаемый aud
+	JWKSURL  string // откуда брать ключи проверки подписи
+	CacheTTL time.Duration
+	// HTTPClient — клиент для запроса JWKS (nil — http.Client с таймаутом 10 секунд).
+	HTTPClient *http.Client
+}
+
+// IDTokenClaims — claims ID-токена, нужные для входа.
+type IDTokenClaims struct {
+	jwt.RegisteredClaims
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+}
+
+// OIDCVerifier проверяет ID-токены провайдера по его JWKS.
+//
+// Ключи кэшируются на CacheTTL; токен с неизвестным kid вызывает
+// внеочередную загрузку JWKS (ротация ключей у провайдера), но не чаще jwksMinRefresh.
+type OIDCVerifier struct {
+	cfg    OIDCConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewOIDCVerifier создаёт OIDCVerifier. JWKS загружается при первой проверке.
+func NewOIDCVerifier(cfg OIDCConfig) *OIDCVerifier {
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &OIDCVerifier{cfg: cfg, client: client}
+}
+
+// Issuer возвращает iss доверенного провайдера.
+func (v *OIDCVerifier) Issuer() string {
+	return v.cfg.Issuer
+}
+
+// Verify проверяет подпись и claims ID-токена.
+//
+// Требования: подпись ключом из JWKS, iss = Issuer, aud содержит ClientID,
+// exp задан и не наступил, sub непустой.
+//
+// Ошибки:
+//   - ErrInvalidIDToken — токен не прошёл проверку
+//   - ошибка загрузки JWKS (провайдер недоступен)
+func (v *OIDCVerifier) Verify(ctx context.Context, raw string) (*IDTokenClaims, error) {
+	var fetchErr error
+	claims := &IDTokenClaims{}
+	parser := jwt.NewParser(
+		jwt.WithValidMethods(idTokenAlgorithms),
+		jwt.WithIssuer(v.cfg.Issuer),
+		jwt.WithAudience(v.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(idTokenLeeway),
+	)
+	_, err := parser.ParseWithClaims(raw, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, err := v.key(ctx, kid)
+		if err != nil && !errors.Is(err, ErrUnknownKeyID) {
+			fetchErr = err
+		}
+		if err != nil {
+			return nil, err
+		}
+		return key, nil
+	})
+	if fetchErr != nil {
+		return nil, fetchErr
+	}
+	if err != nil || claims.Subject == "" {
+		return nil, ErrInvalidIDToken
+	}
+	return claims, nil
+}
+
+// key возвращает ключ по kid, при необходимости перезагружая JWKS.
+//
+// Пустой kid допустим, если у провайдера ровно один ключ.
+func (v *OIDCVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	age := time.Since(v.fetchedAt)
+	if v.keys == nil || age > v.cfg.CacheTTL {
+		if err := v.refresh(ctx); err != nil {
+			return nil, err
+		}
+	} else if _, ok := v.lookup(kid); !ok && age > jwksMinRefresh {
+		if err := v.refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	key, ok := v.lookup(kid)
+	if !ok {
+		return nil, ErrUnknownKeyID
+	}
+	return key, nil
+}
+
+func (v *OIDCVerifier) lookup(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(v.keys) == 1 {
+		for _, k := range v.keys {
+			return k, true
+		}
+	}
+	k, ok := v.keys[kid]
+	return k, ok
+}
+
+// refresh загружает JWKS провайдера. Вызывается под v.mu.
+func (v *OIDCVerifier) refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.cfg.JWKSURL, nil)
+	if err != nil {
+		return fmt.Errorf("jwks request: %w", err)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []JWK `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSBytes)).Decode(&set); err != nil {
+		return fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		// ключи шифрования и неподдерживаемые типы пропускаем
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.PublicKey()
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	v.keys = keys
+	v.fetchedAt = time.Now()
+	return nil
+}
+
+// PublicKey восстанавливает публичный ключ из JWK (RSA, EC P-256, Ed25519).
+func (k JWK) PublicKey() (crypto.PublicKey, error) {
+	b64 := base64.RawURLEncoding
+	switch k.Kty {
+	case "RSA":
+		n, err := b64.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwk n: %w", err)
+		}
+		e, err := b64.DecodeString(k.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, errors.New("jwk e: invalid exponent")
+		}
+		pub := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if pub.N.BitLen() < minRSABits {
+			return nil, fmt.Errorf("rsa key too short: %d bits", pub.N.BitLen())
+		}
+		return pub, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, errX := b64.DecodeString(k.X)
+		y, errY := b64.DecodeString(k.Y)
+		if errX != nil || errY != nil || len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("jwk ec: invalid coordinates")
+		}
+		// uncompressed point: 0x04 || X || Y
+		point := append(append([]byte{4}, x...), y...)
+		pub, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), point)
+		if err != nil {
+			return nil, fmt.Errorf("jwk ec: %w", err)
+		}
+		return pub, nil
+	case "OKP":
+		x, err := b64.DecodeString(k.X)
+		if k.Crv != "Ed25519" || err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("jwk okp: invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
@@ -10,8 +10,42 @@ import (
 	"strings"
 
 	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
 )
 
+// Префиксы хэшей, по которым определяется алгоритм.
+const (
+	argon2Prefix = "argon2id$"
+)
+
+// bcryptPrefixes — варианты префикса bcrypt ($2a$ — Go, $2b$/$2y$ — другие реализации).
+var bcryptPrefixes = []string{"$2a$", "$2b$", "$2y$"}
+
+// ErrUnknownHashFormat — хэш не относится ни к одному из поддерживаемых алгоритмов.
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+// ErrPasswordTooLong — пароль длиннее, чем принимает алгоритм (bcrypt — 72 байта).
+var ErrPasswordTooLong = errors.New("password too long")
+
+// BcryptMaxPasswordLen — сколько байт пароля принимает bcrypt.
+const BcryptMaxPasswordLen = 72
+
+// PasswordHasher — алгоритм хэширования паролей.
+//
+// Реализации:
+//   - Argon2Hasher (argon2id)
+//   - BcryptHasher (bcrypt)
+type PasswordHasher interface {
+	// Hash хэширует пароль с текущими параметрами.
+	Hash(password string) (string, error)
+	// Verify проверяет пароль по хэшу этого алгоритма.
+	Verify(password, encoded string) (bool, error)
+	// Matches сообщает, создан ли хэш этим алгоритмом (по префиксу).
+	Matches(encoded string) bool
+	// NeedsRehash сообщает, что хэш создан с устаревшими параметрами.
+	NeedsRehash(encoded string) bool
+}
+
 // Argon2Params описывает параметры алгоритма Argon2id.
 //
 // Параметры должны подбираться с учётом производительности сервера
@@ -65,7 +99,8 @@ func HashPassword(password string, p Argon2Params) (string, error) {
 
 // VerifyPassword проверяет, соответствует ли пароль ранее сохранённому хэшу.
 //
-// Функция:
+// Алгоритм определяется по префиксу хэша (argon2id или bcrypt).
+// Для argon2id функция:
 //   - извлекает параметры Argon2 из encoded-строки
 //   - повторно вычисляет хэш
 //   - сравнивает его с сохранённым в constant-time
@@ -76,28 +111,159 @@ func HashPassword(password string, p Argon2Params) (string, error) {
 //
 // Ошибка возвращается только при некорректном формате хэша.
 func VerifyPassword(password, encoded string) (bool, error) {
+	switch {
+	case strings.HasPrefix(encoded, argon2Prefix):
+		return verifyArgon2(password, encoded)
+	case isBcrypt(encoded):
+		return verifyBcrypt(password, encoded)
+	default:
+		return false, errors.New("invalid hash format")
+	}
+}
+
+// argon2Hash — разобранный хэш argon2id.
+type argon2Hash struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+	salt    []byte
+	hash    []byte
+}
+
+// parseArgon2 разбирает строку формата argon2id$v=19$m=..,t=..,p=..$salt$hash.
+func parseArgon2(encoded string) (argon2Hash, error) {
+	var h argon2Hash
+
 	parts := strings.Split(encoded, "$")
 	if len(parts) != 5 {
-		return false, errors.New("invalid hash format")
+		return h, errors.New("invalid hash format")
+	}
+
+	if _, err := fmt.Sscanf(parts[2], "m=%d,t=%d,p=%d", &h.memory, &h.time, &h.threads); err != nil {
+		return h, errors.New("invalid params format")
 	}
 
-	var memory uint32
-	var time uint32
-	var threads uint8
-	if _, err := fmt.Sscanf(parts[2], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
-		return false, errors.New("invalid params format")
+	var err error
+	h.salt, err = base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return h, errors.New("invalid salt")
 	}
 
-	salt, err := base64.RawStdEncoding.DecodeString(parts[3])
+	h.hash, err = base64.RawStdEncoding.DecodeString(parts[4])
 	if err != nil {
-		return false, errors.New("invalid salt")
+		return h, errors.New("invalid hash")
 	}
 
-	wantHash, err := base64.RawStdEncoding.DecodeString(parts[4])
+	return h, nil
+}
+
+func verifyArgon2(password, encoded string) (bool, error) {
+	h, err := parseArgon2(encoded)
 	if err != nil {
-		return false, errors.New("invalid hash")
+		return false, err
+	}
+
+	got := argon2.IDKey([]byte(password), h.salt, h.time, h.memory, h.threads, uint32(len(h.hash)))
+	return subtle.ConstantTimeCompare(got, h.hash) == 1, nil
+}
+
+func isBcrypt(encoded string) bool {
+	for _, p := range bcryptPrefixes {
+		if strings.HasPrefix(encoded, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func verifyBcrypt(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, bcrypt.ErrMismatchedHashAndPassword):
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid bcrypt hash: %w", err)
 	}
+}
+
+// Argon2Hasher — PasswordHasher на argon2id.
+type Argon2Hasher struct {
+	Params Argon2Params
+}
 
-	got := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(wantHash)))
-	return subtle.ConstantTimeCompare(got, wantHash) == 1, nil
+// Hash хэширует пароль argon2id с параметрами Params.
+func (h Argon2Hasher) Hash(password string) (string, error) {
+	return HashPassword(password, h.Params)
+}
+
+// Verify проверяет пароль по argon2id-хэшу (параметры берутся из хэша).
+func (h Argon2Hasher) Verify(password, encoded string) (bool, error) {
+	return verifyArgon2(password, encoded)
+}
+
+// Matches — хэш начинается с argon2id$.
+func (h Argon2Hasher) Matches(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2Prefix)
+}
+
+// NeedsRehash сравнивает параметры хэша (m, t, p, длины соли и ключа) с текущими.
+func (h Argon2Hasher) NeedsRehash(encoded string) bool {
+	parsed, err := parseArgon2(encoded)
+	if err != nil {
+		return true
+	}
+	return parsed.memory != h.Params.MemoryKiB ||
+		parsed.time != h.Params.Time ||
+		parsed.threads != h.Params.Threads ||
+		uint32(len(parsed.salt)) != h.Params.SaltLen ||
+		uint32(len(parsed.hash)) != h.Params.KeyLen
+}
+
+// BcryptHasher — PasswordHasher на bcrypt.
+//
+// bcrypt учитывает только первые 72 байта пароля,
+// более длинные пароли отклоняются при хэшировании (ErrPasswordTooLong).
+type BcryptHasher struct {
+	Cost int
+}
+
+// Hash хэширует пароль bcrypt с заданным Cost.
+//
+// Ошибки:
+//   - если пароль пустой
+//   - ErrPasswordTooLong если пароль длиннее BcryptMaxPasswordLen байт
+func (h BcryptHasher) Hash(password string) (string, error) {
+	if strings.TrimSpace(password) == "" {
+		return "", errors.New("empty password")
+	}
+	if len(password) > BcryptMaxPasswordLen {
+		return "", ErrPasswordTooLong
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", fmt.Errorf("bcrypt: %w", err)
+	}
+	return string(hash), nil
+}
+
+// Verify проверяет пароль по bcrypt-хэшу.
+func (h BcryptHasher) Verify(password, encoded string) (bool, error) {
+	return verifyBcrypt(password, encoded)
+}
+
+// Matches — хэш начинается с $2a$/$2b$/$2y$.
+func (h BcryptHasher) Matches(encoded string) bool {
+	return isBcrypt(encoded)
+}
+
+// NeedsRehash — cost хэша отличается от текущего.
+func (h BcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return true
+	}
+	return cost != h.Cost
 }
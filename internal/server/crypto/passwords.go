@@ -0,0 +1,135 @@
+// Выбор алгоритма хэширования паролей и pepper
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+// pepperPrefix помечает хэши, при вычислении которых использовался pepper.
+//
+// Пометка нужна, чтобы включение pepper не ломало вход пользователям
+// со старыми хэшами: они проверяются без pepper и перехэшируются при логине.
+const pepperPrefix = "pepper$"
+
+// ErrPepperRequired — хэш создан с pepper, а pepper в конфиге не задан.
+var ErrPepperRequired = errors.New("password hash requires pepper")
+
+// Passwords хэширует и проверяет пароли.
+//
+// Новые хэши создаются текущим алгоритмом (current),
+// проверка выполняется любым из известных алгоритмов — он определяется
+// по префиксу сохранённого хэша. Это позволяет сменить password.hasher
+// или параметры без сброса паролей: устаревший хэш заменяется при логине
+// (см. NeedsRehash).
+//
+// Если задан pepper, пароль перед хэшированием пропускается через
+// HMAC-SHA256 с этим ключом. Pepper хранится вне БД (в env),
+// поэтому утечка одной таблицы users не даёт перебирать пароли.
+type Passwords struct {
+	current PasswordHasher
+	known   []PasswordHasher
+	pepper  []byte
+}
+
+// NewPasswords создаёт Passwords.
+//
+// current — алгоритм для новых хэшей, pepper — ключ HMAC (может быть пустым),
+// known — дополнительные алгоритмы, хэши которых нужно уметь проверять.
+func NewPasswords(current PasswordHasher, pepper []byte, known ...PasswordHasher) *Passwords {
+	return &Passwords{
+		current: current,
+		known:   append([]PasswordHasher{current}, known...),
+		pepper:  pepper,
+	}
+}
+
+// Hash хэширует пароль текущим алгоритмом (с pepper, если он задан).
+func (p *Passwords) Hash(password string) (string, error) {
+	if strings.TrimSpace(password) == "" {
+		return "", errors.New("empty password")
+	}
+
+	if len(p.pepper) == 0 {
+		return p.current.Hash(password)
+	}
+
+	hash, err := p.current.Hash(p.applyPepper(password))
+	if err != nil {
+		return "", err
+	}
+	return pepperPrefix + hash, nil
+}
+
+// Accepts сообщает, можно ли захэшировать пароль текущим алгоритмом.
+//
+// Ограничение есть только у bcrypt без pepper: пароль длиннее
+// BcryptMaxPasswordLen байт он не принимает. С pepper в bcrypt уходит
+// HMAC фиксированной длины, поэтому длина пароля не важна.
+func (p *Passwords) Accepts(password string) bool {
+	if len(p.pepper) > 0 {
+		return true
+	}
+	if _, ok := p.current.(BcryptHasher); ok {
+		return len(password) <= BcryptMaxPasswordLen
+	}
+	return true
+}
+
+// Verify проверяет пароль по сохранённому хэшу.
+//
+// Ошибки:
+//   - ErrUnknownHashFormat если алгоритм хэша не поддерживается
+//   - ErrPepperRequired если хэш создан с pepper, а pepper не задан
+func (p *Passwords) Verify(password, encoded string) (bool, error) {
+	inner, peppered := strings.CutPrefix(encoded, pepperPrefix)
+	if peppered {
+		if len(p.pepper) == 0 {
+			return false, ErrPepperRequired
+		}
+		password = p.applyPepper(password)
+	}
+
+	h := p.hasherFor(inner)
+	if h == nil {
+		return false, ErrUnknownHashFormat
+	}
+	return h.Verify(password, inner)
+}
+
+// NeedsRehash сообщает, что хэш нужно пересчитать текущим алгоритмом:
+//   - хэш создан другим алгоритмом
+//   - у алгоритма изменились параметры
+//   - pepper включили (или выключили) после создания хэша
+func (p *Passwords) NeedsRehash(encoded string) bool {
+	inner, peppered := strings.CutPrefix(encoded, pepperPrefix)
+	if peppered != (len(p.pepper) > 0) {
+		return true
+	}
+	if !p.current.Matches(inner) {
+		return true
+	}
+	return p.current.NeedsRehash(inner)
+}
+
+func (p *Passwords) hasherFor(encoded string) PasswordHasher {
+	for _, h := range p.known {
+		if h.Matches(encoded) {
+			return h
+		}
+	}
+	return nil
+}
+
+// applyPepper возвращает HMAC-SHA256(pepper, password) в base64.
+//
+// base64 нужен из-за bcrypt: он обрезает пароль по нулевому байту
+// и по длине 72 байта, а 32 байта HMAC в base64 — это 44 символа.
+func (p *Passwords) applyPepper(password string) string {
+	mac := hmac.New(sha256.New, p.pepper)
+	mac.Write([]byte(password))
+	return base64.RawStdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,72 @@
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidChallenge — challenge подделан, повреждён или истёк.
+var ErrInvalidChallenge = errors.New("invalid proof-of-work challenge")
+
+// PoWChallenge — проверенный challenge proof-of-work.
+type PoWChallenge struct {
+	Nonce      string
+	Difficulty int
+	ExpiresAt  time.Time
+}
+
+// NewPoWChallenge выпускает challenge вида "<nonce>.<difficulty>.<expires>.<hmac>".
+//
+// Сервер ничего не хранит: сложность и срок защищены HMAC-SHA256 с ключом key,
+// поэтому клиент не может ни упростить задачу, ни продлить её.
+func NewPoWChallenge(key []byte, difficulty int, expiresAt time.Time) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(b) + "." +
+		strconv.Itoa(difficulty) + "." +
+		strconv.FormatInt(expiresAt.Unix(), 10)
+	return payload + "." + powSignature(key, payload), nil
+}
+
+// ParsePoWChallenge проверяет подпись и срок действия challenge.
+//
+// Возвращает ErrInvalidChallenge, если challenge подделан, повреждён или истёк к now.
+func ParsePoWChallenge(key []byte, challenge string, now time.Time) (PoWChallenge, error) {
+	parts := strings.Split(challenge, ".")
+	if len(parts) != 4 {
+		return PoWChallenge{}, ErrInvalidChallenge
+	}
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(parts[3]), []byte(powSignature(key, payload))) {
+		return PoWChallenge{}, ErrInvalidChallenge
+	}
+
+	difficulty, err := strconv.Atoi(parts[1])
+	if err != nil || difficulty < 0 {
+		return PoWChallenge{}, ErrInvalidChallenge
+	}
+	exp, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return PoWChallenge{}, ErrInvalidChallenge
+	}
+	expiresAt := time.Unix(exp, 0)
+	if !now.Before(expiresAt) {
+		return PoWChallenge{}, ErrInvalidChallenge
+	}
+
+	return PoWChallenge{Nonce: parts[0], Difficulty: difficulty, ExpiresAt: expiresAt}, nil
+}
+
+func powSignature(key []byte, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
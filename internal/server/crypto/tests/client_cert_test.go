@@ -0,0 +1,190 @@
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	crypt "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+)
+
+// testCA — самоподписанный CA для выпуска клиентских сертификатов в тестах
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test client CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create CA: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	return testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue выпускает клиентский сертификат с указанным extended key usage
+func (ca testCA) issue(t *testing.T, cn string, usage x509.ExtKeyUsage, notAfter time.Time) []byte {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatalf("create cert: %v", err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestLoadCertPool(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	ca := newTestCA(t)
+
+	path := filepath.Join(dir, "ca.crt")
+	if err := os.WriteFile(path, ca.pem, 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := crypt.LoadCertPool(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// файл без сертификатов
+	empty := filepath.Join(dir, "empty.crt")
+	if err := os.WriteFile(empty, []byte("not a pem"), 0o600); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if _, err := crypt.LoadCertPool(empty); err == nil {
+		t.Fatal("expected error for file without certificates")
+	}
+
+	if _, err := crypt.LoadCertPool(filepath.Join(dir, "missing.crt")); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+}
+
+func TestParseClientCertPEM(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t)
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	now := time.Now()
+
+	cert, err := crypt.ParseClientCertPEM(ca.issue(t, "laptop", x509.ExtKeyUsageClientAuth, now.Add(time.Hour)), roots, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cert.Subject.CommonName != "laptop" {
+		t.Fatalf("unexpected subject: %v", cert.Subject)
+	}
+
+	other := newTestCA(t)
+	cases := map[string][]byte{
+		"not pem":        []byte("garbage"),
+		"untrusted CA":   other.issue(t, "laptop", x509.ExtKeyUsageClientAuth, now.Add(time.Hour)),
+		"server usage":   ca.issue(t, "laptop", x509.ExtKeyUsageServerAuth, now.Add(time.Hour)),
+		"expired":        ca.issue(t, "laptop", x509.ExtKeyUsageClientAuth, now.Add(-time.Minute)),
+		"private key":    pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("x")}),
+		"broken der":     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("x")}),
+		"empty document": nil,
+	}
+	for name, data := range cases {
+		if _, err := crypt.ParseClientCertPEM(data, roots, now); !errors.Is(err, crypt.ErrInvalidCertificate) {
+			t.Fatalf("%s: expected ErrInvalidCertificate, got %v", name, err)
+		}
+	}
+}
+
+func TestCertFingerprintAndThumbprint(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t)
+	sum := sha256.Sum256(ca.cert.Raw)
+
+	fp := crypt.CertFingerprint(ca.cert)
+	if string(fp) != string(sum[:]) {
+		t.Fatal("fingerprint must be SHA-256 of DER")
+	}
+	if got := crypt.CertThumbprint(fp); got != base64.RawURLEncoding.EncodeToString(sum[:]) {
+		t.Fatalf("unexpected thumbprint %q", got)
+	}
+}
+
+func TestNewBoundAccessToken_Confirmation(t *testing.T) {
+	t.Parallel()
+	cfg := crypt.JWTConfig{
+		Issuer:     "gophkeeper",
+		Audience:   "gophkeeper-cli",
+		SigningKey: "supersecretkeysupersecretkey123456",
+		AccessTTL:  5 * time.Minute,
+	}
+
+	parse := func(tokenStr string) *crypt.AccessClaims {
+		claims := &crypt.AccessClaims{}
+		_, err := jwt.ParseWithClaims(tokenStr, claims, func(*jwt.Token) (any, error) {
+			return []byte(cfg.SigningKey), nil
+		})
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		return claims
+	}
+
+	bound, err := crypt.NewBoundAccessToken("user", "sid", "thumb", cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c := parse(bound); c.Confirmation == nil || c.Confirmation.CertThumbprint != "thumb" {
+		t.Fatalf("expected cnf.x5t#S256, got %+v", c.Confirmation)
+	}
+
+	// без отпечатка claim cnf не добавляется
+	plain, err := crypt.NewAccessToken("user", "sid", cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if c := parse(plain); c.Confirmation != nil {
+		t.Fatalf("expected no cnf, got %+v", c.Confirmation)
+	}
+}
@@ -0,0 +1,43 @@
+package tests
+
+import (
+	"regexp"
+	"testing"
+
+	crypt "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+)
+
+// Формат пользовательского кода и отсутствие повторов
+func TestNewUserCode(t *testing.T) {
+	re := regexp.MustCompile(`^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$`)
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		code, err := crypt.NewUserCode()
+		if err != nil {
+			t.Fatalf("NewUserCode: %v", err)
+		}
+		if !re.MatchString(code) {
+			t.Fatalf("unexpected code format: %q", code)
+		}
+		if seen[code] {
+			t.Fatalf("duplicate code %q", code)
+		}
+		seen[code] = true
+	}
+}
+
+// Регистр, пробелы и дефис не важны; чужие символы отклоняются
+func TestNormalizeUserCode(t *testing.T) {
+	for _, in := range []string{"BCDF-GHJK", "bcdfghjk", " bcdf ghjk "} {
+		got, ok := crypt.NormalizeUserCode(in)
+		if !ok || got != "BCDF-GHJK" {
+			t.Fatalf("%q: expected BCDF-GHJK, got %q ok=%v", in, got, ok)
+		}
+	}
+
+	for _, in := range []string{"", "BCDF-GHJ", "BCDF-GHJKL", "ABCD-EFGH", "BCDF-GHJ1"} {
+		if _, ok := crypt.NormalizeUserCode(in); ok {
+			t.Fatalf("%q: expected to be rejected", in)
+		}
+	}
+}
@@ -18,8 +18,9 @@ func TestNewAccessToken_Success(t *testing.T) {
 	}
 
 	userID := "user-123"
+	sessionID := "session-1"
 
-	tokenStr, err := crypt.NewAccessToken(userID, cfg)
+	tokenStr, err := crypt.NewAccessToken(userID, sessionID, cfg)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -30,7 +31,7 @@ func TestNewAccessToken_Success(t *testing.T) {
 	// Парсим и валидируем токен
 	parsed, err := jwt.ParseWithClaims(
 		tokenStr,
-		&jwt.RegisteredClaims{},
+		&crypt.AccessClaims{},
 		func(token *jwt.Token) (any, error) {
 			// Проверяем алгоритм
 			if token.Method != jwt.SigningMethodHS256 {
@@ -47,11 +48,18 @@ func TestNewAccessToken_Success(t *testing.T) {
 		t.Fatal("token is not valid")
 	}
 
-	claims, ok := parsed.Claims.(*jwt.RegisteredClaims)
+	claims, ok := parsed.Claims.(*crypt.AccessClaims)
 	if !ok {
 		t.Fatal("claims type assertion failed")
 	}
 
+	if claims.SessionID != sessionID {
+		t.Fatalf("expected sid %q, got %q", sessionID, claims.SessionID)
+	}
+	if claims.ID == "" {
+		t.Fatal("expected non-empty jti")
+	}
+
 	if claims.Subject != userID {
 		t.Fatalf("expected subject %q, got %q", userID, claims.Subject)
 	}
@@ -78,7 +86,7 @@ func TestNewAccessToken_EmptySigningKey_TokenDoesNotValidateWithNonEmptyKey(t *t
 		AccessTTL:  time.Minute,
 	}
 
-	tokenStr, err := crypt.NewAccessToken("user", cfg)
+	tokenStr, err := crypt.NewAccessToken("user", "", cfg)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -108,7 +116,7 @@ func TestNewAccessToken_ExpirationRespected(t *testing.T) {
 		AccessTTL:  1 * time.Second,
 	}
 
-	tokenStr, err := crypt.NewAccessToken("user", cfg)
+	tokenStr, err := crypt.NewAccessToken("user", "", cfg)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -128,3 +136,47 @@ func TestNewAccessToken_ExpirationRespected(t *testing.T) {
 		t.Fatal("expected token to be expired")
 	}
 }
+
+func TestNewElevatedAccessToken_SetsElevatedUntil(t *testing.T) {
+	t.Parallel()
+	cfg := crypt.JWTConfig{
+		Issuer:     "issuer",
+		Audience:   "aud",
+		SigningKey: "supersecretkeysupersecretkey123456",
+		AccessTTL:  15 * time.Minute,
+	}
+	until := time.Now().Add(5 * time.Minute).Truncate(time.Second)
+
+	tokenStr, err := crypt.NewElevatedAccessToken("user", "session-1", "", until, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	claims := &crypt.AccessClaims{}
+	if _, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (any, error) {
+		return []byte(cfg.SigningKey), nil
+	}); err != nil {
+		t.Fatalf("failed to parse token: %v", err)
+	}
+	if claims.ElevatedUntil == nil || !claims.ElevatedUntil.Time.Equal(until) {
+		t.Fatalf("expected elevated_until %v, got %v", until, claims.ElevatedUntil)
+	}
+	if claims.SessionID != "session-1" {
+		t.Fatalf("expected sid session-1, got %q", claims.SessionID)
+	}
+
+	// обычный токен повышенных прав не даёт
+	plain, err := crypt.NewAccessToken("user", "session-1", cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	plainClaims := &crypt.AccessClaims{}
+	if _, err := jwt.ParseWithClaims(plain, plainClaims, func(token *jwt.Token) (any, error) {
+		return []byte(cfg.SigningKey), nil
+	}); err != nil {
+		t.Fatalf("failed to parse token: %v", err)
+	}
+	if plainClaims.ElevatedUntil != nil {
+		t.Fatalf("expected no elevated_until, got %v", plainClaims.ElevatedUntil)
+	}
+}
@@ -0,0 +1,156 @@
+package tests
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	crypt "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func ed25519PEM(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	privDER, _ := x509.MarshalPKCS8PrivateKey(priv)
+	pubDER, _ := x509.MarshalPKIXPublicKey(pub)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})
+}
+
+func writeFile(t *testing.T, dir, name string, data []byte) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("write %s: %v", name, err)
+	}
+	return path
+}
+
+// Подпись активным ключом EdDSA: в заголовке kid, проверка публичным ключом
+func TestNewAccessToken_EdDSA_WithKid(t *testing.T) {
+	privPEM, _ := ed25519PEM(t)
+
+	key, err := crypt.ParseSigningKey(privPEM, crypt.AlgEdDSA, "k1")
+	if err != nil {
+		t.Fatalf("ParseSigningKey: %v", err)
+	}
+	ks, err := crypt.NewKeySet(key)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+
+	tokenStr, err := crypt.NewAccessToken("user-1", "", crypt.JWTConfig{
+		Issuer:    "iss",
+		Audience:  "aud",
+		Keys:      ks,
+		AccessTTL: time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("NewAccessToken: %v", err)
+	}
+
+	parsed, err := jwt.Parse(tokenStr, func(tok *jwt.Token) (any, error) {
+		if tok.Header["kid"] != "k1" {
+			t.Fatalf("unexpected kid: %v", tok.Header["kid"])
+		}
+		return key.Public, nil
+	}, jwt.WithValidMethods([]string{"EdDSA"}))
+	if err != nil || !parsed.Valid {
+		t.Fatalf("token not valid: %v", err)
+	}
+}
+
+// RS256: короткие ключи отклоняются, нормальные — принимаются
+func TestParseSigningKey_RSA(t *testing.T) {
+	small, _ := rsa.GenerateKey(rand.Reader, 1024)
+	smallPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(small)})
+	if _, err := crypt.ParseSigningKey(smallPEM, crypt.AlgRS256, ""); err == nil {
+		t.Fatal("expected error for 1024-bit rsa key")
+	}
+
+	big, _ := rsa.GenerateKey(rand.Reader, 2048)
+	bigPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(big)})
+	key, err := crypt.ParseSigningKey(bigPEM, crypt.AlgRS256, "")
+	if err != nil {
+		t.Fatalf("ParseSigningKey: %v", err)
+	}
+	if key.ID == "" {
+		t.Fatal("expected derived kid")
+	}
+
+	// тип ключа не совпадает с алгоритмом
+	if _, err := crypt.ParseSigningKey(bigPEM, crypt.AlgEdDSA, ""); err == nil {
+		t.Fatal("expected error for rsa key with EdDSA")
+	}
+}
+
+// сгенерированный ключ читается как private_key_file своего алгоритма
+func TestGenerateSigningKey(t *testing.T) {
+	for _, alg := range []string{crypt.AlgEdDSA, crypt.AlgRS256} {
+		raw, err := crypt.GenerateSigningKey(alg)
+		if err != nil {
+			t.Fatalf("GenerateSigningKey(%s): %v", alg, err)
+		}
+		key, err := crypt.ParseSigningKey(raw, alg, "")
+		if err != nil {
+			t.Fatalf("ParseSigningKey(%s): %v", alg, err)
+		}
+		if key.Private == nil || key.ID == "" {
+			t.Fatalf("%s: expected private key with derived kid", alg)
+		}
+	}
+
+	if _, err := crypt.GenerateSigningKey("HS256"); err == nil {
+		t.Fatal("expected error for HS256")
+	}
+}
+
+// Загрузка из файлов: retired ключ без приватной части, JWKS содержит оба ключа
+func TestLoadKeySet_ActiveAndRetired(t *testing.T) {
+	dir := t.TempDir()
+
+	activePriv, _ := ed25519PEM(t)
+	_, retiredPub := ed25519PEM(t)
+
+	ks, err := crypt.LoadKeySet(
+		crypt.KeyFile{ID: "new", Alg: crypt.AlgEdDSA, Path: writeFile(t, dir, "new.pem", activePriv)},
+		crypt.KeyFile{ID: "old", Alg: crypt.AlgEdDSA, Path: writeFile(t, dir, "old.pub.pem", retiredPub)},
+	)
+	if err != nil {
+		t.Fatalf("LoadKeySet: %v", err)
+	}
+
+	if ks.Active().ID != "new" {
+		t.Fatalf("unexpected active kid %q", ks.Active().ID)
+	}
+	old, ok := ks.Lookup("old")
+	if !ok || old.Private != nil {
+		t.Fatalf("expected retired verification-only key, got %+v", old)
+	}
+
+	set := ks.JWKS()
+	if len(set.Keys) != 2 || set.Keys[0].Kid != "new" || set.Keys[1].Kid != "old" {
+		t.Fatalf("unexpected jwks: %+v", set)
+	}
+	if set.Keys[0].Kty != "OKP" || set.Keys[0].Crv != "Ed25519" || set.Keys[0].X == "" {
+		t.Fatalf("unexpected jwk: %+v", set.Keys[0])
+	}
+
+	// активный ключ без приватной части не допускается
+	if _, err := crypt.LoadKeySet(crypt.KeyFile{Alg: crypt.AlgEdDSA, Path: filepath.Join(dir, "old.pub.pem")}); err == nil {
+		t.Fatal("expected error for public-only active key")
+	}
+}
@@ -0,0 +1,223 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	crypt "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+)
+
+// stubIdP — локальный OpenID Connect провайдер: отдаёт JWKS и подписывает ID-токены.
+type stubIdP struct {
+	srv     *httptest.Server
+	key     *rsa.PrivateKey
+	kid     string
+	fetches atomic.Int32
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	idp := &stubIdP{key: key, kid: "idp-key-1"}
+
+	idp.srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idp.fetches.Add(1)
+		json.NewEncoder(w).Encode(crypt.JWKS{Keys: []crypt.JWK{{
+			Kty: "RSA",
+			Kid: idp.kid,
+			Alg: "RS256",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	t.Cleanup(idp.srv.Close)
+	return idp
+}
+
+func (idp *stubIdP) issuer() string { return idp.srv.URL }
+
+func (idp *stubIdP) verifier() *crypt.OIDCVerifier {
+	return crypt.NewOIDCVerifier(crypt.OIDCConfig{
+		Issuer:     idp.issuer(),
+		ClientID:   "gophkeeper",
+		JWKSURL:    idp.srv.URL + "/jwks",
+		CacheTTL:   time.Hour,
+		HTTPClient: idp.srv.Client(),
+	})
+}
+
+// token подписывает ID-токен; mutate меняет claims перед подписью.
+func (idp *stubIdP) token(t *testing.T, mutate func(*crypt.IDTokenClaims)) string {
+	t.Helper()
+
+	claims := &crypt.IDTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    idp.issuer(),
+			Subject:   "user-42",
+			Audience:  jwt.ClaimStrings{"gophkeeper"},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(5 * time.Minute)),
+		},
+		Email:         "alice@corp.example",
+		EmailVerified: true,
+	}
+	if mutate != nil {
+		mutate(claims)
+	}
+	tok := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	tok.Header["kid"] = idp.kid
+	raw, err := tok.SignedString(idp.key)
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return raw
+}
+
+func TestOIDCVerifier_Verify_OK(t *testing.T) {
+	t.Parallel()
+
+	idp := newStubIdP(t)
+	v := idp.verifier()
+
+	claims, err := v.Verify(context.Background(), idp.token(t, nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claims.Subject != "user-42" || claims.Email != "alice@corp.example" || !claims.EmailVerified {
+		t.Fatalf("unexpected claims: %+v", claims)
+	}
+
+	// ключи кэшируются: второй токен проверяется без запроса к провайдеру
+	if _, err := v.Verify(context.Background(), idp.token(t, nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := idp.fetches.Load(); n != 1 {
+		t.Fatalf("expected 1 jwks fetch, got %d", n)
+	}
+}
+
+func TestOIDCVerifier_Verify_Rejects(t *testing.T) {
+	t.Parallel()
+
+	idp := newStubIdP(t)
+	v := idp.verifier()
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	forged := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		Issuer:    idp.issuer(),
+		Subject:   "user-42",
+		Audience:  jwt.ClaimStrings{"gophkeeper"},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	})
+	forged.Header["kid"] = idp.kid
+	forgedRaw, _ := forged.SignedString(otherKey)
+
+	cases := map[string]string{
+		"wrong audience": idp.token(t, func(c *crypt.IDTokenClaims) { c.Audience = jwt.ClaimStrings{"other-app"} }),
+		"wrong issuer":   idp.token(t, func(c *crypt.IDTokenClaims) { c.Issuer = "https://evil.example" }),
+		"expired":        idp.token(t, func(c *crypt.IDTokenClaims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour)) }),
+		"no expiry":      idp.token(t, func(c *crypt.IDTokenClaims) { c.ExpiresAt = nil }),
+		"no subject":     idp.token(t, func(c *crypt.IDTokenClaims) { c.Subject = "" }),
+		"forged":         forgedRaw,
+		"garbage":        "not-a-jwt",
+	}
+	for name, raw := range cases {
+		if _, err := v.Verify(context.Background(), raw); !errors.Is(err, crypt.ErrInvalidIDToken) {
+			t.Fatalf("%s: expected ErrInvalidIDToken, got %v", name, err)
+		}
+	}
+}
+
+// Неизвестный kid не вызывает повторной загрузки JWKS чаще раза в минуту
+func TestOIDCVerifier_UnknownKid(t *testing.T) {
+	t.Parallel()
+
+	idp := newStubIdP(t)
+	v := idp.verifier()
+
+	if _, err := v.Verify(context.Background(), idp.token(t, nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	idp.kid = "rotated"
+	for range 3 {
+		if _, err := v.Verify(context.Background(), idp.token(t, nil)); !errors.Is(err, crypt.ErrInvalidIDToken) {
+			t.Fatalf("expected ErrInvalidIDToken, got %v", err)
+		}
+	}
+	if n := idp.fetches.Load(); n != 1 {
+		t.Fatalf("expected 1 jwks fetch, got %d", n)
+	}
+}
+
+// Провайдер недоступен — это не "неверный токен"
+func TestOIDCVerifier_JWKSUnavailable(t *testing.T) {
+	t.Parallel()
+
+	idp := newStubIdP(t)
+	raw := idp.token(t, nil)
+	v := idp.verifier()
+	idp.srv.Close()
+
+	_, err := v.Verify(context.Background(), raw)
+	if err == nil || errors.Is(err, crypt.ErrInvalidIDToken) {
+		t.Fatalf("expected jwks fetch error, got %v", err)
+	}
+}
+
+func TestJWK_PublicKey(t *testing.T) {
+	t.Parallel()
+
+	ec, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	x, y := make([]byte, 32), make([]byte, 32)
+	ec.X.FillBytes(x)
+	ec.Y.FillBytes(y)
+
+	pub, err := crypt.JWK{Kty: "EC", Crv: "P-256",
+		X: base64.RawURLEncoding.EncodeToString(x),
+		Y: base64.RawURLEncoding.EncodeToString(y),
+	}.PublicKey()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ec.PublicKey.Equal(pub) {
+		t.Fatal("ec key mismatch")
+	}
+
+	bad := []crypt.JWK{
+		{Kty: "EC", Crv: "P-384", X: "AA", Y: "AA"},
+		{Kty: "RSA", N: base64.RawURLEncoding.EncodeToString([]byte{1, 2, 3}), E: "AQAB"},
+		{Kty: "OKP", Crv: "X25519", X: base64.RawURLEncoding.EncodeToString(make([]byte, 32))},
+		{Kty: "oct"},
+	}
+	for _, k := range bad {
+		if _, err := k.PublicKey(); err == nil {
+			t.Fatalf("expected error for %+v", k)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	crypt "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+)
+
+const testPepper = "pepperpepperpepperpepperpepper123"
+
+// bcrypt: хэш, проверка, определение по префиксу
+func TestBcryptHasher_HashAndVerify(t *testing.T) {
+	h := crypt.BcryptHasher{Cost: 4}
+
+	hash, err := h.Hash("super-secret-password")
+	if err != nil {
+		t.Fatalf("Hash error: %v", err)
+	}
+	if !h.Matches(hash) || (crypt.Argon2Hasher{}).Matches(hash) {
+		t.Fatalf("unexpected prefix detection for %q", hash)
+	}
+
+	ok, err := crypt.VerifyPassword("super-secret-password", hash)
+	if err != nil || !ok {
+		t.Fatalf("expected valid password, got ok=%v err=%v", ok, err)
+	}
+	ok, err = crypt.VerifyPassword("wrong-password", hash)
+	if err != nil || ok {
+		t.Fatalf("expected invalid password, got ok=%v err=%v", ok, err)
+	}
+
+	if h.NeedsRehash(hash) {
+		t.Fatal("same cost must not need rehash")
+	}
+	if !(crypt.BcryptHasher{Cost: 5}).NeedsRehash(hash) {
+		t.Fatal("other cost must need rehash")
+	}
+}
+
+// bcrypt: пароль длиннее 72 байт отклоняется, Accepts сообщает об этом заранее
+func TestBcryptHasher_PasswordTooLong(t *testing.T) {
+	h := crypt.BcryptHasher{Cost: 4}
+	long := strings.Repeat("a", crypt.BcryptMaxPasswordLen+1)
+
+	if _, err := h.Hash(long); !errors.Is(err, crypt.ErrPasswordTooLong) {
+		t.Fatalf("expected ErrPasswordTooLong, got %v", err)
+	}
+	if crypt.NewPasswords(h, nil).Accepts(long) {
+		t.Fatal("bcrypt without pepper must not accept long password")
+	}
+	if !crypt.NewPasswords(h, nil).Accepts(long[1:]) {
+		t.Fatal("bcrypt must accept 72-byte password")
+	}
+	if !crypt.NewPasswords(h, []byte(testPepper)).Accepts(long) {
+		t.Fatal("bcrypt with pepper must accept long password")
+	}
+	if !crypt.NewPasswords(crypt.Argon2Hasher{Params: defaultParams()}, nil).Accepts(long) {
+		t.Fatal("argon2id must accept long password")
+	}
+}
+
+// argon2id: изменение параметров требует перехэширования
+func TestArgon2Hasher_NeedsRehash(t *testing.T) {
+	h := crypt.Argon2Hasher{Params: defaultParams()}
+
+	hash, err := h.Hash("password")
+	if err != nil {
+		t.Fatalf("Hash error: %v", err)
+	}
+	if h.NeedsRehash(hash) {
+		t.Fatal("same params must not need rehash")
+	}
+
+	p := defaultParams()
+	p.Time = 2
+	if !(crypt.Argon2Hasher{Params: p}).NeedsRehash(hash) {
+		t.Fatal("other params must need rehash")
+	}
+}
+
+// Проверка хэшей обоих алгоритмов и rehash при смене алгоритма
+func TestPasswords_VerifiesKnownAlgorithms(t *testing.T) {
+	argon := crypt.Argon2Hasher{Params: defaultParams()}
+	bcr := crypt.BcryptHasher{Cost: 4}
+
+	oldHash, err := bcr.Hash("password")
+	if err != nil {
+		t.Fatalf("Hash error: %v", err)
+	}
+
+	p := crypt.NewPasswords(argon, nil, bcr)
+
+	ok, err := p.Verify("password", oldHash)
+	if err != nil || !ok {
+		t.Fatalf("expected bcrypt hash to verify, got ok=%v err=%v", ok, err)
+	}
+	if !p.NeedsRehash(oldHash) {
+		t.Fatal("bcrypt hash must need rehash when current is argon2id")
+	}
+
+	newHash, err := p.Hash("password")
+	if err != nil {
+		t.Fatalf("Hash error: %v", err)
+	}
+	if !strings.HasPrefix(newHash, "argon2id$") || p.NeedsRehash(newHash) {
+		t.Fatalf("unexpected new hash %q", newHash)
+	}
+
+	if _, err := p.Verify("password", "md5$abc"); !errors.Is(err, crypt.ErrUnknownHashFormat) {
+		t.Fatalf("expected ErrUnknownHashFormat, got %v", err)
+	}
+}
+
+// Pepper: без ключа хэш не проверить, включение pepper требует rehash
+func TestPasswords_Pepper(t *testing.T) {
+	argon := crypt.Argon2Hasher{Params: defaultParams()}
+
+	withPepper := crypt.NewPasswords(argon, []byte(testPepper))
+	hash, err := withPepper.Hash("password")
+	if err != nil {
+		t.Fatalf("Hash error: %v", err)
+	}
+
+	ok, err := withPepper.Verify("password", hash)
+	if err != nil || !ok {
+		t.Fatalf("expected valid password, got ok=%v err=%v", ok, err)
+	}
+
+	// хэш с pepper нельзя проверить обычной функцией
+	if _, err := crypt.VerifyPassword("password", hash); err == nil {
+		t.Fatal("expected error for peppered hash without pepper")
+	}
+
+	noPepper := crypt.NewPasswords(argon, nil)
+	if _, err := noPepper.Verify("password", hash); !errors.Is(err, crypt.ErrPepperRequired) {
+		t.Fatalf("expected ErrPepperRequired, got %v", err)
+	}
+
+	// старый хэш без pepper по-прежнему проверяется, но требует rehash
+	plain, _ := noPepper.Hash("password")
+	ok, err = withPepper.Verify("password", plain)
+	if err != nil || !ok {
+		t.Fatalf("expected plain hash to verify, got ok=%v err=%v", ok, err)
+	}
+	if !withPepper.NeedsRehash(plain) {
+		t.Fatal("plain hash must need rehash after enabling pepper")
+	}
+}
+
+// Pepper и bcrypt: длинные пароли не упираются в лимит 72 байта
+func TestPasswords_PepperWithBcrypt_LongPassword(t *testing.T) {
+	p := crypt.NewPasswords(crypt.BcryptHasher{Cost: 4}, []byte(testPepper))
+
+	long := strings.Repeat("a", 100)
+	hash, err := p.Hash(long)
+	if err != nil {
+		t.Fatalf("Hash error: %v", err)
+	}
+
+	ok, err := p.Verify(strings.Repeat("a", 99)+"b", hash)
+	if err != nil || ok {
+		t.Fatalf("expected different long password to be rejected, got ok=%v err=%v", ok, err)
+	}
+}
@@ -0,0 +1,52 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	crypt "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+)
+
+var powKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestPoWChallenge_RoundTrip(t *testing.T) {
+	now := time.Now()
+	challenge, err := crypt.NewPoWChallenge(powKey, 18, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("NewPoWChallenge error: %v", err)
+	}
+
+	ch, err := crypt.ParsePoWChallenge(powKey, challenge, now)
+	if err != nil {
+		t.Fatalf("ParsePoWChallenge error: %v", err)
+	}
+	if ch.Difficulty != 18 || ch.Nonce == "" || ch.ExpiresAt.Unix() != now.Add(time.Minute).Unix() {
+		t.Fatalf("unexpected challenge: %+v", ch)
+	}
+}
+
+// сложность и срок защищены подписью, просроченный challenge не принимается
+func TestPoWChallenge_Rejects(t *testing.T) {
+	now := time.Now()
+	challenge, _ := crypt.NewPoWChallenge(powKey, 18, now.Add(time.Minute))
+	parts := strings.Split(challenge, ".")
+
+	cases := map[string]struct {
+		challenge string
+		key       []byte
+		now       time.Time
+	}{
+		"easier":      {strings.Join([]string{parts[0], "1", parts[2], parts[3]}, "."), powKey, now},
+		"extended":    {strings.Join([]string{parts[0], parts[1], "9999999999", parts[3]}, "."), powKey, now},
+		"other key":   {challenge, []byte("another-key-another-key-another-k"), now},
+		"expired":     {challenge, powKey, now.Add(2 * time.Minute)},
+		"malformed":   {"abc", powKey, now},
+		"empty parts": {"...", powKey, now},
+	}
+	for name, c := range cases {
+		if _, err := crypt.ParsePoWChallenge(c.key, c.challenge, c.now); err != crypt.ErrInvalidChallenge {
+			t.Fatalf("%s: expected ErrInvalidChallenge, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"bytes"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	crypt "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+)
+
+// секрет из RFC 6238 (ASCII "12345678901234567890") в base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// Тестовые векторы RFC 6238 (SHA1), последние 6 цифр
+func TestTOTPCode_RFCVectors(t *testing.T) {
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, c := range cases {
+		got, err := crypt.TOTPCode(rfcSecret, crypt.TOTPStep(time.Unix(c.unix, 0)))
+		if err != nil {
+			t.Fatalf("TOTPCode: %v", err)
+		}
+		if got != c.want {
+			t.Fatalf("t=%d: expected %s, got %s", c.unix, c.want, got)
+		}
+	}
+}
+
+// Код принимается с допуском в один интервал, но не дальше
+func TestValidateTOTP_Skew(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	step := crypt.TOTPStep(now)
+
+	prev, _ := crypt.TOTPCode(rfcSecret, step-1)
+	if got, ok := crypt.ValidateTOTP(rfcSecret, prev, now); !ok || got != step-1 {
+		t.Fatalf("expected previous step accepted, got ok=%v step=%d", ok, got)
+	}
+
+	old, _ := crypt.TOTPCode(rfcSecret, step-2)
+	if _, ok := crypt.ValidateTOTP(rfcSecret, old, now); ok {
+		t.Fatal("expected code two steps old to be rejected")
+	}
+
+	if _, ok := crypt.ValidateTOTP(rfcSecret, "12345", now); ok {
+		t.Fatal("expected short code to be rejected")
+	}
+}
+
+// Секрет и otpauth URI
+func TestNewTOTPSecret_AndURI(t *testing.T) {
+	secret, err := crypt.NewTOTPSecret()
+	if err != nil {
+		t.Fatalf("NewTOTPSecret: %v", err)
+	}
+	if len(secret) != 32 {
+		t.Fatalf("expected 32 base32 chars (160 bit), got %d", len(secret))
+	}
+
+	code, err := crypt.TOTPCode(secret, crypt.TOTPStep(time.Now()))
+	if err != nil {
+		t.Fatalf("TOTPCode: %v", err)
+	}
+	if _, ok := crypt.ValidateTOTP(secret, code, time.Now()); !ok {
+		t.Fatal("expected fresh code to be valid")
+	}
+
+	u, err := url.Parse(crypt.TOTPURI("GophKeeper", "user@example.com", secret))
+	if err != nil {
+		t.Fatalf("parse uri: %v", err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		t.Fatalf("unexpected uri: %s", u)
+	}
+	if u.Path != "/GophKeeper:user@example.com" {
+		t.Fatalf("unexpected label: %q", u.Path)
+	}
+	q := u.Query()
+	if q.Get("secret") != secret || q.Get("issuer") != "GophKeeper" || q.Get("digits") != "6" || q.Get("period") != "30" {
+		t.Fatalf("unexpected query: %v", q)
+	}
+}
+
+// Коды восстановления: формат, уникальность, хэш не зависит от регистра и дефиса
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := crypt.NewRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("NewRecoveryCodes: %v", err)
+	}
+	if len(codes) != 10 {
+		t.Fatalf("expected 10 codes, got %d", len(codes))
+	}
+
+	re := regexp.MustCompile(`^[0-9a-z]{5}-[0-9a-z]{5}$`)
+	seen := map[string]bool{}
+	for _, c := range codes {
+		if !re.MatchString(c) {
+			t.Fatalf("unexpected code format: %q", c)
+		}
+		if seen[c] {
+			t.Fatalf("duplicate code %q", c)
+		}
+		seen[c] = true
+	}
+
+	h := crypt.HashRecoveryCode("abcde-fghjk")
+	if !bytes.Equal(h, crypt.HashRecoveryCode(" ABCDEFGHJK ")) {
+		t.Fatal("expected hash to ignore case, spaces and dash")
+	}
+	if bytes.Equal(h, crypt.HashRecoveryCode("abcde-fghjm")) {
+		t.Fatal("expected different codes to have different hashes")
+	}
+}
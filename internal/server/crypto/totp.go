@@ -0,0 +1,148 @@
+// TOTP (RFC 6238) и коды восстановления для двухфакторной аутентификации
+package crypto
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Параметры TOTP: совместимы с Google Authenticator, Aegis, 1Password и т.п.
+const (
+	TOTPDigits = 6
+	TOTPPeriod = 30 * time.Second
+
+	// totpSecretLen — длина секрета в байтах (160 бит, как рекомендует RFC 4226).
+	totpSecretLen = 20
+	// totpSkew — сколько шагов до/после текущего принимается (рассинхрон часов).
+	totpSkew = 1
+)
+
+// recoveryAlphabet — алфавит Crockford base32 (без i, l, o, u), 32 символа.
+const recoveryAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+var b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewTOTPSecret генерирует случайный TOTP-секрет в base32 (без padding).
+func NewTOTPSecret() (string, error) {
+	b := make([]byte, totpSecretLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return b32.EncodeToString(b), nil
+}
+
+// TOTPURI формирует otpauth:// URI для добавления секрета в приложение-аутентификатор
+// (обычно показывается QR-кодом).
+//
+// issuer — название сервиса, account — логин пользователя (email).
+func TOTPURI(issuer, account, secret string) string {
+	label := url.PathEscape(issuer + ":" + account)
+	q := url.Values{}
+	q.Set("secret", secret)
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", fmt.Sprint(TOTPDigits))
+	q.Set("period", fmt.Sprint(int(TOTPPeriod.Seconds())))
+	return "otpauth://totp/" + label + "?" + q.Encode()
+}
+
+// TOTPStep возвращает номер 30-секундного интервала для момента t.
+func TOTPStep(t time.Time) int64 {
+	return t.Unix() / int64(TOTPPeriod.Seconds())
+}
+
+// TOTPCode вычисляет код для секрета и номера интервала.
+func TOTPCode(secret string, step int64) (string, error) {
+	key, err := b32.DecodeString(strings.ToUpper(secret))
+	if err != nil {
+		return "", fmt.Errorf("decode totp secret: %w", err)
+	}
+
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(step))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// dynamic truncation (RFC 4226, 5.3)
+	off := sum[len(sum)-1] & 0x0f
+	bin := binary.BigEndian.Uint32(sum[off:off+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < TOTPDigits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", TOTPDigits, bin%mod), nil
+}
+
+// ValidateTOTP проверяет код на момент now с допуском ±1 интервал.
+//
+// Возвращает номер интервала, которому соответствует код: вызывающий
+// должен запомнить его и не принимать код того же или более раннего
+// интервала повторно (защита от replay).
+func ValidateTOTP(secret, code string, now time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != TOTPDigits {
+		return 0, false
+	}
+
+	cur := TOTPStep(now)
+	for d := int64(-totpSkew); d <= totpSkew; d++ {
+		want, err := TOTPCode(secret, cur+d)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(want), []byte(code)) == 1 {
+			return cur + d, true
+		}
+	}
+	return 0, false
+}
+
+// NewRecoveryCodes генерирует n одноразовых кодов восстановления вида "xxxxx-xxxxx".
+//
+// Коды показываются пользователю один раз, в БД хранятся только хэши (HashRecoveryCode).
+func NewRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	buf := make([]byte, 10)
+	for i := 0; i < n; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		var sb strings.Builder
+		for j, b := range buf {
+			if j == 5 {
+				sb.WriteByte('-')
+			}
+			sb.WriteByte(recoveryAlphabet[b&31])
+		}
+		codes = append(codes, sb.String())
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode приводит введённый код к каноническому виду:
+// нижний регистр, без пробелов и дефисов.
+func NormalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// HashRecoveryCode хэширует код восстановления (SHA-256 от нормализованного кода).
+//
+// Коды случайные и длинные, поэтому, как и для refresh-токенов,
+// медленный хэш не нужен.
+func HashRecoveryCode(code string) []byte {
+	sum := sha256.Sum256([]byte(NormalizeRecoveryCode(code)))
+	return sum[:]
+}
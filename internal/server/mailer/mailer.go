@@ -0,0 +1,57 @@
+// Package mailer отправляет служебные письма сервера: подтверждение email,
+// сброс пароля, уведомления о входе с нового устройства.
+//
+// Реализации:
+//   - SMTP — отправка через SMTP-сервер (STARTTLS, если сервер его поддерживает);
+//   - Outbox — запись писем файлами .eml в каталог (для разработки и тестов).
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+)
+
+// ErrInvalidMessage — в адресе или теме письма есть перевод строки
+// (попытка подставить свои заголовки) или адрес пустой.
+var ErrInvalidMessage = errors.New("mailer: invalid message")
+
+// Message — текстовое письмо одному получателю.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// validate проверяет, что поля письма можно безопасно подставить в заголовки.
+func (m Message) validate() error {
+	if strings.TrimSpace(m.To) == "" || strings.ContainsAny(m.To, "\r\n") || strings.ContainsAny(m.Subject, "\r\n") {
+		return ErrInvalidMessage
+	}
+	return nil
+}
+
+// bytes собирает письмо в формате RFC 5322 (text/plain, UTF-8).
+func (m Message) bytes(from string, date time.Time) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", m.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	// SMTP требует CRLF; точка в начале строки экранируется textproto/smtp
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(m.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return b.Bytes()
+}
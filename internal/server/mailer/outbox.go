@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Outbox вместо отправки сохраняет письма файлами <время>-<случайное>.eml в каталог.
+//
+// Нужен для разработки и тестов: токены подтверждения и сброса пароля
+// читаются прямо из файлов. Файлы доступны только владельцу процесса.
+type Outbox struct {
+	dir  string
+	from string
+}
+
+// NewOutbox создаёт Outbox и при необходимости сам каталог dir.
+func NewOutbox(dir, from string) (*Outbox, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("mailer: create outbox: %w", err)
+	}
+	return &Outbox{dir: dir, from: from}, nil
+}
+
+// Send записывает письмо в каталог.
+func (o *Outbox) Send(ctx context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	now := time.Now()
+	name := fmt.Sprintf("%s-%s.eml", now.UTC().Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+
+	return os.WriteFile(filepath.Join(o.dir, name), msg.bytes(o.from, now), 0o600)
+}
@@ -0,0 +1,102 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTPOptions — параметры SMTP-сервера (mail.smtp).
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string // пусто — без аутентификации
+	Password string
+	From     string
+	Timeout  time.Duration // на всю отправку, если у ctx нет дедлайна (0 — 30s)
+}
+
+// SMTP отправляет письма через SMTP-сервер.
+//
+// Если сервер поддерживает STARTTLS, соединение шифруется до аутентификации;
+// пароль по незашифрованному соединению передаётся только на localhost
+// (ограничение smtp.PlainAuth).
+type SMTP struct {
+	opts SMTPOptions
+
+	// TLSConfig — настройки STARTTLS (подменяется в тестах).
+	TLSConfig *tls.Config
+}
+
+// NewSMTP создаёт SMTP с параметрами opts.
+func NewSMTP(opts SMTPOptions) *SMTP {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	return &SMTP{
+		opts:      opts,
+		TLSConfig: &tls.Config{ServerName: opts.Host, MinVersion: tls.VersionTLS12},
+	}
+}
+
+// Send отправляет письмо.
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.opts.Timeout)
+		defer cancel()
+	}
+
+	addr := net.JoinHostPort(s.opts.Host, strconv.Itoa(s.opts.Port))
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("mailer: dial %s: %w", addr, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	c, err := smtp.NewClient(conn, s.opts.Host)
+	if err != nil {
+		return fmt.Errorf("mailer: smtp handshake: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(s.TLSConfig); err != nil {
+			return fmt.Errorf("mailer: starttls: %w", err)
+		}
+	}
+	if s.opts.Username != "" {
+		auth := smtp.PlainAuth("", s.opts.Username, s.opts.Password, s.opts.Host)
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("mailer: auth: %w", err)
+		}
+	}
+
+	if err := c.Mail(s.opts.From); err != nil {
+		return fmt.Errorf("mailer: MAIL FROM: %w", err)
+	}
+	if err := c.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("mailer: RCPT TO: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("mailer: DATA: %w", err)
+	}
+	if _, err := w.Write(msg.bytes(s.opts.From, time.Now())); err != nil {
+		return fmt.Errorf("mailer: write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("mailer: send message: %w", err)
+	}
+	return c.Quit()
+}
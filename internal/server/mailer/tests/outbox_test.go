@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/mailer"
+)
+
+func TestOutbox_Send_WritesEML(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "outbox")
+	o, err := mailer.NewOutbox(dir, "GophKeeper <noreply@example.com>")
+	if err != nil {
+		t.Fatalf("NewOutbox: %v", err)
+	}
+
+	err = o.Send(context.Background(), mailer.Message{
+		To:      "user@example.com",
+		Subject: "Reset your password",
+		Body:    "token: abc\nbye",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("expected 1 message, got %d", len(files))
+	}
+	info, _ := os.Stat(files[0])
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("expected 0600, got %v", info.Mode().Perm())
+	}
+
+	data, _ := os.ReadFile(files[0])
+	msg := string(data)
+	for _, want := range []string{
+		"From: GophKeeper <noreply@example.com>\r\n",
+		"To: user@example.com\r\n",
+		"Subject: Reset your password\r\n",
+		"\r\n\r\ntoken: abc\r\nbye",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Fatalf("message does not contain %q:\n%s", want, msg)
+		}
+	}
+}
+
+// Перевод строки в адресе или теме подставил бы чужие заголовки
+func TestOutbox_Send_RejectsHeaderInjection(t *testing.T) {
+	o, err := mailer.NewOutbox(t.TempDir(), "noreply@example.com")
+	if err != nil {
+		t.Fatalf("NewOutbox: %v", err)
+	}
+
+	for _, msg := range []mailer.Message{
+		{To: "user@example.com\r\nBcc: evil@example.com", Subject: "hi"},
+		{To: "user@example.com", Subject: "hi\nBcc: evil@example.com"},
+		{To: " ", Subject: "hi"},
+	} {
+		if err := o.Send(context.Background(), msg); !errors.Is(err, mailer.ErrInvalidMessage) {
+			t.Fatalf("expected ErrInvalidMessage for %+v, got %v", msg, err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package tests
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/mailer"
+)
+
+// fakeSMTP принимает одно письмо без STARTTLS и аутентификации
+// и возвращает диалог: команды клиента и тело DATA.
+func fakeSMTP(t *testing.T) (port int, result <-chan []string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	out := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		tp := textproto.NewConn(conn)
+		var got []string
+		tp.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				out <- got
+				return
+			}
+			got = append(got, line)
+			switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+			case "EHLO", "HELO":
+				tp.PrintfLine("250 localhost")
+			case "DATA":
+				tp.PrintfLine("354 go ahead")
+				body, _ := tp.ReadDotLines()
+				got = append(got, strings.Join(body, "\n"))
+				tp.PrintfLine("250 queued")
+			case "QUIT":
+				tp.PrintfLine("221 bye")
+				out <- got
+				return
+			default:
+				tp.PrintfLine("250 ok")
+			}
+		}
+	}()
+
+	return ln.Addr().(*net.TCPAddr).Port, out
+}
+
+func TestSMTP_Send(t *testing.T) {
+	port, result := fakeSMTP(t)
+
+	m := mailer.NewSMTP(mailer.SMTPOptions{Host: "127.0.0.1", Port: port, From: "noreply@example.com", Timeout: 5 * time.Second})
+	err := m.Send(context.Background(), mailer.Message{
+		To:      "user@example.com",
+		Subject: "New sign-in",
+		Body:    "hello\n.dot line",
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	got := <-result
+	dialog := strings.Join(got, "\n")
+	for _, want := range []string{
+		"MAIL FROM:<noreply@example.com>",
+		"RCPT TO:<user@example.com>",
+		"Subject: New sign-in",
+		"hello\n.dot line",
+		"QUIT",
+	} {
+		if !strings.Contains(dialog, want) {
+			t.Fatalf("dialog does not contain %q:\n%s", want, dialog)
+		}
+	}
+}
+
+func TestSMTP_Send_Unreachable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	m := mailer.NewSMTP(mailer.SMTPOptions{Host: "127.0.0.1", Port: port, From: "noreply@example.com"})
+	if err := m.Send(context.Background(), mailer.Message{To: "user@example.com", Subject: "x"}); err == nil {
+		t.Fatal("expected error for unreachable server")
+	}
+}
@@ -6,9 +6,14 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
 )
 
 // ctxKey используется как тип ключа для хранения значений в context.Context.
@@ -18,16 +23,50 @@ type ctxKey string
 // userIDKey — ключ контекста, под которым хранится ID аутентифицированного пользователя.
 const userIDKey ctxKey = "user_id"
 
+// sessionIDKey — ключ контекста, под которым хранится ID сессии (claim sid).
+const sessionIDKey ctxKey = "session_id"
+
+// apiTokenKey — ключ контекста, под которым хранится API-токен запроса.
+const apiTokenKey ctxKey = "api_token"
+
+// elevatedUntilKey — ключ контекста, под которым хранится срок повышенных прав (claim elevated_until).
+const elevatedUntilKey ctxKey = "elevated_until"
+
+// RevocationChecker сообщает, отозвана ли сессия.
+//
+// Реализуется кэшем отозванных сессий в service-слое,
+// чтобы проверка не ходила в БД на каждый запрос.
+type RevocationChecker interface {
+	IsRevoked(sessionID uuid.UUID) bool
+}
+
+// APITokenAuthenticator проверяет персональный API-токен (префикс gkp_).
+//
+// Реализуется AuthService. Ошибки: ErrUnauthorized — токен недействителен,
+// остальные — внутренние.
+type APITokenAuthenticator interface {
+	AuthenticateAPIToken(ctx context.Context, token string) (models.APIToken, error)
+}
+
 // JWTVerifier инкапсулирует параметры проверки JWT access-токенов.
 //
 // Используется в HTTP middleware для:
 //   - проверки подписи токена
 //   - валидации issuer и audience
 //   - извлечения userID из claims.Subject
+//
+// Если задан Keys, подпись проверяется асимметричным ключом,
+// выбранным по kid из заголовка токена, иначе — HS256 с SigningKey.
 type JWTVerifier struct {
-	SigningKey string // симметричный ключ для подписи (HS256)
-	Issuer     string // ожидаемый issuer (опционально)
-	Audience   string // ожидаемая audience (опционально)
+	SigningKey string         // симметричный ключ для подписи (HS256)
+	Keys       *crypto.KeySet // ключи EdDSA/RS256 (активный + retired)
+	Issuer     string         // ожидаемый issuer (опционально)
+	Audience   string         // ожидаемая audience (опционально)
+
+	// Revocations — проверка отзыва сессии по claim sid (опционально).
+	Revocations RevocationChecker
+	// APITokens — проверка персональных API-токенов (опционально, nil — не принимаются).
+	APITokens APITokenAuthenticator
 }
 
 // NewJWTVerifier создаёт новый JWTVerifier с заданными параметрами.
@@ -35,6 +74,37 @@ func NewJWTVerifier(signingKey, issuer, audience string) *JWTVerifier {
 	return &JWTVerifier{SigningKey: signingKey, Issuer: issuer, Audience: audience}
 }
 
+// NewKeySetVerifier создаёт JWTVerifier, проверяющий токены по набору ключей.
+func NewKeySetVerifier(keys *crypto.KeySet, issuer, audience string) *JWTVerifier {
+	return &JWTVerifier{Keys: keys, Issuer: issuer, Audience: audience}
+}
+
+// parse проверяет подпись токена и заполняет claims.
+func (v *JWTVerifier) parse(tokenStr string, claims jwt.Claims) error {
+	if v.Keys == nil {
+		parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}))
+		_, err := parser.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
+			return []byte(v.SigningKey), nil
+		})
+		return err
+	}
+
+	parser := jwt.NewParser(jwt.WithValidMethods(v.Keys.Algorithms()))
+	_, err := parser.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := v.Keys.Lookup(kid)
+		if !ok {
+			return nil, crypto.ErrUnknownKeyID
+		}
+		// alg токена должен совпадать с алгоритмом ключа
+		if t.Method.Alg() != key.Alg {
+			return nil, jwt.ErrTokenSignatureInvalid
+		}
+		return key.Public, nil
+	})
+	return err
+}
+
 // UserIDFromContext извлекает userID аутентифицированного пользователя из контекста.
 //
 // Возвращает:
@@ -46,13 +116,41 @@ func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
 	return s, ok
 }
 
+// SessionIDFromContext извлекает ID сессии, к которой привязан access-токен.
+//
+// Возвращает false, если токен выдан без sid.
+func SessionIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	v, ok := ctx.Value(sessionIDKey).(uuid.UUID)
+	return v, ok
+}
+
+// ElevatedUntilFromContext возвращает срок повышенных прав access-токена.
+//
+// Возвращает false, если токен выдан без повторной аутентификации.
+func ElevatedUntilFromContext(ctx context.Context) (time.Time, bool) {
+	v, ok := ctx.Value(elevatedUntilKey).(time.Time)
+	return v, ok
+}
+
+// APITokenFromContext возвращает API-токен, которым аутентифицирован запрос.
+//
+// Возвращает false, если запрос выполнен с JWT access-токеном (полный доступ).
+func APITokenFromContext(ctx context.Context) (models.APIToken, bool) {
+	v, ok := ctx.Value(apiTokenKey).(models.APIToken)
+	return v, ok
+}
+
 // AuthMiddleware возвращает HTTP middleware для проверки JWT access-токенов.
 //
 // Middleware:
 //   - ожидает заголовок Authorization: Bearer <token>
+//   - токены с префиксом gkp_ проверяет как персональные API-токены (если задан APITokens)
+//     и сохраняет их в контекст — scopes проверяют хендлеры
 //   - валидирует подпись и claims токена
+//   - отклоняет токены отозванных сессий (по claim sid, если задан Revocations)
+//   - для токенов с cnf.x5t#S256 требует mTLS с тем же клиентским сертификатом
 //   - извлекает userID из claims.Subject
-//   - сохраняет userID в context.Context
+//   - сохраняет userID и ID сессии в context.Context
 //
 // В случае ошибки возвращает HTTP 401 Unauthorized.
 func (v *JWTVerifier) AuthMiddleware() func(http.Handler) http.Handler {
@@ -64,14 +162,14 @@ func (v *JWTVerifier) AuthMiddleware() func(http.Handler) http.Handler {
 				return
 			}
 
-			claims := &jwt.RegisteredClaims{}
+			if crypto.IsAPIToken(tokenStr) {
+				v.serveAPIToken(w, r, next, tokenStr)
+				return
+			}
 
-			parser := jwt.NewParser(jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}))
-			_, err := parser.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (any, error) {
-				return []byte(v.SigningKey), nil
-			})
+			claims := &crypto.AccessClaims{}
 
-			if err != nil {
+			if err := v.parse(tokenStr, claims); err != nil {
 				if errors.Is(err, jwt.ErrTokenExpired) {
 					http.Error(w, "token expired", http.StatusUnauthorized)
 					return
@@ -111,12 +209,119 @@ func (v *JWTVerifier) AuthMiddleware() func(http.Handler) http.Handler {
 				return
 			}
 
+			// токен привязан к клиентскому сертификату (RFC 8705)
+			if cnf := claims.Confirmation; cnf != nil && cnf.CertThumbprint != "" {
+				fp := PeerCertFingerprint(r)
+				if fp == nil || crypto.CertThumbprint(fp) != cnf.CertThumbprint {
+					http.Error(w, "client certificate required", http.StatusUnauthorized)
+					return
+				}
+			}
+
 			ctx := context.WithValue(r.Context(), userIDKey, userID)
+
+			// токены без sid выданы до появления claim и истекут сами
+			if sid := strings.TrimSpace(claims.SessionID); sid != "" {
+				sessionID, err := uuid.Parse(sid)
+				if err != nil {
+					http.Error(w, "invalid token session", http.StatusUnauthorized)
+					return
+				}
+				if v.Revocations != nil && v.Revocations.IsRevoked(sessionID) {
+					http.Error(w, "session revoked", http.StatusUnauthorized)
+					return
+				}
+				ctx = context.WithValue(ctx, sessionIDKey, sessionID)
+			}
+			if claims.ElevatedUntil != nil {
+				ctx = context.WithValue(ctx, elevatedUntilKey, claims.ElevatedUntil.Time)
+			}
+
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
+// PeerCertFingerprint возвращает SHA-256 клиентского сертификата, предъявленного
+// при TLS-рукопожатии (mTLS), или nil, если сертификата нет.
+func PeerCertFingerprint(r *http.Request) []byte {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+	return crypto.CertFingerprint(r.TLS.PeerCertificates[0])
+}
+
+// serveAPIToken аутентифицирует запрос персональным API-токеном.
+func (v *JWTVerifier) serveAPIToken(w http.ResponseWriter, r *http.Request, next http.Handler, tokenStr string) {
+	if v.APITokens == nil {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	token, err := v.APITokens.AuthenticateAPIToken(r.Context(), tokenStr)
+	if err != nil {
+		if errors.Is(err, serr.ErrUnauthorized) {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		if errors.Is(err, serr.ErrClientCertRequired) {
+			http.Error(w, "client certificate required", http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, serr.ErrInternal.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ctx := context.WithValue(r.Context(), userIDKey, token.UserID)
+	ctx = context.WithValue(ctx, apiTokenKey, token)
+	next.ServeHTTP(w, r.WithContext(ctx))
+}
+
+// SessionOnly возвращает middleware, которое не пускает запросы с API-токеном.
+//
+// Ставится после AuthMiddleware на управление аккаунтом, сессиями и самими
+// токенами: API-токен даёт доступ только к секретам в пределах своих scopes.
+func SessionOnly() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := APITokenFromContext(r.Context()); ok {
+				http.Error(w, "api tokens are not allowed here", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireElevated возвращает middleware, которое пускает запрос только с access-токеном,
+// выданным после недавней повторной аутентификации (POST /auth/reauth).
+//
+// Ставится после AuthMiddleware на чувствительные операции: украденный
+// access-токен без пароля не позволяет удалять секреты и менять защиту аккаунта.
+// Без повышенных прав отвечает 401 с WWW-Authenticate
+// error="insufficient_user_authentication" (RFC 9470).
+//
+// Запросы с API-токеном отклоняются с 403, как в SessionOnly:
+// повторно аутентифицироваться по API-токену нельзя, а утёкший
+// токен CI не должен позволять удалять секреты.
+func (v *JWTVerifier) RequireElevated() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := APITokenFromContext(r.Context()); ok {
+				http.Error(w, "api tokens are not allowed here", http.StatusForbidden)
+				return
+			}
+			until, ok := ElevatedUntilFromContext(r.Context())
+			if !ok || !time.Now().Before(until) {
+				w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_user_authentication", error_description="reauthentication required"`)
+				http.Error(w, serr.ErrReauthRequired.Error(), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
 // ExtractBearer извлекает JWT из заголовка Authorization.
 //
 // Ожидаемый формат:
@@ -136,6 +341,24 @@ func ExtractBearer(h string) string {
 	return strings.TrimSpace(parts[1])
 }
 
+// ContextWithSessionID возвращает новый context.Context с сохранённым ID сессии.
+// Значение извлекается с помощью функции SessionIDFromContext.
+func ContextWithSessionID(ctx context.Context, sessionID uuid.UUID) context.Context {
+	return context.WithValue(ctx, sessionIDKey, sessionID)
+}
+
+// ContextWithAPIToken возвращает новый context.Context с сохранённым API-токеном.
+// Значение извлекается с помощью функции APITokenFromContext.
+func ContextWithAPIToken(ctx context.Context, token models.APIToken) context.Context {
+	return context.WithValue(ctx, apiTokenKey, token)
+}
+
+// ContextWithElevatedUntil возвращает новый context.Context со сроком повышенных прав.
+// Значение извлекается с помощью функции ElevatedUntilFromContext.
+func ContextWithElevatedUntil(ctx context.Context, until time.Time) context.Context {
+	return context.WithValue(ctx, elevatedUntilKey, until)
+}
+
 // ContextWithUserID возвращает новый context.Context с сохранённым идентификатором пользователя.
 // Функция используется middleware аутентификации для передачи userID
 // userID должен быть строковым представлением UUID пользователя.
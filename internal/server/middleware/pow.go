@@ -0,0 +1,222 @@
+// Proof-of-work для регистрации и входа под нагрузкой
+package middleware
+
+import (
+	"errors"
+	"math/bits"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/pow"
+)
+
+// powSlots — на сколько интервалов делится окно подсчёта нагрузки.
+const powSlots = 10
+
+// HeaderPoWDifficulty — текущая сложность в ответе 428.
+const HeaderPoWDifficulty = "X-PoW-Difficulty"
+
+// PoWOptions — параметры ProofOfWork (security.pow).
+type PoWOptions struct {
+	Key            []byte        // ключ HMAC для подписи challenge
+	ChallengeTTL   time.Duration // сколько действует challenge
+	Window         time.Duration // окно подсчёта нагрузки
+	Threshold      int           // с какой нагрузки за окно требуется решение
+	BaseDifficulty int           // сложность (бит) на пороге
+	MaxDifficulty  int           // потолок сложности
+}
+
+// ProofOfWork требует решение hashcash-задачи на регистрации и входе,
+// когда сервер под нагрузкой. Альтернатива CAPTCHA, работающая для CLI.
+//
+// Нагрузка — число неудачных ответов (4xx) и созданных аккаунтов (201)
+// за скользящее окно. Ниже Threshold решение не требуется; на пороге
+// сложность равна BaseDifficulty и растёт на бит при каждом удвоении нагрузки.
+//
+// Challenge не хранятся (подписаны HMAC и содержат срок), но каждый
+// решённый challenge запоминается до истечения, поэтому используется один раз.
+// При нескольких репликах это ограничение действует в пределах одной реплики.
+type ProofOfWork struct {
+	mu   sync.Mutex
+	opts PoWOptions
+
+	slots [powSlots]powSlot
+	used  map[string]time.Time // nonce решённых challenge → срок действия
+
+	lastSweep time.Time
+
+	// Now — источник времени (подменяется в тестах).
+	Now func() time.Time
+}
+
+// powSlot — счётчик нагрузки за один интервал окна.
+type powSlot struct {
+	idx int64
+	n   int
+}
+
+// PoWStats — снимок состояния для метрик.
+type PoWStats struct {
+	Load       int `json:"load"`
+	Difficulty int `json:"difficulty"`
+}
+
+// NewProofOfWork создаёт ProofOfWork с параметрами opts.
+func NewProofOfWork(opts PoWOptions) *ProofOfWork {
+	if opts.MaxDifficulty > pow.MaxDifficulty {
+		opts.MaxDifficulty = pow.MaxDifficulty
+	}
+	return &ProofOfWork{
+		opts: opts,
+		used: make(map[string]time.Time),
+		Now:  time.Now,
+	}
+}
+
+// Challenge выпускает challenge с текущей сложностью.
+//
+// При сложности 0 challenge тоже выдаётся: решение пока не требуется.
+func (p *ProofOfWork) Challenge() (challenge string, difficulty int, expiresAt time.Time, err error) {
+	difficulty = p.Difficulty()
+	expiresAt = p.Now().Add(p.opts.ChallengeTTL)
+	challenge, err = crypto.NewPoWChallenge(p.opts.Key, difficulty, expiresAt)
+	return challenge, difficulty, expiresAt, err
+}
+
+// Difficulty возвращает сложность, требуемую сейчас (0 — решение не нужно).
+func (p *ProofOfWork) Difficulty() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.difficulty(p.load(p.Now()))
+}
+
+// Stats возвращает нагрузку за окно и текущую сложность.
+func (p *ProofOfWork) Stats() PoWStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	load := p.load(p.Now())
+	return PoWStats{Load: load, Difficulty: p.difficulty(load)}
+}
+
+// Record учитывает одно событие нагрузки (неудачный вход, регистрацию).
+func (p *ProofOfWork) Record() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	idx := p.slotIndex(p.Now())
+	s := &p.slots[idx%powSlots]
+	if s.idx != idx {
+		*s = powSlot{idx: idx}
+	}
+	s.n++
+}
+
+// Verify проверяет решение challenge и помечает challenge использованным.
+//
+// Ошибки:
+//   - ErrPoWRequired — challenge или решение не переданы
+//   - ErrInvalidPoW — challenge подделан, истёк, уже использован,
+//     проще требуемой сложности или решение неверно
+func (p *ProofOfWork) Verify(challenge, solution string, required int) error {
+	if challenge == "" || solution == "" {
+		return serr.ErrPoWRequired
+	}
+
+	now := p.Now()
+	ch, err := crypto.ParsePoWChallenge(p.opts.Key, challenge, now)
+	if err != nil {
+		return serr.ErrInvalidPoW
+	}
+	if ch.Difficulty < required || !pow.Valid(challenge, solution, ch.Difficulty) {
+		return serr.ErrInvalidPoW
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sweep(now)
+	if _, ok := p.used[ch.Nonce]; ok {
+		return serr.ErrInvalidPoW
+	}
+	p.used[ch.Nonce] = ch.ExpiresAt
+	return nil
+}
+
+// Middleware возвращает HTTP middleware для /auth/register и /auth/login.
+//
+// Под нагрузкой без верного решения в заголовках X-PoW-Challenge/X-PoW-Solution
+// отвечает 428 Precondition Required и сообщает сложность в X-PoW-Difficulty:
+// клиент получает challenge через GET /auth/challenge и повторяет запрос.
+func (p *ProofOfWork) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if d := p.Difficulty(); d > 0 {
+				err := p.Verify(r.Header.Get(pow.HeaderChallenge), r.Header.Get(pow.HeaderSolution), d)
+				if err != nil {
+					w.Header().Set(HeaderPoWDifficulty, strconv.Itoa(d))
+					if !errors.Is(err, serr.ErrPoWRequired) {
+						err = serr.ErrInvalidPoW
+					}
+					http.Error(w, err.Error(), http.StatusPreconditionRequired)
+					return
+				}
+			}
+
+			wr := &ResponseWriter{ResponseWriter: w}
+			next.ServeHTTP(wr, r)
+
+			// неудачные попытки и созданные аккаунты поднимают сложность
+			if wr.Status == http.StatusCreated || (wr.Status >= 400 && wr.Status < 500) {
+				p.Record()
+			}
+		})
+	}
+}
+
+// difficulty переводит нагрузку в сложность, вызывается под mu.
+func (p *ProofOfWork) difficulty(load int) int {
+	if p.opts.Threshold <= 0 || load < p.opts.Threshold {
+		return 0
+	}
+	// +1 бит (вдвое больше работы) на каждое удвоение нагрузки сверх порога
+	d := p.opts.BaseDifficulty + bits.Len(uint(load/p.opts.Threshold)) - 1
+	return min(d, p.opts.MaxDifficulty)
+}
+
+// load суммирует события за окно, вызывается под mu.
+func (p *ProofOfWork) load(now time.Time) int {
+	cur := p.slotIndex(now)
+	n := 0
+	for _, s := range p.slots {
+		if s.idx > cur-powSlots && s.idx <= cur {
+			n += s.n
+		}
+	}
+	return n
+}
+
+func (p *ProofOfWork) slotIndex(now time.Time) int64 {
+	slot := p.opts.Window / powSlots
+	if slot <= 0 {
+		slot = time.Second
+	}
+	return now.UnixNano() / int64(slot)
+}
+
+// sweep удаляет истёкшие challenge из памяти.
+// Выполняется не чаще одного раза за ChallengeTTL, вызывается под mu.
+func (p *ProofOfWork) sweep(now time.Time) {
+	if now.Sub(p.lastSweep) < p.opts.ChallengeTTL {
+		return
+	}
+	p.lastSweep = now
+
+	for nonce, exp := range p.used {
+		if !now.Before(exp) {
+			delete(p.used, nonce)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+// Ограничение частоты запросов (token bucket)
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultIdleTTL — через сколько неиспользуемый bucket удаляется из памяти.
+const defaultIdleTTL = 10 * time.Minute
+
+// KeyFunc возвращает ключ, по которому считается лимит (IP, userID и т.п.).
+type KeyFunc func(r *http.Request) string
+
+// RateLimiter реализует rate limit по алгоритму token bucket.
+//
+// Для каждого ключа (IP или пользователь) хранится свой bucket:
+//   - bucket вмещает не больше burst токенов;
+//   - токены пополняются со скоростью rps в секунду;
+//   - каждый запрос забирает один токен, если токенов нет — 429.
+//
+// Bucket'ы, к которым не обращались дольше IdleTTL, вычищаются,
+// чтобы карта не росла бесконечно от случайных IP.
+type RateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+
+	rps   float64
+	burst float64
+	key   KeyFunc
+
+	lastSweep time.Time
+
+	// IdleTTL — время простоя, после которого bucket удаляется.
+	IdleTTL time.Duration
+	// Now — источник времени (подменяется в тестах).
+	Now func() time.Time
+}
+
+// bucket — состояние token bucket для одного ключа.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter создаёт RateLimiter с заданной скоростью и размером bucket.
+//
+// rps — сколько запросов в секунду восстанавливается,
+// burst — сколько запросов можно сделать подряд,
+// key — функция получения ключа из запроса (KeyByIP / KeyByUser).
+func NewRateLimiter(rps float64, burst int, key KeyFunc) *RateLimiter {
+	return &RateLimiter{
+		buckets: make(map[string]*bucket),
+		rps:     rps,
+		burst:   float64(burst),
+		key:     key,
+		IdleTTL: defaultIdleTTL,
+		Now:     time.Now,
+	}
+}
+
+// Allow забирает токен для ключа.
+//
+// Возвращает:
+//   - true, если запрос можно пропустить
+//   - false и время, через которое появится следующий токен
+func (l *RateLimiter) Allow(key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.Now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: l.burst, last: now}
+		l.buckets[key] = b
+	}
+
+	// пополняем bucket за прошедшее время
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(l.burst, b.tokens+elapsed*l.rps)
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - b.tokens) / l.rps * float64(time.Second))
+	return false, wait
+}
+
+// Len возвращает количество bucket'ов в памяти.
+func (l *RateLimiter) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.buckets)
+}
+
+// sweep удаляет простаивающие bucket'ы.
+// Выполняется не чаще одного раза за IdleTTL, вызывается под mu.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.IdleTTL {
+		return
+	}
+	l.lastSweep = now
+
+	for k, b := range l.buckets {
+		if now.Sub(b.last) >= l.IdleTTL {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+// Middleware возвращает HTTP middleware, ограничивающий частоту запросов.
+//
+// При превышении лимита отвечает 429 Too Many Requests
+// и заголовком Retry-After (в секундах).
+func (l *RateLimiter) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ok, wait := l.Allow(l.key(r))
+			if !ok {
+				retry := int(math.Ceil(wait.Seconds()))
+				if retry < 1 {
+					retry = 1
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(retry))
+				http.Error(w, "too many requests", http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// KeyByIP возвращает KeyFunc, считающую лимит по IP клиента.
+//
+// trustProxy — доверять ли X-Forwarded-For (включать только за своим прокси).
+func KeyByIP(trustProxy bool) KeyFunc {
+	return func(r *http.Request) string {
+		return "ip:" + ClientIP(r, trustProxy)
+	}
+}
+
+// KeyByUser возвращает KeyFunc, считающую лимит по userID из контекста.
+//
+// Для запросов без аутентификации (/auth/login и т.п.) используется IP,
+// иначе публичные эндпоинты остались бы без ограничений.
+func KeyByUser(trustProxy bool) KeyFunc {
+	byIP := KeyByIP(trustProxy)
+	return func(r *http.Request) string {
+		if userID, ok := UserIDFromContext(r.Context()); ok {
+			return "user:" + userID.String()
+		}
+		return byIP(r)
+	}
+}
+
+// ClientIP определяет IP клиента.
+//
+// Если trustProxy=true — берётся последний адрес из X-Forwarded-For:
+// его дописал наш прокси, а всё левее клиент мог прислать сам
+// (подменяя первый адрес, он получал бы новый ключ rate limit на каждый запрос).
+// Без X-Forwarded-For — X-Real-IP. Иначе используется RemoteAddr,
+// т.к. заголовки может подделать кто угодно.
+func ClientIP(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if ip := lastForwardedFor(r.Header.Values("X-Forwarded-For")); ip != "" {
+			return ip
+		}
+		if xrip := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(xrip) != nil {
+			return xrip
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// lastForwardedFor возвращает самый правый адрес X-Forwarded-For
+// (заголовок может повторяться — учитывается последний) или "",
+// если там не IP.
+func lastForwardedFor(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	parts := strings.Split(values[len(values)-1], ",")
+	last := strings.TrimSpace(parts[len(parts)-1])
+	if net.ParseIP(last) == nil {
+		return ""
+	}
+	return last
+}
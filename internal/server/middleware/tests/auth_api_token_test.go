@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// stubAPITokens — AuthenticateAPIToken с заранее заданным результатом
+type stubAPITokens struct {
+	token models.APIToken
+	err   error
+	got   string
+}
+
+func (s *stubAPITokens) AuthenticateAPIToken(_ context.Context, token string) (models.APIToken, error) {
+	s.got = token
+	return s.token, s.err
+}
+
+// API-токен принимается наравне с JWT
+func TestAuthMiddleware_APIToken_OK(t *testing.T) {
+	userID := uuid.New()
+	stub := &stubAPITokens{token: models.APIToken{UserID: userID, Scopes: []string{models.ScopeSecretsRead}}}
+
+	v := middleware.NewJWTVerifier("secret", "issuer", "aud")
+	v.APITokens = stub
+
+	called := false
+	handler := v.AuthMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+
+		uid, ok := middleware.UserIDFromContext(r.Context())
+		if !ok || uid != userID {
+			t.Fatalf("unexpected user id: %v", uid)
+		}
+		token, ok := middleware.APITokenFromContext(r.Context())
+		if !ok || !token.HasScope(models.ScopeSecretsRead) {
+			t.Fatalf("api token not found in context: %+v", token)
+		}
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/secrets", nil)
+	req.Header.Set("Authorization", "Bearer gkp_abc")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if !called {
+		t.Fatal("handler was not called")
+	}
+	if stub.got != "gkp_abc" {
+		t.Fatalf("unexpected token passed: %q", stub.got)
+	}
+}
+
+// отозванный/истёкший токен, запрос без привязанного сертификата и ошибка хранилища
+func TestAuthMiddleware_APIToken_Rejected(t *testing.T) {
+	cases := []struct {
+		err  error
+		want int
+	}{
+		{serr.ErrUnauthorized, http.StatusUnauthorized},
+		{serr.ErrClientCertRequired, http.StatusUnauthorized},
+		{serr.ErrInternal, http.StatusInternalServerError},
+	}
+
+	for _, c := range cases {
+		v := middleware.NewJWTVerifier("secret", "issuer", "aud")
+		v.APITokens = &stubAPITokens{err: c.err}
+
+		handler := v.AuthMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			t.Fatal("handler should not be called")
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/secrets", nil)
+		req.Header.Set("Authorization", "Bearer gkp_abc")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != c.want {
+			t.Fatalf("%v: expected %d, got %d", c.err, c.want, rec.Code)
+		}
+	}
+}
+
+// без подключённого хранилища API-токены не принимаются
+func TestAuthMiddleware_APIToken_Disabled(t *testing.T) {
+	v := middleware.NewJWTVerifier("secret", "issuer", "aud")
+
+	handler := v.AuthMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler should not be called")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/secrets", nil)
+	req.Header.Set("Authorization", "Bearer gkp_abc")
+	rec := httptest.NewRecorder()
+
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+}
+
+// SessionOnly не пускает API-токены, но пропускает JWT
+func TestSessionOnly(t *testing.T) {
+	handler := middleware.SessionOnly()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/auth/tokens", nil)
+	req = req.WithContext(middleware.ContextWithAPIToken(req.Context(), models.APIToken{UserID: uuid.New()}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/auth/tokens", nil)
+	req = req.WithContext(middleware.ContextWithUserID(req.Context(), uuid.New()))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+}
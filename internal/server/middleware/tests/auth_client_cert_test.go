@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+)
+
+// токен, привязанный к сертификату с DER raw
+func makeBoundToken(t *testing.T, key string, raw []byte) string {
+	t.Helper()
+
+	cert := &x509.Certificate{Raw: raw}
+	token, err := crypto.NewBoundAccessToken(uuid.NewString(), uuid.NewString(),
+		crypto.CertThumbprint(crypto.CertFingerprint(cert)),
+		crypto.JWTConfig{Issuer: "issuer", Audience: "aud", SigningKey: key, AccessTTL: time.Minute},
+	)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+func TestAuthMiddleware_BoundToken(t *testing.T) {
+	key := "secret"
+	v := middleware.NewJWTVerifier(key, "issuer", "aud")
+	token := makeBoundToken(t, key, []byte("laptop-cert"))
+
+	handler := v.AuthMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	cases := []struct {
+		name  string
+		state *tls.ConnectionState
+		want  int
+	}{
+		{"same certificate", &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Raw: []byte("laptop-cert")}}}, http.StatusOK},
+		{"other certificate", &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Raw: []byte("stolen-token")}}}, http.StatusUnauthorized},
+		{"tls without certificate", &tls.ConnectionState{}, http.StatusUnauthorized},
+		{"plain http", nil, http.StatusUnauthorized},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.TLS = tc.state
+		rr := httptest.NewRecorder()
+
+		handler.ServeHTTP(rr, req)
+
+		if rr.Code != tc.want {
+			t.Fatalf("%s: expected %d, got %d", tc.name, tc.want, rr.Code)
+		}
+	}
+}
+
+// токен без cnf принимается и с сертификатом, и без него
+func TestAuthMiddleware_UnboundTokenWithCertificate(t *testing.T) {
+	key := "secret"
+	v := middleware.NewJWTVerifier(key, "issuer", "aud")
+	token := makeToken(t, key, uuid.NewString(), "issuer", "aud", time.Now().Add(time.Minute))
+
+	handler := v.AuthMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{Raw: []byte("laptop-cert")}}}
+	rr := httptest.NewRecorder()
+
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+	}
+}
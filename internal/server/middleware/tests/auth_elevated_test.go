@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+)
+
+// elevatedRequest прогоняет запрос с токеном через AuthMiddleware и RequireElevated
+func elevatedRequest(t *testing.T, until time.Time) (*httptest.ResponseRecorder, bool) {
+	t.Helper()
+
+	cfg := crypto.JWTConfig{Issuer: "issuer", Audience: "aud", SigningKey: "secret", AccessTTL: time.Minute}
+	token, err := crypto.NewElevatedAccessToken(uuid.NewString(), uuid.NewString(), "", until, cfg)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+
+	v := middleware.NewJWTVerifier("secret", "issuer", "aud")
+	called := false
+	handler := v.AuthMiddleware()(v.RequireElevated()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})))
+
+	req := httptest.NewRequest(http.MethodDelete, "/secrets/1", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec, called
+}
+
+func TestRequireElevated_ElevatedToken_OK(t *testing.T) {
+	rec, called := elevatedRequest(t, time.Now().Add(time.Minute))
+
+	if !called {
+		t.Fatalf("handler was not called, status %d", rec.Code)
+	}
+}
+
+func TestRequireElevated_PlainToken_Unauthorized(t *testing.T) {
+	rec, called := elevatedRequest(t, time.Time{})
+
+	if called {
+		t.Fatal("handler must not be called without elevation")
+	}
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+	if h := rec.Header().Get("WWW-Authenticate"); !strings.Contains(h, `error="insufficient_user_authentication"`) {
+		t.Fatalf("unexpected WWW-Authenticate: %q", h)
+	}
+}
+
+func TestRequireElevated_ExpiredElevation_Unauthorized(t *testing.T) {
+	rec, called := elevatedRequest(t, time.Now().Add(-time.Second))
+
+	if called {
+		t.Fatal("handler must not be called with expired elevation")
+	}
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+}
+
+// API-токен повторно не аутентифицируется — чувствительные операции ему закрыты
+func TestRequireElevated_APIToken_Forbidden(t *testing.T) {
+	v := middleware.NewJWTVerifier("secret", "issuer", "aud")
+	v.APITokens = &stubAPITokens{token: models.APIToken{UserID: uuid.New(), Scopes: []string{models.ScopeSecretsWrite}}}
+
+	called := false
+	handler := v.AuthMiddleware()(v.RequireElevated()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})))
+
+	req := httptest.NewRequest(http.MethodDelete, "/secrets/1", nil)
+	req.Header.Set("Authorization", "Bearer gkp_abc")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if called {
+		t.Fatal("handler must not be called with an api token")
+	}
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", rec.Code)
+	}
+}
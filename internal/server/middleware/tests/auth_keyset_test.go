@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+)
+
+func newEdKey(t *testing.T, kid string) *crypto.SigningKey {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	return &crypto.SigningKey{ID: kid, Alg: crypto.AlgEdDSA, Private: priv, Public: pub}
+}
+
+func signWith(t *testing.T, key *crypto.SigningKey, sub string) string {
+	t.Helper()
+
+	tok := jwt.NewWithClaims(jwt.SigningMethodEdDSA, jwt.RegisteredClaims{
+		Subject:   sub,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	})
+	tok.Header["kid"] = key.ID
+	s, err := tok.SignedString(key.Private)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return s
+}
+
+func callWithToken(v *middleware.JWTVerifier, token string) int {
+	h := v.AuthMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr.Code
+}
+
+// Ключ выбирается по kid: принимаются токены активного и retired ключей
+func TestKeySetVerifier_SelectsKeyByKid(t *testing.T) {
+	active := newEdKey(t, "new")
+	retired := newEdKey(t, "old")
+	stranger := newEdKey(t, "other")
+
+	ks, err := crypto.NewKeySet(active, &crypto.SigningKey{ID: retired.ID, Alg: retired.Alg, Public: retired.Public})
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	v := middleware.NewKeySetVerifier(ks, "", "")
+
+	sub := uuid.New().String()
+	if code := callWithToken(v, signWith(t, active, sub)); code != http.StatusOK {
+		t.Fatalf("active key: expected 200, got %d", code)
+	}
+	if code := callWithToken(v, signWith(t, retired, sub)); code != http.StatusOK {
+		t.Fatalf("retired key: expected 200, got %d", code)
+	}
+	if code := callWithToken(v, signWith(t, stranger, sub)); code != http.StatusUnauthorized {
+		t.Fatalf("unknown kid: expected 401, got %d", code)
+	}
+
+	// подпись чужим ключом под известным kid
+	forged := &crypto.SigningKey{ID: "new", Alg: crypto.AlgEdDSA, Private: stranger.Private}
+	if code := callWithToken(v, signWith(t, forged, sub)); code != http.StatusUnauthorized {
+		t.Fatalf("forged signature: expected 401, got %d", code)
+	}
+}
+
+// HS256-токен не принимается верификатором с асимметричными ключами
+func TestKeySetVerifier_RejectsHS256(t *testing.T) {
+	ks, err := crypto.NewKeySet(newEdKey(t, "k1"))
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	v := middleware.NewKeySetVerifier(ks, "", "")
+
+	token := makeToken(t, "secret", uuid.New().String(), "", "", time.Now().Add(time.Minute))
+	if code := callWithToken(v, token); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", code)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
@@ -142,3 +143,57 @@ func TestExtractBearer(t *testing.T) {
 		}
 	}
 }
+
+// фиксированный набор отозванных сессий
+type revokedSet map[uuid.UUID]bool
+
+func (s revokedSet) IsRevoked(id uuid.UUID) bool { return s[id] }
+
+// Токен отозванной сессии отклоняется, sid попадает в контекст
+func TestAuthMiddleware_RejectsRevokedSession(t *testing.T) {
+	key := "secret"
+	revoked := uuid.New()
+	active := uuid.New()
+
+	v := middleware.NewJWTVerifier(key, "", "")
+	v.Revocations = revokedSet{revoked: true}
+
+	tokenFor := func(sid uuid.UUID) string {
+		claims := crypto.AccessClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   uuid.New().String(),
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+			SessionID: sid.String(),
+		}
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+		if err != nil {
+			t.Fatalf("sign token: %v", err)
+		}
+		return s
+	}
+
+	var gotSID uuid.UUID
+	handler := v.AuthMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotSID, _ = middleware.SessionIDFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	call := func(token string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	if code := call(tokenFor(revoked)); code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for revoked session, got %d", code)
+	}
+	if code := call(tokenFor(active)); code != http.StatusOK {
+		t.Fatalf("expected 200 for active session, got %d", code)
+	}
+	if gotSID != active {
+		t.Fatalf("expected sid %v in context, got %v", active, gotSID)
+	}
+}
@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/pow"
+)
+
+func newTestPoW(clock *fakeClock) *middleware.ProofOfWork {
+	p := middleware.NewProofOfWork(middleware.PoWOptions{
+		Key:            []byte("0123456789abcdef0123456789abcdef"),
+		ChallengeTTL:   time.Minute,
+		Window:         time.Minute,
+		Threshold:      4,
+		BaseDifficulty: 4,
+		MaxDifficulty:  6,
+	})
+	p.Now = clock.Now
+	return p
+}
+
+// хендлер, отвечающий заданным статусом
+func powHandler(p *middleware.ProofOfWork, status int) http.Handler {
+	return p.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+}
+
+func solved(t *testing.T, p *middleware.ProofOfWork) func(r *http.Request) {
+	t.Helper()
+
+	challenge, difficulty, _, err := p.Challenge()
+	if err != nil {
+		t.Fatalf("Challenge error: %v", err)
+	}
+	solution, err := pow.Solve(context.Background(), challenge, difficulty)
+	if err != nil {
+		t.Fatalf("Solve error: %v", err)
+	}
+	return func(r *http.Request) {
+		r.Header.Set(pow.HeaderChallenge, challenge)
+		r.Header.Set(pow.HeaderSolution, solution)
+	}
+}
+
+// Сложность появляется на пороге и растёт на бит при удвоении нагрузки
+func TestProofOfWork_DifficultyScalesWithLoad(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	p := newTestPoW(clock)
+
+	want := map[int]int{3: 0, 4: 4, 7: 4, 8: 5, 16: 6, 64: 6}
+	for n := 1; n <= 64; n++ {
+		p.Record()
+		if d, ok := want[n]; ok && p.Difficulty() != d {
+			t.Fatalf("load %d: expected difficulty %d, got %d", n, d, p.Difficulty())
+		}
+	}
+
+	// нагрузка уходит из окна
+	clock.now = clock.now.Add(2 * time.Minute)
+	if d := p.Difficulty(); d != 0 {
+		t.Fatalf("expected difficulty 0 after window, got %d", d)
+	}
+}
+
+// Неудачные ответы поднимают сложность, дальше без решения — 428
+func TestProofOfWork_Middleware_RequiresSolution(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	p := newTestPoW(clock)
+	failing := powHandler(p, http.StatusUnauthorized)
+
+	for i := 0; i < 4; i++ {
+		if rr := doRequest(failing, "1.2.3.4:1000", nil); rr.Code != http.StatusUnauthorized {
+			t.Fatalf("request %d: expected 401, got %d", i, rr.Code)
+		}
+	}
+
+	rr := doRequest(failing, "1.2.3.4:1000", nil)
+	if rr.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected 428, got %d", rr.Code)
+	}
+	if rr.Header().Get(middleware.HeaderPoWDifficulty) != strconv.Itoa(4) {
+		t.Fatalf("expected difficulty header 4, got %q", rr.Header().Get(middleware.HeaderPoWDifficulty))
+	}
+
+	// с решением запрос проходит
+	ok := powHandler(p, http.StatusOK)
+	if rr := doRequest(ok, "1.2.3.4:1000", solved(t, p)); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+}
+
+// Решённый challenge нельзя использовать повторно
+func TestProofOfWork_SingleUse(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	p := newTestPoW(clock)
+	for i := 0; i < 4; i++ {
+		p.Record()
+	}
+	h := powHandler(p, http.StatusOK)
+	proof := solved(t, p)
+
+	if rr := doRequest(h, "1.2.3.4:1000", proof); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if rr := doRequest(h, "1.2.3.4:1000", proof); rr.Code != http.StatusPreconditionRequired {
+		t.Fatalf("expected 428 on reuse, got %d", rr.Code)
+	}
+}
+
+// Challenge, выданный при меньшей нагрузке, проще требуемого
+func TestProofOfWork_Verify_TooEasy(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	p := newTestPoW(clock)
+
+	challenge, difficulty, _, _ := p.Challenge()
+	solution, _ := pow.Solve(context.Background(), challenge, difficulty)
+
+	if err := p.Verify(challenge, solution, 4); err != serr.ErrInvalidPoW {
+		t.Fatalf("expected ErrInvalidPoW, got %v", err)
+	}
+	if err := p.Verify("", "", 4); err != serr.ErrPoWRequired {
+		t.Fatalf("expected ErrPoWRequired, got %v", err)
+	}
+}
+
+// Без нагрузки решение не требуется, успешные входы нагрузку не увеличивают
+func TestProofOfWork_Middleware_NoLoad(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	p := newTestPoW(clock)
+	h := powHandler(p, http.StatusOK)
+
+	for i := 0; i < 10; i++ {
+		if rr := doRequest(h, "1.2.3.4:1000", nil); rr.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rr.Code)
+		}
+	}
+	if s := p.Stats(); s.Load != 0 || s.Difficulty != 0 {
+		t.Fatalf("unexpected stats: %+v", s)
+	}
+}
+
+func TestProofOfWork_RegistrationsCount(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	p := newTestPoW(clock)
+	h := powHandler(p, http.StatusCreated)
+
+	for i := 0; i < 4; i++ {
+		doRequest(h, "1.2.3.4:1000", nil)
+	}
+	if d := p.Difficulty(); d != 4 {
+		t.Fatalf("expected difficulty 4 after registrations, got %d", d)
+	}
+}
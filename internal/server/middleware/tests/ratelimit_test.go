@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
+	"github.com/google/uuid"
+)
+
+// фиксированные часы для тестов
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func newLimitedHandler(l *middleware.RateLimiter) http.Handler {
+	return l.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+}
+
+func doRequest(h http.Handler, remoteAddr string, mutate func(r *http.Request)) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", nil)
+	req.RemoteAddr = remoteAddr
+	if mutate != nil {
+		mutate(req)
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+// По IP: burst пропускается, дальше 429 с Retry-After
+func TestRateLimiter_KeyByIP_BurstThen429(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	l := middleware.NewRateLimiter(1, 2, middleware.KeyByIP(false))
+	l.Now = clock.Now
+	h := newLimitedHandler(l)
+
+	for i := 0; i < 2; i++ {
+		if rr := doRequest(h, "10.0.0.1:1234", nil); rr.Code != http.StatusOK {
+			t.Fatalf("request %d: expected 200, got %d", i, rr.Code)
+		}
+	}
+
+	rr := doRequest(h, "10.0.0.1:1234", nil)
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rr.Code)
+	}
+	if rr.Header().Get("Retry-After") != "1" {
+		t.Fatalf("expected Retry-After=1, got %q", rr.Header().Get("Retry-After"))
+	}
+
+	// другой IP — свой bucket
+	if rr := doRequest(h, "10.0.0.2:1234", nil); rr.Code != http.StatusOK {
+		t.Fatalf("other ip: expected 200, got %d", rr.Code)
+	}
+
+	// через секунду появляется токен
+	clock.now = clock.now.Add(time.Second)
+	if rr := doRequest(h, "10.0.0.1:1234", nil); rr.Code != http.StatusOK {
+		t.Fatalf("after refill: expected 200, got %d", rr.Code)
+	}
+}
+
+// X-Forwarded-For учитывается только при trust_proxy
+func TestRateLimiter_KeyByIP_TrustProxy(t *testing.T) {
+	// прокси дописывает адрес клиента в конец заголовка
+	xff := func(ip string) func(r *http.Request) {
+		return func(r *http.Request) { r.Header.Set("X-Forwarded-For", ip) }
+	}
+
+	// trust_proxy=false: все запросы с одного RemoteAddr — один bucket
+	l := middleware.NewRateLimiter(1, 1, middleware.KeyByIP(false))
+	h := newLimitedHandler(l)
+	if rr := doRequest(h, "10.0.0.1:1", xff("1.1.1.1")); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if rr := doRequest(h, "10.0.0.1:1", xff("2.2.2.2")); rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 (xff ignored), got %d", rr.Code)
+	}
+
+	// trust_proxy=true: разные клиенты за прокси считаются отдельно
+	l = middleware.NewRateLimiter(1, 1, middleware.KeyByIP(true))
+	h = newLimitedHandler(l)
+	if rr := doRequest(h, "10.0.0.1:1", xff("1.1.1.1")); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if rr := doRequest(h, "10.0.0.1:1", xff("2.2.2.2")); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 for other client, got %d", rr.Code)
+	}
+	if rr := doRequest(h, "10.0.0.1:1", xff("1.1.1.1")); rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rr.Code)
+	}
+}
+
+// Левые адреса X-Forwarded-For присылает сам клиент:
+// подменяя их, нельзя получать новый bucket на каждый запрос
+func TestRateLimiter_KeyByIP_SpoofedForwardedFor(t *testing.T) {
+	spoofed := func(fake string) func(r *http.Request) {
+		return func(r *http.Request) { r.Header.Set("X-Forwarded-For", fake+", 203.0.113.7") }
+	}
+
+	l := middleware.NewRateLimiter(1, 1, middleware.KeyByIP(true))
+	h := newLimitedHandler(l)
+	if rr := doRequest(h, "10.0.0.1:1", spoofed("1.1.1.1")); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if rr := doRequest(h, "10.0.0.1:1", spoofed("2.2.2.2")); rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 (spoofed xff ignored), got %d", rr.Code)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.RemoteAddr = "10.0.0.1:1"
+	spoofed("1.1.1.1")(r)
+	if ip := middleware.ClientIP(r, true); ip != "203.0.113.7" {
+		t.Fatalf("expected rightmost xff address, got %q", ip)
+	}
+	// мусор вместо адреса — берём RemoteAddr
+	r.Header.Set("X-Forwarded-For", "1.1.1.1, not-an-ip")
+	if ip := middleware.ClientIP(r, true); ip != "10.0.0.1" {
+		t.Fatalf("expected RemoteAddr, got %q", ip)
+	}
+}
+
+// По пользователю: разные userID не мешают друг другу, даже с одного IP
+func TestRateLimiter_KeyByUser(t *testing.T) {
+	l := middleware.NewRateLimiter(1, 1, middleware.KeyByUser(false))
+	h := newLimitedHandler(l)
+
+	user1 := uuid.New()
+	user2 := uuid.New()
+	as := func(id uuid.UUID) func(r *http.Request) {
+		return func(r *http.Request) {
+			*r = *r.WithContext(middleware.ContextWithUserID(r.Context(), id))
+		}
+	}
+
+	if rr := doRequest(h, "10.0.0.1:1", as(user1)); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+	if rr := doRequest(h, "10.0.0.1:1", as(user2)); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 for other user, got %d", rr.Code)
+	}
+	if rr := doRequest(h, "10.0.0.1:1", as(user1)); rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d", rr.Code)
+	}
+
+	// без пользователя в контексте лимит считается по IP
+	if rr := doRequest(h, "10.0.0.1:1", nil); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 for anonymous, got %d", rr.Code)
+	}
+	if rr := doRequest(h, "10.0.0.1:1", nil); rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 for anonymous, got %d", rr.Code)
+	}
+}
+
+// Простаивающие bucket'ы удаляются
+func TestRateLimiter_EvictsIdleBuckets(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	l := middleware.NewRateLimiter(1, 1, middleware.KeyByIP(false))
+	l.Now = clock.Now
+	l.IdleTTL = time.Minute
+
+	l.Allow("a")
+	l.Allow("b")
+	if l.Len() != 2 {
+		t.Fatalf("expected 2 buckets, got %d", l.Len())
+	}
+
+	clock.now = clock.now.Add(2 * time.Minute)
+	l.Allow("c")
+
+	if l.Len() != 1 {
+		t.Fatalf("expected idle buckets evicted, got %d", l.Len())
+	}
+}
@@ -7,6 +7,7 @@
 package http
 
 import (
+	"expvar"
 	"net/http"
 
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/api"
@@ -15,36 +16,152 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
+// NewMetricsRouter создаёт роутер отдельного листенера метрик
+// (observability.metrics.addr): expvar по пути path.
+//
+// На основном роутере метрик нет: в них cmdline процесса и внутренние счётчики,
+// поэтому листенер метрик по умолчанию слушает только loopback.
+func NewMetricsRouter(path string) http.Handler {
+	r := chi.NewRouter()
+	// метрики (в том числе очередь хэширования паролей)
+	r.Get(path, expvar.Handler().ServeHTTP)
+	return r
+}
+
 // NewRouter создаёт и настраивает HTTP-роутер сервера.
 //
 // Роутер использует chi.Router и регистрирует:
-//   - публичные эндпоинты аутентификации под префиксом /auth;
+//   - публичные эндпоинты аутентификации под префиксом /auth
+//     (включая /auth/2fa/verify — второй шаг входа, /auth/restore — отмену удаления аккаунта
+//     /auth/device/code, /auth/device/token — вход по коду устройства
+//     /auth/oidc — вход по ID-токену внешнего OpenID Connect провайдера,
+//     /auth/email/verify и /auth/password/reset — подтверждение email и сброс пароля по почте);
+//   - /.well-known/jwks.json с публичными ключами подписи JWT;
 //   - middleware логирования для всех запросов;
-//   - группу защищённых JWT эндпоинтов (пока без маршрутов secrets).
+//   - rate limit (если h.Limiter задан) для /auth и защищённых путей;
+//   - группу защищённых JWT эндпоинтов (/me, /secrets);
+//   - /admin для администраторов сервера (users.is_admin): список пользователей,
+//     блокировка аккаунтов, отзыв сессий и статистика хранилища;
+//     персональные API-токены принимаются только для /secrets и GET /me;
+//   - повышенные права после POST /auth/reauth для удаления секретов, выпуска
+//     API-токенов, привязки сертификатов, подключения 2FA, подтверждения входа
+//     по коду устройства и выпуска приглашений. Смена пароля и удаление аккаунта
+//     и так требуют пароль в теле запроса.
 func NewRouter(h *api.Handler) http.Handler {
 	r := chi.NewRouter()
 	// логирование всех запросов
 	r.Use(middleware.LoggerMiddleware())
+	// сведения о клиенте (устройство, UA, IP) для сессий
+	r.Use(h.ClientInfoMiddleware())
 
 	// добавляем swagger
 	r.Get("/swagger/*", httpSwagger.WrapHandler)
+	// публичные ключи проверки access-токенов
+	r.Get("/.well-known/jwks.json", h.JWKS)
 	// Публичные пути
 	r.Route("/auth", func(r chi.Router) {
-		r.Post("/register", h.Register)
-		r.Post("/login", h.Login)
+		// ограничиваем перебор паролей на /auth/login
+		if h.Limiter != nil {
+			r.Use(h.Limiter.Middleware())
+		}
+		// под нагрузкой регистрация и вход требуют решения proof-of-work
+		r.Group(func(r chi.Router) {
+			if h.PoW != nil {
+				r.Use(h.PoW.Middleware())
+			}
+			r.Post("/register", h.Register)
+			r.Post("/login", h.Login)
+			// запросы, отправляющие письма
+			r.Post("/email/verify/resend", h.ResendVerification)
+			r.Post("/password/reset/request", h.RequestPasswordReset)
+		})
+		r.Get("/challenge", h.Challenge)
 		r.Post("/refresh", h.Refresh)
+		r.Post("/logout", h.Logout)
+		// второй шаг входа при подключённом TOTP
+		r.Post("/2fa/verify", h.VerifyMFA)
+		// отмена удаления аккаунта (вход в помеченный аккаунт заблокирован)
+		r.Post("/restore", h.RestoreAccount)
+		// вход по коду устройства: headless-клиент получает код и опрашивает токены
+		r.Post("/device/code", h.DeviceCode)
+		r.Post("/device/token", h.DeviceToken)
+		// вход по ID-токену корпоративного провайдера
+		r.Post("/oidc", h.LoginOIDC)
+		// токены из писем: подтверждение email и сброс пароля
+		r.Post("/email/verify", h.VerifyEmail)
+		r.Post("/password/reset", h.ResetPassword)
+
+		// управление сессиями, паролем, 2FA, API-токенами, клиентскими сертификатами,
+		// приглашениями и подтверждение входа по коду устройства требуют access токен сессии
+		r.Group(func(r chi.Router) {
+			r.Use(h.Verifier.AuthMiddleware())
+			r.Use(middleware.SessionOnly())
+			// повторный ввод пароля выдаёт токен с повышенными правами
+			r.Post("/reauth", h.Reauth)
+			r.Get("/sessions", h.ListSessions)
+			r.Delete("/sessions/{id}", h.RevokeSession)
+			r.Post("/2fa/confirm", h.ConfirmTOTP)
+			r.Post("/password", h.ChangePassword)
+			r.Get("/tokens", h.ListAPITokens)
+			r.Delete("/tokens/{id}", h.RevokeAPIToken)
+			r.Post("/device/deny", h.DenyDevice)
+			r.Get("/certs", h.ListClientCerts)
+			r.Get("/invites", h.ListInvites)
+			r.Delete("/invites/{id}", h.RevokeInvite)
+
+			// с украденным access-токеном нельзя закрепиться в аккаунте:
+			// выпустить API-токен, привязать свой сертификат или TOTP, впустить своё устройство
+			r.Group(func(r chi.Router) {
+				r.Use(h.Verifier.RequireElevated())
+				r.Post("/2fa/enroll", h.EnrollTOTP)
+				r.Post("/tokens", h.CreateAPIToken)
+				r.Post("/device/approve", h.ApproveDevice)
+				r.Post("/certs", h.BindClientCert)
+				r.Delete("/certs/{id}", h.UnbindClientCert)
+				// коды приглашений (только для auth.invites.admins)
+				r.Post("/invites", h.CreateInvite)
+			})
+		})
 	})
 	// защищены пути
 	r.Group(func(r chi.Router) {
 		// проверка access токена
 		r.Use(h.Verifier.AuthMiddleware())
+		// лимит ставим после проверки токена, чтобы при key=user был известен userID
+		if h.Limiter != nil {
+			r.Use(h.Limiter.Middleware())
+		}
+		// сведения о текущем пользователе и удаление аккаунта
+		r.Get("/me", h.Me)
+		r.With(middleware.SessionOnly()).Delete("/me", h.DeleteMe)
+		// журнал аудита: входы, отзыв сессий, изменения секретов
+		r.With(middleware.SessionOnly()).Get("/audit", h.ListAudit)
 		// запросы для секретов
 		r.Route("/secrets", func(r chi.Router) {
 			r.Post("/", h.CreateSecret) // Создание секрета
 			r.Get("/", h.ListSecrets)   // Получение все секретов на клиенте делается каманда sync
 			// r.Get("/{id}", h.GetSecret) // реализуется на клиенте
-			r.Put("/{id}", h.UpdateSecret)    // обновляем, передаём id в параметрах и данные секрета в теле
-			r.Delete("/{id}", h.DeleteSecret) // удаляем секрет по id и по ?version
+			r.Put("/{id}", h.UpdateSecret) // обновляем, передаём id в параметрах и данные секрета в теле
+			// удаляем секрет по id и по ?version; с access-токеном — только после /auth/reauth
+			r.With(h.Verifier.RequireElevated()).Delete("/{id}", h.DeleteSecret)
+		})
+	})
+	// администрирование: только access-токен сессии администратора
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(h.Verifier.AuthMiddleware())
+		if h.Limiter != nil {
+			r.Use(h.Limiter.Middleware())
+		}
+		r.Use(middleware.SessionOnly())
+		r.Use(h.RequireAdmin())
+		r.Get("/users", h.ListAdminUsers)
+		r.Get("/stats", h.AdminStats)
+		// действия над чужими аккаунтами — только после /auth/reauth
+		r.Group(func(r chi.Router) {
+			r.Use(h.Verifier.RequireElevated())
+			r.Post("/users/{id}/disable", h.DisableUser)
+			r.Post("/users/{id}/enable", h.EnableUser)
+			r.Delete("/users/{id}/sessions", h.RevokeUserSessions)
 		})
 	})
 
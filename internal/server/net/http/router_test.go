@@ -20,6 +20,7 @@ import (
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/middleware"
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
 	svcmocks "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/logger"
 )
 
@@ -101,9 +102,14 @@ func TestRouter_AuthLogin_OK(t *testing.T) {
 
 	sessionsRepo.
 		EXPECT().
-		Create(gomock.Any(), userID, gomock.Any(), gomock.Any()).
+		Create(gomock.Any(), userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(uuid.New(), nil)
 
+	sessionsRepo.
+		EXPECT().
+		RevokeOldestActive(gomock.Any(), userID, 5).
+		Return(nil)
+
 	// --- act ---
 	body, _ := json.Marshal(map[string]string{
 		"email":    email,
@@ -141,3 +147,179 @@ func TestRouter_AuthLogin_OK(t *testing.T) {
 		t.Fatalf("access_token does not look like JWT: %q", resp.AccessToken)
 	}
 }
+
+func TestRouter_RateLimit_Returns429(t *testing.T) {
+	cfg := &config.Config{}
+	authSvc := service.NewAuthService(nil, nil, cfg)
+	verifier := middleware.NewJWTVerifier("supersecretkeysupersecretkey123456", "issuer", "audience")
+
+	h := api.NewHandler(&service.Services{Auth: authSvc}, logger.NewHTTPLogger(), verifier)
+	h.Limiter = middleware.NewRateLimiter(1, 1, middleware.KeyByIP(false))
+	router := NewRouter(h)
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader("{bad json"))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send(); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+	rec := send()
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected %d, got %d", http.StatusTooManyRequests, rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("expected Retry-After header")
+	}
+}
+
+func TestRouter_Metrics(t *testing.T) {
+	verifier := middleware.NewJWTVerifier("supersecretkeysupersecretkey123456", "issuer", "audience")
+
+	// основной роутер метрики не отдаёт
+	h := api.NewHandler(&service.Services{}, logger.NewHTTPLogger(), verifier)
+	rec := httptest.NewRecorder()
+	NewRouter(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	// только отдельный листенер метрик
+	rec = httptest.NewRecorder()
+	NewMetricsRouter("/metrics").ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var vars map[string]json.RawMessage
+	if err := json.NewDecoder(rec.Body).Decode(&vars); err != nil {
+		t.Fatalf("decode metrics: %v", err)
+	}
+	if _, ok := vars["memstats"]; !ok {
+		t.Fatalf("expected expvar output, got keys %v", len(vars))
+	}
+}
+
+// удаление секрета и выпуск API-токена требуют токена после /auth/reauth
+func TestRouter_SensitiveRoutes_RequireReauth(t *testing.T) {
+	const key = "supersecretkeysupersecretkey123456"
+	verifier := middleware.NewJWTVerifier(key, "issuer", "audience")
+	h := api.NewHandler(&service.Services{}, logger.NewHTTPLogger(), verifier)
+	router := NewRouter(h)
+
+	jwtCfg := crypto.JWTConfig{Issuer: "issuer", Audience: "audience", SigningKey: key, AccessTTL: time.Minute}
+	access, err := crypto.NewAccessToken(uuid.NewString(), uuid.NewString(), jwtCfg)
+	if err != nil {
+		t.Fatalf("NewAccessToken: %v", err)
+	}
+
+	for _, route := range []struct{ method, path string }{
+		{http.MethodDelete, "/secrets/" + uuid.NewString() + "?version=1"},
+		{http.MethodPost, "/auth/tokens"},
+		{http.MethodPost, "/auth/certs"},
+		{http.MethodPost, "/auth/2fa/enroll"},
+		{http.MethodPost, "/auth/device/approve"},
+	} {
+		req := httptest.NewRequest(route.method, route.path, nil)
+		req.Header.Set("Authorization", "Bearer "+access)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("%s %s: expected %d, got %d", route.method, route.path, http.StatusUnauthorized, rec.Code)
+		}
+		if !strings.Contains(rec.Header().Get("WWW-Authenticate"), "insufficient_user_authentication") {
+			t.Fatalf("%s %s: unexpected WWW-Authenticate %q", route.method, route.path, rec.Header().Get("WWW-Authenticate"))
+		}
+	}
+}
+
+func TestRouter_Audit(t *testing.T) {
+	const key = "supersecretkeysupersecretkey123456"
+	verifier := middleware.NewJWTVerifier(key, "issuer", "audience")
+	h := api.NewHandler(&service.Services{}, logger.NewHTTPLogger(), verifier)
+	router := NewRouter(h)
+
+	// без токена журнал недоступен
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/audit", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	jwtCfg := crypto.JWTConfig{Issuer: "issuer", Audience: "audience", SigningKey: key, AccessTTL: time.Minute}
+	access, err := crypto.NewAccessToken(uuid.NewString(), uuid.NewString(), jwtCfg)
+	if err != nil {
+		t.Fatalf("NewAccessToken: %v", err)
+	}
+
+	// журнал аудита не подключён
+	req := httptest.NewRequest(http.MethodGet, "/audit", nil)
+	req.Header.Set("Authorization", "Bearer "+access)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+}
+
+// Без mail.driver эндпоинты писем зарегистрированы, но отвечают 404
+func TestRouter_EmailRoutes_MailDisabled(t *testing.T) {
+	auth := service.NewAuthService(nil, nil, &config.Config{})
+	h := api.NewHandler(&service.Services{Auth: auth}, logger.NewHTTPLogger(), nil)
+	router := NewRouter(h)
+
+	for _, path := range []string{
+		"/auth/email/verify",
+		"/auth/email/verify/resend",
+		"/auth/password/reset/request",
+		"/auth/password/reset",
+	} {
+		body := strings.NewReader(`{"token":"t","email":"user@example.com","new_password":"NewPass123"}`)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, body))
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("%s: expected %d, got %d", path, http.StatusNotFound, rec.Code)
+		}
+	}
+}
+
+// /admin: только с токеном, только администраторам, действия над аккаунтами — после reauth
+func TestRouter_Admin(t *testing.T) {
+	const key = "supersecretkeysupersecretkey123456"
+	verifier := middleware.NewJWTVerifier(key, "issuer", "audience")
+
+	ctrl := gomock.NewController(t)
+	repo := svcmocks.NewMockAdminRepo(ctrl)
+	svc := service.NewServices(service.Repositories{
+		Users:    svcmocks.NewMockUsersRepo(ctrl),
+		Sessions: svcmocks.NewMockSessionsRepo(ctrl),
+		Admin:    repo,
+	}, &config.Config{})
+	router := NewRouter(api.NewHandler(svc, logger.NewHTTPLogger(), verifier))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/stats", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+
+	adminID := uuid.New()
+	jwtCfg := crypto.JWTConfig{Issuer: "issuer", Audience: "audience", SigningKey: key, AccessTTL: time.Minute}
+	access, err := crypto.NewAccessToken(adminID.String(), uuid.NewString(), jwtCfg)
+	if err != nil {
+		t.Fatalf("NewAccessToken: %v", err)
+	}
+	repo.EXPECT().GetStatus(gomock.Any(), adminID).Return(models.UserStatus{IsAdmin: true}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/users/"+uuid.NewString()+"/disable", nil)
+	req.Header.Set("Authorization", "Bearer "+access)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Header().Get("WWW-Authenticate"), "insufficient_user_authentication") {
+		t.Fatalf("expected reauth challenge, got %d %q", rec.Code, rec.Header().Get("WWW-Authenticate"))
+	}
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// AdminRepository — роль и блокировка пользователей, данные для администрирования.
+type AdminRepository struct {
+	db *sql.DB
+}
+
+// NewAdminRepository создаёт новый AdminRepository.
+func NewAdminRepository(db *sql.DB) *AdminRepository {
+	return &AdminRepository{db: db}
+}
+
+// GetStatus возвращает роль, блокировку и пометку удаления пользователя.
+//
+// Ошибки:
+//   - ErrNotFound — пользователя нет
+//   - ErrInternal — ошибка БД
+func (r *AdminRepository) GetStatus(ctx context.Context, userID uuid.UUID) (models.UserStatus, error) {
+	var st models.UserStatus
+	err := r.db.QueryRowContext(ctx,
+		`SELECT is_admin, disabled_at IS NOT NULL, delete_after IS NOT NULL FROM users WHERE id = $1`,
+		userID,
+	).Scan(&st.IsAdmin, &st.Disabled, &st.PendingDeletion)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.UserStatus{}, serr.ErrNotFound
+		}
+		return models.UserStatus{}, serr.ErrInternal
+	}
+	return st, nil
+}
+
+// RecordLogin запоминает время последнего входа пользователя.
+func (r *AdminRepository) RecordLogin(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users SET last_login_at = now() WHERE id = $1`,
+		userID,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	return nil
+}
+
+// ListUsers возвращает страницу пользователей в порядке регистрации
+// с числом секретов и временем последнего входа.
+func (r *AdminRepository) ListUsers(ctx context.Context, limit, offset int) ([]models.AdminUser, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT u.id, u.email, u.is_admin, u.disabled_at, u.created_at,
+		        (SELECT COUNT(*) FROM secrets s WHERE s.user_id = u.id),
+		        u.last_login_at
+		   FROM users u
+		  ORDER BY u.created_at, u.id
+		  LIMIT $1 OFFSET $2`,
+		limit, offset,
+	)
+	if err != nil {
+		return nil, serr.ErrInternal
+	}
+	defer rows.Close()
+
+	users := make([]models.AdminUser, 0, limit)
+	for rows.Next() {
+		var (
+			u          models.AdminUser
+			disabledAt sql.NullTime
+			lastLogin  sql.NullTime
+		)
+		if err := rows.Scan(&u.ID, &u.Email, &u.IsAdmin, &disabledAt, &u.CreatedAt, &u.SecretCount, &lastLogin); err != nil {
+			return nil, serr.ErrInternal
+		}
+		if disabledAt.Valid {
+			u.DisabledAt = &disabledAt.Time
+		}
+		if lastLogin.Valid {
+			u.LastLoginAt = &lastLogin.Time
+		}
+		users = append(users, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, serr.ErrInternal
+	}
+	return users, nil
+}
+
+// SetDisabled блокирует (disabled=true) или разблокирует аккаунт.
+// Повторная блокировка не меняет время первой.
+//
+// Ошибки:
+//   - ErrNotFound — пользователя нет
+//   - ErrInternal — ошибка БД
+func (r *AdminRepository) SetDisabled(ctx context.Context, userID uuid.UUID, disabled bool) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users
+		    SET disabled_at = CASE WHEN $2 THEN COALESCE(disabled_at, now()) ELSE NULL END
+		  WHERE id = $1`,
+		userID, disabled,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if n == 0 {
+		return serr.ErrNotFound
+	}
+	return nil
+}
+
+// SetAdmin выдаёт (isAdmin=true) или снимает роль администратора.
+//
+// Ошибки:
+//   - ErrNotFound — пользователя нет
+//   - ErrInternal — ошибка БД
+func (r *AdminRepository) SetAdmin(ctx context.Context, userID uuid.UUID, isAdmin bool) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET is_admin = $2 WHERE id = $1`,
+		userID, isAdmin,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if n == 0 {
+		return serr.ErrNotFound
+	}
+	return nil
+}
+
+// StorageStats возвращает сводную статистику хранилища.
+func (r *AdminRepository) StorageStats(ctx context.Context) (models.StorageStats, error) {
+	var st models.StorageStats
+	err := r.db.QueryRowContext(ctx,
+		`SELECT (SELECT COUNT(*) FROM users),
+		        (SELECT COUNT(*) FROM users WHERE disabled_at IS NOT NULL),
+		        (SELECT COUNT(*) FROM secrets),
+		        (SELECT COALESCE(SUM(octet_length(payload) + COALESCE(octet_length(meta), 0)), 0) FROM secrets),
+		        (SELECT COUNT(*) FROM sessions WHERE revoked_at IS NULL AND expires_at > now())`,
+	).Scan(&st.Users, &st.DisabledUsers, &st.Secrets, &st.SecretBytes, &st.ActiveSessions)
+	if err != nil {
+		return models.StorageStats{}, serr.ErrInternal
+	}
+	return st, nil
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// APITokensRepository хранит персональные API-токены пользователей.
+//
+// Токен хранится только в виде SHA-256, scopes и types — строками через запятую.
+type APITokensRepository struct {
+	db *sql.DB
+}
+
+// NewAPITokensRepository создаёт новый APITokensRepository.
+func NewAPITokensRepository(db *sql.DB) *APITokensRepository {
+	return &APITokensRepository{db: db}
+}
+
+// Create сохраняет новый API-токен.
+//
+// Возвращает ID токена и время создания или ErrInternal — при ошибке БД
+func (r *APITokensRepository) Create(ctx context.Context, userID uuid.UUID, name string, tokenHash []byte, scopes, types []string, expiresAt time.Time) (uuid.UUID, time.Time, error) {
+	var (
+		id        uuid.UUID
+		createdAt time.Time
+	)
+
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO api_tokens (user_id, name, token_hash, scopes, types, expires_at)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id, created_at`,
+		userID, name, tokenHash, joinList(scopes), joinList(types), expiresAt,
+	).Scan(&id, &createdAt)
+	if err != nil {
+		return uuid.Nil, time.Time{}, serr.ErrInternal
+	}
+
+	return id, createdAt, nil
+}
+
+// lastUsedInterval — как часто обновляется last_used_at: токены CI дёргают API
+// на каждом шаге, и запись на каждый запрос нагружала бы БД впустую.
+const lastUsedInterval = time.Minute
+
+// Use находит действующий токен по хэшу и отмечает время использования
+// (не чаще раза в lastUsedInterval).
+//
+// Токены отозванные, истёкшие и принадлежащие аккаунтам,
+// ожидающим удаления, не принимаются.
+//
+// Ошибки:
+//   - ErrUnauthorized если действующего токена нет
+//   - ErrInternal при ошибке БД
+func (r *APITokensRepository) Use(ctx context.Context, tokenHash []byte) (models.APIToken, error) {
+	var (
+		t             models.APIToken
+		scopes, types string
+		lastUsedAt    sql.NullTime
+	)
+
+	err := r.db.QueryRowContext(ctx,
+		`SELECT t.id, t.user_id, t.name, t.scopes, t.types, t.created_at, t.expires_at, t.last_used_at
+		   FROM api_tokens t
+		   JOIN users u ON u.id = t.user_id
+		  WHERE t.token_hash = $1
+		    AND t.revoked_at IS NULL
+		    AND t.expires_at > now()
+		    AND u.delete_after IS NULL`,
+		tokenHash,
+	).Scan(&t.ID, &t.UserID, &t.Name, &scopes, &types, &t.CreatedAt, &t.ExpiresAt, &lastUsedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.APIToken{}, serr.ErrUnauthorized
+		}
+		return models.APIToken{}, serr.ErrInternal
+	}
+
+	t.Scopes = splitList(scopes)
+	t.Types = splitList(types)
+	if lastUsedAt.Valid {
+		t.LastUsedAt = &lastUsedAt.Time
+	}
+
+	now := time.Now()
+	if t.LastUsedAt == nil || now.Sub(*t.LastUsedAt) >= lastUsedInterval {
+		// условие повторяется в SQL: параллельные запросы не пишут дважды
+		_, err := r.db.ExecContext(ctx,
+			`UPDATE api_tokens
+			    SET last_used_at = $2
+			  WHERE id = $1
+			    AND (last_used_at IS NULL OR last_used_at <= $3)`,
+			t.ID, now, now.Add(-lastUsedInterval),
+		)
+		if err != nil {
+			return models.APIToken{}, serr.ErrInternal
+		}
+		t.LastUsedAt = &now
+	}
+	return t, nil
+}
+
+// ListByUser возвращает неотозванные и не истёкшие токены пользователя,
+// новые первыми.
+//
+// Возвращает ErrInternal — при ошибке БД
+func (r *APITokensRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.APIToken, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, user_id, name, scopes, types, created_at, expires_at, last_used_at
+		   FROM api_tokens
+		  WHERE user_id = $1
+		    AND revoked_at IS NULL
+		    AND expires_at > now()
+		  ORDER BY created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, serr.ErrInternal
+	}
+	defer rows.Close()
+
+	var list []models.APIToken
+	for rows.Next() {
+		var (
+			t             models.APIToken
+			scopes, types string
+			lastUsedAt    sql.NullTime
+		)
+		if err := rows.Scan(&t.ID, &t.UserID, &t.Name, &scopes, &types, &t.CreatedAt, &t.ExpiresAt, &lastUsedAt); err != nil {
+			return nil, serr.ErrInternal
+		}
+		t.Scopes = splitList(scopes)
+		t.Types = splitList(types)
+		if lastUsedAt.Valid {
+			t.LastUsedAt = &lastUsedAt.Time
+		}
+		list = append(list, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, serr.ErrInternal
+	}
+
+	return list, nil
+}
+
+// Revoke отзывает токен пользователя.
+//
+// Ошибки:
+//   - ErrNotFound если токена нет, он чужой или уже отозван
+//   - ErrInternal при ошибке БД
+func (r *APITokensRepository) Revoke(ctx context.Context, userID, tokenID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE api_tokens
+		    SET revoked_at = now()
+		  WHERE id = $1
+		    AND user_id = $2
+		    AND revoked_at IS NULL`,
+		tokenID, userID,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if affected == 0 {
+		return serr.ErrNotFound
+	}
+	return nil
+}
+
+// RevokeAllForUser отзывает все действующие токены пользователя
+// (после смены или сброса пароля).
+//
+// Возвращает ErrInternal — при ошибке БД
+func (r *APITokensRepository) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE api_tokens
+		    SET revoked_at = now()
+		  WHERE user_id = $1
+		    AND revoked_at IS NULL`,
+		userID,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	return nil
+}
+
+func joinList(items []string) string {
+	return strings.Join(items, ",")
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
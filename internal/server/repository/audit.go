@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// AuditRepository хранит журнал аудита (таблица audit_events).
+//
+// Записи только добавляются: UPDATE запрещён триггером,
+// удаляются лишь события старше срока хранения (Prune).
+type AuditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository создаёт новый AuditRepository.
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Append добавляет событие в журнал. Время события выставляет БД.
+//
+// Возвращает ErrInternal — при ошибке БД
+func (r *AuditRepository) Append(ctx context.Context, e models.AuditEvent) error {
+	if _, err := r.db.ExecContext(ctx,
+		`INSERT INTO audit_events (user_id, event, ip, user_agent, secret_id)
+		 VALUES ($1, $2, $3, $4, $5)`,
+		e.UserID, e.Type, e.IP, e.UserAgent, uuid.NullUUID{UUID: e.SecretID, Valid: e.SecretID != uuid.Nil},
+	); err != nil {
+		return serr.ErrInternal
+	}
+	return nil
+}
+
+// List возвращает события пользователя по фильтру, от новых к старым.
+//
+// Возвращает ErrInternal — при ошибке БД
+func (r *AuditRepository) List(ctx context.Context, userID uuid.UUID, f models.AuditFilter) ([]models.AuditEvent, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, user_id, event, ip, user_agent, secret_id, created_at
+		   FROM audit_events
+		  WHERE user_id = $1
+		    AND ($2::timestamptz IS NULL OR created_at >= $2)
+		    AND ($3 = '' OR event = $3)
+		    AND ($4 = 0 OR id < $4)
+		  ORDER BY id DESC
+		  LIMIT $5`,
+		userID, sql.NullTime{Time: f.Since, Valid: !f.Since.IsZero()}, f.Type, f.Before, f.Limit,
+	)
+	if err != nil {
+		return nil, serr.ErrInternal
+	}
+	defer rows.Close()
+
+	var list []models.AuditEvent
+	for rows.Next() {
+		var (
+			e        models.AuditEvent
+			secretID uuid.NullUUID
+		)
+		if err := rows.Scan(&e.ID, &e.UserID, &e.Type, &e.IP, &e.UserAgent, &secretID, &e.CreatedAt); err != nil {
+			return nil, serr.ErrInternal
+		}
+		e.SecretID = secretID.UUID
+		list = append(list, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, serr.ErrInternal
+	}
+
+	return list, nil
+}
+
+// Prune удаляет события старше before и возвращает их число.
+//
+// Возвращает ErrInternal — при ошибке БД
+func (r *AuditRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM audit_events WHERE created_at < $1`,
+		before,
+	)
+	if err != nil {
+		return 0, serr.ErrInternal
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, serr.ErrInternal
+	}
+	return n, nil
+}
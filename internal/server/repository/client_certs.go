@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// ClientCertsRepository хранит клиентские сертификаты, привязанные к аккаунтам.
+//
+// Сохраняется только SHA-256 сертификата и сведения для списка (subject, срок действия).
+type ClientCertsRepository struct {
+	db *sql.DB
+}
+
+// NewClientCertsRepository создаёт новый ClientCertsRepository.
+func NewClientCertsRepository(db *sql.DB) *ClientCertsRepository {
+	return &ClientCertsRepository{db: db}
+}
+
+// Create привязывает сертификат к пользователю.
+//
+// Возвращает сохранённую запись (с ID и временем создания).
+//
+// Ошибки:
+//   - ErrAlreadyExists если сертификат с таким отпечатком уже привязан
+//   - ErrInternal при ошибке БД
+func (r *ClientCertsRepository) Create(ctx context.Context, cert models.ClientCert) (models.ClientCert, error) {
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO client_certificates (user_id, name, subject, fingerprint, not_after)
+		 VALUES ($1, $2, $3, $4, $5)
+		 RETURNING id, created_at`,
+		cert.UserID, cert.Name, cert.Subject, cert.Fingerprint, cert.NotAfter,
+	).Scan(&cert.ID, &cert.CreatedAt)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+			return models.ClientCert{}, serr.ErrAlreadyExists
+		}
+		return models.ClientCert{}, serr.ErrInternal
+	}
+
+	return cert, nil
+}
+
+// ListByUser возвращает сертификаты пользователя, новые первыми.
+//
+// Возвращает ErrInternal — при ошибке БД
+func (r *ClientCertsRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.ClientCert, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, user_id, name, subject, fingerprint, not_after, created_at
+		   FROM client_certificates
+		  WHERE user_id = $1
+		  ORDER BY created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, serr.ErrInternal
+	}
+	defer rows.Close()
+
+	var list []models.ClientCert
+	for rows.Next() {
+		var c models.ClientCert
+		if err := rows.Scan(&c.ID, &c.UserID, &c.Name, &c.Subject, &c.Fingerprint, &c.NotAfter, &c.CreatedAt); err != nil {
+			return nil, serr.ErrInternal
+		}
+		list = append(list, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, serr.ErrInternal
+	}
+
+	return list, nil
+}
+
+// Match одним запросом сообщает, привязаны ли к пользователю сертификаты (bound)
+// и есть ли среди них сертификат с отпечатком fingerprint (matched).
+//
+// Возвращает ErrInternal — при ошибке БД
+func (r *ClientCertsRepository) Match(ctx context.Context, userID uuid.UUID, fingerprint []byte) (bool, bool, error) {
+	var bound, matched bool
+
+	err := r.db.QueryRowContext(ctx,
+		`SELECT count(*) > 0,
+		        coalesce(bool_or(fingerprint = $2), false)
+		   FROM client_certificates
+		  WHERE user_id = $1`,
+		userID, fingerprint,
+	).Scan(&bound, &matched)
+	if err != nil {
+		return false, false, serr.ErrInternal
+	}
+
+	return bound, matched, nil
+}
+
+// Delete отвязывает сертификат пользователя.
+//
+// Ошибки:
+//   - ErrNotFound если сертификата нет или он чужой
+//   - ErrInternal при ошибке БД
+func (r *ClientCertsRepository) Delete(ctx context.Context, userID, certID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM client_certificates
+		  WHERE id = $1
+		    AND user_id = $2`,
+		certID, userID,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if affected == 0 {
+		return serr.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// DeviceCodesRepository хранит запросы входа по коду устройства (RFC 8628).
+type DeviceCodesRepository struct {
+	db *sql.DB
+}
+
+// NewDeviceCodesRepository создаёт новый DeviceCodesRepository.
+func NewDeviceCodesRepository(db *sql.DB) *DeviceCodesRepository {
+	return &DeviceCodesRepository{db: db}
+}
+
+// Create сохраняет новый запрос: хэш device_code и пользовательский код.
+//
+// Ошибки:
+//   - ErrAlreadyExists если такой user_code уже выдан (нужно сгенерировать другой)
+//   - ErrInternal при ошибке БД
+func (r *DeviceCodesRepository) Create(ctx context.Context, deviceCodeHash []byte, userCode string, interval time.Duration, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO device_codes (device_code_hash, user_code, interval_seconds, expires_at)
+		 VALUES ($1, $2, $3, $4)`,
+		deviceCodeHash, userCode, int(interval/time.Second), expiresAt,
+	)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" { // unique_violation
+			return serr.ErrAlreadyExists
+		}
+		return serr.ErrInternal
+	}
+	return nil
+}
+
+// Decide подтверждает (approve=true) или отклоняет запрос от имени пользователя.
+//
+// Решение принимается один раз и только пока код не истёк.
+//
+// Ошибки:
+//   - ErrNotFound если кода нет, он истёк или решение уже принято
+//   - ErrInternal при ошибке БД
+func (r *DeviceCodesRepository) Decide(ctx context.Context, userCode string, userID uuid.UUID, approve bool) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE device_codes
+		    SET user_id = $2,
+		        approved_at = CASE WHEN $3 THEN now() END,
+		        denied_at = CASE WHEN $3 THEN NULL ELSE now() END
+		  WHERE user_code = $1
+		    AND approved_at IS NULL
+		    AND denied_at IS NULL
+		    AND expires_at > now()`,
+		userCode, userID, approve,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if affected == 0 {
+		return serr.ErrNotFound
+	}
+	return nil
+}
+
+// Poll отмечает опрос по хэшу device_code и возвращает состояние запроса
+// вместе со временем предыдущего опроса.
+//
+// Ошибки:
+//   - ErrNotFound если кода нет (в том числе удалён после истечения)
+//   - ErrInternal при ошибке БД
+func (r *DeviceCodesRepository) Poll(ctx context.Context, deviceCodeHash []byte) (models.DeviceCode, error) {
+	var (
+		d          models.DeviceCode
+		userID     uuid.NullUUID
+		interval   int
+		lastPolled sql.NullTime
+	)
+
+	err := r.db.QueryRowContext(ctx,
+		`WITH prev AS (
+		     SELECT id, last_polled_at
+		       FROM device_codes
+		      WHERE device_code_hash = $1
+		        FOR UPDATE
+		 )
+		 UPDATE device_codes d
+		    SET last_polled_at = now()
+		   FROM prev
+		  WHERE d.id = prev.id
+		 RETURNING d.id, d.user_id, d.interval_seconds, d.expires_at,
+		           d.approved_at IS NOT NULL, d.denied_at IS NOT NULL, d.used_at IS NOT NULL,
+		           prev.last_polled_at`,
+		deviceCodeHash,
+	).Scan(&d.ID, &userID, &interval, &d.ExpiresAt, &d.Approved, &d.Denied, &d.Used, &lastPolled)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.DeviceCode{}, serr.ErrNotFound
+		}
+		return models.DeviceCode{}, serr.ErrInternal
+	}
+
+	if userID.Valid {
+		d.UserID = userID.UUID
+	}
+	d.Interval = time.Duration(interval) * time.Second
+	if lastPolled.Valid {
+		d.LastPolledAt = &lastPolled.Time
+	}
+	return d, nil
+}
+
+// SlowDown увеличивает интервал опроса на step (ответ slow_down).
+//
+// Возвращает ErrInternal — при ошибке БД
+func (r *DeviceCodesRepository) SlowDown(ctx context.Context, id uuid.UUID, step time.Duration) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE device_codes SET interval_seconds = interval_seconds + $2 WHERE id = $1`,
+		id, int(step/time.Second),
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	return nil
+}
+
+// Consume гасит подтверждённый запрос: токены по нему выдаются один раз.
+//
+// Ошибки:
+//   - ErrNotFound если запрос уже использован (параллельный опрос)
+//   - ErrInternal при ошибке БД
+func (r *DeviceCodesRepository) Consume(ctx context.Context, id uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE device_codes
+		    SET used_at = now()
+		  WHERE id = $1
+		    AND approved_at IS NOT NULL
+		    AND used_at IS NULL`,
+		id,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if affected == 0 {
+		return serr.ErrNotFound
+	}
+	return nil
+}
+
+// Prune удаляет истёкшие запросы.
+//
+// Возвращает ErrInternal — при ошибке БД
+func (r *DeviceCodesRepository) Prune(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM device_codes WHERE expires_at < now()`); err != nil {
+		return serr.ErrInternal
+	}
+	return nil
+}
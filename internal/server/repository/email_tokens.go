@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// EmailTokensRepository хранит одноразовые токены из писем
+// (подтверждение email, сброс пароля) и отметку о подтверждении email.
+//
+// Токен хранится только в виде SHA-256.
+type EmailTokensRepository struct {
+	db *sql.DB
+}
+
+// NewEmailTokensRepository создаёт новый EmailTokensRepository.
+func NewEmailTokensRepository(db *sql.DB) *EmailTokensRepository {
+	return &EmailTokensRepository{db: db}
+}
+
+// Create сохраняет новый токен назначения purpose и отменяет
+// прежние неиспользованные токены того же назначения.
+//
+// Ошибки:
+//   - ErrConflict — токен этого назначения выпущен меньше minInterval назад
+//     (защита от рассылки писем по чужому адресу)
+//   - ErrInternal — ошибка БД
+func (r *EmailTokensRepository) Create(ctx context.Context, userID uuid.UUID, purpose string, tokenHash []byte, expiresAt time.Time, minInterval time.Duration) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	defer tx.Rollback()
+
+	// блокируем пользователя, чтобы параллельные запросы не обошли интервал
+	var locked uuid.UUID
+	err = tx.QueryRowContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&locked)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return serr.ErrNotFound
+		}
+		return serr.ErrInternal
+	}
+
+	var recent bool
+	err = tx.QueryRowContext(ctx,
+		`SELECT EXISTS (
+		     SELECT 1 FROM email_tokens
+		      WHERE user_id = $1 AND purpose = $2 AND created_at > $3
+		 )`,
+		userID, purpose, time.Now().Add(-minInterval),
+	).Scan(&recent)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if recent {
+		return serr.ErrConflict
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE email_tokens
+		    SET used_at = now()
+		  WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL`,
+		userID, purpose,
+	); err != nil {
+		return serr.ErrInternal
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO email_tokens (user_id, purpose, token_hash, expires_at)
+		 VALUES ($1, $2, $3, $4)`,
+		userID, purpose, tokenHash, expiresAt,
+	); err != nil {
+		return serr.ErrInternal
+	}
+
+	if err := tx.Commit(); err != nil {
+		return serr.ErrInternal
+	}
+	return nil
+}
+
+// Consume атомарно помечает токен использованным и возвращает его владельца.
+//
+// Ошибки:
+//   - ErrNotFound — токена нет, он истёк, уже использован или другого назначения
+//   - ErrInternal — ошибка БД
+func (r *EmailTokensRepository) Consume(ctx context.Context, purpose string, tokenHash []byte) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.db.QueryRowContext(ctx,
+		`UPDATE email_tokens
+		    SET used_at = now()
+		  WHERE token_hash = $1
+		    AND purpose = $2
+		    AND used_at IS NULL
+		    AND expires_at > now()
+		 RETURNING user_id`,
+		tokenHash, purpose,
+	).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, serr.ErrNotFound
+		}
+		return uuid.Nil, serr.ErrInternal
+	}
+	return userID, nil
+}
+
+// MarkVerified отмечает email пользователя подтверждённым (повторный вызов ничего не меняет).
+//
+// Возвращает ErrInternal — при ошибке БД
+func (r *EmailTokensRepository) MarkVerified(ctx context.Context, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE users
+		    SET email_verified_at = now()
+		  WHERE id = $1 AND email_verified_at IS NULL`,
+		userID,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	return nil
+}
+
+// GetEmail возвращает email пользователя и признак его подтверждения.
+//
+// Ошибки:
+//   - ErrNotFound — пользователя нет
+//   - ErrInternal — ошибка БД
+func (r *EmailTokensRepository) GetEmail(ctx context.Context, userID uuid.UUID) (string, bool, error) {
+	var (
+		email    string
+		verified bool
+	)
+	err := r.db.QueryRowContext(ctx,
+		`SELECT email, email_verified_at IS NOT NULL FROM users WHERE id = $1`,
+		userID,
+	).Scan(&email, &verified)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", false, serr.ErrNotFound
+		}
+		return "", false, serr.ErrInternal
+	}
+	return email, verified, nil
+}
+
+// Prune удаляет истёкшие токены.
+//
+// Возвращает ErrInternal — при ошибке БД
+func (r *EmailTokensRepository) Prune(ctx context.Context) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM email_tokens WHERE expires_at < now()`); err != nil {
+		return serr.ErrInternal
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// InvitesRepository хранит коды приглашений для регистрации.
+//
+// Код хранится только в виде SHA-256.
+type InvitesRepository struct {
+	db *sql.DB
+}
+
+// NewInvitesRepository создаёт новый InvitesRepository.
+func NewInvitesRepository(db *sql.DB) *InvitesRepository {
+	return &InvitesRepository{db: db}
+}
+
+// Create сохраняет новый код приглашения.
+//
+// Возвращает сохранённое приглашение или ErrInternal — при ошибке БД
+func (r *InvitesRepository) Create(ctx context.Context, createdBy uuid.UUID, codeHash []byte, maxUses int, expiresAt time.Time) (models.Invite, error) {
+	inv := models.Invite{CreatedBy: createdBy, MaxUses: maxUses, ExpiresAt: expiresAt}
+
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO invites (code_hash, created_by, max_uses, expires_at)
+		 VALUES ($1, $2, $3, $4)
+		 RETURNING id, created_at`,
+		codeHash, createdBy, maxUses, expiresAt,
+	).Scan(&inv.ID, &inv.CreatedAt)
+	if err != nil {
+		return models.Invite{}, serr.ErrInternal
+	}
+
+	return inv, nil
+}
+
+// Check проверяет, что код существует, не истёк и не исчерпан, не расходуя его.
+//
+// Нужен, чтобы не хэшировать пароль для запроса с заведомо неверным кодом.
+//
+// Ошибки:
+//   - ErrInvalidInvite если кода нет, он истёк или исчерпан
+//   - ErrInternal при ошибке БД
+func (r *InvitesRepository) Check(ctx context.Context, codeHash []byte) error {
+	var ok bool
+
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (
+		     SELECT 1 FROM invites
+		      WHERE code_hash = $1
+		        AND uses < max_uses
+		        AND expires_at > now()
+		 )`,
+		codeHash,
+	).Scan(&ok)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if !ok {
+		return serr.ErrInvalidInvite
+	}
+	return nil
+}
+
+// CreateUser в одной транзакции расходует одно использование кода и создаёт пользователя.
+//
+// Строка приглашения блокируется UPDATE, поэтому параллельные регистрации
+// по одному коду не превысят max_uses. Если email уже занят,
+// транзакция откатывается и использование не списывается.
+//
+// Ошибки:
+//   - ErrInvalidInvite если кода нет, он истёк или исчерпан
+//   - ErrAlreadyExists если email уже зарегистрирован
+//   - ErrInternal при ошибке БД
+func (r *InvitesRepository) CreateUser(ctx context.Context, codeHash []byte, email, passwordHash string) (uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, serr.ErrInternal
+	}
+	defer tx.Rollback()
+
+	var inviteID uuid.UUID
+	err = tx.QueryRowContext(ctx,
+		`UPDATE invites
+		    SET uses = uses + 1
+		  WHERE code_hash = $1
+		    AND uses < max_uses
+		    AND expires_at > now()
+		 RETURNING id`,
+		codeHash,
+	).Scan(&inviteID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, serr.ErrInvalidInvite
+		}
+		return uuid.Nil, serr.ErrInternal
+	}
+
+	var userID uuid.UUID
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO users (email, password_hash)
+		 VALUES ($1, $2)
+		 RETURNING id`,
+		email, passwordHash,
+	).Scan(&userID)
+	if err != nil {
+		return uuid.Nil, uniqueViolation(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, serr.ErrInternal
+	}
+	return userID, nil
+}
+
+// ListActive возвращает неистёкшие и неисчерпанные приглашения, новые первыми.
+//
+// Возвращает ErrInternal — при ошибке БД
+func (r *InvitesRepository) ListActive(ctx context.Context) ([]models.Invite, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, created_by, max_uses, uses, expires_at, created_at
+		   FROM invites
+		  WHERE uses < max_uses
+		    AND expires_at > now()
+		  ORDER BY created_at DESC`,
+	)
+	if err != nil {
+		return nil, serr.ErrInternal
+	}
+	defer rows.Close()
+
+	var list []models.Invite
+	for rows.Next() {
+		var (
+			inv       models.Invite
+			createdBy uuid.NullUUID
+		)
+		if err := rows.Scan(&inv.ID, &createdBy, &inv.MaxUses, &inv.Uses, &inv.ExpiresAt, &inv.CreatedAt); err != nil {
+			return nil, serr.ErrInternal
+		}
+		inv.CreatedBy = createdBy.UUID
+		list = append(list, inv)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, serr.ErrInternal
+	}
+
+	return list, nil
+}
+
+// Delete отзывает приглашение.
+//
+// Ошибки:
+//   - ErrNotFound если приглашения нет
+//   - ErrInternal при ошибке БД
+func (r *InvitesRepository) Delete(ctx context.Context, inviteID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM invites WHERE id = $1`, inviteID)
+	if err != nil {
+		return serr.ErrInternal
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if affected == 0 {
+		return serr.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// LoginAttemptsRepository хранит счётчики неудачных попыток входа по email.
+type LoginAttemptsRepository struct {
+	db *sql.DB
+}
+
+// NewLoginAttemptsRepository создаёт новый LoginAttemptsRepository.
+//
+// db — инициализированное подключение к PostgreSQL.
+func NewLoginAttemptsRepository(db *sql.DB) *LoginAttemptsRepository {
+	return &LoginAttemptsRepository{db: db}
+}
+
+// Get возвращает число неудач подряд и время последней из них.
+//
+// Возвращает ErrNotFound — если неудач не было или ErrInternal — при ошибке БД
+func (r *LoginAttemptsRepository) Get(ctx context.Context, email string) (int, time.Time, error) {
+	var (
+		failures int
+		last     time.Time
+	)
+
+	err := r.db.QueryRowContext(ctx,
+		`SELECT failures, last_failure_at FROM login_attempts WHERE email=$1`,
+		email,
+	).Scan(&failures, &last)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, time.Time{}, serr.ErrNotFound
+		}
+		return 0, time.Time{}, serr.ErrInternal
+	}
+
+	return failures, last, nil
+}
+
+// RecordFailure атомарно увеличивает счётчик неудач.
+//
+// Если последняя неудача была раньше resetBefore, счётчик начинается заново.
+// Возвращает новое число неудач и время текущей неудачи или ErrInternal — при ошибке БД
+func (r *LoginAttemptsRepository) RecordFailure(ctx context.Context, email string, resetBefore time.Time) (int, time.Time, error) {
+	var (
+		failures int
+		last     time.Time
+	)
+
+	err := r.db.QueryRowContext(ctx,
+		`INSERT INTO login_attempts (email, failures, last_failure_at)
+		 VALUES ($1, 1, now())
+		 ON CONFLICT (email) DO UPDATE
+		    SET failures = CASE WHEN login_attempts.last_failure_at < $2 THEN 1
+		                        ELSE login_attempts.failures + 1 END,
+		        last_failure_at = now()
+		 RETURNING failures, last_failure_at`,
+		email, resetBefore,
+	).Scan(&failures, &last)
+	if err != nil {
+		return 0, time.Time{}, serr.ErrInternal
+	}
+
+	return failures, last, nil
+}
+
+// Reset сбрасывает счётчик после успешного входа.
+//
+// Возвращает ErrInternal — при ошибке БД
+func (r *LoginAttemptsRepository) Reset(ctx context.Context, email string) error {
+	if _, err := r.db.ExecContext(ctx,
+		`DELETE FROM login_attempts WHERE email=$1`,
+		email,
+	); err != nil {
+		return serr.ErrInternal
+	}
+	return nil
+}
+
+// Prune удаляет счётчики, последняя неудача которых была раньше before:
+// они уже не влияют на вход.
+//
+// Возвращает ErrInternal — при ошибке БД
+func (r *LoginAttemptsRepository) Prune(ctx context.Context, before time.Time) error {
+	if _, err := r.db.ExecContext(ctx,
+		`DELETE FROM login_attempts WHERE last_failure_at < $1`,
+		before,
+	); err != nil {
+		return serr.ErrInternal
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// OIDCIdentitiesRepository хранит привязки пользователей OpenID Connect провайдера к аккаунтам.
+type OIDCIdentitiesRepository struct {
+	db *sql.DB
+}
+
+// NewOIDCIdentitiesRepository создаёт новый OIDCIdentitiesRepository.
+func NewOIDCIdentitiesRepository(db *sql.DB) *OIDCIdentitiesRepository {
+	return &OIDCIdentitiesRepository{db: db}
+}
+
+// GetUser возвращает аккаунт, привязанный к (issuer, subject).
+//
+// Аккаунты, помеченные к удалению, не возвращаются: вход в них заблокирован.
+//
+// Ошибки:
+//   - ErrNotFound если привязки нет
+//   - ErrInternal при ошибке БД
+func (r *OIDCIdentitiesRepository) GetUser(ctx context.Context, issuer, subject string) (uuid.UUID, error) {
+	var userID uuid.UUID
+
+	err := r.db.QueryRowContext(ctx,
+		`SELECT u.id
+		   FROM oidc_identities i
+		   JOIN users u ON u.id = i.user_id
+		  WHERE i.issuer = $1
+		    AND i.subject = $2
+		    AND u.delete_after IS NULL`,
+		issuer, subject,
+	).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, serr.ErrNotFound
+		}
+		return uuid.Nil, serr.ErrInternal
+	}
+
+	return userID, nil
+}
+
+// Link привязывает пользователя провайдера к существующему аккаунту.
+//
+// Ошибки:
+//   - ErrAlreadyExists если (issuer, subject) уже привязан
+//   - ErrInternal при ошибке БД
+func (r *OIDCIdentitiesRepository) Link(ctx context.Context, issuer, subject, email string, userID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO oidc_identities (issuer, subject, user_id, email)
+		 VALUES ($1, $2, $3, $4)`,
+		issuer, subject, userID, email,
+	)
+	if err != nil {
+		return uniqueViolation(err)
+	}
+	return nil
+}
+
+// CreateUser в одной транзакции создаёт аккаунт и привязывает к нему пользователя провайдера.
+//
+// passwordHash — хэш случайного пароля: войти по паролю в такой аккаунт нельзя,
+// пока пользователь не задаст пароль сам.
+//
+// Ошибки:
+//   - ErrAlreadyExists если email уже занят или (issuer, subject) уже привязан
+//   - ErrInternal при ошибке БД
+func (r *OIDCIdentitiesRepository) CreateUser(ctx context.Context, issuer, subject, email, passwordHash string) (uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return uuid.Nil, serr.ErrInternal
+	}
+	defer tx.Rollback()
+
+	var userID uuid.UUID
+	err = tx.QueryRowContext(ctx,
+		`INSERT INTO users (email, password_hash)
+		 VALUES ($1, $2)
+		 RETURNING id`,
+		email, passwordHash,
+	).Scan(&userID)
+	if err != nil {
+		return uuid.Nil, uniqueViolation(err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO oidc_identities (issuer, subject, user_id, email)
+		 VALUES ($1, $2, $3, $4)`,
+		issuer, subject, userID, email,
+	); err != nil {
+		return uuid.Nil, uniqueViolation(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return uuid.Nil, serr.ErrInternal
+	}
+	return userID, nil
+}
+
+// uniqueViolation превращает нарушение уникальности в ErrAlreadyExists, остальное — в ErrInternal.
+func uniqueViolation(err error) error {
+	if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
+		return serr.ErrAlreadyExists
+	}
+	return serr.ErrInternal
+}
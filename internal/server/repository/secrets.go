@@ -246,3 +246,26 @@ func (r *SecretsRepository) DeleteSecret(ctx context.Context, userID uuid.UUID,
 
 	return serr.ErrConflict
 }
+
+// GetType возвращает тип секрета пользователя.
+//
+// Нужен для проверки API-токенов, ограниченных типами секретов.
+//
+// Ошибки:
+//   - ErrNotFound — секрет не найден
+//   - ErrInternal — ошибка базы данных
+func (r *SecretsRepository) GetType(ctx context.Context, userID uuid.UUID, secretID uuid.UUID) (service.SecretType, error) {
+	var typ string
+	err := r.db.QueryRowContext(ctx, `
+    	SELECT type FROM secrets
+    	WHERE user_id = $1 AND id = $2`, userID, secretID).Scan(&typ)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", serr.ErrNotFound
+		}
+		return "", serr.ErrInternal
+	}
+
+	return service.SecretType(typ), nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgconn"
 
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
 	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
 )
 
@@ -36,18 +37,27 @@ func NewSessionsRepository(db *sql.DB) *SessionsRepository {
 //   - userID
 //   - хэш refresh-токена
 //   - срок действия
+//   - сведения об устройстве (имя, user agent, IP)
+//   - семейство (familyID), к которому относится сессия
+//
+// familyID == uuid.Nil означает новый логин: сессия открывает
+// собственное семейство с family_id = id. При ротации передаётся
+// семейство старой сессии.
 //
 // Возвращает:
 //   - id созданной сессии
 //   - ErrConflict при нарушении уникальности или ErrInternal при других ошибках БД
-func (r *SessionsRepository) Create(ctx context.Context, userID uuid.UUID, refreshHash []byte, expiresAt time.Time) (uuid.UUID, error) {
-	var id uuid.UUID
-	err := r.db.QueryRowContext(ctx,
-		`INSERT INTO sessions (user_id, refresh_hash, expires_at)
-		 VALUES ($1,$2,$3)
-		 RETURNING id`,
-		userID, refreshHash, expiresAt,
-	).Scan(&id)
+func (r *SessionsRepository) Create(ctx context.Context, userID, familyID uuid.UUID, refreshHash []byte, expiresAt time.Time, client models.ClientInfo) (uuid.UUID, error) {
+	id := uuid.New()
+	if familyID == uuid.Nil {
+		familyID = id
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO sessions (id, user_id, family_id, refresh_hash, expires_at, device_name, user_agent, ip)
+		 VALUES ($1,$2,$3,$4,$5,$6,$7,$8)`,
+		id, userID, familyID, refreshHash, expiresAt, client.DeviceName, client.UserAgent, client.IP,
+	)
 
 	if err != nil {
 		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23505" {
@@ -68,13 +78,15 @@ func (r *SessionsRepository) Create(ctx context.Context, userID uuid.UUID, refre
 //   - expiresAt
 //   - revokedAt (nil если активна)
 //   - replacedBy (nil если не была заменена)
+//   - familyID (семейство ротации)
 //
 // Ошибки:
 //   - ErrUnauthorized если сессия не найдена или ErrInternal при ошибке БД
-func (r *SessionsRepository) GetByRefreshHash(ctx context.Context, refreshHash []byte) (uuid.UUID, uuid.UUID, time.Time, *time.Time, *uuid.UUID, error) {
+func (r *SessionsRepository) GetByRefreshHash(ctx context.Context, refreshHash []byte) (uuid.UUID, uuid.UUID, time.Time, *time.Time, *uuid.UUID, uuid.UUID, error) {
 	var (
 		sessID    uuid.UUID
 		userID    uuid.UUID
+		familyID  uuid.UUID
 		expiresAt time.Time
 
 		revokedAt sql.NullTime
@@ -82,17 +94,17 @@ func (r *SessionsRepository) GetByRefreshHash(ctx context.Context, refreshHash [
 	)
 
 	err := r.db.QueryRowContext(ctx,
-		`SELECT id, user_id, expires_at, revoked_at, replaced_by
+		`SELECT id, user_id, expires_at, revoked_at, replaced_by, family_id
 		   FROM sessions
 		  WHERE refresh_hash=$1`,
 		refreshHash,
-	).Scan(&sessID, &userID, &expiresAt, &revokedAt, &replaced)
+	).Scan(&sessID, &userID, &expiresAt, &revokedAt, &replaced, &familyID)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return uuid.Nil, uuid.Nil, time.Time{}, nil, nil, serr.ErrUnauthorized
+			return uuid.Nil, uuid.Nil, time.Time{}, nil, nil, uuid.Nil, serr.ErrUnauthorized
 		}
-		return uuid.Nil, uuid.Nil, time.Time{}, nil, nil, serr.ErrInternal
+		return uuid.Nil, uuid.Nil, time.Time{}, nil, nil, uuid.Nil, serr.ErrInternal
 	}
 
 	var revokedPtr *time.Time
@@ -108,15 +120,21 @@ func (r *SessionsRepository) GetByRefreshHash(ctx context.Context, refreshHash [
 		}
 	}
 
-	return sessID, userID, expiresAt, revokedPtr, replacedPtr, nil
+	return sessID, userID, expiresAt, revokedPtr, replacedPtr, familyID, nil
 }
 
 // RevokeAndReplace отзывает старую refresh-сессию
 // и помечает ее замененной новой.
 //
 // Используется для refresh token rotation.
+// Условие revoked_at IS NULL делает замену атомарной: если два запроса
+// одновременно пришли с одним refresh-токеном, заменить сессию сможет только один.
+//
+// Ошибки:
+//   - ErrConflict если сессия уже отозвана (проиграна гонка или повторное использование)
+//   - ErrInternal при ошибке БД
 func (r *SessionsRepository) RevokeAndReplace(ctx context.Context, oldID, newID uuid.UUID) error {
-	_, err := r.db.ExecContext(ctx,
+	res, err := r.db.ExecContext(ctx,
 		`UPDATE sessions
 		    SET revoked_at = now(),
 		        replaced_by = $2
@@ -127,6 +145,33 @@ func (r *SessionsRepository) RevokeAndReplace(ctx context.Context, oldID, newID
 	if err != nil {
 		return serr.ErrInternal
 	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if affected == 0 {
+		return serr.ErrConflict
+	}
+	return nil
+}
+
+// RevokeFamily отзывает все активные сессии семейства ротации.
+//
+// Используется при повторном использовании refresh-токена:
+// отзывается только скомпрометированная цепочка, а сессии пользователя
+// на других устройствах продолжают работать.
+func (r *SessionsRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions
+		    SET revoked_at = now()
+		  WHERE family_id = $1
+		    AND revoked_at IS NULL`,
+		familyID,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
 	return nil
 }
 
@@ -146,3 +191,190 @@ func (r *SessionsRepository) RevokeAllForUser(ctx context.Context, userID uuid.U
 	}
 	return nil
 }
+
+// Revoke отзывает одну активную refresh-сессию пользователя.
+//
+// Используется при logout с текущего устройства.
+//
+// Ошибки:
+//   - ErrNotFound если сессия не найдена, уже отозвана или принадлежит другому пользователю
+//   - ErrInternal при ошибке БД
+func (r *SessionsRepository) Revoke(ctx context.Context, userID, sessionID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE sessions
+		    SET revoked_at = now()
+		  WHERE id = $1
+		    AND user_id = $2
+		    AND revoked_at IS NULL`,
+		sessionID, userID,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if affected == 0 {
+		return serr.ErrNotFound
+	}
+	return nil
+}
+
+// CountActive возвращает количество активных (не отозванных и не истёкших)
+// refresh-сессий пользователя.
+func (r *SessionsRepository) CountActive(ctx context.Context, userID uuid.UUID) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT count(*)
+		   FROM sessions
+		  WHERE user_id = $1
+		    AND revoked_at IS NULL
+		    AND expires_at > now()`,
+		userID,
+	).Scan(&n)
+	if err != nil {
+		return 0, serr.ErrInternal
+	}
+	return n, nil
+}
+
+// KnownDevice сообщает, открывал ли пользователь раньше сессию
+// с этого устройства (совпадают имя устройства и User-Agent),
+// включая отозванные и истёкшие сессии.
+//
+// Возвращает ErrInternal — при ошибке БД
+func (r *SessionsRepository) KnownDevice(ctx context.Context, userID uuid.UUID, deviceName, userAgent string) (bool, error) {
+	var known bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (
+		     SELECT 1 FROM sessions
+		      WHERE user_id = $1 AND device_name = $2 AND user_agent = $3
+		 )`,
+		userID, deviceName, userAgent,
+	).Scan(&known)
+	if err != nil {
+		return false, serr.ErrInternal
+	}
+	return known, nil
+}
+
+// ListActive возвращает активные refresh-сессии пользователя,
+// отсортированные от новых к старым.
+func (r *SessionsRepository) ListActive(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, device_name, user_agent, ip, created_at, expires_at
+		   FROM sessions
+		  WHERE user_id = $1
+		    AND revoked_at IS NULL
+		    AND expires_at > now()
+		  ORDER BY created_at DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, serr.ErrInternal
+	}
+	defer rows.Close()
+
+	var result []models.Session
+	for rows.Next() {
+		var s models.Session
+		if err := rows.Scan(&s.ID, &s.DeviceName, &s.UserAgent, &s.IP, &s.CreatedAt, &s.ExpiresAt); err != nil {
+			return nil, serr.ErrInternal
+		}
+		result = append(result, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, serr.ErrInternal
+	}
+	return result, nil
+}
+
+// RevokeOldestActive отзывает самые старые активные сессии пользователя,
+// оставляя keep самых новых.
+//
+// Используется для соблюдения auth.sessions.max_sessions_per_user.
+// Запрос идемпотентен: при гонке двух логинов лишние сессии всё равно будут отозваны.
+func (r *SessionsRepository) RevokeOldestActive(ctx context.Context, userID uuid.UUID, keep int) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE sessions
+		    SET revoked_at = now()
+		  WHERE id IN (
+		        SELECT id
+		          FROM sessions
+		         WHERE user_id = $1
+		           AND revoked_at IS NULL
+		           AND expires_at > now()
+		         ORDER BY created_at DESC
+		        OFFSET $2
+		  )`,
+		userID, keep,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	return nil
+}
+
+// ListRevokedSince возвращает сессии, отозванные начиная с since (включительно),
+// в порядке отзыва.
+//
+// Сессии, отозванные ротацией (replaced_by задан), не возвращаются:
+// их access-токены остаются валидными до истечения — клиент может
+// продолжать запросы со старым access, пока получает новую пару.
+func (r *SessionsRepository) ListRevokedSince(ctx context.Context, since time.Time) ([]models.RevokedSession, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT id, revoked_at
+		   FROM sessions
+		  WHERE revoked_at >= $1
+		    AND replaced_by IS NULL
+		  ORDER BY revoked_at`,
+		since,
+	)
+	if err != nil {
+		return nil, serr.ErrInternal
+	}
+	defer rows.Close()
+
+	var result []models.RevokedSession
+	for rows.Next() {
+		var s models.RevokedSession
+		if err := rows.Scan(&s.ID, &s.RevokedAt); err != nil {
+			return nil, serr.ErrInternal
+		}
+		result = append(result, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, serr.ErrInternal
+	}
+	return result, nil
+}
+
+// PurgeExpired удаляет сессии, refresh-токен которых истёк раньше before.
+//
+// Сессия, на которую ссылается ещё живая сессия той же цепочки ротации
+// (replaced_by), остаётся до истечения последней.
+//
+// Возвращает число удалённых сессий или ErrInternal — при ошибке БД.
+func (r *SessionsRepository) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM sessions s
+		  WHERE s.expires_at < $1
+		    AND NOT EXISTS (
+		        SELECT 1 FROM sessions p
+		         WHERE p.replaced_by = s.id
+		           AND p.expires_at >= $1
+		    )`,
+		before,
+	)
+	if err != nil {
+		return 0, serr.ErrInternal
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, serr.ErrInternal
+	}
+	return n, nil
+}
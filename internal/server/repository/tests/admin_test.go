@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/repository"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+func TestAdminRepository_GetStatus(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAdminRepository(db)
+	userID, missingID := uuid.New(), uuid.New()
+
+	mock.ExpectQuery(`SELECT is_admin, disabled_at IS NOT NULL, delete_after IS NOT NULL FROM users WHERE id = \$1`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"is_admin", "disabled", "pending_deletion"}).AddRow(true, false, true))
+	mock.ExpectQuery(`SELECT is_admin`).
+		WithArgs(missingID).
+		WillReturnRows(sqlmock.NewRows([]string{"is_admin", "disabled", "pending_deletion"}))
+
+	st, err := repo.GetStatus(context.Background(), userID)
+	if err != nil || !st.IsAdmin || st.Disabled || !st.PendingDeletion {
+		t.Fatalf("unexpected result: %+v, %v", st, err)
+	}
+	if _, err := repo.GetStatus(context.Background(), missingID); !errors.Is(err, serr.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestAdminRepository_ListUsers(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAdminRepository(db)
+	now := time.Now().UTC()
+	activeID, disabledID := uuid.New(), uuid.New()
+
+	mock.ExpectQuery(`SELECT u.id, u.email, u.is_admin, u.disabled_at, u.created_at,.*FROM users u\s+ORDER BY u.created_at, u.id\s+LIMIT \$1 OFFSET \$2`).
+		WithArgs(2, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "is_admin", "disabled_at", "created_at", "secrets", "last_login_at"}).
+			AddRow(activeID, "a@example.com", true, nil, now, 3, now).
+			AddRow(disabledID, "b@example.com", false, now, now, 0, nil))
+
+	users, err := repo.ListUsers(context.Background(), 2, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(users))
+	}
+	if users[0].DisabledAt != nil || users[0].LastLoginAt == nil || users[0].SecretCount != 3 || !users[0].IsAdmin {
+		t.Fatalf("unexpected first user: %+v", users[0])
+	}
+	if users[1].DisabledAt == nil || users[1].LastLoginAt != nil {
+		t.Fatalf("unexpected second user: %+v", users[1])
+	}
+}
+
+func TestAdminRepository_SetDisabled(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAdminRepository(db)
+	userID, missingID := uuid.New(), uuid.New()
+
+	mock.ExpectExec(`UPDATE users\s+SET disabled_at = CASE WHEN \$2 THEN COALESCE\(disabled_at, now\(\)\) ELSE NULL END`).
+		WithArgs(userID, true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE users`).
+		WithArgs(missingID, false).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := repo.SetDisabled(context.Background(), userID, true); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if err := repo.SetDisabled(context.Background(), missingID, false); !errors.Is(err, serr.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestAdminRepository_RecordLogin(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAdminRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectExec(`UPDATE users SET last_login_at = now\(\) WHERE id = \$1`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.RecordLogin(context.Background(), userID); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+}
+
+func TestAdminRepository_StorageStats(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAdminRepository(db)
+
+	mock.ExpectQuery(`SELECT \(SELECT COUNT\(\*\) FROM users\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"users", "disabled", "secrets", "bytes", "sessions"}).
+			AddRow(10, 1, 42, 4096, 7))
+
+	st, err := repo.StorageStats(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st.Users != 10 || st.DisabledUsers != 1 || st.Secrets != 42 || st.SecretBytes != 4096 || st.ActiveSessions != 7 {
+		t.Fatalf("unexpected stats: %+v", st)
+	}
+}
+
+func TestAdminRepository_StorageStats_DBError(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAdminRepository(db)
+
+	mock.ExpectQuery(`SELECT`).WillReturnError(errors.New("db down"))
+
+	if _, err := repo.StorageStats(context.Background()); !errors.Is(err, serr.ErrInternal) {
+		t.Fatalf("expected ErrInternal, got %v", err)
+	}
+}
+
+func TestAdminRepository_SetAdmin(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAdminRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectExec(`UPDATE users SET is_admin = \$2 WHERE id = \$1`).
+		WithArgs(userID, true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE users SET is_admin`).
+		WithArgs(userID, false).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := repo.SetAdmin(context.Background(), userID, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := repo.SetAdmin(context.Background(), userID, false); !errors.Is(err, serr.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
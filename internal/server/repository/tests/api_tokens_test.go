@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/repository"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// scopes и типы хранятся строкой через запятую
+func TestAPITokensRepository_Create_OK(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAPITokensRepository(db)
+	userID := uuid.New()
+	tokenID := uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+	createdAt := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO api_tokens`).
+		WithArgs(userID, "ci", []byte("h"), "secrets:read,secrets:write", "text", expiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(tokenID, createdAt))
+
+	id, created, err := repo.Create(context.Background(), userID, "ci", []byte("h"),
+		[]string{"secrets:read", "secrets:write"}, []string{"text"}, expiresAt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id != tokenID || !created.Equal(createdAt) {
+		t.Fatalf("unexpected result: %v %v", id, created)
+	}
+}
+
+// действующий токен отмечается использованным и возвращается
+func TestAPITokensRepository_Use_OK(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAPITokensRepository(db)
+	tokenID := uuid.New()
+	userID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT t.id, t.user_id, .* FROM api_tokens t`).
+		WithArgs([]byte("h")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "scopes", "types", "created_at", "expires_at", "last_used_at"}).
+			AddRow(tokenID, userID, "ci", "secrets:read", "", now, now.Add(time.Hour), nil))
+	mock.ExpectExec(`UPDATE api_tokens\s+SET last_used_at = \$2`).
+		WithArgs(tokenID, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	got, err := repo.Use(context.Background(), []byte("h"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ID != tokenID || got.UserID != userID || len(got.Scopes) != 1 || got.Types != nil || got.LastUsedAt == nil {
+		t.Fatalf("unexpected token: %+v", got)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// недавно использованный токен не перезаписывается на каждый запрос
+func TestAPITokensRepository_Use_ThrottlesLastUsed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAPITokensRepository(db)
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT t.id, t.user_id, .* FROM api_tokens t`).
+		WithArgs([]byte("h")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "scopes", "types", "created_at", "expires_at", "last_used_at"}).
+			AddRow(uuid.New(), uuid.New(), "ci", "secrets:read", "", now, now.Add(time.Hour), now.Add(-10*time.Second)))
+
+	got, err := repo.Use(context.Background(), []byte("h"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.LastUsedAt == nil {
+		t.Fatalf("expected last_used_at, got %+v", got)
+	}
+	// UPDATE не ожидался
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// отозванный, истёкший или токен удаляемого аккаунта
+func TestAPITokensRepository_Use_Invalid(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAPITokensRepository(db)
+
+	mock.ExpectQuery(`SELECT .* FROM api_tokens t`).
+		WillReturnError(sql.ErrNoRows)
+
+	if _, err := repo.Use(context.Background(), []byte("h")); err != serr.ErrUnauthorized {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestAPITokensRepository_ListByUser(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAPITokensRepository(db)
+	userID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT id, user_id, name, scopes, types`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "scopes", "types", "created_at", "expires_at", "last_used_at"}).
+			AddRow(uuid.New(), userID, "ci", "secrets:read,secrets:write", "binary,text", now, now.Add(time.Hour), nil))
+
+	list, err := repo.ListByUser(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 1 || len(list[0].Scopes) != 2 || len(list[0].Types) != 2 || list[0].LastUsedAt != nil {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+}
+
+// чужой или уже отозванный токен
+func TestAPITokensRepository_Revoke_NotFound(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAPITokensRepository(db)
+
+	mock.ExpectExec(`UPDATE api_tokens\s+SET revoked_at = now\(\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := repo.Revoke(context.Background(), uuid.New(), uuid.New()); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// отзыв всех токенов пользователя после смены пароля
+func TestAPITokensRepository_RevokeAllForUser(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAPITokensRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectExec(`UPDATE api_tokens\s+SET revoked_at = now\(\)\s+WHERE user_id = \$1`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	if err := repo.RevokeAllForUser(context.Background(), userID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
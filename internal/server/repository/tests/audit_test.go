@@ -0,0 +1,98 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/repository"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+func TestAuditRepository_Append(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAuditRepository(db)
+	userID, secretID := uuid.New(), uuid.New()
+
+	mock.ExpectExec(`INSERT INTO audit_events`).
+		WithArgs(userID, models.AuditSecretDeleted, "10.0.0.1", "cli", uuid.NullUUID{UUID: secretID, Valid: true}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	// событие без секрета пишет NULL в secret_id
+	mock.ExpectExec(`INSERT INTO audit_events`).
+		WithArgs(userID, models.AuditLogin, "", "", uuid.NullUUID{}).
+		WillReturnResult(sqlmock.NewResult(2, 1))
+
+	err := repo.Append(context.Background(), models.AuditEvent{
+		UserID: userID, Type: models.AuditSecretDeleted, IP: "10.0.0.1", UserAgent: "cli", SecretID: secretID,
+	})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if err := repo.Append(context.Background(), models.AuditEvent{UserID: userID, Type: models.AuditLogin}); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuditRepository_List(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAuditRepository(db)
+	userID, secretID := uuid.New(), uuid.New()
+	since := time.Now().Add(-time.Hour)
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT id, user_id, event, ip, user_agent, secret_id, created_at\s+FROM audit_events`).
+		WithArgs(userID, sql.NullTime{Time: since, Valid: true}, "", int64(10), 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "event", "ip", "user_agent", "secret_id", "created_at"}).
+			AddRow(int64(9), userID, models.AuditSecretUpdated, "10.0.0.1", "cli", secretID, now).
+			AddRow(int64(8), userID, models.AuditLogin, "10.0.0.1", "cli", nil, now))
+
+	list, err := repo.List(context.Background(), userID, models.AuditFilter{Since: since, Before: 10, Limit: 3})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if len(list) != 2 || list[0].ID != 9 || list[0].SecretID != secretID || list[1].SecretID != uuid.Nil {
+		t.Fatalf("unexpected list: %+v", list)
+	}
+}
+
+func TestAuditRepository_List_DBError(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAuditRepository(db)
+	mock.ExpectQuery(`FROM audit_events`).WillReturnError(errors.New("boom"))
+
+	if _, err := repo.List(context.Background(), uuid.New(), models.AuditFilter{Limit: 10}); !errors.Is(err, serr.ErrInternal) {
+		t.Fatalf("expected ErrInternal, got %v", err)
+	}
+}
+
+func TestAuditRepository_Prune(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewAuditRepository(db)
+	before := time.Now().Add(-90 * 24 * time.Hour)
+
+	mock.ExpectExec(`DELETE FROM audit_events WHERE created_at < \$1`).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 7))
+
+	n, err := repo.Prune(context.Background(), before)
+	if err != nil || n != 7 {
+		t.Fatalf("unexpected result: %d, %v", n, err)
+	}
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/repository"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// сертификат сохраняется, ID и время создания берутся из БД
+func TestClientCertsRepository_Create_OK(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewClientCertsRepository(db)
+	cert := models.ClientCert{
+		UserID:      uuid.New(),
+		Name:        "laptop",
+		Subject:     "CN=laptop",
+		Fingerprint: []byte("fp"),
+		NotAfter:    time.Now().Add(time.Hour),
+	}
+	id := uuid.New()
+	createdAt := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO client_certificates`).
+		WithArgs(cert.UserID, "laptop", "CN=laptop", []byte("fp"), cert.NotAfter).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(id, createdAt))
+
+	got, err := repo.Create(context.Background(), cert)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ID != id || !got.CreatedAt.Equal(createdAt) || got.Name != "laptop" {
+		t.Fatalf("unexpected result: %+v", got)
+	}
+}
+
+// один сертификат нельзя привязать дважды
+func TestClientCertsRepository_Create_Duplicate(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewClientCertsRepository(db)
+
+	mock.ExpectQuery(`INSERT INTO client_certificates`).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+
+	_, err := repo.Create(context.Background(), models.ClientCert{UserID: uuid.New()})
+	if err != serr.ErrAlreadyExists {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+}
+
+func TestClientCertsRepository_ListByUser(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewClientCertsRepository(db)
+	userID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT id, user_id, name, subject, fingerprint, not_after, created_at`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "subject", "fingerprint", "not_after", "created_at"}).
+			AddRow(uuid.New(), userID, "laptop", "CN=laptop", []byte("fp"), now.Add(time.Hour), now))
+
+	list, err := repo.ListByUser(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 1 || list[0].Name != "laptop" || string(list[0].Fingerprint) != "fp" {
+		t.Fatalf("unexpected result: %+v", list)
+	}
+}
+
+// привязка и совпадение отпечатка — одним запросом
+func TestClientCertsRepository_Match(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewClientCertsRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectQuery(`SELECT count\(\*\) > 0`).
+		WithArgs(userID, []byte("fp")).
+		WillReturnRows(sqlmock.NewRows([]string{"bound", "matched"}).AddRow(true, false))
+
+	bound, matched, err := repo.Match(context.Background(), userID, []byte("fp"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bound || matched {
+		t.Fatalf("unexpected result: bound=%v matched=%v", bound, matched)
+	}
+
+	mock.ExpectQuery(`SELECT count\(\*\) > 0`).
+		WillReturnError(errors.New("db down"))
+
+	if _, _, err := repo.Match(context.Background(), userID, nil); err != serr.ErrInternal {
+		t.Fatalf("expected ErrInternal, got %v", err)
+	}
+}
+
+// чужой или несуществующий сертификат
+func TestClientCertsRepository_Delete_NotFound(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewClientCertsRepository(db)
+
+	mock.ExpectExec(`DELETE FROM client_certificates`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := repo.Delete(context.Background(), uuid.New(), uuid.New()); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
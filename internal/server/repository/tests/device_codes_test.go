@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/repository"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// интервал хранится в секундах
+func TestDeviceCodesRepository_Create_OK(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewDeviceCodesRepository(db)
+	expiresAt := time.Now().Add(10 * time.Minute)
+
+	mock.ExpectExec(`INSERT INTO device_codes`).
+		WithArgs([]byte("h"), "BCDF-GHJK", 5, expiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.Create(context.Background(), []byte("h"), "BCDF-GHJK", 5*time.Second, expiresAt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// совпадение user_code с уже выданным
+func TestDeviceCodesRepository_Create_Duplicate(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewDeviceCodesRepository(db)
+
+	mock.ExpectExec(`INSERT INTO device_codes`).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+
+	err := repo.Create(context.Background(), []byte("h"), "BCDF-GHJK", 5*time.Second, time.Now())
+	if err != serr.ErrAlreadyExists {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+}
+
+// решение принимается один раз
+func TestDeviceCodesRepository_Decide(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewDeviceCodesRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectExec(`UPDATE device_codes\s+SET user_id = \$2`).
+		WithArgs("BCDF-GHJK", userID, true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.Decide(context.Background(), "BCDF-GHJK", userID, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// истёк, уже подтверждён или отклонён
+	mock.ExpectExec(`UPDATE device_codes`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := repo.Decide(context.Background(), "BCDF-GHJK", userID, false); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// опрос возвращает состояние и время предыдущего опроса
+func TestDeviceCodesRepository_Poll(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewDeviceCodesRepository(db)
+	id := uuid.New()
+	userID := uuid.New()
+	expiresAt := time.Now().Add(time.Minute)
+	prev := time.Now().Add(-10 * time.Second)
+
+	mock.ExpectQuery(`WITH prev AS`).
+		WithArgs([]byte("h")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "interval_seconds", "expires_at", "approved", "denied", "used", "last_polled_at"}).
+			AddRow(id, userID, 5, expiresAt, true, false, false, prev))
+
+	d, err := repo.Poll(context.Background(), []byte("h"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.ID != id || d.UserID != userID || d.Interval != 5*time.Second || !d.Approved || d.Denied || d.Used {
+		t.Fatalf("unexpected device code: %+v", d)
+	}
+	if d.LastPolledAt == nil || !d.LastPolledAt.Equal(prev) {
+		t.Fatalf("unexpected last polled: %v", d.LastPolledAt)
+	}
+
+	// первый опрос ещё не подтверждённого кода
+	mock.ExpectQuery(`WITH prev AS`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "interval_seconds", "expires_at", "approved", "denied", "used", "last_polled_at"}).
+			AddRow(id, nil, 5, expiresAt, false, false, false, nil))
+
+	d, err = repo.Poll(context.Background(), []byte("h"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.UserID != uuid.Nil || d.LastPolledAt != nil {
+		t.Fatalf("unexpected device code: %+v", d)
+	}
+
+	mock.ExpectQuery(`WITH prev AS`).
+		WillReturnError(sql.ErrNoRows)
+
+	if _, err := repo.Poll(context.Background(), []byte("h")); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// токены по коду выдаются один раз
+func TestDeviceCodesRepository_Consume_AlreadyUsed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewDeviceCodesRepository(db)
+
+	mock.ExpectExec(`UPDATE device_codes\s+SET used_at = now\(\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := repo.Consume(context.Background(), uuid.New()); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/repository"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// Новый токен отменяет прежние того же назначения
+func TestEmailTokensRepository_Create_OK(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewEmailTokensRepository(db)
+	userID := uuid.New()
+	hash := []byte("hash")
+	expiresAt := time.Now().Add(30 * time.Minute)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT id FROM users WHERE id = \$1 FOR UPDATE`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userID))
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs(userID, models.EmailTokenReset, sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+	mock.ExpectExec(`UPDATE email_tokens\s+SET used_at = now\(\)`).
+		WithArgs(userID, models.EmailTokenReset).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO email_tokens`).
+		WithArgs(userID, models.EmailTokenReset, hash, expiresAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := repo.Create(context.Background(), userID, models.EmailTokenReset, hash, expiresAt, time.Minute); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Письмо того же назначения отправлялось недавно — ErrConflict, прежний токен действует
+func TestEmailTokensRepository_Create_TooSoon(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewEmailTokensRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userID))
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectRollback()
+
+	err := repo.Create(context.Background(), userID, models.EmailTokenVerify, []byte("hash"), time.Now().Add(time.Hour), time.Minute)
+	if !errors.Is(err, serr.ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEmailTokensRepository_Consume(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewEmailTokensRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectQuery(`UPDATE email_tokens\s+SET used_at = now\(\)\s+WHERE token_hash = \$1`).
+		WithArgs([]byte("good"), models.EmailTokenVerify).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
+	mock.ExpectQuery(`UPDATE email_tokens`).
+		WithArgs([]byte("used"), models.EmailTokenVerify).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+	got, err := repo.Consume(context.Background(), models.EmailTokenVerify, []byte("good"))
+	if err != nil || got != userID {
+		t.Fatalf("unexpected result: %v, %v", got, err)
+	}
+	if _, err := repo.Consume(context.Background(), models.EmailTokenVerify, []byte("used")); !errors.Is(err, serr.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestEmailTokensRepository_GetEmail(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewEmailTokensRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectQuery(`SELECT email, email_verified_at IS NOT NULL FROM users`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"email", "verified"}).AddRow("user@example.com", true))
+
+	email, verified, err := repo.GetEmail(context.Background(), userID)
+	if err != nil || email != "user@example.com" || !verified {
+		t.Fatalf("unexpected result: %q, %v, %v", email, verified, err)
+	}
+}
+
+func TestEmailTokensRepository_MarkVerified(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewEmailTokensRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectExec(`UPDATE users\s+SET email_verified_at = now\(\)`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.MarkVerified(context.Background(), userID); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/repository"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+func TestInvitesRepository_Create(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewInvitesRepository(db)
+	adminID := uuid.New()
+	inviteID := uuid.New()
+	expiresAt := time.Now().Add(time.Hour)
+
+	mock.ExpectQuery(`INSERT INTO invites`).
+		WithArgs([]byte("hash"), adminID, 3, expiresAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(inviteID, time.Now()))
+
+	inv, err := repo.Create(context.Background(), adminID, []byte("hash"), 3, expiresAt)
+	if err != nil || inv.ID != inviteID || inv.MaxUses != 3 {
+		t.Fatalf("unexpected result: %+v, %v", inv, err)
+	}
+}
+
+func TestInvitesRepository_Check(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewInvitesRepository(db)
+
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs([]byte("good")).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`SELECT EXISTS`).
+		WithArgs([]byte("bad")).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+	if err := repo.Check(context.Background(), []byte("good")); err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if err := repo.Check(context.Background(), []byte("bad")); err != serr.ErrInvalidInvite {
+		t.Fatalf("expected ErrInvalidInvite, got %v", err)
+	}
+}
+
+// использование списывается и пользователь создаётся в одной транзакции
+func TestInvitesRepository_CreateUser_OK(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewInvitesRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE invites\s+SET uses = uses \+ 1`).
+		WithArgs([]byte("hash")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectQuery(`INSERT INTO users`).
+		WithArgs("a@b.c", "pwhash").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userID))
+	mock.ExpectCommit()
+
+	got, err := repo.CreateUser(context.Background(), []byte("hash"), "a@b.c", "pwhash")
+	if err != nil || got != userID {
+		t.Fatalf("unexpected result: %v, %v", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// код истёк или исчерпан — пользователь не создаётся
+func TestInvitesRepository_CreateUser_InvalidInvite(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewInvitesRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE invites`).
+		WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, err := repo.CreateUser(context.Background(), []byte("hash"), "a@b.c", "pwhash")
+	if err != serr.ErrInvalidInvite {
+		t.Fatalf("expected ErrInvalidInvite, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// email занят — транзакция откатывается, использование не списывается
+func TestInvitesRepository_CreateUser_EmailTaken(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewInvitesRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE invites`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectQuery(`INSERT INTO users`).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err := repo.CreateUser(context.Background(), []byte("hash"), "a@b.c", "pwhash")
+	if err != serr.ErrAlreadyExists {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+func TestInvitesRepository_ListActive(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewInvitesRepository(db)
+	adminID := uuid.New()
+
+	mock.ExpectQuery(`SELECT id, created_by, max_uses, uses, expires_at, created_at`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_by", "max_uses", "uses", "expires_at", "created_at"}).
+			AddRow(uuid.New(), adminID, 5, 2, time.Now().Add(time.Hour), time.Now()).
+			AddRow(uuid.New(), nil, 1, 0, time.Now().Add(time.Hour), time.Now()))
+
+	list, err := repo.ListActive(context.Background())
+	if err != nil || len(list) != 2 {
+		t.Fatalf("unexpected result: %+v, %v", list, err)
+	}
+	if list[0].CreatedBy != adminID || list[1].CreatedBy != uuid.Nil {
+		t.Fatalf("unexpected created_by: %v, %v", list[0].CreatedBy, list[1].CreatedBy)
+	}
+}
+
+func TestInvitesRepository_Delete_NotFound(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewInvitesRepository(db)
+
+	mock.ExpectExec(`DELETE FROM invites`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := repo.Delete(context.Background(), uuid.New()); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/repository"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// счётчик и время последней неудачи
+func TestLoginAttemptsRepository_Get(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewLoginAttemptsRepository(db)
+	last := time.Now()
+
+	mock.ExpectQuery(`SELECT failures, last_failure_at FROM login_attempts`).
+		WithArgs("user@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"failures", "last_failure_at"}).AddRow(3, last))
+
+	failures, got, err := repo.Get(context.Background(), "user@example.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if failures != 3 || !got.Equal(last) {
+		t.Fatalf("unexpected result: %d %v", failures, got)
+	}
+
+	mock.ExpectQuery(`SELECT failures, last_failure_at FROM login_attempts`).
+		WillReturnError(sql.ErrNoRows)
+
+	if _, _, err := repo.Get(context.Background(), "user@example.com"); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// неудача учитывается одним upsert-запросом
+func TestLoginAttemptsRepository_RecordFailure(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewLoginAttemptsRepository(db)
+	resetBefore := time.Now().Add(-15 * time.Minute)
+	last := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO login_attempts .* ON CONFLICT \(email\) DO UPDATE`).
+		WithArgs("user@example.com", resetBefore).
+		WillReturnRows(sqlmock.NewRows([]string{"failures", "last_failure_at"}).AddRow(2, last))
+
+	failures, got, err := repo.RecordFailure(context.Background(), "user@example.com", resetBefore)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if failures != 2 || !got.Equal(last) {
+		t.Fatalf("unexpected result: %d %v", failures, got)
+	}
+
+	mock.ExpectQuery(`INSERT INTO login_attempts`).
+		WillReturnError(sql.ErrConnDone)
+
+	if _, _, err := repo.RecordFailure(context.Background(), "user@example.com", resetBefore); err != serr.ErrInternal {
+		t.Fatalf("expected ErrInternal, got %v", err)
+	}
+}
+
+// сброс после успешного входа
+func TestLoginAttemptsRepository_Reset(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewLoginAttemptsRepository(db)
+
+	mock.ExpectExec(`DELETE FROM login_attempts WHERE email=\$1`).
+		WithArgs("user@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.Reset(context.Background(), "user@example.com"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// устаревшие счётчики удаляются
+func TestLoginAttemptsRepository_Prune(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewLoginAttemptsRepository(db)
+	before := time.Now().Add(-15 * time.Minute)
+
+	mock.ExpectExec(`DELETE FROM login_attempts WHERE last_failure_at < \$1`).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	if err := repo.Prune(context.Background(), before); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/repository"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+func TestOIDCIdentitiesRepository_GetUser(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewOIDCIdentitiesRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectQuery(`SELECT u.id\s+FROM oidc_identities`).
+		WithArgs("https://idp", "sub-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userID))
+	mock.ExpectQuery(`SELECT u.id\s+FROM oidc_identities`).
+		WithArgs("https://idp", "sub-2").
+		WillReturnError(sql.ErrNoRows)
+
+	got, err := repo.GetUser(context.Background(), "https://idp", "sub-1")
+	if err != nil || got != userID {
+		t.Fatalf("unexpected result: %v, %v", got, err)
+	}
+	if _, err := repo.GetUser(context.Background(), "https://idp", "sub-2"); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestOIDCIdentitiesRepository_Link_Duplicate(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewOIDCIdentitiesRepository(db)
+
+	mock.ExpectExec(`INSERT INTO oidc_identities`).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+
+	err := repo.Link(context.Background(), "https://idp", "sub-1", "a@b.c", uuid.New())
+	if err != serr.ErrAlreadyExists {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+}
+
+// аккаунт и привязка создаются в одной транзакции
+func TestOIDCIdentitiesRepository_CreateUser_OK(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewOIDCIdentitiesRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO users`).
+		WithArgs("a@b.c", "hash").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(userID))
+	mock.ExpectExec(`INSERT INTO oidc_identities`).
+		WithArgs("https://idp", "sub-1", userID, "a@b.c").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	got, err := repo.CreateUser(context.Background(), "https://idp", "sub-1", "a@b.c", "hash")
+	if err != nil || got != userID {
+		t.Fatalf("unexpected result: %v, %v", got, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// email уже занят — транзакция откатывается
+func TestOIDCIdentitiesRepository_CreateUser_EmailTaken(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewOIDCIdentitiesRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO users`).
+		WillReturnError(&pgconn.PgError{Code: "23505"})
+	mock.ExpectRollback()
+
+	_, err := repo.CreateUser(context.Background(), "https://idp", "sub-1", "a@b.c", "hash")
+	if err != serr.ErrAlreadyExists {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
@@ -114,3 +114,35 @@ func TestSecretsRepository_ListSecrets_DBError(t *testing.T) {
 type assertErr struct{}
 
 func (assertErr) Error() string { return "db error" }
+
+// тип секрета для проверки API-токенов
+func TestSecretsRepository_GetType(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer db.Close()
+
+	repo := repository.NewSecretsRepository(db)
+	userID := uuid.New()
+	secretID := uuid.New()
+
+	mock.ExpectQuery(`SELECT type FROM secrets`).
+		WithArgs(userID, secretID).
+		WillReturnRows(sqlmock.NewRows([]string{"type"}).AddRow("text"))
+
+	typ, err := repo.GetType(context.Background(), userID, secretID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if typ != "text" {
+		t.Fatalf("expected text, got %q", typ)
+	}
+
+	mock.ExpectQuery(`SELECT type FROM secrets`).
+		WillReturnRows(sqlmock.NewRows([]string{"type"}))
+
+	if _, err := repo.GetType(context.Background(), userID, secretID); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/repository"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// Подсчёт активных сессий
+func TestSessionsRepository_CountActive_OK(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewSessionsRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectQuery(`SELECT count\(\*\)\s+FROM sessions`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	n, err := repo.CountActive(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3, got %d", n)
+	}
+}
+
+// Список активных сессий
+func TestSessionsRepository_ListActive_OK(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewSessionsRepository(db)
+	userID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT id, device_name, user_agent, ip, created_at, expires_at`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "device_name", "user_agent", "ip", "created_at", "expires_at"}).
+			AddRow(uuid.New(), "laptop", "gophkeeper-cli", "10.0.0.1", now, now.Add(time.Hour)).
+			AddRow(uuid.New(), "desktop", "gophkeeper-cli", "10.0.0.2", now.Add(-time.Hour), now.Add(time.Hour)))
+
+	list, err := repo.ListActive(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(list))
+	}
+	if list[0].DeviceName != "laptop" || list[0].IP != "10.0.0.1" {
+		t.Fatalf("unexpected device info: %+v", list[0])
+	}
+}
+
+// Отзыв старых сессий
+func TestSessionsRepository_RevokeOldestActive_OK(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewSessionsRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectExec(`UPDATE sessions\s+SET revoked_at = now\(\)\s+WHERE id IN`).
+		WithArgs(userID, 5).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	if err := repo.RevokeOldestActive(context.Background(), userID, 5); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// Ошибка БД
+func TestSessionsRepository_RevokeOldestActive_DBError(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewSessionsRepository(db)
+
+	mock.ExpectExec(`UPDATE sessions`).
+		WillReturnError(errors.New("db down"))
+
+	if err := repo.RevokeOldestActive(context.Background(), uuid.New(), 5); err != serr.ErrInternal {
+		t.Fatalf("expected ErrInternal, got %v", err)
+	}
+}
+
+// Выборка отозванных сессий для кэша отзыва
+func TestSessionsRepository_ListRevokedSince(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewSessionsRepository(db)
+
+	since := time.Now().Add(-time.Hour)
+	id := uuid.New()
+	at := time.Now()
+
+	mock.ExpectQuery(`SELECT id, revoked_at\s+FROM sessions\s+WHERE revoked_at >= \$1\s+AND replaced_by IS NULL`).
+		WithArgs(since).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "revoked_at"}).AddRow(id, at))
+
+	list, err := repo.ListRevokedSince(context.Background(), since)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list) != 1 || list[0].ID != id || !list[0].RevokedAt.Equal(at) {
+		t.Fatalf("unexpected result: %+v", list)
+	}
+}
+
+// Устройство считается известным по любой прежней сессии, в том числе отозванной
+func TestSessionsRepository_KnownDevice(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewSessionsRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectQuery(`SELECT EXISTS \(\s+SELECT 1 FROM sessions`).
+		WithArgs(userID, "laptop", "gophkeeper-cli").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	known, err := repo.KnownDevice(context.Background(), userID, "laptop", "gophkeeper-cli")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !known {
+		t.Fatal("expected known device")
+	}
+}
+
+// живая сессия цепочки ротации удерживает истёкшую, на которую ссылается
+func TestSessionsRepository_PurgeExpired(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewSessionsRepository(db)
+	before := time.Now()
+
+	mock.ExpectExec(`DELETE FROM sessions s\s+WHERE s.expires_at < \$1\s+AND NOT EXISTS \(\s+SELECT 1 FROM sessions p\s+WHERE p.replaced_by = s.id\s+AND p.expires_at >= \$1`).
+		WithArgs(before).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(`DELETE FROM sessions`).
+		WillReturnError(errors.New("db down"))
+
+	n, err := repo.PurgeExpired(context.Background(), before)
+	if err != nil || n != 4 {
+		t.Fatalf("unexpected result: %d, %v", n, err)
+	}
+	if _, err := repo.PurgeExpired(context.Background(), before); !errors.Is(err, serr.ErrInternal) {
+		t.Fatalf("expected ErrInternal, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
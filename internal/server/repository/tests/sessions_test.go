@@ -3,18 +3,20 @@ package tests
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/repository"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
 	"github.com/google/uuid"
 	"github.com/jackc/pgconn"
 
 	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
 )
 
-// Успех
+// Успех: новый логин открывает собственное семейство (family_id = id)
 func TestSessionsRepository_Create_OK(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer db.Close()
@@ -22,24 +24,67 @@ func TestSessionsRepository_Create_OK(t *testing.T) {
 	repo := repository.NewSessionsRepository(db)
 
 	userID := uuid.New()
-	sessID := uuid.New()
 	hash := []byte("hash")
 	exp := time.Now().Add(time.Hour)
 
-	mock.ExpectQuery(`INSERT INTO sessions`).
-		WithArgs(userID, hash, exp).
-		WillReturnRows(
-			sqlmock.NewRows([]string{"id"}).AddRow(sessID),
-		)
+	var insertedID, insertedFamily uuid.UUID
+	mock.ExpectExec(`INSERT INTO sessions`).
+		WithArgs(captureUUID(&insertedID), userID, captureUUID(&insertedFamily), hash, exp, "laptop", "gophkeeper-cli", "10.0.0.1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	id, err := repo.Create(context.Background(), userID, hash, exp)
+	client := models.ClientInfo{DeviceName: "laptop", UserAgent: "gophkeeper-cli", IP: "10.0.0.1"}
+	id, err := repo.Create(context.Background(), userID, uuid.Nil, hash, exp, client)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if id != sessID {
-		t.Fatalf("expected %v, got %v", sessID, id)
+	if id == uuid.Nil || id != insertedID {
+		t.Fatalf("expected inserted id %v, got %v", insertedID, id)
+	}
+	if insertedFamily != id {
+		t.Fatalf("expected family_id = id for new login, got %v", insertedFamily)
+	}
+}
+
+// Ротация: новая сессия остаётся в семействе старой
+func TestSessionsRepository_Create_KeepsFamily(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewSessionsRepository(db)
+
+	userID := uuid.New()
+	familyID := uuid.New()
+
+	mock.ExpectExec(`INSERT INTO sessions`).
+		WithArgs(sqlmock.AnyArg(), userID, familyID, sqlmock.AnyArg(), sqlmock.AnyArg(), "", "", "").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	id, err := repo.Create(context.Background(), userID, familyID, []byte("hash"), time.Now(), models.ClientInfo{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if id == familyID {
+		t.Fatalf("expected new session id, got family id")
+	}
+}
+
+// captureUUID — matcher sqlmock, сохраняющий значение аргумента
+type uuidCapture struct{ dst *uuid.UUID }
+
+func captureUUID(dst *uuid.UUID) sqlmock.Argument { return uuidCapture{dst: dst} }
+
+func (c uuidCapture) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return false
 	}
+	*c.dst = id
+	return true
 }
 
 // Конфликт
@@ -53,14 +98,16 @@ func TestSessionsRepository_Create_Conflict(t *testing.T) {
 		Code: "23505", // unique_violation
 	}
 
-	mock.ExpectQuery(`INSERT INTO sessions`).
+	mock.ExpectExec(`INSERT INTO sessions`).
 		WillReturnError(pgErr)
 
 	_, err := repo.Create(
 		context.Background(),
 		uuid.New(),
+		uuid.Nil,
 		[]byte("hash"),
 		time.Now(),
+		models.ClientInfo{},
 	)
 
 	if err != serr.ErrConflict {
@@ -77,21 +124,22 @@ func TestSessionsRepository_GetByRefreshHash_OK(t *testing.T) {
 
 	sessID := uuid.New()
 	userID := uuid.New()
+	familyID := uuid.New()
 	exp := time.Now()
 
 	mock.ExpectQuery(`SELECT id, user_id, expires_at`).
 		WillReturnRows(sqlmock.NewRows(
-			[]string{"id", "user_id", "expires_at", "revoked_at", "replaced_by"},
-		).AddRow(sessID, userID, exp, nil, nil))
+			[]string{"id", "user_id", "expires_at", "revoked_at", "replaced_by", "family_id"},
+		).AddRow(sessID, userID, exp, nil, nil, familyID))
 
-	gotSess, gotUser, _, revoked, replaced, err :=
+	gotSess, gotUser, _, revoked, replaced, gotFamily, err :=
 		repo.GetByRefreshHash(context.Background(), []byte("hash"))
 
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if gotSess != sessID || gotUser != userID {
+	if gotSess != sessID || gotUser != userID || gotFamily != familyID {
 		t.Fatal("unexpected ids")
 	}
 	if revoked != nil || replaced != nil {
@@ -109,7 +157,7 @@ func TestSessionsRepository_GetByRefreshHash_NotFound(t *testing.T) {
 	mock.ExpectQuery(`SELECT id, user_id, expires_at`).
 		WillReturnError(sql.ErrNoRows)
 
-	_, _, _, _, _, err :=
+	_, _, _, _, _, _, err :=
 		repo.GetByRefreshHash(context.Background(), []byte("x"))
 
 	if err != serr.ErrUnauthorized {
@@ -133,6 +181,42 @@ func TestSessionsRepository_RevokeAndReplace_OK(t *testing.T) {
 	}
 }
 
+// сессию уже заменил параллельный запрос
+func TestSessionsRepository_RevokeAndReplace_AlreadyRevoked(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewSessionsRepository(db)
+
+	mock.ExpectExec(`UPDATE sessions`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.RevokeAndReplace(context.Background(), uuid.New(), uuid.New())
+	if err != serr.ErrConflict {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+}
+
+// отзыв семейства ротации
+func TestSessionsRepository_RevokeFamily_OK(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewSessionsRepository(db)
+
+	familyID := uuid.New()
+	mock.ExpectExec(`UPDATE sessions\s+SET revoked_at = now\(\)\s+WHERE family_id = \$1`).
+		WithArgs(familyID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	if err := repo.RevokeFamily(context.Background(), familyID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
 // отозван для всех пользователей юзера
 func TestSessionsRepository_RevokeAllForUser_OK(t *testing.T) {
 	db, mock, _ := sqlmock.New()
@@ -0,0 +1,202 @@
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/repository"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// новый секрет сохраняется
+func TestTwoFactorRepository_SaveTOTPSecret_OK(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewTwoFactorRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectExec(`INSERT INTO user_totp`).
+		WithArgs(userID, "SECRET").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.SaveTOTPSecret(context.Background(), userID, "SECRET"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// подтверждённый TOTP не перезаписывается
+func TestTwoFactorRepository_SaveTOTPSecret_AlreadyEnabled(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewTwoFactorRepository(db)
+
+	mock.ExpectExec(`INSERT INTO user_totp`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err := repo.SaveTOTPSecret(context.Background(), uuid.New(), "SECRET")
+	if err != serr.ErrAlreadyExists {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+}
+
+// секрет и признак подтверждения
+func TestTwoFactorRepository_GetTOTP(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewTwoFactorRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectQuery(`SELECT secret, confirmed_at`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"secret", "confirmed_at"}).AddRow("SECRET", time.Now()))
+
+	secret, confirmed, err := repo.GetTOTP(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secret != "SECRET" || !confirmed {
+		t.Fatalf("unexpected result: %q %v", secret, confirmed)
+	}
+
+	mock.ExpectQuery(`SELECT secret, confirmed_at`).
+		WillReturnError(sql.ErrNoRows)
+
+	if _, _, err := repo.GetTOTP(context.Background(), userID); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// подтверждение и коды восстановления — в одной транзакции
+func TestTwoFactorRepository_EnableTOTP_OK(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewTwoFactorRepository(db)
+	userID := uuid.New()
+	hashes := [][]byte{[]byte("h1"), []byte("h2")}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE user_totp`).
+		WithArgs(userID, int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM recovery_codes`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO recovery_codes`).
+		WithArgs(userID, []byte("h1")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO recovery_codes`).
+		WithArgs(userID, []byte("h2")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := repo.EnableTOTP(context.Background(), userID, 42, hashes); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// уже подтверждён — транзакция откатывается
+func TestTwoFactorRepository_EnableTOTP_Conflict(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewTwoFactorRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE user_totp`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.EnableTOTP(context.Background(), uuid.New(), 1, [][]byte{[]byte("h")})
+	if err != serr.ErrConflict {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet expectations: %v", err)
+	}
+}
+
+// код того же интервала повторно не принимается
+func TestTwoFactorRepository_UseTOTPStep_Replay(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewTwoFactorRepository(db)
+
+	mock.ExpectExec(`UPDATE user_totp\s+SET last_step = \$2`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := repo.UseTOTPStep(context.Background(), uuid.New(), 100); err != serr.ErrConflict {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+}
+
+// использованный код восстановления
+func TestTwoFactorRepository_UseRecoveryCode_NotFound(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewTwoFactorRepository(db)
+
+	mock.ExpectExec(`UPDATE recovery_codes`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := repo.UseRecoveryCode(context.Background(), uuid.New(), []byte("h")); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// попытка по живому challenge возвращает пользователя
+func TestTwoFactorRepository_ChallengeAttempt(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewTwoFactorRepository(db)
+	userID := uuid.New()
+
+	mock.ExpectQuery(`UPDATE mfa_challenges\s+SET attempts = attempts \+ 1`).
+		WithArgs([]byte("h"), 5).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userID))
+
+	got, err := repo.ChallengeAttempt(context.Background(), []byte("h"), 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != userID {
+		t.Fatalf("expected %v, got %v", userID, got)
+	}
+
+	// истёк / использован / попытки исчерпаны
+	mock.ExpectQuery(`UPDATE mfa_challenges`).
+		WillReturnError(sql.ErrNoRows)
+
+	if _, err := repo.ChallengeAttempt(context.Background(), []byte("h"), 5); err != serr.ErrUnauthorized {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
+
+// challenge одноразовый
+func TestTwoFactorRepository_CompleteChallenge_AlreadyUsed(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewTwoFactorRepository(db)
+
+	mock.ExpectExec(`UPDATE mfa_challenges\s+SET used_at = now\(\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := repo.CompleteChallenge(context.Background(), []byte("h")); err != serr.ErrUnauthorized {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
@@ -136,3 +137,200 @@ func TestUsersRepository_GetByEmail_InternalError(t *testing.T) {
 		t.Fatalf("expected ErrInternal, got %v", err)
 	}
 }
+
+// Профиль пользователя
+func TestUsersRepository_GetProfile_OK(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewUsersRepository(db)
+
+	id := uuid.New()
+	created := time.Now().UTC()
+
+	mock.ExpectQuery(`SELECT u.email`).
+		WithArgs(id).
+		WillReturnRows(
+			sqlmock.NewRows([]string{"email", "created_at", "secrets", "bytes", "sessions"}).
+				AddRow("test@mail.com", created, 3, int64(512), 2),
+		)
+
+	p, err := repo.GetProfile(context.Background(), id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.ID != id || p.Email != "test@mail.com" || p.SecretCount != 3 || p.StorageBytes != 512 || p.ActiveSessions != 2 {
+		t.Fatalf("unexpected profile: %+v", p)
+	}
+}
+
+// Пользователь не найден
+func TestUsersRepository_GetProfile_NotFound(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewUsersRepository(db)
+
+	mock.ExpectQuery(`SELECT u.email`).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err := repo.GetProfile(context.Background(), uuid.New())
+	if err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// Хэш пароля по ID пользователя
+func TestUsersRepository_GetPasswordHash(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewUsersRepository(db)
+
+	id := uuid.New()
+
+	mock.ExpectQuery(`SELECT password_hash FROM users WHERE id`).
+		WithArgs(id).
+		WillReturnRows(sqlmock.NewRows([]string{"password_hash"}).AddRow("hash"))
+
+	hash, err := repo.GetPasswordHash(context.Background(), id)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hash != "hash" {
+		t.Fatalf("expected hash, got %q", hash)
+	}
+
+	mock.ExpectQuery(`SELECT password_hash FROM users WHERE id`).
+		WillReturnError(sql.ErrNoRows)
+
+	if _, err := repo.GetPasswordHash(context.Background(), id); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// Обновление хэша пароля
+func TestUsersRepository_UpdatePasswordHash(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewUsersRepository(db)
+
+	id := uuid.New()
+
+	mock.ExpectExec(`UPDATE users SET password_hash`).
+		WithArgs(id, "new-hash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.UpdatePasswordHash(context.Background(), id, "new-hash"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mock.ExpectExec(`UPDATE users SET password_hash`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := repo.UpdatePasswordHash(context.Background(), id, "new-hash"); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// Пометка к удалению
+func TestUsersRepository_ScheduleDeletion(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewUsersRepository(db)
+
+	id := uuid.New()
+	deleteAfter := time.Now().Add(time.Hour)
+
+	mock.ExpectExec(`UPDATE users SET delete_after=\$2 WHERE id=\$1 AND delete_after IS NULL`).
+		WithArgs(id, deleteAfter).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.ScheduleDeletion(context.Background(), id, deleteAfter); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// уже помечен
+	mock.ExpectExec(`UPDATE users SET delete_after`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := repo.ScheduleDeletion(context.Background(), id, deleteAfter); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// Помеченный аккаунт ищется по email
+func TestUsersRepository_GetPendingDeletion(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewUsersRepository(db)
+
+	id := uuid.New()
+	deleteAfter := time.Now().Add(time.Hour)
+
+	mock.ExpectQuery(`SELECT id, password_hash, delete_after FROM users WHERE email=\$1 AND delete_after IS NOT NULL`).
+		WithArgs("test@mail.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "password_hash", "delete_after"}).AddRow(id, "hash", deleteAfter))
+
+	gotID, gotHash, gotAfter, err := repo.GetPendingDeletion(context.Background(), "test@mail.com")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotID != id || gotHash != "hash" || !gotAfter.Equal(deleteAfter) {
+		t.Fatalf("unexpected result: %v %q %v", gotID, gotHash, gotAfter)
+	}
+
+	mock.ExpectQuery(`SELECT id, password_hash, delete_after FROM users`).
+		WillReturnError(sql.ErrNoRows)
+
+	if _, _, _, err := repo.GetPendingDeletion(context.Background(), "test@mail.com"); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// Отмена удаления только до истечения отсрочки
+func TestUsersRepository_CancelDeletion(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewUsersRepository(db)
+
+	id := uuid.New()
+
+	mock.ExpectExec(`UPDATE users SET delete_after=NULL WHERE id=\$1 AND delete_after > now\(\)`).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := repo.CancelDeletion(context.Background(), id); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	mock.ExpectExec(`UPDATE users SET delete_after=NULL`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := repo.CancelDeletion(context.Background(), id); err != serr.ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+// Удаление аккаунтов с истёкшей отсрочкой
+func TestUsersRepository_PurgeDeleted(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := repository.NewUsersRepository(db)
+
+	mock.ExpectExec(`DELETE FROM users WHERE delete_after <= now\(\)`).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	n, err := repo.PurgeDeleted(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3, got %d", n)
+	}
+}
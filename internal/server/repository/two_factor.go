@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// TwoFactorRepository хранит данные двухфакторной аутентификации:
+//   - TOTP-секреты пользователей
+//   - хэши одноразовых кодов восстановления
+//   - challenge "mfa required" между проверкой пароля и вводом кода
+type TwoFactorRepository struct {
+	db *sql.DB
+}
+
+// NewTwoFactorRepository создаёт новый TwoFactorRepository.
+func NewTwoFactorRepository(db *sql.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// SaveTOTPSecret сохраняет неподтверждённый TOTP-секрет пользователя.
+//
+// Повторный вызов до подтверждения заменяет секрет (пользователь начал подключение заново).
+//
+// Ошибки:
+//   - ErrAlreadyExists если TOTP уже подключён и подтверждён
+//   - ErrInternal при ошибке БД
+func (r *TwoFactorRepository) SaveTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	res, err := r.db.ExecContext(ctx,
+		`INSERT INTO user_totp (user_id, secret)
+		 VALUES ($1, $2)
+		 ON CONFLICT (user_id) DO UPDATE
+		    SET secret = EXCLUDED.secret,
+		        last_step = 0,
+		        created_at = now()
+		  WHERE user_totp.confirmed_at IS NULL`,
+		userID, secret,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if affected == 0 {
+		return serr.ErrAlreadyExists
+	}
+	return nil
+}
+
+// GetTOTP возвращает TOTP-секрет пользователя и признак того, что подключение подтверждено.
+//
+// Ошибки:
+//   - ErrNotFound если пользователь не начинал подключение TOTP
+//   - ErrInternal при ошибке БД
+func (r *TwoFactorRepository) GetTOTP(ctx context.Context, userID uuid.UUID) (string, bool, error) {
+	var (
+		secret    string
+		confirmed sql.NullTime
+	)
+	err := r.db.QueryRowContext(ctx,
+		`SELECT secret, confirmed_at
+		   FROM user_totp
+		  WHERE user_id = $1`,
+		userID,
+	).Scan(&secret, &confirmed)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", false, serr.ErrNotFound
+		}
+		return "", false, serr.ErrInternal
+	}
+	return secret, confirmed.Valid, nil
+}
+
+// EnableTOTP подтверждает подключение TOTP и сохраняет хэши кодов восстановления.
+//
+// step — интервал кода, которым подтверждено подключение (он же не примется повторно при логине).
+// Старые коды восстановления удаляются. Всё выполняется в одной транзакции.
+//
+// Ошибки:
+//   - ErrConflict если подключение не начато или уже подтверждено
+//   - ErrInternal при ошибке БД
+func (r *TwoFactorRepository) EnableTOTP(ctx context.Context, userID uuid.UUID, step int64, recoveryHashes [][]byte) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE user_totp
+		    SET confirmed_at = now(),
+		        last_step = $2
+		  WHERE user_id = $1
+		    AND confirmed_at IS NULL`,
+		userID, step,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if affected == 0 {
+		return serr.ErrConflict
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return serr.ErrInternal
+	}
+	for _, h := range recoveryHashes {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)`,
+			userID, h,
+		); err != nil {
+			return serr.ErrInternal
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return serr.ErrInternal
+	}
+	return nil
+}
+
+// UseTOTPStep запоминает интервал принятого TOTP-кода.
+//
+// Код того же или более раннего интервала повторно не принимается.
+//
+// Ошибки:
+//   - ErrConflict если интервал уже использован
+//   - ErrInternal при ошибке БД
+func (r *TwoFactorRepository) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE user_totp
+		    SET last_step = $2
+		  WHERE user_id = $1
+		    AND confirmed_at IS NOT NULL
+		    AND last_step < $2`,
+		userID, step,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if affected == 0 {
+		return serr.ErrConflict
+	}
+	return nil
+}
+
+// UseRecoveryCode гасит код восстановления по его хэшу.
+//
+// Ошибки:
+//   - ErrNotFound если кода нет или он уже использован
+//   - ErrInternal при ошибке БД
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash []byte) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE recovery_codes
+		    SET used_at = now()
+		  WHERE user_id = $1
+		    AND code_hash = $2
+		    AND used_at IS NULL`,
+		userID, codeHash,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if affected == 0 {
+		return serr.ErrNotFound
+	}
+	return nil
+}
+
+// CreateChallenge сохраняет challenge "mfa required" (хэш его токена).
+func (r *TwoFactorRepository) CreateChallenge(ctx context.Context, userID uuid.UUID, tokenHash []byte, expiresAt time.Time) error {
+	_, err := r.db.ExecContext(ctx,
+		`INSERT INTO mfa_challenges (user_id, token_hash, expires_at)
+		 VALUES ($1, $2, $3)`,
+		userID, tokenHash, expiresAt,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	return nil
+}
+
+// ChallengeAttempt засчитывает попытку ввода кода по challenge и возвращает пользователя.
+//
+// Попытка принимается, только если challenge не истёк, не использован
+// и число попыток меньше maxAttempts. Счётчик увеличивается атомарно,
+// поэтому параллельный перебор кодов тоже упирается в лимит.
+//
+// Ошибки:
+//   - ErrUnauthorized если challenge не найден, истёк, использован или попытки исчерпаны
+//   - ErrInternal при ошибке БД
+func (r *TwoFactorRepository) ChallengeAttempt(ctx context.Context, tokenHash []byte, maxAttempts int) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := r.db.QueryRowContext(ctx,
+		`UPDATE mfa_challenges
+		    SET attempts = attempts + 1
+		  WHERE token_hash = $1
+		    AND used_at IS NULL
+		    AND expires_at > now()
+		    AND attempts < $2
+		 RETURNING user_id`,
+		tokenHash, maxAttempts,
+	).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, serr.ErrUnauthorized
+		}
+		return uuid.Nil, serr.ErrInternal
+	}
+	return userID, nil
+}
+
+// CompleteChallenge помечает challenge использованным.
+//
+// Ошибки:
+//   - ErrUnauthorized если challenge уже использован параллельным запросом
+//   - ErrInternal при ошибке БД
+func (r *TwoFactorRepository) CompleteChallenge(ctx context.Context, tokenHash []byte) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE mfa_challenges
+		    SET used_at = now()
+		  WHERE token_hash = $1
+		    AND used_at IS NULL`,
+		tokenHash,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if affected == 0 {
+		return serr.ErrUnauthorized
+	}
+	return nil
+}
@@ -3,10 +3,12 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgconn"
 
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
 	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
 )
 
@@ -56,6 +58,8 @@ func (r *UsersRepository) Create(ctx context.Context, email, passwordHash string
 
 // GetByEmail возвращает пользователя по email.
 //
+// Аккаунты, помеченные к удалению, не возвращаются: вход в них заблокирован.
+//
 // Возвращает:
 //   - id пользователя
 //   - password hash
@@ -67,7 +71,7 @@ func (r *UsersRepository) GetByEmail(ctx context.Context, email string) (uuid.UU
 	)
 
 	err := r.db.QueryRowContext(ctx,
-		`SELECT id, password_hash FROM users WHERE email=$1`,
+		`SELECT id, password_hash FROM users WHERE email=$1 AND delete_after IS NULL`,
 		email,
 	).Scan(&id, &hash)
 
@@ -80,3 +84,181 @@ func (r *UsersRepository) GetByEmail(ctx context.Context, email string) (uuid.UU
 
 	return id, hash, nil
 }
+
+// GetPasswordHash возвращает текущий хэш пароля пользователя по его ID.
+//
+// Используется при смене пароля для проверки старого пароля.
+//
+// Возвращает ErrNotFound — если пользователь не найден или ErrInternal — при ошибке БД
+func (r *UsersRepository) GetPasswordHash(ctx context.Context, userID uuid.UUID) (string, error) {
+	var hash string
+
+	err := r.db.QueryRowContext(ctx,
+		`SELECT password_hash FROM users WHERE id=$1`,
+		userID,
+	).Scan(&hash)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", serr.ErrNotFound
+		}
+		return "", serr.ErrInternal
+	}
+
+	return hash, nil
+}
+
+// UpdatePasswordHash заменяет хэш пароля пользователя.
+//
+// Используется для перехэширования при логине (смена алгоритма, параметров или pepper)
+// и при смене пароля пользователем.
+//
+// Возвращает ErrNotFound — если пользователь не найден или ErrInternal — при ошибке БД
+func (r *UsersRepository) UpdatePasswordHash(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET password_hash=$2 WHERE id=$1`,
+		userID, passwordHash,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if n == 0 {
+		return serr.ErrNotFound
+	}
+	return nil
+}
+
+// GetProfile возвращает сводку по аккаунту пользователя.
+//
+// Одним запросом собирает:
+//   - email и дату создания аккаунта
+//   - количество секретов и их суммарный размер (payload + meta) в байтах
+//   - количество активных сессий
+//
+// Возвращает ErrNotFound — если пользователь не найден или ErrInternal — при ошибке БД
+func (r *UsersRepository) GetProfile(ctx context.Context, userID uuid.UUID) (models.Profile, error) {
+	p := models.Profile{ID: userID}
+
+	err := r.db.QueryRowContext(ctx,
+		`SELECT u.email,
+		        u.created_at,
+		        (SELECT COUNT(*) FROM secrets s WHERE s.user_id = u.id),
+		        (SELECT COALESCE(SUM(octet_length(s.payload) + COALESCE(octet_length(s.meta), 0)), 0)
+		           FROM secrets s WHERE s.user_id = u.id),
+		        (SELECT COUNT(*) FROM sessions ss
+		          WHERE ss.user_id = u.id AND ss.revoked_at IS NULL AND ss.expires_at > now())
+		   FROM users u
+		  WHERE u.id = $1`,
+		userID,
+	).Scan(&p.Email, &p.CreatedAt, &p.SecretCount, &p.StorageBytes, &p.ActiveSessions)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return models.Profile{}, serr.ErrNotFound
+		}
+		return models.Profile{}, serr.ErrInternal
+	}
+
+	return p, nil
+}
+
+// ScheduleDeletion помечает аккаунт к удалению после deleteAfter.
+//
+// Возвращает ErrNotFound — если пользователь не найден или уже помечен к удалению,
+// ErrInternal — при ошибке БД
+func (r *UsersRepository) ScheduleDeletion(ctx context.Context, userID uuid.UUID, deleteAfter time.Time) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET delete_after=$2 WHERE id=$1 AND delete_after IS NULL`,
+		userID, deleteAfter,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if n == 0 {
+		return serr.ErrNotFound
+	}
+	return nil
+}
+
+// GetPendingDeletion возвращает аккаунт, помеченный к удалению, по email.
+//
+// Используется для восстановления аккаунта в течение отсрочки.
+//
+// Возвращает:
+//   - id пользователя
+//   - password hash
+//   - момент, после которого аккаунт будет удалён
+//   - ErrNotFound — если такого аккаунта нет или он не помечен к удалению, ErrInternal — при ошибке БД
+func (r *UsersRepository) GetPendingDeletion(ctx context.Context, email string) (uuid.UUID, string, time.Time, error) {
+	var (
+		id          uuid.UUID
+		hash        string
+		deleteAfter time.Time
+	)
+
+	err := r.db.QueryRowContext(ctx,
+		`SELECT id, password_hash, delete_after FROM users WHERE email=$1 AND delete_after IS NOT NULL`,
+		email,
+	).Scan(&id, &hash, &deleteAfter)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return uuid.Nil, "", time.Time{}, serr.ErrNotFound
+		}
+		return uuid.Nil, "", time.Time{}, serr.ErrInternal
+	}
+
+	return id, hash, deleteAfter, nil
+}
+
+// CancelDeletion снимает пометку удаления, если отсрочка ещё не истекла.
+//
+// Возвращает ErrNotFound — если аккаунт не помечен к удалению или срок уже истёк,
+// ErrInternal — при ошибке БД
+func (r *UsersRepository) CancelDeletion(ctx context.Context, userID uuid.UUID) error {
+	res, err := r.db.ExecContext(ctx,
+		`UPDATE users SET delete_after=NULL WHERE id=$1 AND delete_after > now()`,
+		userID,
+	)
+	if err != nil {
+		return serr.ErrInternal
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return serr.ErrInternal
+	}
+	if n == 0 {
+		return serr.ErrNotFound
+	}
+	return nil
+}
+
+// PurgeDeleted удаляет аккаунты с истёкшей отсрочкой.
+// Секреты, сессии и данные 2FA удаляются каскадно.
+//
+// Возвращает число удалённых аккаунтов или ErrInternal — при ошибке БД
+func (r *UsersRepository) PurgeDeleted(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM users WHERE delete_after <= now()`,
+	)
+	if err != nil {
+		return 0, serr.ErrInternal
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, serr.ErrInternal
+	}
+	return n, nil
+}
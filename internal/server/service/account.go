@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// RequestDeletion помечает аккаунт к удалению.
+//
+// Поведение:
+//   - требуется текущий пароль
+//   - вход в аккаунт сразу блокируется, все сессии отзываются
+//   - аккаунт вместе с секретами удаляется после auth.account_deletion.grace_period,
+//     до этого момента его можно восстановить через RestoreAccount
+//   - неверный пароль учитывается в счётчике auth.lockout, как при входе
+//
+// Возвращает момент, после которого аккаунт будет удалён.
+//
+// Ошибки:
+//   - ErrUserIDEmpty
+//   - ErrInvalidInput
+//   - ErrInvalidCredentials если пароль неверен
+//   - ErrNotFound если пользователь не найден или уже помечен к удалению
+//   - *LoginLockedError (errors.Is(err, ErrTooManyAttempts))
+func (s *AuthService) RequestDeletion(ctx context.Context, userID uuid.UUID, password string) (time.Time, error) {
+	if userID == uuid.Nil {
+		return time.Time{}, serr.ErrUserIDEmpty
+	}
+	password = strings.TrimSpace(password)
+	if password == "" {
+		return time.Time{}, serr.ErrInvalidInput
+	}
+
+	email, err := s.lockoutEmail(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if err := s.checkLockout(ctx, email); err != nil {
+		return time.Time{}, err
+	}
+
+	hash, err := s.users.GetPasswordHash(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	ok, err := s.verifyPassword(ctx, password, hash)
+	if err != nil {
+		return time.Time{}, hashingError(err)
+	}
+	if !ok {
+		s.securityEvent(ctx, "account_deletion_failed", zap.String("user_id", userID.String()))
+		return time.Time{}, s.loginFailed(ctx, email)
+	}
+	s.loginSucceeded(ctx, email)
+
+	deleteAfter := time.Now().Add(s.deletionGrace)
+	if err := s.users.ScheduleDeletion(ctx, userID, deleteAfter); err != nil {
+		return time.Time{}, err
+	}
+	if err := s.sessions.RevokeAllForUser(ctx, userID); err != nil {
+		return time.Time{}, err
+	}
+	s.syncRevocations(ctx)
+	s.securityEvent(ctx, "account_deletion_requested",
+		zap.String("user_id", userID.String()),
+		zap.Time("delete_after", deleteAfter),
+	)
+
+	return deleteAfter, nil
+}
+
+// RestoreAccount снимает пометку удаления с аккаунта.
+//
+// Вход в помеченный аккаунт заблокирован, поэтому восстановление
+// выполняется по email и паролю. После восстановления нужен обычный логин.
+//
+// Не раскрывает, существует ли аккаунт: для неизвестного email,
+// неверного пароля и истёкшей отсрочки возвращается одна и та же ошибка,
+// а пароль проверяется против dummy-хэша, как в Login.
+// Неудачи учитываются в том же счётчике auth.lockout, что и при входе.
+//
+// Ошибки:
+//   - ErrInvalidInput
+//   - ErrInvalidCredentials
+//   - *LoginLockedError (errors.Is(err, ErrTooManyAttempts))
+//   - *BusyError (errors.Is(err, ErrServerBusy))
+func (s *AuthService) RestoreAccount(ctx context.Context, email, password string) error {
+	email = strings.TrimSpace(strings.ToLower(email))
+	password = strings.TrimSpace(password)
+	if email == "" || password == "" {
+		return serr.ErrInvalidInput
+	}
+	if err := s.checkLockout(ctx, email); err != nil {
+		return err
+	}
+
+	userID, hash, deleteAfter, err := s.users.GetPendingDeletion(ctx, email)
+	if err != nil {
+		// не палим по времени ответа, какие аккаунты ждут удаления
+		if errors.Is(err, serr.ErrNotFound) {
+			if err := s.dummyVerify(ctx, password); err != nil {
+				return hashingError(err)
+			}
+			return s.loginFailed(ctx, email)
+		}
+		return err
+	}
+	ok, err := s.verifyPassword(ctx, password, hash)
+	if err != nil {
+		return hashingError(err)
+	}
+	if !ok || !deleteAfter.After(time.Now()) {
+		return s.loginFailed(ctx, email)
+	}
+
+	if err := s.users.CancelDeletion(ctx, userID); err != nil {
+		// отсрочка истекла между проверкой и обновлением
+		if errors.Is(err, serr.ErrNotFound) {
+			return serr.ErrInvalidCredentials
+		}
+		return err
+	}
+	s.loginSucceeded(ctx, email)
+	s.securityEvent(ctx, "account_restored", zap.String("user_id", userID.String()))
+	return nil
+}
+
+// PurgeDeletedAccounts удаляет аккаунты с истёкшей отсрочкой
+// и возвращает их число.
+func (s *AuthService) PurgeDeletedAccounts(ctx context.Context) (int64, error) {
+	return s.users.PurgeDeleted(ctx)
+}
+
+// RunAccountPurge периодически вызывает PurgeDeletedAccounts до отмены ctx,
+// заодно удаляя устаревшие счётчики неудачных входов, истёкшие коды устройств
+// и токены из писем.
+// onErr (может быть nil) получает ошибки удаления.
+func (s *AuthService) RunAccountPurge(ctx context.Context, interval time.Duration, onErr func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.PurgeDeletedAccounts(ctx); err != nil && onErr != nil {
+				onErr(err)
+			}
+			if err := s.PruneLoginAttempts(ctx); err != nil && onErr != nil {
+				onErr(err)
+			}
+			if err := s.PruneDeviceCodes(ctx); err != nil && onErr != nil {
+				onErr(err)
+			}
+			if err := s.PruneEmailTokens(ctx); err != nil && onErr != nil {
+				onErr(err)
+			}
+		}
+	}
+}
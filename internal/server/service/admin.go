@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// Размер страницы списка пользователей.
+const (
+	defaultAdminUsersLimit = 50
+	maxAdminUsersLimit     = 200
+)
+
+// UseAdmin подключает роль администратора и блокировку аккаунтов.
+//
+// Заблокированный пользователь не может войти (любым способом), обновить
+// токены и пользоваться API-токенами. Администраторы (users.is_admin)
+// могут выпускать приглашения наравне с auth.invites.admins.
+func (s *AuthService) UseAdmin(repo AdminRepo) {
+	s.accounts = repo
+}
+
+// requireEnabled пропускает только незаблокированных пользователей.
+//
+// Аккаунт, помеченный к удалению, тоже не пропускается (ErrUnauthorized):
+// вход в него блокируется сразу, в том числе по challenge 2FA или коду
+// устройства, полученным до DELETE /me.
+func (s *AuthService) requireEnabled(ctx context.Context, userID uuid.UUID) error {
+	if s.accounts == nil {
+		return nil
+	}
+	st, err := s.accounts.GetStatus(ctx, userID)
+	if err != nil {
+		if errors.Is(err, serr.ErrNotFound) {
+			return serr.ErrUnauthorized
+		}
+		return err
+	}
+	if st.Disabled {
+		return serr.ErrAccountDisabled
+	}
+	if st.PendingDeletion {
+		return serr.ErrUnauthorized
+	}
+	return nil
+}
+
+// isAdmin сообщает, есть ли у пользователя роль администратора.
+func (s *AuthService) isAdmin(ctx context.Context, userID uuid.UUID) (bool, error) {
+	if s.accounts == nil {
+		return false, nil
+	}
+	st, err := s.accounts.GetStatus(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return st.IsAdmin, nil
+}
+
+// recordLogin запоминает время входа для списка пользователей администратора.
+// Сбой не отменяет вход.
+func (s *AuthService) recordLogin(ctx context.Context, userID uuid.UUID) {
+	if s.accounts == nil {
+		return
+	}
+	_ = s.accounts.RecordLogin(ctx, userID)
+}
+
+// AdminService — операции администратора сервера: список пользователей,
+// блокировка аккаунтов, принудительный отзыв сессий и статистика хранилища.
+type AdminService struct {
+	repo     AdminRepo
+	sessions SessionsRepo
+	auth     *AuthService // кэш отзыва сессий, журналы безопасности и аудита
+}
+
+// NewAdminService создаёт AdminService.
+func NewAdminService(repo AdminRepo, sessions SessionsRepo, auth *AuthService) *AdminService {
+	return &AdminService{repo: repo, sessions: sessions, auth: auth}
+}
+
+// RequireAdmin проверяет, что пользователь — администратор.
+//
+// Ошибки:
+//   - ErrUnauthorized — пользователя нет
+//   - ErrForbidden — пользователь не администратор или заблокирован
+func (s *AdminService) RequireAdmin(ctx context.Context, userID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return serr.ErrUnauthorized
+	}
+	st, err := s.repo.GetStatus(ctx, userID)
+	if err != nil {
+		if errors.Is(err, serr.ErrNotFound) {
+			return serr.ErrUnauthorized
+		}
+		return err
+	}
+	if !st.IsAdmin || st.Disabled {
+		return serr.ErrForbidden
+	}
+	return nil
+}
+
+// ListUsers возвращает страницу пользователей в порядке регистрации.
+//
+// limit=0 — 50 записей; больше 200 за раз не отдаётся.
+//
+// Ошибки:
+//   - ErrInvalidInput — отрицательные limit или offset, limit больше 200
+func (s *AdminService) ListUsers(ctx context.Context, limit, offset int) ([]models.AdminUser, error) {
+	if limit == 0 {
+		limit = defaultAdminUsersLimit
+	}
+	if limit < 0 || limit > maxAdminUsersLimit || offset < 0 {
+		return nil, serr.ErrInvalidInput
+	}
+	return s.repo.ListUsers(ctx, limit, offset)
+}
+
+// SetDisabled блокирует или разблокирует аккаунт userID.
+//
+// При блокировке все сессии пользователя сразу отзываются, а его
+// access-токены перестают приниматься. Секреты не затрагиваются.
+// Заблокировать себя администратор не может.
+//
+// Ошибки:
+//   - ErrInvalidInput — adminID == userID
+//   - ErrNotFound — пользователя нет
+func (s *AdminService) SetDisabled(ctx context.Context, adminID, userID uuid.UUID, disabled bool) error {
+	if userID == uuid.Nil {
+		return serr.ErrInvalidInput
+	}
+	// иначе сервер может остаться без администратора
+	if adminID == userID && disabled {
+		return serr.ErrInvalidInput
+	}
+	if err := s.repo.SetDisabled(ctx, userID, disabled); err != nil {
+		return err
+	}
+
+	event, audit := "account_enabled", models.AuditAccountEnabled
+	if disabled {
+		event, audit = "account_disabled", models.AuditAccountDisabled
+		if err := s.revokeAll(ctx, userID); err != nil {
+			return err
+		}
+	}
+	s.auth.securityEvent(ctx, event,
+		zap.String("user_id", userID.String()),
+		zap.String("admin_id", adminID.String()),
+	)
+	s.auth.audit.Record(ctx, userID, audit, uuid.Nil)
+	return nil
+}
+
+// RevokeSessions отзывает все сессии пользователя userID (выход на всех устройствах).
+//
+// Ошибки:
+//   - ErrInvalidInput — userID пустой
+//   - ErrNotFound — пользователя нет
+func (s *AdminService) RevokeSessions(ctx context.Context, adminID, userID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return serr.ErrInvalidInput
+	}
+	if _, err := s.repo.GetStatus(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.revokeAll(ctx, userID); err != nil {
+		return err
+	}
+
+	s.auth.securityEvent(ctx, "sessions_revoked_by_admin",
+		zap.String("user_id", userID.String()),
+		zap.String("admin_id", adminID.String()),
+	)
+	s.auth.audit.Record(ctx, userID, models.AuditSessionsRevoked, uuid.Nil)
+	return nil
+}
+
+// CreateUser создаёт пользователя в обход auth.registration
+// (server user create). Email такого пользователя считается подтверждённым:
+// его завёл оператор сервера.
+//
+// Ошибки:
+//   - ErrInvalidInput — некорректный email, пароль короче 8 символов или длиннее, чем принимает хэшер
+//   - ErrAlreadyExists — email уже зарегистрирован
+func (s *AdminService) CreateUser(ctx context.Context, email, password string, isAdmin bool) (uuid.UUID, error) {
+	email, password, ok := s.auth.normalizeCredentials(email, password)
+	if !ok {
+		return uuid.Nil, serr.ErrInvalidInput
+	}
+
+	userID, err := s.auth.createUser(ctx, email, password)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if isAdmin {
+		if err := s.repo.SetAdmin(ctx, userID, true); err != nil {
+			return uuid.Nil, err
+		}
+	}
+	if s.auth.emailTokens != nil {
+		if err := s.auth.emailTokens.MarkVerified(ctx, userID); err != nil {
+			return uuid.Nil, err
+		}
+	}
+
+	s.auth.securityEvent(ctx, "user_created_by_admin",
+		zap.String("user_id", userID.String()),
+		zap.Bool("admin", isAdmin),
+	)
+	return userID, nil
+}
+
+// ResolveUser находит пользователя по ID или email.
+//
+// Ошибки:
+//   - ErrNotFound — пользователя нет
+func (s *AdminService) ResolveUser(ctx context.Context, ref string) (uuid.UUID, error) {
+	ref = strings.TrimSpace(ref)
+	if id, err := uuid.Parse(ref); err == nil {
+		if _, err := s.repo.GetStatus(ctx, id); err != nil {
+			return uuid.Nil, err
+		}
+		return id, nil
+	}
+	if ref == "" {
+		return uuid.Nil, serr.ErrInvalidInput
+	}
+	id, _, err := s.auth.users.GetByEmail(ctx, strings.ToLower(ref))
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return id, nil
+}
+
+// Stats возвращает сводную статистику хранилища.
+func (s *AdminService) Stats(ctx context.Context) (models.StorageStats, error) {
+	return s.repo.StorageStats(ctx)
+}
+
+func (s *AdminService) revokeAll(ctx context.Context, userID uuid.UUID) error {
+	if err := s.sessions.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+	s.auth.syncRevocations(ctx)
+	return nil
+}
@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// maxAPITokenNameLen — ограничение длины имени API-токена.
+const maxAPITokenNameLen = 100
+
+// knownScopes — scopes, которые можно выдать API-токену.
+var knownScopes = []string{models.ScopeSecretsRead, models.ScopeSecretsWrite}
+
+// apiTokenSettings — параметры auth.api_tokens и допустимые типы секретов.
+type apiTokenSettings struct {
+	defaultTTL  time.Duration
+	maxTTL      time.Duration
+	secretTypes []string // secrets.allowed_types
+}
+
+// NewAPIToken — созданный API-токен. Token возвращается только один раз.
+type NewAPIToken struct {
+	Token string
+	models.APIToken
+}
+
+// UseAPITokens подключает хранилище персональных API-токенов.
+//
+// Без вызова создание токенов недоступно, а вход по ним отклоняется.
+func (s *AuthService) UseAPITokens(repo APITokensRepo) {
+	s.apiTokens = repo
+}
+
+// CreateAPIToken выпускает персональный API-токен пользователя.
+//
+// Параметры:
+//   - name — имя токена для списка (например, "github-actions")
+//   - scopes — хотя бы один из secrets:read, secrets:write
+//   - types — типы секретов, доступные токену (пусто — любые)
+//   - ttl — срок действия (0 — auth.api_tokens.default_ttl, не больше max_ttl)
+//
+// Токен возвращается только здесь: в БД сохраняется лишь его SHA-256.
+//
+// Ошибки:
+//   - ErrUserIDEmpty
+//   - ErrInvalidInput — пустое имя, неизвестный scope или тип, неверный срок
+func (s *AuthService) CreateAPIToken(ctx context.Context, userID uuid.UUID, name string, scopes, types []string, ttl time.Duration) (NewAPIToken, error) {
+	if userID == uuid.Nil {
+		return NewAPIToken{}, serr.ErrUserIDEmpty
+	}
+	if s.apiTokens == nil {
+		return NewAPIToken{}, serr.ErrInternal
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxAPITokenNameLen {
+		return NewAPIToken{}, serr.ErrInvalidInput
+	}
+	scopes, ok := normalizeList(scopes, knownScopes)
+	if !ok || len(scopes) == 0 {
+		return NewAPIToken{}, serr.ErrInvalidInput
+	}
+	types, ok = normalizeList(types, s.apiTokenTTL.secretTypes)
+	if !ok {
+		return NewAPIToken{}, serr.ErrInvalidInput
+	}
+	if ttl == 0 {
+		ttl = s.apiTokenTTL.defaultTTL
+	}
+	if ttl <= 0 || ttl > s.apiTokenTTL.maxTTL {
+		return NewAPIToken{}, serr.ErrInvalidInput
+	}
+
+	token, err := crypto.NewAPIToken()
+	if err != nil {
+		return NewAPIToken{}, serr.ErrInternal
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	id, createdAt, err := s.apiTokens.Create(ctx, userID, name, crypto.HashRefreshToken(token), scopes, types, expiresAt)
+	if err != nil {
+		return NewAPIToken{}, err
+	}
+
+	s.securityEvent(ctx, "api_token_created",
+		zap.String("user_id", userID.String()),
+		zap.String("token_id", id.String()),
+		zap.Strings("scopes", scopes),
+		zap.Strings("types", types),
+		zap.Time("expires_at", expiresAt),
+	)
+
+	return NewAPIToken{
+		Token: token,
+		APIToken: models.APIToken{
+			ID:        id,
+			UserID:    userID,
+			Name:      name,
+			Scopes:    scopes,
+			Types:     types,
+			CreatedAt: createdAt,
+			ExpiresAt: expiresAt,
+		},
+	}, nil
+}
+
+// ListAPITokens возвращает действующие API-токены пользователя.
+func (s *AuthService) ListAPITokens(ctx context.Context, userID uuid.UUID) ([]models.APIToken, error) {
+	if userID == uuid.Nil {
+		return nil, serr.ErrUserIDEmpty
+	}
+	if s.apiTokens == nil {
+		return nil, nil
+	}
+	return s.apiTokens.ListByUser(ctx, userID)
+}
+
+// RevokeAPIToken отзывает API-токен пользователя.
+//
+// Ошибки:
+//   - ErrNotFound — токена нет, он чужой или уже отозван
+func (s *AuthService) RevokeAPIToken(ctx context.Context, userID, tokenID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return serr.ErrUserIDEmpty
+	}
+	if s.apiTokens == nil {
+		return serr.ErrNotFound
+	}
+	if err := s.apiTokens.Revoke(ctx, userID, tokenID); err != nil {
+		return err
+	}
+
+	s.securityEvent(ctx, "api_token_revoked",
+		zap.String("user_id", userID.String()),
+		zap.String("token_id", tokenID.String()),
+	)
+	return nil
+}
+
+// revokeAPITokens отзывает все API-токены пользователя, если они подключены.
+//
+// Вызывается вместе с отзывом сессий при смене и сбросе пароля:
+// токен, выпущенный тем, кто завладел аккаунтом, не должен пережить смену пароля.
+func (s *AuthService) revokeAPITokens(ctx context.Context, userID uuid.UUID) error {
+	if s.apiTokens == nil {
+		return nil
+	}
+	return s.apiTokens.RevokeAllForUser(ctx, userID)
+}
+
+// AuthenticateAPIToken проверяет персональный API-токен из заголовка Authorization.
+//
+// Используется middleware.JWTVerifier для токенов с префиксом gkp_.
+// Если к аккаунту привязан клиентский сертификат, токен принимается только
+// вместе с ним — как и access-токены.
+//
+// Ошибки:
+//   - ErrUnauthorized — токен неизвестен, отозван, истёк или аккаунт заблокирован
+//   - ErrClientCertRequired — к аккаунту привязан сертификат, а запрос пришёл без него
+//   - ErrInternal — ошибка хранилища
+func (s *AuthService) AuthenticateAPIToken(ctx context.Context, token string) (models.APIToken, error) {
+	if s.apiTokens == nil || !crypto.IsAPIToken(token) {
+		return models.APIToken{}, serr.ErrUnauthorized
+	}
+	t, err := s.apiTokens.Use(ctx, crypto.HashRefreshToken(token))
+	if err != nil {
+		if errors.Is(err, serr.ErrUnauthorized) {
+			return models.APIToken{}, serr.ErrUnauthorized
+		}
+		return models.APIToken{}, err
+	}
+	// токены заблокированного аккаунта не принимаются
+	if err := s.requireEnabled(ctx, t.UserID); err != nil {
+		if errors.Is(err, serr.ErrAccountDisabled) {
+			return models.APIToken{}, serr.ErrUnauthorized
+		}
+		return models.APIToken{}, err
+	}
+	// иначе API-токен обходил бы привязку сертификата
+	if _, err := s.certBinding(ctx, t.UserID); err != nil {
+		return models.APIToken{}, err
+	}
+	return t, nil
+}
+
+// normalizeList убирает пробелы и повторы и проверяет, что все значения допустимы.
+// Пустой allowed — допустимо любое значение.
+func normalizeList(items, allowed []string) ([]string, bool) {
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" || slices.Contains(out, item) {
+			continue
+		}
+		if len(allowed) > 0 && !slices.Contains(allowed, item) {
+			return nil, false
+		}
+		out = append(out, item)
+	}
+	slices.Sort(out)
+	return out, true
+}
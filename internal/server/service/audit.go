@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+const (
+	// defaultAuditLimit — размер страницы GET /audit, если limit не задан.
+	defaultAuditLimit = 50
+	// maxAuditLimit — наибольший размер страницы GET /audit.
+	maxAuditLimit = 200
+)
+
+// AuditService ведёт журнал аудита пользователя: входы, отзыв сессий,
+// изменения секретов. В отличие от журнала безопасности (лог сервера)
+// журнал хранится в БД и доступен самому пользователю.
+//
+// Запись best-effort: сбой журнала не отменяет операцию,
+// ошибка передаётся в OnError.
+type AuditService struct {
+	repo      AuditRepo
+	retention time.Duration
+
+	// OnError получает ошибки записи событий (может быть nil).
+	OnError func(error)
+
+	// Now — источник времени (подменяется в тестах).
+	Now func() time.Time
+}
+
+// NewAuditService создаёт AuditService со сроком хранения из cfg.
+func NewAuditService(repo AuditRepo, cfg config.AuditConfig) *AuditService {
+	return &AuditService{
+		repo:      repo,
+		retention: cfg.Retention,
+		Now:       time.Now,
+	}
+}
+
+// Record добавляет событие typ в журнал пользователя.
+//
+// IP и User-Agent берутся из ClientInfo контекста; secretID — uuid.Nil,
+// если событие не относится к секрету. Безопасен для nil-получателя
+// (журнал выключен).
+func (s *AuditService) Record(ctx context.Context, userID uuid.UUID, typ string, secretID uuid.UUID) {
+	if s == nil || userID == uuid.Nil {
+		return
+	}
+	client := ClientInfoFromContext(ctx)
+	err := s.repo.Append(ctx, models.AuditEvent{
+		UserID:    userID,
+		Type:      typ,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+		SecretID:  secretID,
+	})
+	if err != nil && s.OnError != nil {
+		s.OnError(err)
+	}
+}
+
+// List возвращает страницу журнала пользователя от новых событий к старым.
+//
+// next — курсор следующей страницы (передаётся как f.Before), 0 — страниц больше нет.
+//
+// Ошибки:
+//   - ErrUserIDEmpty если userID пустой
+//   - ErrInvalidInput если тип события неизвестен или limit/курсор отрицательные
+func (s *AuditService) List(ctx context.Context, userID uuid.UUID, f models.AuditFilter) (events []models.AuditEvent, next int64, err error) {
+	if userID == uuid.Nil {
+		return nil, 0, serr.ErrUserIDEmpty
+	}
+	if f.Type != "" && !slices.Contains(models.AuditEventTypes, f.Type) {
+		return nil, 0, serr.ErrInvalidInput
+	}
+	if f.Limit < 0 || f.Before < 0 {
+		return nil, 0, serr.ErrInvalidInput
+	}
+	if f.Limit == 0 {
+		f.Limit = defaultAuditLimit
+	}
+	f.Limit = min(f.Limit, maxAuditLimit)
+
+	// лишняя запись показывает, есть ли следующая страница
+	limit := f.Limit
+	f.Limit++
+	events, err = s.repo.List(ctx, userID, f)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(events) > limit {
+		events = events[:limit]
+		next = events[limit-1].ID
+	}
+	return events, next, nil
+}
+
+// Prune удаляет события старше срока хранения и возвращает их число.
+func (s *AuditService) Prune(ctx context.Context) (int64, error) {
+	if s.retention <= 0 {
+		return 0, nil
+	}
+	return s.repo.Prune(ctx, s.Now().Add(-s.retention))
+}
+
+// RunRetention периодически вызывает Prune до отмены ctx.
+// onErr (может быть nil) получает ошибки удаления.
+func (s *AuditService) RunRetention(ctx context.Context, interval time.Duration, onErr func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Prune(ctx); err != nil && onErr != nil {
+				onErr(err)
+			}
+		}
+	}
+}
@@ -2,16 +2,22 @@ package service
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
 	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/mailer"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
 	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/logger"
 )
 
 // AuthService реализует бизнес-логику аутентификации и управления сессиями.
@@ -23,16 +29,71 @@ import (
 //   - обновление access токенов по refresh
 //   - rotation refresh токенов
 //   - reuse detection (защита от повторного использования refresh)
+//   - журнал событий безопасности
+//   - ограничение числа активных сессий пользователя
+//   - задержки и временная блокировка после неудачных входов
+//   - двухфакторная аутентификация (TOTP, коды восстановления)
+//   - смена пароля и удаление аккаунта с отсрочкой
+//   - персональные API-токены со scopes
+//   - вход по коду устройства (RFC 8628)
+//   - клиентские сертификаты (mTLS) как второй фактор
+//   - вход через внешний OpenID Connect провайдер
+//   - повторная аутентификация перед чувствительными операциями
 type AuthService struct {
 	users    UsersRepo
 	sessions SessionsRepo
 
-	pass crypto.Argon2Params
+	pass *crypto.Passwords
 	jwt  crypto.JWTConfig
 
 	refreshTTL     time.Duration
 	rotateRefresh  bool
 	reuseDetection bool
+
+	maxSessions     int  // 0 — без ограничения
+	rejectOverLimit bool // true — отказ в логине, false — отзыв самых старых сессий
+
+	revocations *SessionRevocations    // кэш отзыва access-токенов (опционально)
+	security    *logger.SecurityLogger // журнал событий безопасности (опционально)
+	audit       *AuditService          // журнал аудита пользователя (опционально)
+
+	twoFactor TwoFactorRepo // данные 2FA (опционально, nil — 2FA недоступна)
+	mfa       mfaSettings
+
+	deletionGrace time.Duration // сколько удалённый аккаунт можно восстановить
+
+	attempts LoginAttemptsRepo // счётчики неудачных входов (опционально)
+	lockout  lockoutSettings
+	dummy    dummyHash // для проверки пароля, когда email не найден
+
+	hashLimit *hashLimiter // бюджет памяти на хэширование (nil — без ограничений)
+
+	apiTokens   APITokensRepo // персональные API-токены (опционально)
+	apiTokenTTL apiTokenSettings
+
+	deviceCodes DeviceCodesRepo // вход по коду устройства (опционально)
+	device      deviceSettings
+
+	clientCerts ClientCertsRepo // привязанные клиентские сертификаты (опционально)
+	clientCAs   *x509.CertPool  // CA из tls.client_ca_file
+
+	oidc            *crypto.OIDCVerifier // проверка ID-токенов провайдера (опционально)
+	oidcIdentities  OIDCIdentitiesRepo
+	oidcLinkByEmail bool
+
+	registration string         // auth.registration: open | invite | closed
+	invites      InvitesRepo    // коды приглашений (опционально)
+	invite       inviteSettings // auth.invites
+
+	elevatedTTL time.Duration // сколько действуют повышенные права после Reauthenticate
+
+	mailer      mailer.Mailer   // служебные письма (опционально)
+	emailTokens EmailTokensRepo // токены подтверждения email и сброса пароля
+	mail        mailSettings    // mail
+	mailErr     func(error)     // ошибки отправки писем (может быть nil)
+	mailWG      sync.WaitGroup  // письма, отправляемые в фоне
+
+	accounts AdminRepo // роль администратора и блокировка аккаунтов (опционально)
 }
 
 // TokenPair представляет пару access / refresh токенов.
@@ -47,13 +108,7 @@ func NewAuthService(users UsersRepo, sessions SessionsRepo, cfg *config.Config)
 		users:    users,
 		sessions: sessions,
 
-		pass: crypto.Argon2Params{
-			Time:      cfg.Password.Argon2.Time,
-			MemoryKiB: cfg.Password.Argon2.MemoryKiB,
-			Threads:   cfg.Password.Argon2.Threads,
-			KeyLen:    cfg.Password.Argon2.KeyLen,
-			SaltLen:   cfg.Password.Argon2.SaltLen,
-		},
+		pass: newPasswords(cfg.Password),
 		jwt: crypto.JWTConfig{
 			Issuer:     cfg.Auth.Issuer,
 			Audience:   cfg.Auth.Audience,
@@ -64,7 +119,112 @@ func NewAuthService(users UsersRepo, sessions SessionsRepo, cfg *config.Config)
 		refreshTTL:     cfg.Auth.RefreshTTL,
 		rotateRefresh:  cfg.Auth.Sessions.RotateRefresh,
 		reuseDetection: cfg.Auth.Sessions.ReuseDetection,
+
+		maxSessions:     cfg.Auth.Sessions.MaxSessionsPerUser,
+		rejectOverLimit: cfg.Auth.Sessions.LimitPolicy == "reject",
+
+		mfa: newMFASettings(cfg.Auth.MFA),
+
+		deletionGrace: cfg.Auth.AccountDeletion.GracePeriod,
+
+		lockout: newLockoutSettings(cfg.Auth.Lockout),
+
+		hashLimit: newHashLimiter(cfg.Password),
+
+		apiTokenTTL: apiTokenSettings{
+			defaultTTL:  cfg.Auth.APITokens.DefaultTTL,
+			maxTTL:      cfg.Auth.APITokens.MaxTTL,
+			secretTypes: cfg.Secrets.AllowedTypes,
+		},
+
+		device: deviceSettings{
+			codeTTL:      cfg.Auth.Device.CodeTTL,
+			pollInterval: cfg.Auth.Device.PollInterval,
+		},
+
+		registration: cfg.Auth.Registration,
+		invite:       newInviteSettings(cfg.Auth.Invites),
+
+		elevatedTTL: cfg.Auth.Reauth.ElevatedTTL,
+
+		mail: newMailSettings(cfg.Mail),
+	}
+}
+
+// UseKeySet включает подпись access-токенов асимметричным ключом (EdDSA/RS256).
+//
+// Ключи загружаются из PEM-файлов при старте сервера (см. auth.jwt в конфиге);
+// без вызова используется HS256 с auth.jwt.signing_key.
+func (s *AuthService) UseKeySet(keys *crypto.KeySet) {
+	s.jwt.Keys = keys
+}
+
+// UseRevocations подключает кэш отозванных сессий.
+//
+// После каждого отзыва сессии сервис сразу обновляет кэш,
+// чтобы access-токены этой сессии перестали приниматься без задержки
+// на периодическую синхронизацию.
+func (s *AuthService) UseRevocations(r *SessionRevocations) {
+	s.revocations = r
+}
+
+// syncRevocations обновляет кэш отзыва, если он подключён.
+// Ошибка не критична: кэш догонит БД при следующей периодической синхронизации.
+func (s *AuthService) syncRevocations(ctx context.Context) {
+	if s.revocations == nil {
+		return
+	}
+	_ = s.revocations.Refresh(ctx)
+}
+
+// UseSecurityLog подключает журнал событий безопасности
+// (например, повторное использование refresh-токена).
+func (s *AuthService) UseSecurityLog(l *logger.SecurityLogger) {
+	s.security = l
+}
+
+// UseAudit подключает журнал аудита пользователя (GET /audit).
+func (s *AuthService) UseAudit(a *AuditService) {
+	s.audit = a
+}
+
+// securityEvent пишет событие в журнал безопасности, если он подключён.
+func (s *AuthService) securityEvent(ctx context.Context, event string, fields ...zap.Field) {
+	if s.security == nil {
+		return
+	}
+	client := ClientInfoFromContext(ctx)
+	fields = append(fields,
+		zap.String("ip", client.IP),
+		zap.String("device", client.DeviceName),
+		zap.String("user_agent", client.UserAgent),
+	)
+	s.security.Event(event, fields...)
+}
+
+// newPasswords собирает хэшер паролей из конфига.
+//
+// Текущий алгоритм задаётся password.hasher (по умолчанию argon2id),
+// второй алгоритм остаётся доступным для проверки старых хэшей.
+func newPasswords(cfg config.PasswordConfig) *crypto.Passwords {
+	argonHasher := crypto.Argon2Hasher{Params: crypto.Argon2Params{
+		Time:      cfg.Argon2.Time,
+		MemoryKiB: cfg.Argon2.MemoryKiB,
+		Threads:   cfg.Argon2.Threads,
+		KeyLen:    cfg.Argon2.KeyLen,
+		SaltLen:   cfg.Argon2.SaltLen,
+	}}
+	bcryptHasher := crypto.BcryptHasher{Cost: cfg.Bcrypt.Cost}
+
+	var pepper []byte
+	if p := strings.TrimSpace(cfg.Pepper); p != "" {
+		pepper = []byte(p)
+	}
+
+	if strings.ToLower(cfg.Hasher) == "bcrypt" {
+		return crypto.NewPasswords(bcryptHasher, pepper, argonHasher)
 	}
+	return crypto.NewPasswords(argonHasher, pepper, bcryptHasher)
 }
 
 // Register регистрирует нового пользователя.
@@ -73,60 +233,209 @@ func NewAuthService(users UsersRepo, sessions SessionsRepo, cfg *config.Config)
 //   - email обязателен и должен быть валидным
 //   - пароль обязателен и длиной >= 8 символов
 //
+// Режим auth.registration:
+//   - open — invite игнорируется
+//   - invite — нужен действующий код приглашения; он расходуется
+//     в одной транзакции с созданием пользователя
+//   - closed — регистрация недоступна
+//
 // Возвращает:
 //   - id пользователя
 //   - ErrInvalidInput при некорректных данных или ErrAlreadyExists если email уже зарегистрирован
-func (s *AuthService) Register(ctx context.Context, email, password string) (uuid.UUID, error) {
+//   - ErrRegistrationClosed / ErrInvalidInvite
+//   - *BusyError (errors.Is(err, ErrServerBusy)), если очередь хэширования переполнена
+func (s *AuthService) Register(ctx context.Context, email, password, invite string) (uuid.UUID, error) {
+	if s.registration == config.RegistrationClosed {
+		return uuid.Nil, serr.ErrRegistrationClosed
+	}
+
+	email, password, ok := s.normalizeCredentials(email, password)
+	if !ok {
+		return uuid.Nil, serr.ErrInvalidInput
+	}
+
+	var (
+		userID uuid.UUID
+		err    error
+	)
+	if s.registration == config.RegistrationInvite {
+		userID, err = s.registerWithInvite(ctx, email, password, invite)
+	} else {
+		userID, err = s.createUser(ctx, email, password)
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	// аккаунт уже создан: сбой почты не отменяет регистрацию
+	if s.mailer != nil {
+		if err := s.sendVerification(ctx, userID, email); err != nil {
+			s.reportMailError(err)
+		}
+	}
+	return userID, nil
+}
+
+// normalizeCredentials приводит email к нижнему регистру, обрезает пробелы
+// и проверяет email и пароль по правилам регистрации.
+func (s *AuthService) normalizeCredentials(email, password string) (string, string, bool) {
 	email = strings.TrimSpace(strings.ToLower(email))
 	password = strings.TrimSpace(password)
 
-	if email == "" || password == "" || !regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`).MatchString(email) || len(password) < 8 {
-		return uuid.Nil, serr.ErrInvalidInput
+	if email == "" || !regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`).MatchString(email) || !s.validPassword(password) {
+		return "", "", false
 	}
+	return email, password, true
+}
 
-	hash, err := crypto.HashPassword(password, s.pass)
+// createUser хэширует пароль и создаёт пользователя.
+// email и password уже проверены normalizeCredentials.
+func (s *AuthService) createUser(ctx context.Context, email, password string) (uuid.UUID, error) {
+	hash, err := s.hashPassword(ctx, password)
 	if err != nil {
-		return uuid.Nil, serr.ErrInternal
+		return uuid.Nil, hashingError(err)
 	}
 	return s.users.Create(ctx, email, hash)
 }
 
+// validPassword проверяет пароль по правилам регистрации:
+// непустой, длиной >= 8 символов и принимается текущим алгоритмом хэширования
+// (bcrypt без pepper — не длиннее 72 байт). Пробелы по краям обрезаются заранее.
+func (s *AuthService) validPassword(password string) bool {
+	return password != "" && len(password) >= 8 && s.pass.Accepts(password)
+}
+
 // Login аутентифицирует пользователя и выдаёт пару токенов.
 //
 // Поведение:
-//   - не раскрывает факт существования email
+//   - не раскрывает факт существования email: для неизвестного email
+//     выполняется проверка пароля против dummy-хэша, чтобы ответ занимал
+//     столько же времени, а ошибка та же — ErrInvalidCredentials
+//   - при auth.lockout после неудачных попыток вход по этому email
+//     временно недоступен (*LoginLockedError), пароль при этом не проверяется
 //   - при успехе создаёт refresh-сессию
+//   - если у пользователя подключён TOTP, токены не выдаются: возвращается
+//     *MFARequiredError с challenge, который завершается через CompleteMFA
+//
+// Лимит сессий (auth.sessions.max_sessions_per_user):
+//   - limit_policy=reject — при достижении лимита логин отклоняется (ErrTooManySessions)
+//   - limit_policy=revoke_oldest — самые старые сессии отзываются
 //
 // Ошибки:
 //   - ErrInvalidInput
 //   - ErrInvalidCredentials
+//   - ErrTooManySessions
+//   - ErrClientCertRequired — к аккаунту привязан сертификат, а вход без него
+//   - ErrEmailNotVerified — при mail.require_verified email не подтверждён
+//   - ErrAccountDisabled — аккаунт заблокирован администратором
+//   - *LoginLockedError (errors.Is(err, ErrTooManyAttempts))
+//   - *BusyError (errors.Is(err, ErrServerBusy)) — очередь хэширования переполнена
+//   - *MFARequiredError (errors.Is(err, ErrMFARequired))
 func (s *AuthService) Login(ctx context.Context, email, password string) (TokenPair, error) {
 	email = strings.TrimSpace(strings.ToLower(email))
 	password = strings.TrimSpace(password)
 	if email == "" || password == "" {
 		return TokenPair{}, serr.ErrInvalidInput
 	}
+	// пауза после неудачных попыток или временная блокировка
+	if err := s.checkLockout(ctx, email); err != nil {
+		return TokenPair{}, err
+	}
 	// получаем юзера по email
 	userID, hash, err := s.users.GetByEmail(ctx, email)
 	if err != nil {
-		// не палим существование email
+		// не палим существование email ни ответом, ни временем ответа
 		if errors.Is(err, serr.ErrNotFound) {
-			return TokenPair{}, serr.ErrInvalidCredentials
+			if err := s.dummyVerify(ctx, password); err != nil {
+				return TokenPair{}, hashingError(err)
+			}
+			return TokenPair{}, s.loginFailed(ctx, email)
 		}
 		return TokenPair{}, err
 	}
 	// проверяем пароль
-	ok, err := crypto.VerifyPassword(password, hash)
+	ok, err := s.verifyPassword(ctx, password, hash)
 	if err != nil {
-		return TokenPair{}, serr.ErrInternal
+		return TokenPair{}, hashingError(err)
 	}
 	if !ok {
-		return TokenPair{}, serr.ErrInvalidCredentials
+		s.audit.Record(ctx, userID, models.AuditLoginFailed, uuid.Nil)
+		return TokenPair{}, s.loginFailed(ctx, email)
+	}
+	// счётчик неудач не сбрасываем до второго фактора: см. completeLogin
+	// пароль известен только сейчас — обновляем устаревший хэш
+	s.rehashIfNeeded(ctx, userID, password, hash)
+	// заблокированному аккаунту не выдаём и challenge второго фактора
+	if err := s.requireEnabled(ctx, userID); err != nil {
+		return TokenPair{}, err
+	}
+	// при mail.require_verified — только с подтверждённым email
+	if err := s.requireVerifiedEmail(ctx, userID); err != nil {
+		return TokenPair{}, err
 	}
-	// создаём новый access окен
-	access, err := crypto.NewAccessToken(userID.String(), s.jwt)
+	// при подключённом TOTP токены выдаются только после ввода кода
+	enabled, err := s.totpEnabled(ctx, userID)
 	if err != nil {
-		return TokenPair{}, serr.ErrInternal
+		return TokenPair{}, err
+	}
+	if enabled {
+		challenge, err := s.newMFAChallenge(ctx, userID)
+		if err != nil {
+			return TokenPair{}, err
+		}
+		return TokenPair{}, &MFARequiredError{Challenge: challenge}
+	}
+
+	return s.completeLogin(ctx, userID)
+}
+
+// completeLogin выдаёт токены по завершении входа (любым способом),
+// сбрасывает счётчик неудачных входов, записывает вход в журнал аудита
+// и при mail.notify_new_device сообщает письмом о входе с нового устройства.
+func (s *AuthService) completeLogin(ctx context.Context, userID uuid.UUID) (TokenPair, error) {
+	// проверяем до создания сессии: она сама сделает устройство известным
+	known := s.knownDevice(ctx, userID)
+
+	pair, err := s.issueTokens(ctx, userID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	// все факторы пройдены — только теперь сбрасываем счётчик неудач
+	if email, err := s.lockoutEmail(ctx, userID); err == nil {
+		s.loginSucceeded(ctx, email)
+	}
+	s.audit.Record(ctx, userID, models.AuditLogin, uuid.Nil)
+	s.recordLogin(ctx, userID)
+	if !known {
+		s.notifyNewDevice(ctx, userID)
+	}
+	return pair, nil
+}
+
+// issueTokens создаёт refresh-сессию и выдаёт пару токенов
+// пользователю, прошедшему аутентификацию.
+//
+// Лимит сессий проверяется здесь, а не при проверке пароля,
+// чтобы он соблюдался и при входе со вторым фактором.
+func (s *AuthService) issueTokens(ctx context.Context, userID uuid.UUID) (TokenPair, error) {
+	// заблокированный аккаунт не получает токены ни одним способом входа
+	if err := s.requireEnabled(ctx, userID); err != nil {
+		return TokenPair{}, err
+	}
+	// привязанный к аккаунту сертификат — второй фактор для любого способа входа
+	thumbprint, err := s.certBinding(ctx, userID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	// при политике reject проверяем лимит до выдачи токенов
+	if s.maxSessions > 0 && s.rejectOverLimit {
+		n, err := s.sessions.CountActive(ctx, userID)
+		if err != nil {
+			return TokenPair{}, err
+		}
+		if n >= s.maxSessions {
+			return TokenPair{}, serr.ErrTooManySessions
+		}
 	}
 	// создаём новый refresh токен
 	refresh, err := crypto.NewRefreshToken()
@@ -136,23 +445,57 @@ func (s *AuthService) Login(ctx context.Context, email, password string) (TokenP
 	// хэш для refresh токена
 	refreshHash := crypto.HashRefreshToken(refresh)
 	// создаём сессию
-	_, err = s.sessions.Create(ctx, userID, refreshHash, time.Now().Add(s.refreshTTL))
+	sessID, err := s.sessions.Create(ctx, userID, uuid.Nil, refreshHash, time.Now().Add(s.refreshTTL), ClientInfoFromContext(ctx))
 	if err != nil {
 		return TokenPair{}, err
 	}
+	// вытесняем самые старые сессии сверх лимита
+	if err := s.trimSessions(ctx, userID); err != nil {
+		return TokenPair{}, err
+	}
+	// создаём новый access токен, привязанный к сессии (и сертификату)
+	access, err := crypto.NewBoundAccessToken(userID.String(), sessID.String(), thumbprint, s.jwt)
+	if err != nil {
+		return TokenPair{}, serr.ErrInternal
+	}
 
 	return TokenPair{AccessToken: access, RefreshToken: refresh}, nil
 }
 
+// rehashIfNeeded пересчитывает хэш пароля, если он создан другим алгоритмом,
+// с устаревшими параметрами или без pepper.
+//
+// Ошибки не прерывают логин: пароль уже проверен,
+// а хэш будет обновлён при следующем входе.
+func (s *AuthService) rehashIfNeeded(ctx context.Context, userID uuid.UUID, password, hash string) {
+	if !s.pass.NeedsRehash(hash) {
+		return
+	}
+	newHash, err := s.hashPassword(ctx, password)
+	if err != nil {
+		return
+	}
+	_ = s.users.UpdatePasswordHash(ctx, userID, newHash)
+}
+
 // Refresh обновляет access токен по refresh токену.
 //
 // Поддерживает:
 //   - rotation refresh токенов
-//   - reuse detection (отзыв всех сессий при атаке)
+//   - reuse detection (отзыв семейства ротации при атаке)
+//
+// Все сессии, полученные ротацией от одного логина, образуют семейство (family_id).
+// Если предъявлен уже отозванный refresh-токен, отзывается только его семейство:
+// сессии пользователя на других устройствах не затрагиваются.
+//
+// Гонка двух запросов с одним токеном разрешается атомарной заменой сессии:
+// выигравший получает новую пару, проигравший считается повторным использованием.
 //
 // Ошибки:
 //   - ErrInvalidInput
 //   - ErrUnauthorized
+//   - ErrClientCertRequired — к аккаунту привязан сертификат, а запрос без него
+//   - ErrAccountDisabled — аккаунт заблокирован администратором
 func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (TokenPair, error) {
 	refreshToken = strings.TrimSpace(refreshToken)
 	if refreshToken == "" {
@@ -161,7 +504,7 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (TokenPa
 
 	hash := crypto.HashRefreshToken(refreshToken)
 
-	sessID, userID, expiresAt, revokedAt, _, err := s.sessions.GetByRefreshHash(ctx, hash) // пропускаю на что поменялась сессия т.к. логировать не собираюсь
+	sessID, userID, expiresAt, revokedAt, _, familyID, err := s.sessions.GetByRefreshHash(ctx, hash) // пропускаю на что поменялась сессия т.к. логировать не собираюсь
 	if err != nil {
 		return TokenPair{}, err
 	}
@@ -173,21 +516,31 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (TokenPa
 
 	// если токен уже отозван — значит кто-то пытается переиспользовать
 	if revokedAt != nil {
-		if s.reuseDetection {
-			if err := s.sessions.RevokeAllForUser(ctx, userID); err != nil {
-				return TokenPair{}, err
-			}
+		if err := s.handleReuse(ctx, userID, familyID, sessID); err != nil {
+			return TokenPair{}, err
 		}
 		return TokenPair{}, serr.ErrUnauthorized
 	}
 
-	access, err := crypto.NewAccessToken(userID.String(), s.jwt)
+	// сессии отзываются при блокировке, но проверяем и здесь:
+	// токен мог быть выдан в момент блокировки
+	if err := s.requireEnabled(ctx, userID); err != nil {
+		return TokenPair{}, err
+	}
+
+	// сертификат проверяется и при обновлении: сессии, созданные до привязки,
+	// без него не продлеваются
+	thumbprint, err := s.certBinding(ctx, userID)
 	if err != nil {
-		return TokenPair{}, serr.ErrInternal
+		return TokenPair{}, err
 	}
 
 	// если rotate_refresh выключен — возвращаем только новый access, refresh тот же
 	if !s.rotateRefresh {
+		access, err := crypto.NewBoundAccessToken(userID.String(), sessID.String(), thumbprint, s.jwt)
+		if err != nil {
+			return TokenPair{}, serr.ErrInternal
+		}
 		return TokenPair{AccessToken: access, RefreshToken: refreshToken}, nil
 	}
 
@@ -198,15 +551,242 @@ func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (TokenPa
 	}
 	newHash := crypto.HashRefreshToken(newRefresh)
 
-	newID, err := s.sessions.Create(ctx, userID, newHash, now.Add(s.refreshTTL))
+	newID, err := s.sessions.Create(ctx, userID, familyID, newHash, now.Add(s.refreshTTL), ClientInfoFromContext(ctx))
 	if err != nil {
 		return TokenPair{}, err
 	}
 
 	// пометить старый как revoked и связать с новым
 	if err := s.sessions.RevokeAndReplace(ctx, sessID, newID); err != nil {
+		if !errors.Is(err, serr.ErrConflict) {
+			return TokenPair{}, err
+		}
+		// параллельный запрос уже заменил сессию этим же токеном
+		if err := s.handleReuse(ctx, userID, familyID, sessID); err != nil {
+			return TokenPair{}, err
+		}
+		// без reuse detection семейство не отзывается — убираем только свою сессию
+		if !s.reuseDetection {
+			if err := s.sessions.Revoke(ctx, userID, newID); err != nil && !errors.Is(err, serr.ErrNotFound) {
+				return TokenPair{}, err
+			}
+		}
+		return TokenPair{}, serr.ErrUnauthorized
+	}
+	// ротация не увеличивает число сессий, но лимит могли уменьшить в конфиге
+	if err := s.trimSessions(ctx, userID); err != nil {
 		return TokenPair{}, err
 	}
 
+	access, err := crypto.NewBoundAccessToken(userID.String(), newID.String(), thumbprint, s.jwt)
+	if err != nil {
+		return TokenPair{}, serr.ErrInternal
+	}
+
 	return TokenPair{AccessToken: access, RefreshToken: newRefresh}, nil
 }
+
+// handleReuse реагирует на повторное использование refresh-токена:
+// пишет событие в журнал безопасности и, если включён reuse detection,
+// отзывает все сессии семейства, включая выданные ротацией после
+// скомпрометированного токена.
+func (s *AuthService) handleReuse(ctx context.Context, userID, familyID, sessID uuid.UUID) error {
+	s.securityEvent(ctx, "refresh_token_reuse",
+		zap.String("user_id", userID.String()),
+		zap.String("family_id", familyID.String()),
+		zap.String("session_id", sessID.String()),
+		zap.Bool("family_revoked", s.reuseDetection),
+	)
+	s.audit.Record(ctx, userID, models.AuditRefreshReuse, uuid.Nil)
+	if !s.reuseDetection {
+		return nil
+	}
+	if err := s.sessions.RevokeFamily(ctx, familyID); err != nil {
+		return err
+	}
+	s.syncRevocations(ctx)
+	return nil
+}
+
+// trimSessions отзывает самые старые активные сессии сверх max_sessions_per_user.
+//
+// При limit_policy=reject ничего не делает: лишние сессии там не создаются.
+func (s *AuthService) trimSessions(ctx context.Context, userID uuid.UUID) error {
+	if s.maxSessions <= 0 || s.rejectOverLimit {
+		return nil
+	}
+	if err := s.sessions.RevokeOldestActive(ctx, userID, s.maxSessions); err != nil {
+		return err
+	}
+	s.syncRevocations(ctx)
+	return nil
+}
+
+// Logout отзывает refresh-сессию, к которой относится refreshToken.
+//
+// Если all=true — отзываются все сессии пользователя (выход со всех устройств).
+// Повторный logout уже отозванным токеном без all считается успешным,
+// а вот выход со всех устройств по отозванному токену запрещён:
+// иначе старый утёкший токен позволял бы разлогинивать владельца.
+//
+// Ошибки:
+//   - ErrInvalidInput
+//   - ErrUnauthorized
+func (s *AuthService) Logout(ctx context.Context, refreshToken string, all bool) error {
+	refreshToken = strings.TrimSpace(refreshToken)
+	if refreshToken == "" {
+		return serr.ErrInvalidInput
+	}
+
+	sessID, userID, _, revokedAt, _, _, err := s.sessions.GetByRefreshHash(ctx, crypto.HashRefreshToken(refreshToken))
+	if err != nil {
+		return err
+	}
+
+	if revokedAt != nil {
+		if all {
+			return serr.ErrUnauthorized
+		}
+		return nil
+	}
+
+	if all {
+		err = s.sessions.RevokeAllForUser(ctx, userID)
+	} else {
+		err = s.sessions.Revoke(ctx, userID, sessID)
+		// сессию успели отозвать параллельным запросом — результат тот же
+		if errors.Is(err, serr.ErrNotFound) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	s.syncRevocations(ctx)
+	s.audit.Record(ctx, userID, models.AuditLogout, uuid.Nil)
+	return nil
+}
+
+// ListSessions возвращает активные refresh-сессии пользователя
+// вместе со сведениями об устройствах.
+func (s *AuthService) ListSessions(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	if userID == uuid.Nil {
+		return nil, serr.ErrUserIDEmpty
+	}
+	return s.sessions.ListActive(ctx, userID)
+}
+
+// Me возвращает сводную информацию об аккаунте пользователя.
+//
+// Ошибки:
+//   - ErrUserIDEmpty если userID не передан
+//   - ErrNotFound если пользователь удалён
+func (s *AuthService) Me(ctx context.Context, userID uuid.UUID) (models.Profile, error) {
+	if userID == uuid.Nil {
+		return models.Profile{}, serr.ErrUserIDEmpty
+	}
+	return s.users.GetProfile(ctx, userID)
+}
+
+// ChangePassword меняет пароль пользователя.
+//
+// Поведение:
+//   - старый пароль обязателен и проверяется по текущему хэшу
+//   - новый пароль проверяется по тем же правилам, что и при регистрации
+//   - новый хэш считается текущим алгоритмом с параметрами из конфига
+//   - все сессии пользователя отзываются (выход на других устройствах),
+//     а текущему устройству выдаётся новая пара токенов
+//   - все персональные API-токены отзываются
+//   - неверный старый пароль учитывается в счётчике auth.lockout, как при входе
+//
+// Ошибки:
+//   - ErrUserIDEmpty
+//   - ErrInvalidInput
+//   - ErrInvalidCredentials если старый пароль неверен
+//   - *LoginLockedError (errors.Is(err, ErrTooManyAttempts))
+func (s *AuthService) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) (TokenPair, error) {
+	if userID == uuid.Nil {
+		return TokenPair{}, serr.ErrUserIDEmpty
+	}
+	oldPassword = strings.TrimSpace(oldPassword)
+	newPassword = strings.TrimSpace(newPassword)
+	if oldPassword == "" || !s.validPassword(newPassword) {
+		return TokenPair{}, serr.ErrInvalidInput
+	}
+
+	email, err := s.lockoutEmail(ctx, userID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if err := s.checkLockout(ctx, email); err != nil {
+		return TokenPair{}, err
+	}
+
+	hash, err := s.users.GetPasswordHash(ctx, userID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	ok, err := s.verifyPassword(ctx, oldPassword, hash)
+	if err != nil {
+		return TokenPair{}, hashingError(err)
+	}
+	if !ok {
+		s.securityEvent(ctx, "password_change_failed", zap.String("user_id", userID.String()))
+		return TokenPair{}, s.loginFailed(ctx, email)
+	}
+	s.loginSucceeded(ctx, email)
+
+	newHash, err := s.hashPassword(ctx, newPassword)
+	if err != nil {
+		return TokenPair{}, hashingError(err)
+	}
+	if err := s.users.UpdatePasswordHash(ctx, userID, newHash); err != nil {
+		return TokenPair{}, err
+	}
+	// старый пароль мог утечь — выходим везде, включая текущую сессию
+	if err := s.sessions.RevokeAllForUser(ctx, userID); err != nil {
+		return TokenPair{}, err
+	}
+	s.syncRevocations(ctx)
+	if err := s.revokeAPITokens(ctx, userID); err != nil {
+		return TokenPair{}, err
+	}
+	s.securityEvent(ctx, "password_changed", zap.String("user_id", userID.String()))
+	s.audit.Record(ctx, userID, models.AuditPasswordChanged, uuid.Nil)
+
+	return s.issueTokens(ctx, userID)
+}
+
+// RevokeSession отзывает одну сессию пользователя по её ID
+// (например, сессию потерянного ноутбука).
+//
+// Ошибки:
+//   - ErrNotFound если сессии нет, она уже отозвана или чужая
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return serr.ErrUserIDEmpty
+	}
+	if err := s.sessions.Revoke(ctx, userID, sessionID); err != nil {
+		return err
+	}
+
+	s.syncRevocations(ctx)
+	s.audit.Record(ctx, userID, models.AuditSessionRevoked, uuid.Nil)
+	return nil
+}
+
+// PurgeExpiredSessions удаляет сессии, refresh-токен которых истёк
+// больше olderThan назад, и возвращает их число.
+//
+// После удаления вход с устройств этих сессий снова считается входом
+// с нового устройства (mail.notify_new_device).
+//
+// Ошибки:
+//   - ErrInvalidInput — olderThan отрицательный
+func (s *AuthService) PurgeExpiredSessions(ctx context.Context, olderThan time.Duration) (int64, error) {
+	if olderThan < 0 {
+		return 0, serr.ErrInvalidInput
+	}
+	return s.sessions.PurgeExpired(ctx, time.Now().Add(-olderThan))
+}
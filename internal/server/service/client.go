@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+)
+
+// clientInfoKey — ключ контекста для сведений о клиенте.
+type clientInfoKey struct{}
+
+// WithClientInfo возвращает контекст со сведениями о клиенте (устройство, UA, IP).
+//
+// Вызывается HTTP-слоем, сервисы читают значение через ClientInfoFromContext.
+func WithClientInfo(ctx context.Context, info models.ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoFromContext извлекает сведения о клиенте из контекста.
+//
+// Если их нет (например, вызов не из HTTP), возвращается пустая структура.
+func ClientInfoFromContext(ctx context.Context) models.ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(models.ClientInfo)
+	return info
+}
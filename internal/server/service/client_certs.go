@@ -0,0 +1,155 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// maxClientCertNameLen — ограничение длины имени привязанного сертификата.
+const maxClientCertNameLen = 100
+
+// UseClientCerts подключает привязку клиентских сертификатов к аккаунтам.
+//
+// roots — CA из tls.client_ca_file: привязать можно только выпущенный ими сертификат.
+// Без вызова привязка недоступна, а токены выдаются без проверки сертификата.
+func (s *AuthService) UseClientCerts(repo ClientCertsRepo, roots *x509.CertPool) {
+	s.clientCerts = repo
+	s.clientCAs = roots
+}
+
+// BindClientCert привязывает клиентский сертификат к аккаунту.
+//
+// После привязки первого сертификата вход, обновление токенов и вход по коду
+// устройства требуют mTLS с одним из привязанных сертификатов, а access-токены
+// принимаются только вместе с сертификатом, для которого выданы.
+//
+// Привязать можно только сертификат, предъявленный в TLS-рукопожатии этого же
+// запроса: так клиент доказывает, что владеет его закрытым ключом. Иначе
+// чужой сертификат можно было бы привязать и тем самым закрыть владельцу вход.
+//
+// Параметры:
+//   - certPEM — сертификат в PEM (закрытый ключ не нужен)
+//   - name — имя для списка (пусто — CN сертификата)
+//
+// Ошибки:
+//   - ErrUserIDEmpty
+//   - ErrNotFound — на сервере не включены клиентские сертификаты
+//   - ErrInvalidInput — сертификат не разобран, не выпущен доверенным CA или истёк
+//   - ErrClientCertRequired — запрос пришёл без mTLS или с другим сертификатом
+//   - ErrAlreadyExists — сертификат уже привязан (к этому или другому аккаунту)
+func (s *AuthService) BindClientCert(ctx context.Context, userID uuid.UUID, certPEM, name string) (models.ClientCert, error) {
+	if userID == uuid.Nil {
+		return models.ClientCert{}, serr.ErrUserIDEmpty
+	}
+	if s.clientCerts == nil {
+		return models.ClientCert{}, serr.ErrNotFound
+	}
+
+	cert, err := crypto.ParseClientCertPEM([]byte(certPEM), s.clientCAs, time.Now())
+	if err != nil {
+		return models.ClientCert{}, serr.ErrInvalidInput
+	}
+	fp := crypto.CertFingerprint(cert)
+	if presented := ClientInfoFromContext(ctx).CertFingerprint; len(presented) == 0 || !bytes.Equal(presented, fp) {
+		s.securityEvent(ctx, "client_cert_bind_rejected", zap.String("user_id", userID.String()))
+		return models.ClientCert{}, serr.ErrClientCertRequired
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = cert.Subject.CommonName
+	}
+	if name == "" || len(name) > maxClientCertNameLen {
+		return models.ClientCert{}, serr.ErrInvalidInput
+	}
+
+	bound, err := s.clientCerts.Create(ctx, models.ClientCert{
+		UserID:      userID,
+		Name:        name,
+		Subject:     cert.Subject.String(),
+		Fingerprint: fp,
+		NotAfter:    cert.NotAfter,
+	})
+	if err != nil {
+		return models.ClientCert{}, err
+	}
+
+	s.securityEvent(ctx, "client_cert_bound",
+		zap.String("user_id", userID.String()),
+		zap.String("cert_id", bound.ID.String()),
+		zap.String("subject", bound.Subject),
+	)
+	return bound, nil
+}
+
+// ListClientCerts возвращает сертификаты, привязанные к аккаунту.
+func (s *AuthService) ListClientCerts(ctx context.Context, userID uuid.UUID) ([]models.ClientCert, error) {
+	if userID == uuid.Nil {
+		return nil, serr.ErrUserIDEmpty
+	}
+	if s.clientCerts == nil {
+		return nil, nil
+	}
+	return s.clientCerts.ListByUser(ctx, userID)
+}
+
+// UnbindClientCert отвязывает сертификат от аккаунта.
+//
+// Когда отвязан последний сертификат, вход снова возможен без mTLS.
+//
+// Ошибки:
+//   - ErrNotFound — сертификата нет или он привязан к другому аккаунту
+func (s *AuthService) UnbindClientCert(ctx context.Context, userID, certID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return serr.ErrUserIDEmpty
+	}
+	if s.clientCerts == nil {
+		return serr.ErrNotFound
+	}
+	if err := s.clientCerts.Delete(ctx, userID, certID); err != nil {
+		return err
+	}
+
+	s.securityEvent(ctx, "client_cert_unbound",
+		zap.String("user_id", userID.String()),
+		zap.String("cert_id", certID.String()),
+	)
+	return nil
+}
+
+// certBinding проверяет клиентский сертификат запроса перед выдачей токенов.
+//
+// Возвращает отпечаток для claim cnf.x5t#S256 или пустую строку,
+// если к аккаунту не привязано ни одного сертификата.
+//
+// Возвращает ErrClientCertRequired, если сертификаты привязаны,
+// а запрос пришёл без одного из них.
+func (s *AuthService) certBinding(ctx context.Context, userID uuid.UUID) (string, error) {
+	if s.clientCerts == nil {
+		return "", nil
+	}
+
+	fp := ClientInfoFromContext(ctx).CertFingerprint
+	bound, matched, err := s.clientCerts.Match(ctx, userID, fp)
+	if err != nil {
+		return "", err
+	}
+	if !bound {
+		return "", nil
+	}
+	if !matched {
+		s.securityEvent(ctx, "client_cert_rejected", zap.String("user_id", userID.String()))
+		return "", serr.ErrClientCertRequired
+	}
+	return crypto.CertThumbprint(fp), nil
+}
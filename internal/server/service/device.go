@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+const (
+	// deviceSlowDownStep — на сколько растёт интервал опроса после slow_down (RFC 8628, 3.5).
+	deviceSlowDownStep = 5 * time.Second
+	// devicePollLeeway — допуск на сетевые задержки при проверке интервала опроса.
+	devicePollLeeway = time.Second
+	// userCodeAttempts — сколько раз генерировать user_code при совпадении с выданным.
+	userCodeAttempts = 3
+)
+
+// deviceSettings — параметры auth.device.
+type deviceSettings struct {
+	codeTTL      time.Duration
+	pollInterval time.Duration
+}
+
+// DeviceAuthorization — ответ на запрос входа по коду устройства.
+//
+// DeviceCode остаётся у headless-клиента и используется для опроса,
+// UserCode пользователь вводит на уже авторизованном устройстве.
+type DeviceAuthorization struct {
+	DeviceCode string
+	UserCode   string
+	ExpiresAt  time.Time
+	Interval   time.Duration
+}
+
+// UseDeviceCodes подключает хранилище входа по коду устройства.
+//
+// Без вызова вход по коду устройства недоступен.
+func (s *AuthService) UseDeviceCodes(repo DeviceCodesRepo) {
+	s.deviceCodes = repo
+}
+
+// StartDeviceAuthorization начинает вход по коду устройства (RFC 8628).
+//
+// В БД сохраняется SHA-256 device_code и user_code; клиент опрашивает
+// PollDeviceToken не чаще Interval, пока пользователь не подтвердит код.
+//
+// Возвращает ErrInternal — если вход по коду устройства не подключён или при ошибке хранилища.
+func (s *AuthService) StartDeviceAuthorization(ctx context.Context) (DeviceAuthorization, error) {
+	if s.deviceCodes == nil {
+		return DeviceAuthorization{}, serr.ErrInternal
+	}
+
+	deviceCode, err := crypto.NewRefreshToken()
+	if err != nil {
+		return DeviceAuthorization{}, serr.ErrInternal
+	}
+	expiresAt := time.Now().Add(s.device.codeTTL)
+
+	// user_code короткий, поэтому совпадение с ещё живым кодом возможно
+	for i := 0; i < userCodeAttempts; i++ {
+		userCode, err := crypto.NewUserCode()
+		if err != nil {
+			return DeviceAuthorization{}, serr.ErrInternal
+		}
+
+		err = s.deviceCodes.Create(ctx, crypto.HashRefreshToken(deviceCode), userCode, s.device.pollInterval, expiresAt)
+		if errors.Is(err, serr.ErrAlreadyExists) {
+			continue
+		}
+		if err != nil {
+			return DeviceAuthorization{}, err
+		}
+
+		return DeviceAuthorization{
+			DeviceCode: deviceCode,
+			UserCode:   userCode,
+			ExpiresAt:  expiresAt,
+			Interval:   s.device.pollInterval,
+		}, nil
+	}
+	return DeviceAuthorization{}, serr.ErrInternal
+}
+
+// DecideDevice подтверждает (approve=true) или отклоняет вход по user_code
+// от имени авторизованного пользователя.
+//
+// Ошибки:
+//   - ErrUserIDEmpty
+//   - ErrInvalidInput — код не похож на user_code
+//   - ErrNotFound — кода нет, он истёк или решение по нему уже принято
+func (s *AuthService) DecideDevice(ctx context.Context, userID uuid.UUID, userCode string, approve bool) error {
+	if userID == uuid.Nil {
+		return serr.ErrUserIDEmpty
+	}
+	code, ok := crypto.NormalizeUserCode(userCode)
+	if !ok {
+		return serr.ErrInvalidInput
+	}
+	if s.deviceCodes == nil {
+		return serr.ErrNotFound
+	}
+
+	if err := s.deviceCodes.Decide(ctx, code, userID, approve); err != nil {
+		return err
+	}
+
+	event := "device_approved"
+	if !approve {
+		event = "device_denied"
+	}
+	s.securityEvent(ctx, event, zap.String("user_id", userID.String()))
+	return nil
+}
+
+// PollDeviceToken обменивает подтверждённый device_code на пару токенов.
+//
+// Ошибки (RFC 8628, раздел 3.5):
+//   - ErrAuthorizationPending — пользователь ещё не подтвердил код
+//   - ErrSlowDown — опрос чаще интервала; интервал увеличивается на 5 секунд
+//   - ErrAccessDenied — пользователь отклонил запрос
+//   - ErrExpiredToken — код истёк
+//   - ErrInvalidGrant — код неизвестен или уже обменян на токены
+//   - ErrTooManySessions — при политике reject и превышении лимита сессий
+//   - ErrClientCertRequired — к аккаунту привязан сертификат, а опрос без него
+func (s *AuthService) PollDeviceToken(ctx context.Context, deviceCode string) (TokenPair, error) {
+	if deviceCode == "" || s.deviceCodes == nil {
+		return TokenPair{}, serr.ErrInvalidGrant
+	}
+
+	d, err := s.deviceCodes.Poll(ctx, crypto.HashRefreshToken(deviceCode))
+	if err != nil {
+		if errors.Is(err, serr.ErrNotFound) {
+			return TokenPair{}, serr.ErrInvalidGrant
+		}
+		return TokenPair{}, err
+	}
+
+	now := time.Now()
+	switch {
+	case d.Used:
+		return TokenPair{}, serr.ErrInvalidGrant
+	case !now.Before(d.ExpiresAt):
+		return TokenPair{}, serr.ErrExpiredToken
+	case d.Denied:
+		return TokenPair{}, serr.ErrAccessDenied
+	}
+
+	if d.LastPolledAt != nil && now.Sub(*d.LastPolledAt)+devicePollLeeway < d.Interval {
+		if err := s.deviceCodes.SlowDown(ctx, d.ID, deviceSlowDownStep); err != nil {
+			return TokenPair{}, err
+		}
+		return TokenPair{}, serr.ErrSlowDown
+	}
+	if !d.Approved {
+		return TokenPair{}, serr.ErrAuthorizationPending
+	}
+
+	// параллельный опрос мог обменять код раньше
+	if err := s.deviceCodes.Consume(ctx, d.ID); err != nil {
+		if errors.Is(err, serr.ErrNotFound) {
+			return TokenPair{}, serr.ErrInvalidGrant
+		}
+		return TokenPair{}, err
+	}
+
+	pair, err := s.completeLogin(ctx, d.UserID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	s.securityEvent(ctx, "device_login", zap.String("user_id", d.UserID.String()))
+	return pair, nil
+}
+
+// PruneDeviceCodes удаляет истёкшие запросы входа по коду устройства.
+func (s *AuthService) PruneDeviceCodes(ctx context.Context) error {
+	if s.deviceCodes == nil {
+		return nil
+	}
+	return s.deviceCodes.Prune(ctx)
+}
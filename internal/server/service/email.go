@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/mailer"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// mailSettings — параметры секции mail.
+type mailSettings struct {
+	verifyTTL       time.Duration
+	resetTTL        time.Duration
+	resendInterval  time.Duration
+	requireVerified bool
+	notifyNewDevice bool
+}
+
+func newMailSettings(cfg config.MailConfig) mailSettings {
+	return mailSettings{
+		verifyTTL:       cfg.VerifyTTL,
+		resetTTL:        cfg.ResetTTL,
+		resendInterval:  cfg.ResendInterval,
+		requireVerified: cfg.RequireVerified,
+		notifyNewDevice: cfg.NotifyNewDevice,
+	}
+}
+
+// UseMailer подключает служебные письма: подтверждение email при регистрации,
+// сброс пароля и уведомления о входе с нового устройства.
+//
+// onErr (может быть nil) получает ошибки отправки: письма отправляются
+// best-effort, и сбой почты не отменяет регистрацию или вход.
+// Без вызова подтверждение email и сброс пароля недоступны.
+func (s *AuthService) UseMailer(repo EmailTokensRepo, m mailer.Mailer, onErr func(error)) {
+	s.emailTokens = repo
+	s.mailer = m
+	s.mailErr = onErr
+}
+
+// VerifyEmail подтверждает email по токену из письма.
+//
+// Ошибки:
+//   - ErrNotFound — письма не настроены
+//   - ErrInvalidInput — токен пустой
+//   - ErrInvalidEmailToken — токен неизвестен, истёк или уже использован
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+	if s.mailer == nil {
+		return serr.ErrNotFound
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return serr.ErrInvalidInput
+	}
+
+	userID, err := s.consumeEmailToken(ctx, models.EmailTokenVerify, token)
+	if err != nil {
+		return err
+	}
+	if err := s.emailTokens.MarkVerified(ctx, userID); err != nil {
+		return err
+	}
+
+	s.securityEvent(ctx, "email_verified", zap.String("user_id", userID.String()))
+	return nil
+}
+
+// ResendVerification повторно отправляет письмо подтверждения email.
+//
+// Результат не зависит от того, есть ли аккаунт с таким email и подтверждён ли он,
+// и время ответа тоже: как и в RequestPasswordReset, поиск аккаунта
+// и отправка письма выполняются в фоне, их ошибки получает onErr из UseMailer.
+//
+// Ошибки:
+//   - ErrNotFound — письма не настроены
+//   - ErrInvalidInput — email пустой
+func (s *AuthService) ResendVerification(ctx context.Context, email string) error {
+	if s.mailer == nil {
+		return serr.ErrNotFound
+	}
+	email = strings.TrimSpace(strings.ToLower(email))
+	if email == "" {
+		return serr.ErrInvalidInput
+	}
+
+	s.goMail(ctx, func(ctx context.Context) {
+		if err := s.resendVerification(ctx, email); err != nil {
+			s.reportMailError(err)
+		}
+	})
+	return nil
+}
+
+// resendVerification отправляет письмо подтверждения,
+// если аккаунт с таким email существует и ещё не подтверждён.
+func (s *AuthService) resendVerification(ctx context.Context, email string) error {
+	userID, _, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, serr.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	_, verified, err := s.emailTokens.GetEmail(ctx, userID)
+	if err != nil || verified {
+		return err
+	}
+	return s.sendVerification(ctx, userID, email)
+}
+
+// RequestPasswordReset отправляет на email письмо с одноразовым токеном сброса пароля.
+//
+// Результат не зависит от того, есть ли аккаунт с таким email, и время ответа тоже:
+// поиск аккаунта, выпуск токена и отправка письма выполняются в фоне,
+// их ошибки получает onErr из UseMailer.
+// Токен живёт mail.reset_ttl; новый запрос отменяет прежний токен.
+//
+// Ошибки:
+//   - ErrNotFound — письма не настроены
+//   - ErrInvalidInput — email пустой
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	if s.mailer == nil {
+		return serr.ErrNotFound
+	}
+	email = strings.TrimSpace(strings.ToLower(email))
+	if email == "" {
+		return serr.ErrInvalidInput
+	}
+
+	s.goMail(ctx, func(ctx context.Context) {
+		if err := s.sendPasswordReset(ctx, email); err != nil {
+			s.reportMailError(err)
+		}
+	})
+	return nil
+}
+
+// sendPasswordReset выпускает токен сброса и отправляет письмо,
+// если аккаунт с таким email существует.
+func (s *AuthService) sendPasswordReset(ctx context.Context, email string) error {
+	userID, _, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, serr.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, ok, err := s.newEmailToken(ctx, userID, models.EmailTokenReset, s.mail.resetTTL)
+	if err != nil || !ok {
+		return err
+	}
+
+	s.securityEvent(ctx, "password_reset_requested", zap.String("user_id", userID.String()))
+	s.sendMail(ctx, email, "Reset your GophKeeper password", fmt.Sprintf(
+		"Someone (hopefully you) asked to reset the password of your GophKeeper account.\n\n"+
+			"To set a new password, run:\n\n"+
+			"    gophkeeper account reset-password --token %s\n\n"+
+			"The token is valid for %s and can be used once. All sessions will be signed out\n"+
+			"and all API tokens revoked.\n\n"+
+			"A new account password does NOT recover your secrets: they are encrypted on your\n"+
+			"devices with your master password, and the server cannot decrypt them.\n\n"+
+			"If you did not ask for this, ignore this email.\n",
+		token, s.mail.resetTTL,
+	))
+	return nil
+}
+
+// ResetPassword задаёт новый пароль по токену из письма сброса.
+//
+// Все сессии и API-токены пользователя отзываются: пароль сбрасывают
+// и при взломе аккаунта, а токен из письма мог получить тот, у кого был
+// доступ к почте, а не к устройствам. Email считается подтверждённым.
+// Содержимое хранилища сброс не затрагивает: секреты зашифрованы
+// на клиенте мастер-паролем, сервер их не расшифрует.
+//
+// Ошибки:
+//   - ErrNotFound — письма не настроены
+//   - ErrInvalidInput — токен пустой или пароль не проходит правила регистрации
+//   - ErrInvalidEmailToken — токен неизвестен, истёк или уже использован
+//   - *BusyError (errors.Is(err, ErrServerBusy)) — очередь хэширования переполнена
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if s.mailer == nil {
+		return serr.ErrNotFound
+	}
+	token = strings.TrimSpace(token)
+	newPassword = strings.TrimSpace(newPassword)
+	if token == "" || !s.validPassword(newPassword) {
+		return serr.ErrInvalidInput
+	}
+
+	userID, err := s.consumeEmailToken(ctx, models.EmailTokenReset, token)
+	if err != nil {
+		return err
+	}
+
+	hash, err := s.hashPassword(ctx, newPassword)
+	if err != nil {
+		return hashingError(err)
+	}
+	if err := s.users.UpdatePasswordHash(ctx, userID, hash); err != nil {
+		return err
+	}
+	if err := s.sessions.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+	s.syncRevocations(ctx)
+	if err := s.revokeAPITokens(ctx, userID); err != nil {
+		return err
+	}
+
+	// токен пришёл на этот адрес — владение email доказано
+	if err := s.emailTokens.MarkVerified(ctx, userID); err != nil {
+		return err
+	}
+
+	s.securityEvent(ctx, "password_reset", zap.String("user_id", userID.String()))
+	s.audit.Record(ctx, userID, models.AuditPasswordReset, uuid.Nil)
+
+	if email, _, err := s.emailTokens.GetEmail(ctx, userID); err == nil {
+		s.sendMail(ctx, email, "Your GophKeeper password was reset",
+			"The password of your GophKeeper account was reset, all sessions were signed out\n"+
+				"and all API tokens were revoked.\n\n"+
+				"If this was not you, reset the password again right away and check\n"+
+				"your email account: whoever did this could read your mail.\n")
+	}
+	return nil
+}
+
+// PruneEmailTokens удаляет истёкшие токены подтверждения email и сброса пароля.
+func (s *AuthService) PruneEmailTokens(ctx context.Context) error {
+	if s.emailTokens == nil {
+		return nil
+	}
+	return s.emailTokens.Prune(ctx)
+}
+
+// sendVerification выпускает токен подтверждения и отправляет письмо.
+// Если письмо отправлялось меньше mail.resend_interval назад, новое не отправляется.
+func (s *AuthService) sendVerification(ctx context.Context, userID uuid.UUID, email string) error {
+	token, ok, err := s.newEmailToken(ctx, userID, models.EmailTokenVerify, s.mail.verifyTTL)
+	if err != nil || !ok {
+		return err
+	}
+
+	s.sendMail(ctx, email, "Confirm your GophKeeper email", fmt.Sprintf(
+		"Welcome to GophKeeper!\n\n"+
+			"To confirm this email address, run:\n\n"+
+			"    gophkeeper account verify-email --token %s\n\n"+
+			"The token is valid for %s. A confirmed address lets you reset\n"+
+			"a forgotten account password.\n\n"+
+			"If you did not create an account, ignore this email.\n",
+		token, s.mail.verifyTTL,
+	))
+	return nil
+}
+
+// newEmailToken выпускает одноразовый токен назначения purpose.
+//
+// ok=false — токен этого назначения выпускался меньше mail.resend_interval назад,
+// письмо отправлять не нужно.
+func (s *AuthService) newEmailToken(ctx context.Context, userID uuid.UUID, purpose string, ttl time.Duration) (token string, ok bool, err error) {
+	token, err = crypto.NewRefreshToken()
+	if err != nil {
+		return "", false, serr.ErrInternal
+	}
+	err = s.emailTokens.Create(ctx, userID, purpose, crypto.HashRefreshToken(token), time.Now().Add(ttl), s.mail.resendInterval)
+	if err != nil {
+		if errors.Is(err, serr.ErrConflict) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return token, true, nil
+}
+
+// consumeEmailToken расходует токен из письма и возвращает его владельца.
+func (s *AuthService) consumeEmailToken(ctx context.Context, purpose, token string) (uuid.UUID, error) {
+	userID, err := s.emailTokens.Consume(ctx, purpose, crypto.HashRefreshToken(token))
+	if err != nil {
+		if errors.Is(err, serr.ErrNotFound) {
+			return uuid.Nil, serr.ErrInvalidEmailToken
+		}
+		return uuid.Nil, err
+	}
+	return userID, nil
+}
+
+// requireVerifiedEmail при mail.require_verified пропускает только пользователей
+// с подтверждённым email.
+func (s *AuthService) requireVerifiedEmail(ctx context.Context, userID uuid.UUID) error {
+	if s.mailer == nil || !s.mail.requireVerified {
+		return nil
+	}
+	_, verified, err := s.emailTokens.GetEmail(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return serr.ErrEmailNotVerified
+	}
+	return nil
+}
+
+// knownDevice сообщает, входил ли пользователь раньше с устройства из ClientInfo.
+//
+// Без уведомлений о новых устройствах и при ошибке хранилища
+// устройство считается известным: письмо не отправляется.
+func (s *AuthService) knownDevice(ctx context.Context, userID uuid.UUID) bool {
+	if s.mailer == nil || !s.mail.notifyNewDevice {
+		return true
+	}
+	client := ClientInfoFromContext(ctx)
+	known, err := s.sessions.KnownDevice(ctx, userID, client.DeviceName, client.UserAgent)
+	if err != nil {
+		s.reportMailError(err)
+		return true
+	}
+	return known
+}
+
+// notifyNewDevice отправляет письмо о входе с нового устройства.
+func (s *AuthService) notifyNewDevice(ctx context.Context, userID uuid.UUID) {
+	email, _, err := s.emailTokens.GetEmail(ctx, userID)
+	if err != nil {
+		s.reportMailError(err)
+		return
+	}
+
+	client := ClientInfoFromContext(ctx)
+	device := client.DeviceName
+	if device == "" {
+		device = "unknown"
+	}
+	s.sendMail(ctx, email, "New sign-in to your GophKeeper account", fmt.Sprintf(
+		"Your GophKeeper account was just used to sign in from a new device.\n\n"+
+			"    Time:       %s\n"+
+			"    Device:     %s\n"+
+			"    IP address: %s\n"+
+			"    Client:     %s\n\n"+
+			"If this was you, no action is needed.\n\n"+
+			"If not, change your password (gophkeeper account passwd) and sign the\n"+
+			"device out (gophkeeper sessions list, gophkeeper sessions revoke <id>).\n",
+		time.Now().UTC().Format(time.RFC1123), device, client.IP, client.UserAgent,
+	))
+}
+
+// backgroundMailTimeout ограничивает фоновую отправку письма:
+// контекст запроса к этому моменту уже отменён.
+const backgroundMailTimeout = 30 * time.Second
+
+// goMail выполняет fn в фоне. Контекст не отменяется вместе с запросом,
+// но сохраняет его значения (ClientInfo для журнала безопасности).
+func (s *AuthService) goMail(ctx context.Context, fn func(ctx context.Context)) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), backgroundMailTimeout)
+	s.mailWG.Add(1)
+	go func() {
+		defer s.mailWG.Done()
+		defer cancel()
+		fn(ctx)
+	}()
+}
+
+// WaitMail ждёт писем, отправляемых в фоне.
+// Вызывается при остановке сервера, после http.Server.Shutdown.
+func (s *AuthService) WaitMail() {
+	s.mailWG.Wait()
+}
+
+// sendMail отправляет письмо; ошибка передаётся в mailErr.
+func (s *AuthService) sendMail(ctx context.Context, to, subject, body string) {
+	err := s.mailer.Send(ctx, mailer.Message{To: to, Subject: subject, Body: body})
+	if err != nil {
+		s.reportMailError(err)
+	}
+}
+
+func (s *AuthService) reportMailError(err error) {
+	if s.mailErr != nil {
+		s.mailErr(err)
+	}
+}
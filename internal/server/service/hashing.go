@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/semaphore"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// minHashCostKiB — вес операции, если argon2 не настроен (bcrypt занимает единицы KiB).
+const minHashCostKiB = 4
+
+// BusyError возвращается, если хэширование пароля не дождалось своей
+// очереди за password.limits.queue_timeout.
+//
+// errors.Is(err, serr.ErrServerBusy) == true.
+type BusyError struct {
+	RetryAfter time.Duration
+}
+
+func (e *BusyError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", serr.ErrServerBusy, e.RetryAfter)
+}
+
+// Unwrap позволяет сравнивать ошибку с serr.ErrServerBusy.
+func (e *BusyError) Unwrap() error { return serr.ErrServerBusy }
+
+// HashingStats — состояние очереди хэширования паролей для мониторинга.
+type HashingStats struct {
+	CapacityKiB      int64   `json:"capacity_kib"`       // бюджет памяти
+	InUseKiB         int64   `json:"in_use_kib"`         // занято выполняющимися хэшированиями
+	QueueDepth       int64   `json:"queue_depth"`        // сколько запросов ждут сейчас
+	Acquired         int64   `json:"acquired"`           // сколько хэширований выполнено
+	Timeouts         int64   `json:"timeouts"`           // сколько запросов не дождались очереди
+	WaitSecondsTotal float64 `json:"wait_seconds_total"` // суммарное время ожидания
+	WaitSecondsMax   float64 `json:"wait_seconds_max"`   // максимальное время ожидания
+}
+
+// hashLimiter ограничивает память, одновременно занятую хэшированием паролей.
+//
+// Это взвешенный семафор: каждая операция занимает cost KiB из capacity KiB.
+type hashLimiter struct {
+	sem      *semaphore.Weighted
+	capacity int64
+	cost     int64
+	timeout  time.Duration
+
+	inUse    atomic.Int64
+	waiting  atomic.Int64
+	acquired atomic.Int64
+	timeouts atomic.Int64
+
+	mu        sync.Mutex
+	waitTotal time.Duration
+	waitMax   time.Duration
+}
+
+// newHashLimiter создаёт ограничитель по password.limits.
+// Возвращает nil, если бюджет не задан (без ограничений).
+func newHashLimiter(cfg config.PasswordConfig) *hashLimiter {
+	if cfg.Limits.MemoryBudgetMiB == 0 {
+		return nil
+	}
+	capacity := int64(cfg.Limits.MemoryBudgetMiB) * 1024
+	cost := max(int64(cfg.Argon2.MemoryKiB), minHashCostKiB)
+	// операция дороже бюджета не должна висеть вечно
+	cost = min(cost, capacity)
+
+	return &hashLimiter{
+		sem:      semaphore.NewWeighted(capacity),
+		capacity: capacity,
+		cost:     cost,
+		timeout:  cfg.Limits.QueueTimeout,
+	}
+}
+
+// acquire занимает место под одно хэширование.
+//
+// Ошибки:
+//   - *BusyError, если место не освободилось за timeout
+//   - ошибка ctx, если запрос отменён раньше
+func (l *hashLimiter) acquire(ctx context.Context) error {
+	if l.sem.TryAcquire(l.cost) {
+		l.acquired.Add(1)
+		l.inUse.Add(l.cost)
+		return nil
+	}
+
+	waitCtx := ctx
+	if l.timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, l.timeout)
+		defer cancel()
+	}
+
+	l.waiting.Add(1)
+	start := time.Now()
+	err := l.sem.Acquire(waitCtx, l.cost)
+	waited := time.Since(start)
+	l.waiting.Add(-1)
+	l.observeWait(waited)
+
+	if err != nil {
+		// клиент ушёл сам — это не перегрузка
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		l.timeouts.Add(1)
+		return &BusyError{RetryAfter: max(l.timeout, time.Second)}
+	}
+	l.acquired.Add(1)
+	l.inUse.Add(l.cost)
+	return nil
+}
+
+// release освобождает место, занятое acquire.
+func (l *hashLimiter) release() {
+	l.inUse.Add(-l.cost)
+	l.sem.Release(l.cost)
+}
+
+func (l *hashLimiter) observeWait(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.waitTotal += d
+	if d > l.waitMax {
+		l.waitMax = d
+	}
+}
+
+func (l *hashLimiter) stats() HashingStats {
+	l.mu.Lock()
+	waitTotal, waitMax := l.waitTotal, l.waitMax
+	l.mu.Unlock()
+
+	return HashingStats{
+		CapacityKiB:      l.capacity,
+		InUseKiB:         l.inUse.Load(),
+		QueueDepth:       l.waiting.Load(),
+		Acquired:         l.acquired.Load(),
+		Timeouts:         l.timeouts.Load(),
+		WaitSecondsTotal: waitTotal.Seconds(),
+		WaitSecondsMax:   waitMax.Seconds(),
+	}
+}
+
+// HashingStats возвращает состояние очереди хэширования паролей.
+// Без password.limits возвращает нулевую статистику.
+func (s *AuthService) HashingStats() HashingStats {
+	if s.hashLimit == nil {
+		return HashingStats{}
+	}
+	return s.hashLimit.stats()
+}
+
+// hashPassword хэширует пароль в пределах бюджета памяти.
+func (s *AuthService) hashPassword(ctx context.Context, password string) (string, error) {
+	if s.hashLimit != nil {
+		if err := s.hashLimit.acquire(ctx); err != nil {
+			return "", err
+		}
+		defer s.hashLimit.release()
+	}
+	return s.pass.Hash(password)
+}
+
+// verifyPassword проверяет пароль в пределах бюджета памяти.
+func (s *AuthService) verifyPassword(ctx context.Context, password, hash string) (bool, error) {
+	if s.hashLimit != nil {
+		if err := s.hashLimit.acquire(ctx); err != nil {
+			return false, err
+		}
+		defer s.hashLimit.release()
+	}
+	return s.pass.Verify(password, hash)
+}
+
+// hashingError оставляет как есть перегрузку и отмену запроса,
+// слишком длинный пароль превращает в ErrInvalidInput,
+// остальные ошибки хэшера — в ErrInternal.
+func hashingError(err error) error {
+	if errors.Is(err, crypto.ErrPasswordTooLong) {
+		return serr.ErrInvalidInput
+	}
+	if errors.Is(err, serr.ErrServerBusy) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return serr.ErrInternal
+}
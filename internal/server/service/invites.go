@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// inviteSettings — параметры auth.invites.
+type inviteSettings struct {
+	admins     []string // email в нижнем регистре
+	defaultTTL time.Duration
+	maxTTL     time.Duration
+	maxUses    int
+}
+
+func newInviteSettings(cfg config.InvitesConfig) inviteSettings {
+	admins := make([]string, 0, len(cfg.Admins))
+	for _, a := range cfg.Admins {
+		admins = append(admins, strings.TrimSpace(strings.ToLower(a)))
+	}
+	return inviteSettings{
+		admins:     admins,
+		defaultTTL: cfg.DefaultTTL,
+		maxTTL:     cfg.MaxTTL,
+		maxUses:    cfg.MaxUses,
+	}
+}
+
+// NewInvite — выпущенное приглашение. Code возвращается только один раз.
+type NewInvite struct {
+	Code string
+	models.Invite
+}
+
+// UseInvites подключает хранилище кодов приглашений.
+//
+// Без вызова регистрация в режиме auth.registration=invite невозможна.
+func (s *AuthService) UseInvites(repo InvitesRepo) {
+	s.invites = repo
+}
+
+// CreateInvite выпускает код приглашения.
+//
+// Параметры:
+//   - maxUses — сколько регистраций возможно по коду (0 — одна, не больше auth.invites.max_uses)
+//   - ttl — срок действия (0 — auth.invites.default_ttl, не больше max_ttl)
+//
+// Код возвращается только здесь: в БД сохраняется лишь его SHA-256.
+//
+// Ошибки:
+//   - ErrUserIDEmpty
+//   - ErrForbidden — пользователя нет в auth.invites.admins и он не администратор
+//   - ErrInvalidInput — неверное число использований или срок
+func (s *AuthService) CreateInvite(ctx context.Context, userID uuid.UUID, maxUses int, ttl time.Duration) (NewInvite, error) {
+	if err := s.requireInviteAdmin(ctx, userID); err != nil {
+		return NewInvite{}, err
+	}
+
+	if maxUses == 0 {
+		maxUses = 1
+	}
+	if maxUses < 0 || maxUses > s.invite.maxUses {
+		return NewInvite{}, serr.ErrInvalidInput
+	}
+	if ttl == 0 {
+		ttl = s.invite.defaultTTL
+	}
+	if ttl <= 0 || ttl > s.invite.maxTTL {
+		return NewInvite{}, serr.ErrInvalidInput
+	}
+
+	code, err := crypto.NewRefreshToken()
+	if err != nil {
+		return NewInvite{}, serr.ErrInternal
+	}
+	inv, err := s.invites.Create(ctx, userID, crypto.HashRefreshToken(code), maxUses, time.Now().Add(ttl))
+	if err != nil {
+		return NewInvite{}, err
+	}
+
+	s.securityEvent(ctx, "invite_created",
+		zap.String("user_id", userID.String()),
+		zap.String("invite_id", inv.ID.String()),
+		zap.Int("max_uses", inv.MaxUses),
+		zap.Time("expires_at", inv.ExpiresAt),
+	)
+	return NewInvite{Code: code, Invite: inv}, nil
+}
+
+// ListInvites возвращает действующие приглашения (всех администраторов).
+//
+// Ошибки:
+//   - ErrForbidden — пользователя нет в auth.invites.admins и он не администратор
+func (s *AuthService) ListInvites(ctx context.Context, userID uuid.UUID) ([]models.Invite, error) {
+	if err := s.requireInviteAdmin(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.invites.ListActive(ctx)
+}
+
+// RevokeInvite отзывает приглашение: по нему больше нельзя зарегистрироваться.
+//
+// Ошибки:
+//   - ErrForbidden — пользователя нет в auth.invites.admins и он не администратор
+//   - ErrNotFound — приглашения нет
+func (s *AuthService) RevokeInvite(ctx context.Context, userID, inviteID uuid.UUID) error {
+	if err := s.requireInviteAdmin(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.invites.Delete(ctx, inviteID); err != nil {
+		return err
+	}
+
+	s.securityEvent(ctx, "invite_revoked",
+		zap.String("user_id", userID.String()),
+		zap.String("invite_id", inviteID.String()),
+	)
+	return nil
+}
+
+// requireInviteAdmin проверяет, что пользователь может управлять приглашениями.
+func (s *AuthService) requireInviteAdmin(ctx context.Context, userID uuid.UUID) error {
+	if userID == uuid.Nil {
+		return serr.ErrUserIDEmpty
+	}
+	if s.invites == nil {
+		return serr.ErrInternal
+	}
+	profile, err := s.users.GetProfile(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(s.invite.admins, strings.ToLower(profile.Email)) {
+		return nil
+	}
+	// администраторы сервера (users.is_admin) управляют приглашениями без записи в конфиге
+	admin, err := s.isAdmin(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !admin {
+		return serr.ErrForbidden
+	}
+	return nil
+}
+
+// registerWithInvite проверяет код и атомарно расходует его при создании пользователя.
+func (s *AuthService) registerWithInvite(ctx context.Context, email, password, invite string) (uuid.UUID, error) {
+	invite = strings.TrimSpace(invite)
+	if invite == "" || s.invites == nil {
+		return uuid.Nil, serr.ErrInvalidInvite
+	}
+	codeHash := crypto.HashRefreshToken(invite)
+	// заведомо неверный код не должен стоить серверу хэширования пароля
+	if err := s.invites.Check(ctx, codeHash); err != nil {
+		return uuid.Nil, err
+	}
+
+	hash, err := s.hashPassword(ctx, password)
+	if err != nil {
+		return uuid.Nil, hashingError(err)
+	}
+	userID, err := s.invites.CreateUser(ctx, codeHash, email, hash)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	s.securityEvent(ctx, "invite_used", zap.String("user_id", userID.String()))
+	return userID, nil
+}
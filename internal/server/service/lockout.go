@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// lockoutSettings — параметры auth.lockout.
+type lockoutSettings struct {
+	maxFailures int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	duration    time.Duration
+}
+
+func newLockoutSettings(cfg config.LockoutConfig) lockoutSettings {
+	return lockoutSettings{
+		maxFailures: cfg.MaxFailures,
+		baseDelay:   cfg.BaseDelay,
+		maxDelay:    cfg.MaxDelay,
+		duration:    cfg.Duration,
+	}
+}
+
+// blockedFor возвращает, сколько после последней неудачи вход недоступен.
+//
+// После k-й неудачи подряд — baseDelay * 2^(k-1), но не больше maxDelay;
+// начиная с maxFailures — duration (временная блокировка).
+func (l lockoutSettings) blockedFor(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+	if l.maxFailures > 0 && failures >= l.maxFailures {
+		return l.duration
+	}
+	d := l.baseDelay
+	for i := 1; i < failures && d < l.maxDelay; i++ {
+		d *= 2
+	}
+	if d > l.maxDelay {
+		d = l.maxDelay
+	}
+	return d
+}
+
+// LoginLockedError возвращается из Login, пока для email действует
+// задержка после неудачных попыток или временная блокировка.
+//
+// errors.Is(err, serr.ErrTooManyAttempts) == true.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", serr.ErrTooManyAttempts, e.RetryAfter)
+}
+
+// Unwrap позволяет сравнивать ошибку с serr.ErrTooManyAttempts.
+func (e *LoginLockedError) Unwrap() error { return serr.ErrTooManyAttempts }
+
+// UseLoginAttempts подключает счётчики неудачных входов (auth.lockout).
+//
+// Без вызова Login не ограничивает число попыток для аккаунта
+// (остаётся только rate limit по IP).
+func (s *AuthService) UseLoginAttempts(repo LoginAttemptsRepo) {
+	s.attempts = repo
+}
+
+// checkLockout возвращает *LoginLockedError, если после последней
+// неудачной попытки для email ещё не прошла положенная пауза.
+func (s *AuthService) checkLockout(ctx context.Context, email string) error {
+	if s.attempts == nil {
+		return nil
+	}
+	failures, last, err := s.attempts.Get(ctx, email)
+	if err != nil {
+		if errors.Is(err, serr.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if wait := time.Until(last.Add(s.lockout.blockedFor(failures))); wait > 0 {
+		return &LoginLockedError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// loginFailed учитывает неудачную попытку входа и возвращает ErrInvalidCredentials.
+//
+// Ответ одинаков для неизвестного email и неверного пароля,
+// блокировка тоже ведётся по email независимо от его существования.
+func (s *AuthService) loginFailed(ctx context.Context, email string) error {
+	if err := s.recordFailure(ctx, email); err != nil {
+		return err
+	}
+	return serr.ErrInvalidCredentials
+}
+
+// recordFailure увеличивает счётчик неудач для email.
+//
+// Используется и для неверного второго фактора: иначе, зная пароль,
+// можно перебирать TOTP-коды, каждый раз начиная вход заново.
+func (s *AuthService) recordFailure(ctx context.Context, email string) error {
+	if s.attempts == nil {
+		return nil
+	}
+	failures, _, err := s.attempts.RecordFailure(ctx, email, time.Now().Add(-s.lockout.duration))
+	if err != nil {
+		return err
+	}
+	if failures == s.lockout.maxFailures {
+		s.securityEvent(ctx, "login_locked",
+			zap.String("email", email),
+			zap.Int("failures", failures),
+			zap.Duration("duration", s.lockout.duration),
+		)
+	}
+	return nil
+}
+
+// loginSucceeded сбрасывает счётчик неудач, когда пройдены все факторы.
+// Ошибка не критична: счётчик сам устареет через auth.lockout.duration.
+func (s *AuthService) loginSucceeded(ctx context.Context, email string) {
+	if s.attempts == nil {
+		return
+	}
+	_ = s.attempts.Reset(ctx, email)
+}
+
+// lockoutEmail возвращает email пользователя — ключ счётчика неудач —
+// для шагов, где известен только userID (второй фактор, повторная аутентификация).
+// Без auth.lockout возвращает пустую строку, не обращаясь к БД.
+func (s *AuthService) lockoutEmail(ctx context.Context, userID uuid.UUID) (string, error) {
+	if s.attempts == nil {
+		return "", nil
+	}
+	profile, err := s.users.GetProfile(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return profile.Email, nil
+}
+
+// PruneLoginAttempts удаляет устаревшие счётчики неудачных входов.
+func (s *AuthService) PruneLoginAttempts(ctx context.Context) error {
+	if s.attempts == nil {
+		return nil
+	}
+	return s.attempts.Prune(ctx, time.Now().Add(-s.lockout.duration))
+}
+
+// dummyHash — хэш случайного пароля с текущими параметрами хэшера.
+// Проверяется, когда email не найден, чтобы ответ занимал столько же
+// времени, сколько проверка настоящего пароля.
+type dummyHash struct {
+	once sync.Once
+	hash string
+}
+
+// dummyVerify выполняет проверку пароля против dummy-хэша.
+// Результат не важен — важно время выполнения и очередь хэширования:
+// при перегрузке ответ тот же, что и для существующего email.
+func (s *AuthService) dummyVerify(ctx context.Context, password string) error {
+	s.dummy.once.Do(func() {
+		random, err := crypto.NewRefreshToken()
+		if err != nil {
+			return
+		}
+		s.dummy.hash, _ = s.pass.Hash(random)
+	})
+	if s.dummy.hash == "" {
+		return nil
+	}
+	_, err := s.verifyPassword(ctx, password, s.dummy.hash)
+	return err
+}
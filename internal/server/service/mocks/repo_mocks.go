@@ -45,6 +45,20 @@ func (m *MockUsersRepo) EXPECT() *MockUsersRepoMockRecorder {
 	return m.recorder
 }
 
+// CancelDeletion mocks base method.
+func (m *MockUsersRepo) CancelDeletion(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelDeletion", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelDeletion indicates an expected call of CancelDeletion.
+func (mr *MockUsersRepoMockRecorder) CancelDeletion(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelDeletion", reflect.TypeOf((*MockUsersRepo)(nil).CancelDeletion), ctx, userID)
+}
+
 // Create mocks base method.
 func (m *MockUsersRepo) Create(ctx context.Context, email, passwordHash string) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
@@ -76,6 +90,96 @@ func (mr *MockUsersRepoMockRecorder) GetByEmail(ctx, email any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockUsersRepo)(nil).GetByEmail), ctx, email)
 }
 
+// GetPasswordHash mocks base method.
+func (m *MockUsersRepo) GetPasswordHash(ctx context.Context, userID uuid.UUID) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPasswordHash", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPasswordHash indicates an expected call of GetPasswordHash.
+func (mr *MockUsersRepoMockRecorder) GetPasswordHash(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPasswordHash", reflect.TypeOf((*MockUsersRepo)(nil).GetPasswordHash), ctx, userID)
+}
+
+// GetPendingDeletion mocks base method.
+func (m *MockUsersRepo) GetPendingDeletion(ctx context.Context, email string) (uuid.UUID, string, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingDeletion", ctx, email)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(time.Time)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// GetPendingDeletion indicates an expected call of GetPendingDeletion.
+func (mr *MockUsersRepoMockRecorder) GetPendingDeletion(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingDeletion", reflect.TypeOf((*MockUsersRepo)(nil).GetPendingDeletion), ctx, email)
+}
+
+// GetProfile mocks base method.
+func (m *MockUsersRepo) GetProfile(ctx context.Context, userID uuid.UUID) (models.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", ctx, userID)
+	ret0, _ := ret[0].(models.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockUsersRepoMockRecorder) GetProfile(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockUsersRepo)(nil).GetProfile), ctx, userID)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockUsersRepo) PurgeDeleted(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockUsersRepoMockRecorder) PurgeDeleted(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockUsersRepo)(nil).PurgeDeleted), ctx)
+}
+
+// ScheduleDeletion mocks base method.
+func (m *MockUsersRepo) ScheduleDeletion(ctx context.Context, userID uuid.UUID, deleteAfter time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduleDeletion", ctx, userID, deleteAfter)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ScheduleDeletion indicates an expected call of ScheduleDeletion.
+func (mr *MockUsersRepoMockRecorder) ScheduleDeletion(ctx, userID, deleteAfter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduleDeletion", reflect.TypeOf((*MockUsersRepo)(nil).ScheduleDeletion), ctx, userID, deleteAfter)
+}
+
+// UpdatePasswordHash mocks base method.
+func (m *MockUsersRepo) UpdatePasswordHash(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePasswordHash", ctx, userID, passwordHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdatePasswordHash indicates an expected call of UpdatePasswordHash.
+func (mr *MockUsersRepoMockRecorder) UpdatePasswordHash(ctx, userID, passwordHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePasswordHash", reflect.TypeOf((*MockUsersRepo)(nil).UpdatePasswordHash), ctx, userID, passwordHash)
+}
+
 // MockSessionsRepo is a mock of SessionsRepo interface.
 type MockSessionsRepo struct {
 	ctrl     *gomock.Controller
@@ -100,23 +204,38 @@ func (m *MockSessionsRepo) EXPECT() *MockSessionsRepoMockRecorder {
 	return m.recorder
 }
 
+// CountActive mocks base method.
+func (m *MockSessionsRepo) CountActive(ctx context.Context, userID uuid.UUID) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActive", ctx, userID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActive indicates an expected call of CountActive.
+func (mr *MockSessionsRepoMockRecorder) CountActive(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActive", reflect.TypeOf((*MockSessionsRepo)(nil).CountActive), ctx, userID)
+}
+
 // Create mocks base method.
-func (m *MockSessionsRepo) Create(ctx context.Context, userID uuid.UUID, refreshHash []byte, expiresAt time.Time) (uuid.UUID, error) {
+func (m *MockSessionsRepo) Create(ctx context.Context, userID, familyID uuid.UUID, refreshHash []byte, expiresAt time.Time, client models.ClientInfo) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, userID, refreshHash, expiresAt)
+	ret := m.ctrl.Call(m, "Create", ctx, userID, familyID, refreshHash, expiresAt, client)
 	ret0, _ := ret[0].(uuid.UUID)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockSessionsRepoMockRecorder) Create(ctx, userID, refreshHash, expiresAt any) *gomock.Call {
+func (mr *MockSessionsRepoMockRecorder) Create(ctx, userID, familyID, refreshHash, expiresAt, client any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionsRepo)(nil).Create), ctx, userID, refreshHash, expiresAt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSessionsRepo)(nil).Create), ctx, userID, familyID, refreshHash, expiresAt, client)
 }
 
 // GetByRefreshHash mocks base method.
-func (m *MockSessionsRepo) GetByRefreshHash(ctx context.Context, refreshHash []byte) (uuid.UUID, uuid.UUID, time.Time, *time.Time, *uuid.UUID, error) {
+func (m *MockSessionsRepo) GetByRefreshHash(ctx context.Context, refreshHash []byte) (uuid.UUID, uuid.UUID, time.Time, *time.Time, *uuid.UUID, uuid.UUID, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetByRefreshHash", ctx, refreshHash)
 	ret0, _ := ret[0].(uuid.UUID)
@@ -124,8 +243,9 @@ func (m *MockSessionsRepo) GetByRefreshHash(ctx context.Context, refreshHash []b
 	ret2, _ := ret[2].(time.Time)
 	ret3, _ := ret[3].(*time.Time)
 	ret4, _ := ret[4].(*uuid.UUID)
-	ret5, _ := ret[5].(error)
-	return ret0, ret1, ret2, ret3, ret4, ret5
+	ret5, _ := ret[5].(uuid.UUID)
+	ret6, _ := ret[6].(error)
+	return ret0, ret1, ret2, ret3, ret4, ret5, ret6
 }
 
 // GetByRefreshHash indicates an expected call of GetByRefreshHash.
@@ -134,6 +254,80 @@ func (mr *MockSessionsRepoMockRecorder) GetByRefreshHash(ctx, refreshHash any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByRefreshHash", reflect.TypeOf((*MockSessionsRepo)(nil).GetByRefreshHash), ctx, refreshHash)
 }
 
+// KnownDevice mocks base method.
+func (m *MockSessionsRepo) KnownDevice(ctx context.Context, userID uuid.UUID, deviceName, userAgent string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KnownDevice", ctx, userID, deviceName, userAgent)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// KnownDevice indicates an expected call of KnownDevice.
+func (mr *MockSessionsRepoMockRecorder) KnownDevice(ctx, userID, deviceName, userAgent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KnownDevice", reflect.TypeOf((*MockSessionsRepo)(nil).KnownDevice), ctx, userID, deviceName, userAgent)
+}
+
+// ListActive mocks base method.
+func (m *MockSessionsRepo) ListActive(ctx context.Context, userID uuid.UUID) ([]models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActive", ctx, userID)
+	ret0, _ := ret[0].([]models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActive indicates an expected call of ListActive.
+func (mr *MockSessionsRepoMockRecorder) ListActive(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActive", reflect.TypeOf((*MockSessionsRepo)(nil).ListActive), ctx, userID)
+}
+
+// ListRevokedSince mocks base method.
+func (m *MockSessionsRepo) ListRevokedSince(ctx context.Context, since time.Time) ([]models.RevokedSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRevokedSince", ctx, since)
+	ret0, _ := ret[0].([]models.RevokedSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRevokedSince indicates an expected call of ListRevokedSince.
+func (mr *MockSessionsRepoMockRecorder) ListRevokedSince(ctx, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRevokedSince", reflect.TypeOf((*MockSessionsRepo)(nil).ListRevokedSince), ctx, since)
+}
+
+// PurgeExpired mocks base method.
+func (m *MockSessionsRepo) PurgeExpired(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockSessionsRepoMockRecorder) PurgeExpired(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockSessionsRepo)(nil).PurgeExpired), ctx, before)
+}
+
+// Revoke mocks base method.
+func (m *MockSessionsRepo) Revoke(ctx context.Context, userID, sessionID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockSessionsRepoMockRecorder) Revoke(ctx, userID, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockSessionsRepo)(nil).Revoke), ctx, userID, sessionID)
+}
+
 // RevokeAllForUser mocks base method.
 func (m *MockSessionsRepo) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
 	m.ctrl.T.Helper()
@@ -162,59 +356,1057 @@ func (mr *MockSessionsRepoMockRecorder) RevokeAndReplace(ctx, oldID, newID any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAndReplace", reflect.TypeOf((*MockSessionsRepo)(nil).RevokeAndReplace), ctx, oldID, newID)
 }
 
-// MockSecretsRepo is a mock of SecretsRepo interface.
-type MockSecretsRepo struct {
+// RevokeFamily mocks base method.
+func (m *MockSessionsRepo) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamily", ctx, familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFamily indicates an expected call of RevokeFamily.
+func (mr *MockSessionsRepoMockRecorder) RevokeFamily(ctx, familyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamily", reflect.TypeOf((*MockSessionsRepo)(nil).RevokeFamily), ctx, familyID)
+}
+
+// RevokeOldestActive mocks base method.
+func (m *MockSessionsRepo) RevokeOldestActive(ctx context.Context, userID uuid.UUID, keep int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOldestActive", ctx, userID, keep)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeOldestActive indicates an expected call of RevokeOldestActive.
+func (mr *MockSessionsRepoMockRecorder) RevokeOldestActive(ctx, userID, keep any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOldestActive", reflect.TypeOf((*MockSessionsRepo)(nil).RevokeOldestActive), ctx, userID, keep)
+}
+
+// MockTwoFactorRepo is a mock of TwoFactorRepo interface.
+type MockTwoFactorRepo struct {
 	ctrl     *gomock.Controller
-	recorder *MockSecretsRepoMockRecorder
+	recorder *MockTwoFactorRepoMockRecorder
 	isgomock struct{}
 }
 
-// MockSecretsRepoMockRecorder is the mock recorder for MockSecretsRepo.
-type MockSecretsRepoMockRecorder struct {
-	mock *MockSecretsRepo
+// MockTwoFactorRepoMockRecorder is the mock recorder for MockTwoFactorRepo.
+type MockTwoFactorRepoMockRecorder struct {
+	mock *MockTwoFactorRepo
 }
 
-// NewMockSecretsRepo creates a new mock instance.
-func NewMockSecretsRepo(ctrl *gomock.Controller) *MockSecretsRepo {
-	mock := &MockSecretsRepo{ctrl: ctrl}
-	mock.recorder = &MockSecretsRepoMockRecorder{mock}
+// NewMockTwoFactorRepo creates a new mock instance.
+func NewMockTwoFactorRepo(ctrl *gomock.Controller) *MockTwoFactorRepo {
+	mock := &MockTwoFactorRepo{ctrl: ctrl}
+	mock.recorder = &MockTwoFactorRepoMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockSecretsRepo) EXPECT() *MockSecretsRepoMockRecorder {
+func (m *MockTwoFactorRepo) EXPECT() *MockTwoFactorRepoMockRecorder {
 	return m.recorder
 }
 
-// Create mocks base method.
-func (m *MockSecretsRepo) Create(ctx context.Context, userID uuid.UUID, typ service.SecretType, title, payload string, meta *string) (uuid.UUID, int, time.Time, error) {
+// ChallengeAttempt mocks base method.
+func (m *MockTwoFactorRepo) ChallengeAttempt(ctx context.Context, tokenHash []byte, maxAttempts int) (uuid.UUID, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, userID, typ, title, payload, meta)
+	ret := m.ctrl.Call(m, "ChallengeAttempt", ctx, tokenHash, maxAttempts)
 	ret0, _ := ret[0].(uuid.UUID)
-	ret1, _ := ret[1].(int)
-	ret2, _ := ret[2].(time.Time)
-	ret3, _ := ret[3].(error)
-	return ret0, ret1, ret2, ret3
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// Create indicates an expected call of Create.
-func (mr *MockSecretsRepoMockRecorder) Create(ctx, userID, typ, title, payload, meta any) *gomock.Call {
+// ChallengeAttempt indicates an expected call of ChallengeAttempt.
+func (mr *MockTwoFactorRepoMockRecorder) ChallengeAttempt(ctx, tokenHash, maxAttempts any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSecretsRepo)(nil).Create), ctx, userID, typ, title, payload, meta)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChallengeAttempt", reflect.TypeOf((*MockTwoFactorRepo)(nil).ChallengeAttempt), ctx, tokenHash, maxAttempts)
 }
 
-// DeleteSecret mocks base method.
-func (m *MockSecretsRepo) DeleteSecret(ctx context.Context, userID, secretID uuid.UUID, version int) error {
+// CompleteChallenge mocks base method.
+func (m *MockTwoFactorRepo) CompleteChallenge(ctx context.Context, tokenHash []byte) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteSecret", ctx, userID, secretID, version)
+	ret := m.ctrl.Call(m, "CompleteChallenge", ctx, tokenHash)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteSecret indicates an expected call of DeleteSecret.
-func (mr *MockSecretsRepoMockRecorder) DeleteSecret(ctx, userID, secretID, version any) *gomock.Call {
+// CompleteChallenge indicates an expected call of CompleteChallenge.
+func (mr *MockTwoFactorRepoMockRecorder) CompleteChallenge(ctx, tokenHash any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecret", reflect.TypeOf((*MockSecretsRepo)(nil).DeleteSecret), ctx, userID, secretID, version)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteChallenge", reflect.TypeOf((*MockTwoFactorRepo)(nil).CompleteChallenge), ctx, tokenHash)
+}
+
+// CreateChallenge mocks base method.
+func (m *MockTwoFactorRepo) CreateChallenge(ctx context.Context, userID uuid.UUID, tokenHash []byte, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChallenge", ctx, userID, tokenHash, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateChallenge indicates an expected call of CreateChallenge.
+func (mr *MockTwoFactorRepoMockRecorder) CreateChallenge(ctx, userID, tokenHash, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChallenge", reflect.TypeOf((*MockTwoFactorRepo)(nil).CreateChallenge), ctx, userID, tokenHash, expiresAt)
+}
+
+// EnableTOTP mocks base method.
+func (m *MockTwoFactorRepo) EnableTOTP(ctx context.Context, userID uuid.UUID, step int64, recoveryHashes [][]byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTOTP", ctx, userID, step, recoveryHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableTOTP indicates an expected call of EnableTOTP.
+func (mr *MockTwoFactorRepoMockRecorder) EnableTOTP(ctx, userID, step, recoveryHashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTOTP", reflect.TypeOf((*MockTwoFactorRepo)(nil).EnableTOTP), ctx, userID, step, recoveryHashes)
+}
+
+// GetTOTP mocks base method.
+func (m *MockTwoFactorRepo) GetTOTP(ctx context.Context, userID uuid.UUID) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTOTP", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTOTP indicates an expected call of GetTOTP.
+func (mr *MockTwoFactorRepoMockRecorder) GetTOTP(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTOTP", reflect.TypeOf((*MockTwoFactorRepo)(nil).GetTOTP), ctx, userID)
+}
+
+// SaveTOTPSecret mocks base method.
+func (m *MockTwoFactorRepo) SaveTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveTOTPSecret", ctx, userID, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveTOTPSecret indicates an expected call of SaveTOTPSecret.
+func (mr *MockTwoFactorRepoMockRecorder) SaveTOTPSecret(ctx, userID, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTOTPSecret", reflect.TypeOf((*MockTwoFactorRepo)(nil).SaveTOTPSecret), ctx, userID, secret)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockTwoFactorRepo) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", ctx, userID, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockTwoFactorRepoMockRecorder) UseRecoveryCode(ctx, userID, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockTwoFactorRepo)(nil).UseRecoveryCode), ctx, userID, codeHash)
+}
+
+// UseTOTPStep mocks base method.
+func (m *MockTwoFactorRepo) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTOTPStep", ctx, userID, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseTOTPStep indicates an expected call of UseTOTPStep.
+func (mr *MockTwoFactorRepoMockRecorder) UseTOTPStep(ctx, userID, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTOTPStep", reflect.TypeOf((*MockTwoFactorRepo)(nil).UseTOTPStep), ctx, userID, step)
+}
+
+// MockLoginAttemptsRepo is a mock of LoginAttemptsRepo interface.
+type MockLoginAttemptsRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockLoginAttemptsRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockLoginAttemptsRepoMockRecorder is the mock recorder for MockLoginAttemptsRepo.
+type MockLoginAttemptsRepoMockRecorder struct {
+	mock *MockLoginAttemptsRepo
+}
+
+// NewMockLoginAttemptsRepo creates a new mock instance.
+func NewMockLoginAttemptsRepo(ctrl *gomock.Controller) *MockLoginAttemptsRepo {
+	mock := &MockLoginAttemptsRepo{ctrl: ctrl}
+	mock.recorder = &MockLoginAttemptsRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLoginAttemptsRepo) EXPECT() *MockLoginAttemptsRepoMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockLoginAttemptsRepo) Get(ctx context.Context, email string) (int, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, email)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockLoginAttemptsRepoMockRecorder) Get(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockLoginAttemptsRepo)(nil).Get), ctx, email)
+}
+
+// Prune mocks base method.
+func (m *MockLoginAttemptsRepo) Prune(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Prune indicates an expected call of Prune.
+func (mr *MockLoginAttemptsRepoMockRecorder) Prune(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockLoginAttemptsRepo)(nil).Prune), ctx, before)
+}
+
+// RecordFailure mocks base method.
+func (m *MockLoginAttemptsRepo) RecordFailure(ctx context.Context, email string, resetBefore time.Time) (int, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, email, resetBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockLoginAttemptsRepoMockRecorder) RecordFailure(ctx, email, resetBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockLoginAttemptsRepo)(nil).RecordFailure), ctx, email, resetBefore)
+}
+
+// Reset mocks base method.
+func (m *MockLoginAttemptsRepo) Reset(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reset", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reset indicates an expected call of Reset.
+func (mr *MockLoginAttemptsRepoMockRecorder) Reset(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reset", reflect.TypeOf((*MockLoginAttemptsRepo)(nil).Reset), ctx, email)
+}
+
+// MockAPITokensRepo is a mock of APITokensRepo interface.
+type MockAPITokensRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAPITokensRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockAPITokensRepoMockRecorder is the mock recorder for MockAPITokensRepo.
+type MockAPITokensRepoMockRecorder struct {
+	mock *MockAPITokensRepo
+}
+
+// NewMockAPITokensRepo creates a new mock instance.
+func NewMockAPITokensRepo(ctrl *gomock.Controller) *MockAPITokensRepo {
+	mock := &MockAPITokensRepo{ctrl: ctrl}
+	mock.recorder = &MockAPITokensRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAPITokensRepo) EXPECT() *MockAPITokensRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockAPITokensRepo) Create(ctx context.Context, userID uuid.UUID, name string, tokenHash []byte, scopes, types []string, expiresAt time.Time) (uuid.UUID, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, name, tokenHash, scopes, types, expiresAt)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockAPITokensRepoMockRecorder) Create(ctx, userID, name, tokenHash, scopes, types, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockAPITokensRepo)(nil).Create), ctx, userID, name, tokenHash, scopes, types, expiresAt)
+}
+
+// ListByUser mocks base method.
+func (m *MockAPITokensRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]models.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockAPITokensRepoMockRecorder) ListByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockAPITokensRepo)(nil).ListByUser), ctx, userID)
+}
+
+// Revoke mocks base method.
+func (m *MockAPITokensRepo) Revoke(ctx context.Context, userID, tokenID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoke", ctx, userID, tokenID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Revoke indicates an expected call of Revoke.
+func (mr *MockAPITokensRepoMockRecorder) Revoke(ctx, userID, tokenID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockAPITokensRepo)(nil).Revoke), ctx, userID, tokenID)
+}
+
+// RevokeAllForUser mocks base method.
+func (m *MockAPITokensRepo) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAllForUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAllForUser indicates an expected call of RevokeAllForUser.
+func (mr *MockAPITokensRepoMockRecorder) RevokeAllForUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAllForUser", reflect.TypeOf((*MockAPITokensRepo)(nil).RevokeAllForUser), ctx, userID)
+}
+
+// Use mocks base method.
+func (m *MockAPITokensRepo) Use(ctx context.Context, tokenHash []byte) (models.APIToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Use", ctx, tokenHash)
+	ret0, _ := ret[0].(models.APIToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Use indicates an expected call of Use.
+func (mr *MockAPITokensRepoMockRecorder) Use(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Use", reflect.TypeOf((*MockAPITokensRepo)(nil).Use), ctx, tokenHash)
+}
+
+// MockDeviceCodesRepo is a mock of DeviceCodesRepo interface.
+type MockDeviceCodesRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeviceCodesRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockDeviceCodesRepoMockRecorder is the mock recorder for MockDeviceCodesRepo.
+type MockDeviceCodesRepoMockRecorder struct {
+	mock *MockDeviceCodesRepo
+}
+
+// NewMockDeviceCodesRepo creates a new mock instance.
+func NewMockDeviceCodesRepo(ctrl *gomock.Controller) *MockDeviceCodesRepo {
+	mock := &MockDeviceCodesRepo{ctrl: ctrl}
+	mock.recorder = &MockDeviceCodesRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeviceCodesRepo) EXPECT() *MockDeviceCodesRepoMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockDeviceCodesRepo) Consume(ctx context.Context, id uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockDeviceCodesRepoMockRecorder) Consume(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockDeviceCodesRepo)(nil).Consume), ctx, id)
+}
+
+// Create mocks base method.
+func (m *MockDeviceCodesRepo) Create(ctx context.Context, deviceCodeHash []byte, userCode string, interval time.Duration, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, deviceCodeHash, userCode, interval, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockDeviceCodesRepoMockRecorder) Create(ctx, deviceCodeHash, userCode, interval, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockDeviceCodesRepo)(nil).Create), ctx, deviceCodeHash, userCode, interval, expiresAt)
+}
+
+// Decide mocks base method.
+func (m *MockDeviceCodesRepo) Decide(ctx context.Context, userCode string, userID uuid.UUID, approve bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decide", ctx, userCode, userID, approve)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Decide indicates an expected call of Decide.
+func (mr *MockDeviceCodesRepoMockRecorder) Decide(ctx, userCode, userID, approve any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decide", reflect.TypeOf((*MockDeviceCodesRepo)(nil).Decide), ctx, userCode, userID, approve)
+}
+
+// Poll mocks base method.
+func (m *MockDeviceCodesRepo) Poll(ctx context.Context, deviceCodeHash []byte) (models.DeviceCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Poll", ctx, deviceCodeHash)
+	ret0, _ := ret[0].(models.DeviceCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Poll indicates an expected call of Poll.
+func (mr *MockDeviceCodesRepoMockRecorder) Poll(ctx, deviceCodeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Poll", reflect.TypeOf((*MockDeviceCodesRepo)(nil).Poll), ctx, deviceCodeHash)
+}
+
+// Prune mocks base method.
+func (m *MockDeviceCodesRepo) Prune(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Prune indicates an expected call of Prune.
+func (mr *MockDeviceCodesRepoMockRecorder) Prune(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockDeviceCodesRepo)(nil).Prune), ctx)
+}
+
+// SlowDown mocks base method.
+func (m *MockDeviceCodesRepo) SlowDown(ctx context.Context, id uuid.UUID, step time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SlowDown", ctx, id, step)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SlowDown indicates an expected call of SlowDown.
+func (mr *MockDeviceCodesRepoMockRecorder) SlowDown(ctx, id, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SlowDown", reflect.TypeOf((*MockDeviceCodesRepo)(nil).SlowDown), ctx, id, step)
+}
+
+// MockClientCertsRepo is a mock of ClientCertsRepo interface.
+type MockClientCertsRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockClientCertsRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockClientCertsRepoMockRecorder is the mock recorder for MockClientCertsRepo.
+type MockClientCertsRepoMockRecorder struct {
+	mock *MockClientCertsRepo
+}
+
+// NewMockClientCertsRepo creates a new mock instance.
+func NewMockClientCertsRepo(ctrl *gomock.Controller) *MockClientCertsRepo {
+	mock := &MockClientCertsRepo{ctrl: ctrl}
+	mock.recorder = &MockClientCertsRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockClientCertsRepo) EXPECT() *MockClientCertsRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockClientCertsRepo) Create(ctx context.Context, cert models.ClientCert) (models.ClientCert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, cert)
+	ret0, _ := ret[0].(models.ClientCert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockClientCertsRepoMockRecorder) Create(ctx, cert any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockClientCertsRepo)(nil).Create), ctx, cert)
+}
+
+// Delete mocks base method.
+func (m *MockClientCertsRepo) Delete(ctx context.Context, userID, certID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userID, certID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockClientCertsRepoMockRecorder) Delete(ctx, userID, certID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockClientCertsRepo)(nil).Delete), ctx, userID, certID)
+}
+
+// ListByUser mocks base method.
+func (m *MockClientCertsRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.ClientCert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListByUser", ctx, userID)
+	ret0, _ := ret[0].([]models.ClientCert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListByUser indicates an expected call of ListByUser.
+func (mr *MockClientCertsRepoMockRecorder) ListByUser(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListByUser", reflect.TypeOf((*MockClientCertsRepo)(nil).ListByUser), ctx, userID)
+}
+
+// Match mocks base method.
+func (m *MockClientCertsRepo) Match(ctx context.Context, userID uuid.UUID, fingerprint []byte) (bool, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Match", ctx, userID, fingerprint)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Match indicates an expected call of Match.
+func (mr *MockClientCertsRepoMockRecorder) Match(ctx, userID, fingerprint any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Match", reflect.TypeOf((*MockClientCertsRepo)(nil).Match), ctx, userID, fingerprint)
+}
+
+// MockOIDCIdentitiesRepo is a mock of OIDCIdentitiesRepo interface.
+type MockOIDCIdentitiesRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockOIDCIdentitiesRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockOIDCIdentitiesRepoMockRecorder is the mock recorder for MockOIDCIdentitiesRepo.
+type MockOIDCIdentitiesRepoMockRecorder struct {
+	mock *MockOIDCIdentitiesRepo
+}
+
+// NewMockOIDCIdentitiesRepo creates a new mock instance.
+func NewMockOIDCIdentitiesRepo(ctrl *gomock.Controller) *MockOIDCIdentitiesRepo {
+	mock := &MockOIDCIdentitiesRepo{ctrl: ctrl}
+	mock.recorder = &MockOIDCIdentitiesRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOIDCIdentitiesRepo) EXPECT() *MockOIDCIdentitiesRepoMockRecorder {
+	return m.recorder
+}
+
+// CreateUser mocks base method.
+func (m *MockOIDCIdentitiesRepo) CreateUser(ctx context.Context, issuer, subject, email, passwordHash string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, issuer, subject, email, passwordHash)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockOIDCIdentitiesRepoMockRecorder) CreateUser(ctx, issuer, subject, email, passwordHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockOIDCIdentitiesRepo)(nil).CreateUser), ctx, issuer, subject, email, passwordHash)
+}
+
+// GetUser mocks base method.
+func (m *MockOIDCIdentitiesRepo) GetUser(ctx context.Context, issuer, subject string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, issuer, subject)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockOIDCIdentitiesRepoMockRecorder) GetUser(ctx, issuer, subject any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockOIDCIdentitiesRepo)(nil).GetUser), ctx, issuer, subject)
+}
+
+// Link mocks base method.
+func (m *MockOIDCIdentitiesRepo) Link(ctx context.Context, issuer, subject, email string, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Link", ctx, issuer, subject, email, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Link indicates an expected call of Link.
+func (mr *MockOIDCIdentitiesRepoMockRecorder) Link(ctx, issuer, subject, email, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Link", reflect.TypeOf((*MockOIDCIdentitiesRepo)(nil).Link), ctx, issuer, subject, email, userID)
+}
+
+// MockInvitesRepo is a mock of InvitesRepo interface.
+type MockInvitesRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockInvitesRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockInvitesRepoMockRecorder is the mock recorder for MockInvitesRepo.
+type MockInvitesRepoMockRecorder struct {
+	mock *MockInvitesRepo
+}
+
+// NewMockInvitesRepo creates a new mock instance.
+func NewMockInvitesRepo(ctrl *gomock.Controller) *MockInvitesRepo {
+	mock := &MockInvitesRepo{ctrl: ctrl}
+	mock.recorder = &MockInvitesRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInvitesRepo) EXPECT() *MockInvitesRepoMockRecorder {
+	return m.recorder
+}
+
+// Check mocks base method.
+func (m *MockInvitesRepo) Check(ctx context.Context, codeHash []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Check", ctx, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Check indicates an expected call of Check.
+func (mr *MockInvitesRepoMockRecorder) Check(ctx, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Check", reflect.TypeOf((*MockInvitesRepo)(nil).Check), ctx, codeHash)
+}
+
+// Create mocks base method.
+func (m *MockInvitesRepo) Create(ctx context.Context, createdBy uuid.UUID, codeHash []byte, maxUses int, expiresAt time.Time) (models.Invite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, createdBy, codeHash, maxUses, expiresAt)
+	ret0, _ := ret[0].(models.Invite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockInvitesRepoMockRecorder) Create(ctx, createdBy, codeHash, maxUses, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockInvitesRepo)(nil).Create), ctx, createdBy, codeHash, maxUses, expiresAt)
+}
+
+// CreateUser mocks base method.
+func (m *MockInvitesRepo) CreateUser(ctx context.Context, codeHash []byte, email, passwordHash string) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, codeHash, email, passwordHash)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockInvitesRepoMockRecorder) CreateUser(ctx, codeHash, email, passwordHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockInvitesRepo)(nil).CreateUser), ctx, codeHash, email, passwordHash)
+}
+
+// Delete mocks base method.
+func (m *MockInvitesRepo) Delete(ctx context.Context, inviteID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, inviteID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockInvitesRepoMockRecorder) Delete(ctx, inviteID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockInvitesRepo)(nil).Delete), ctx, inviteID)
+}
+
+// ListActive mocks base method.
+func (m *MockInvitesRepo) ListActive(ctx context.Context) ([]models.Invite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListActive", ctx)
+	ret0, _ := ret[0].([]models.Invite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListActive indicates an expected call of ListActive.
+func (mr *MockInvitesRepoMockRecorder) ListActive(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListActive", reflect.TypeOf((*MockInvitesRepo)(nil).ListActive), ctx)
+}
+
+// MockEmailTokensRepo is a mock of EmailTokensRepo interface.
+type MockEmailTokensRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockEmailTokensRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockEmailTokensRepoMockRecorder is the mock recorder for MockEmailTokensRepo.
+type MockEmailTokensRepoMockRecorder struct {
+	mock *MockEmailTokensRepo
+}
+
+// NewMockEmailTokensRepo creates a new mock instance.
+func NewMockEmailTokensRepo(ctrl *gomock.Controller) *MockEmailTokensRepo {
+	mock := &MockEmailTokensRepo{ctrl: ctrl}
+	mock.recorder = &MockEmailTokensRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockEmailTokensRepo) EXPECT() *MockEmailTokensRepoMockRecorder {
+	return m.recorder
+}
+
+// Consume mocks base method.
+func (m *MockEmailTokensRepo) Consume(ctx context.Context, purpose string, tokenHash []byte) (uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Consume", ctx, purpose, tokenHash)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Consume indicates an expected call of Consume.
+func (mr *MockEmailTokensRepoMockRecorder) Consume(ctx, purpose, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockEmailTokensRepo)(nil).Consume), ctx, purpose, tokenHash)
+}
+
+// Create mocks base method.
+func (m *MockEmailTokensRepo) Create(ctx context.Context, userID uuid.UUID, purpose string, tokenHash []byte, expiresAt time.Time, minInterval time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, purpose, tokenHash, expiresAt, minInterval)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockEmailTokensRepoMockRecorder) Create(ctx, userID, purpose, tokenHash, expiresAt, minInterval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockEmailTokensRepo)(nil).Create), ctx, userID, purpose, tokenHash, expiresAt, minInterval)
+}
+
+// GetEmail mocks base method.
+func (m *MockEmailTokensRepo) GetEmail(ctx context.Context, userID uuid.UUID) (string, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmail", ctx, userID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetEmail indicates an expected call of GetEmail.
+func (mr *MockEmailTokensRepoMockRecorder) GetEmail(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmail", reflect.TypeOf((*MockEmailTokensRepo)(nil).GetEmail), ctx, userID)
+}
+
+// MarkVerified mocks base method.
+func (m *MockEmailTokensRepo) MarkVerified(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkVerified", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkVerified indicates an expected call of MarkVerified.
+func (mr *MockEmailTokensRepoMockRecorder) MarkVerified(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkVerified", reflect.TypeOf((*MockEmailTokensRepo)(nil).MarkVerified), ctx, userID)
+}
+
+// Prune mocks base method.
+func (m *MockEmailTokensRepo) Prune(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Prune indicates an expected call of Prune.
+func (mr *MockEmailTokensRepoMockRecorder) Prune(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockEmailTokensRepo)(nil).Prune), ctx)
+}
+
+// MockAuditRepo is a mock of AuditRepo interface.
+type MockAuditRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAuditRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockAuditRepoMockRecorder is the mock recorder for MockAuditRepo.
+type MockAuditRepoMockRecorder struct {
+	mock *MockAuditRepo
+}
+
+// NewMockAuditRepo creates a new mock instance.
+func NewMockAuditRepo(ctrl *gomock.Controller) *MockAuditRepo {
+	mock := &MockAuditRepo{ctrl: ctrl}
+	mock.recorder = &MockAuditRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAuditRepo) EXPECT() *MockAuditRepoMockRecorder {
+	return m.recorder
+}
+
+// Append mocks base method.
+func (m *MockAuditRepo) Append(ctx context.Context, e models.AuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Append", ctx, e)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Append indicates an expected call of Append.
+func (mr *MockAuditRepoMockRecorder) Append(ctx, e any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Append", reflect.TypeOf((*MockAuditRepo)(nil).Append), ctx, e)
+}
+
+// List mocks base method.
+func (m *MockAuditRepo) List(ctx context.Context, userID uuid.UUID, f models.AuditFilter) ([]models.AuditEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userID, f)
+	ret0, _ := ret[0].([]models.AuditEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockAuditRepoMockRecorder) List(ctx, userID, f any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockAuditRepo)(nil).List), ctx, userID, f)
+}
+
+// Prune mocks base method.
+func (m *MockAuditRepo) Prune(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prune", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Prune indicates an expected call of Prune.
+func (mr *MockAuditRepoMockRecorder) Prune(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prune", reflect.TypeOf((*MockAuditRepo)(nil).Prune), ctx, before)
+}
+
+// MockAdminRepo is a mock of AdminRepo interface.
+type MockAdminRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockAdminRepoMockRecorder is the mock recorder for MockAdminRepo.
+type MockAdminRepoMockRecorder struct {
+	mock *MockAdminRepo
+}
+
+// NewMockAdminRepo creates a new mock instance.
+func NewMockAdminRepo(ctrl *gomock.Controller) *MockAdminRepo {
+	mock := &MockAdminRepo{ctrl: ctrl}
+	mock.recorder = &MockAdminRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminRepo) EXPECT() *MockAdminRepoMockRecorder {
+	return m.recorder
+}
+
+// GetStatus mocks base method.
+func (m *MockAdminRepo) GetStatus(ctx context.Context, userID uuid.UUID) (models.UserStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatus", ctx, userID)
+	ret0, _ := ret[0].(models.UserStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatus indicates an expected call of GetStatus.
+func (mr *MockAdminRepoMockRecorder) GetStatus(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatus", reflect.TypeOf((*MockAdminRepo)(nil).GetStatus), ctx, userID)
+}
+
+// ListUsers mocks base method.
+func (m *MockAdminRepo) ListUsers(ctx context.Context, limit, offset int) ([]models.AdminUser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx, limit, offset)
+	ret0, _ := ret[0].([]models.AdminUser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockAdminRepoMockRecorder) ListUsers(ctx, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockAdminRepo)(nil).ListUsers), ctx, limit, offset)
+}
+
+// RecordLogin mocks base method.
+func (m *MockAdminRepo) RecordLogin(ctx context.Context, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLogin", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordLogin indicates an expected call of RecordLogin.
+func (mr *MockAdminRepoMockRecorder) RecordLogin(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLogin", reflect.TypeOf((*MockAdminRepo)(nil).RecordLogin), ctx, userID)
+}
+
+// SetAdmin mocks base method.
+func (m *MockAdminRepo) SetAdmin(ctx context.Context, userID uuid.UUID, isAdmin bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAdmin", ctx, userID, isAdmin)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetAdmin indicates an expected call of SetAdmin.
+func (mr *MockAdminRepoMockRecorder) SetAdmin(ctx, userID, isAdmin any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAdmin", reflect.TypeOf((*MockAdminRepo)(nil).SetAdmin), ctx, userID, isAdmin)
+}
+
+// SetDisabled mocks base method.
+func (m *MockAdminRepo) SetDisabled(ctx context.Context, userID uuid.UUID, disabled bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDisabled", ctx, userID, disabled)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDisabled indicates an expected call of SetDisabled.
+func (mr *MockAdminRepoMockRecorder) SetDisabled(ctx, userID, disabled any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDisabled", reflect.TypeOf((*MockAdminRepo)(nil).SetDisabled), ctx, userID, disabled)
+}
+
+// StorageStats mocks base method.
+func (m *MockAdminRepo) StorageStats(ctx context.Context) (models.StorageStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StorageStats", ctx)
+	ret0, _ := ret[0].(models.StorageStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StorageStats indicates an expected call of StorageStats.
+func (mr *MockAdminRepoMockRecorder) StorageStats(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StorageStats", reflect.TypeOf((*MockAdminRepo)(nil).StorageStats), ctx)
+}
+
+// MockSecretsRepo is a mock of SecretsRepo interface.
+type MockSecretsRepo struct {
+	ctrl     *gomock.Controller
+	recorder *MockSecretsRepoMockRecorder
+	isgomock struct{}
+}
+
+// MockSecretsRepoMockRecorder is the mock recorder for MockSecretsRepo.
+type MockSecretsRepoMockRecorder struct {
+	mock *MockSecretsRepo
+}
+
+// NewMockSecretsRepo creates a new mock instance.
+func NewMockSecretsRepo(ctrl *gomock.Controller) *MockSecretsRepo {
+	mock := &MockSecretsRepo{ctrl: ctrl}
+	mock.recorder = &MockSecretsRepoMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSecretsRepo) EXPECT() *MockSecretsRepoMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockSecretsRepo) Create(ctx context.Context, userID uuid.UUID, typ service.SecretType, title, payload string, meta *string) (uuid.UUID, int, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userID, typ, title, payload, meta)
+	ret0, _ := ret[0].(uuid.UUID)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(time.Time)
+	ret3, _ := ret[3].(error)
+	return ret0, ret1, ret2, ret3
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockSecretsRepoMockRecorder) Create(ctx, userID, typ, title, payload, meta any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockSecretsRepo)(nil).Create), ctx, userID, typ, title, payload, meta)
+}
+
+// DeleteSecret mocks base method.
+func (m *MockSecretsRepo) DeleteSecret(ctx context.Context, userID, secretID uuid.UUID, version int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSecret", ctx, userID, secretID, version)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSecret indicates an expected call of DeleteSecret.
+func (mr *MockSecretsRepoMockRecorder) DeleteSecret(ctx, userID, secretID, version any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSecret", reflect.TypeOf((*MockSecretsRepo)(nil).DeleteSecret), ctx, userID, secretID, version)
+}
+
+// GetType mocks base method.
+func (m *MockSecretsRepo) GetType(ctx context.Context, userID, secretID uuid.UUID) (service.SecretType, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetType", ctx, userID, secretID)
+	ret0, _ := ret[0].(service.SecretType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetType indicates an expected call of GetType.
+func (mr *MockSecretsRepoMockRecorder) GetType(ctx, userID, secretID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetType", reflect.TypeOf((*MockSecretsRepo)(nil).GetType), ctx, userID, secretID)
 }
 
 // ListSecrets mocks base method.
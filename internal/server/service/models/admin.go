@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserStatus — роль и блокировка аккаунта.
+type UserStatus struct {
+	IsAdmin         bool
+	Disabled        bool
+	PendingDeletion bool // аккаунт помечен к удалению (DELETE /me)
+}
+
+// AdminUser — пользователь в списке администратора.
+type AdminUser struct {
+	ID          uuid.UUID
+	Email       string
+	IsAdmin     bool
+	DisabledAt  *time.Time // nil — аккаунт активен
+	CreatedAt   time.Time
+	SecretCount int
+	LastLoginAt *time.Time // nil — ещё не входил
+}
+
+// StorageStats — сводная статистика хранилища.
+type StorageStats struct {
+	Users          int64
+	DisabledUsers  int64
+	Secrets        int64
+	SecretBytes    int64 // payload + meta всех секретов
+	ActiveSessions int64
+}
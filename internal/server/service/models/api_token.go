@@ -0,0 +1,38 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scopes персональных API-токенов.
+const (
+	ScopeSecretsRead  = "secrets:read"  // чтение секретов
+	ScopeSecretsWrite = "secrets:write" // создание и изменение секретов (удаление требует reauth и API-токену недоступно)
+)
+
+// APIToken — персональный API-токен пользователя (без самого токена и его хэша).
+//
+// Types ограничивает типы секретов, доступных токену; пустой список — любые типы.
+type APIToken struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	Scopes     []string
+	Types      []string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+}
+
+// HasScope сообщает, выдан ли токену scope.
+func (t APIToken) HasScope(scope string) bool {
+	return slices.Contains(t.Scopes, scope)
+}
+
+// AllowsType сообщает, доступны ли токену секреты типа typ.
+func (t APIToken) AllowsType(typ string) bool {
+	return len(t.Types) == 0 || slices.Contains(t.Types, typ)
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы событий журнала аудита.
+const (
+	AuditLogin           = "login"            // выданы токены новой сессии
+	AuditLoginFailed     = "login_failed"     // неверный пароль или код второго фактора
+	AuditLogout          = "logout"           // выход (отзыв своей сессии или всех)
+	AuditRefreshReuse    = "refresh_reuse"    // повторно предъявлен отозванный refresh-токен
+	AuditSessionRevoked  = "session_revoked"  // сессия отозвана из списка устройств
+	AuditPasswordChanged = "password_changed" // пароль сменён, сессии отозваны
+	AuditPasswordReset   = "password_reset"   // пароль сброшен по токену из письма, сессии отозваны
+	AuditReauth          = "reauth"           // повторная аутентификация перед чувствительной операцией
+	AuditAccountDisabled = "account_disabled" // администратор заблокировал аккаунт, сессии отозваны
+	AuditAccountEnabled  = "account_enabled"  // администратор разблокировал аккаунт
+	AuditSessionsRevoked = "sessions_revoked" // администратор отозвал все сессии
+	AuditSecretCreated   = "secret_created"
+	AuditSecretUpdated   = "secret_updated"
+	AuditSecretDeleted   = "secret_deleted"
+)
+
+// AuditEventTypes — все типы событий журнала аудита (для фильтра GET /audit).
+var AuditEventTypes = []string{
+	AuditLogin, AuditLoginFailed, AuditLogout, AuditRefreshReuse, AuditSessionRevoked,
+	AuditPasswordChanged, AuditPasswordReset, AuditReauth, AuditAccountDisabled, AuditAccountEnabled,
+	AuditSessionsRevoked, AuditSecretCreated, AuditSecretUpdated, AuditSecretDeleted,
+}
+
+// AuditEvent — запись журнала аудита пользователя.
+//
+// IP и UserAgent берутся из ClientInfo запроса, SecretID задан
+// только для событий секретов (uuid.Nil — нет).
+type AuditEvent struct {
+	ID        int64
+	UserID    uuid.UUID
+	Type      string
+	IP        string
+	UserAgent string
+	SecretID  uuid.UUID
+	CreatedAt time.Time
+}
+
+// AuditFilter — выборка журнала аудита.
+//
+// События возвращаются от новых к старым. Before — курсор страницы:
+// только события с ID меньше заданного (0 — с самого нового).
+type AuditFilter struct {
+	Since  time.Time // zero — без ограничения
+	Type   string    // пусто — все типы
+	Before int64
+	Limit  int
+}
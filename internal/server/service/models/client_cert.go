@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClientCert — клиентский сертификат (mTLS), привязанный к аккаунту.
+//
+// Fingerprint — SHA-256 DER-кодировки сертификата; сам сертификат не хранится.
+type ClientCert struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Name        string
+	Subject     string
+	Fingerprint []byte
+	NotAfter    time.Time
+	CreatedAt   time.Time
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeviceCode — запрос входа по коду устройства (RFC 8628) глазами опрашивающего клиента.
+//
+// UserID заполнен после подтверждения или отказа.
+// LastPolledAt — время предыдущего опроса (nil — первый опрос).
+type DeviceCode struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	Interval     time.Duration
+	ExpiresAt    time.Time
+	Approved     bool
+	Denied       bool
+	Used         bool
+	LastPolledAt *time.Time
+}
@@ -0,0 +1,7 @@
+package models
+
+// Назначения одноразовых токенов из писем (email_tokens.purpose).
+const (
+	EmailTokenVerify = "verify" // подтверждение email
+	EmailTokenReset  = "reset"  // сброс пароля
+)
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invite — код приглашения для регистрации (сам код не хранится, только SHA-256).
+type Invite struct {
+	ID        uuid.UUID
+	CreatedBy uuid.UUID // uuid.Nil — автор удалён
+	MaxUses   int
+	Uses      int
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session — активная refresh-сессия пользователя (без хэша токена).
+//
+// DeviceName, UserAgent и IP запоминаются при создании сессии,
+// чтобы пользователь мог понять, какое устройство держит токен.
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	DeviceName string    `json:"device_name"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// ClientInfo — сведения о клиенте, выполняющем запрос.
+//
+// Заполняется в HTTP-слое и передаётся в сервисы через context.Context.
+type ClientInfo struct {
+	DeviceName string
+	UserAgent  string
+	IP         string
+	// CertFingerprint — SHA-256 клиентского сертификата (mTLS), nil — без сертификата.
+	CertFingerprint []byte
+}
+
+// RevokedSession — отозванная сессия для кэша отзыва access-токенов.
+type RevokedSession struct {
+	ID        uuid.UUID
+	RevokedAt time.Time
+}
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Profile — сводная информация об аккаунте пользователя для GET /me.
+//
+// SecretCount и StorageBytes считаются по таблице secrets,
+// ActiveSessions — по неотозванным и не истёкшим сессиям.
+type Profile struct {
+	ID             uuid.UUID
+	Email          string
+	CreatedAt      time.Time
+	SecretCount    int
+	StorageBytes   int64
+	ActiveSessions int
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// UseOIDC подключает вход через внешний OpenID Connect провайдер.
+//
+// verifier проверяет ID-токены по JWKS провайдера (см. auth.oidc в конфиге),
+// linkByEmail разрешает привязывать к провайдеру существующие аккаунты с тем же email.
+// Без вызова вход через провайдер недоступен.
+func (s *AuthService) UseOIDC(repo OIDCIdentitiesRepo, verifier *crypto.OIDCVerifier, linkByEmail bool) {
+	s.oidcIdentities = repo
+	s.oidc = verifier
+	s.oidcLinkByEmail = linkByEmail
+}
+
+// LoginOIDC обменивает ID-токен провайдера на пару токенов GophKeeper.
+//
+// Поведение:
+//   - аккаунт ищется по (iss, sub) ID-токена
+//   - при первом входе аккаунт создаётся по email провайдера (email_verified обязателен);
+//     пароль у такого аккаунта случайный — войти по паролю нельзя
+//   - если аккаунт с этим email уже есть, он привязывается к провайдеру только
+//     при auth.oidc.link_by_email, иначе — ErrAlreadyExists
+//   - TOTP и привязанные клиентские сертификаты действуют так же, как при входе по паролю
+//
+// Ошибки:
+//   - ErrInvalidInput
+//   - ErrNotFound — вход через провайдер не включён
+//   - ErrUnauthorized — ID-токен не прошёл проверку или email провайдера не подтверждён
+//   - ErrAlreadyExists — email занят аккаунтом, не привязанным к провайдеру
+//   - ErrTooManySessions
+//   - ErrClientCertRequired
+//   - *BusyError (errors.Is(err, ErrServerBusy)) — очередь хэширования переполнена
+//   - *MFARequiredError (errors.Is(err, ErrMFARequired))
+func (s *AuthService) LoginOIDC(ctx context.Context, idToken string) (TokenPair, error) {
+	idToken = strings.TrimSpace(idToken)
+	if idToken == "" {
+		return TokenPair{}, serr.ErrInvalidInput
+	}
+	if s.oidc == nil {
+		return TokenPair{}, serr.ErrNotFound
+	}
+
+	claims, err := s.oidc.Verify(ctx, idToken)
+	if err != nil {
+		if errors.Is(err, crypto.ErrInvalidIDToken) {
+			s.securityEvent(ctx, "oidc_token_rejected")
+			return TokenPair{}, serr.ErrUnauthorized
+		}
+		return TokenPair{}, err
+	}
+
+	userID, err := s.oidcIdentities.GetUser(ctx, claims.Issuer, claims.Subject)
+	if errors.Is(err, serr.ErrNotFound) {
+		userID, err = s.oidcFirstLogin(ctx, claims)
+	}
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	// второй фактор аккаунта действует и при входе через провайдер
+	enabled, err := s.totpEnabled(ctx, userID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if enabled {
+		challenge, err := s.newMFAChallenge(ctx, userID)
+		if err != nil {
+			return TokenPair{}, err
+		}
+		return TokenPair{}, &MFARequiredError{Challenge: challenge}
+	}
+
+	return s.completeLogin(ctx, userID)
+}
+
+// oidcFirstLogin создаёт аккаунт для пользователя провайдера или привязывает существующий.
+func (s *AuthService) oidcFirstLogin(ctx context.Context, claims *crypto.IDTokenClaims) (uuid.UUID, error) {
+	email := strings.TrimSpace(strings.ToLower(claims.Email))
+	// без подтверждённого email нельзя ни создать аккаунт, ни найти существующий
+	if email == "" || !claims.EmailVerified {
+		s.securityEvent(ctx, "oidc_email_unverified",
+			zap.String("issuer", claims.Issuer),
+			zap.String("subject", claims.Subject),
+		)
+		return uuid.Nil, serr.ErrUnauthorized
+	}
+
+	userID, _, err := s.users.GetByEmail(ctx, email)
+	switch {
+	case err == nil:
+		if !s.oidcLinkByEmail {
+			return uuid.Nil, serr.ErrAlreadyExists
+		}
+		if err := s.oidcIdentities.Link(ctx, claims.Issuer, claims.Subject, email, userID); err != nil {
+			return uuid.Nil, err
+		}
+		s.securityEvent(ctx, "oidc_identity_linked",
+			zap.String("user_id", userID.String()),
+			zap.String("issuer", claims.Issuer),
+			zap.String("subject", claims.Subject),
+		)
+		return userID, nil
+	case !errors.Is(err, serr.ErrNotFound):
+		return uuid.Nil, err
+	}
+
+	// случайный пароль никому не известен: вход только через провайдер
+	password, err := crypto.NewRefreshToken()
+	if err != nil {
+		return uuid.Nil, serr.ErrInternal
+	}
+	hash, err := s.hashPassword(ctx, password)
+	if err != nil {
+		return uuid.Nil, hashingError(err)
+	}
+	userID, err = s.oidcIdentities.CreateUser(ctx, claims.Issuer, claims.Subject, email, hash)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	s.securityEvent(ctx, "oidc_user_created",
+		zap.String("user_id", userID.String()),
+		zap.String("issuer", claims.Issuer),
+		zap.String("subject", claims.Subject),
+	)
+	return userID, nil
+}
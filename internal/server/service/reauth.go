@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// Elevation — access-токен с повышенными правами после повторной аутентификации.
+type Elevation struct {
+	AccessToken   string
+	ElevatedUntil time.Time
+}
+
+// Reauthenticate подтверждает личность владельца сессии паролем
+// и выдаёт новый access-токен той же сессии с повышенными правами.
+//
+// Поведение:
+//   - пароль проверяется по текущему хэшу
+//   - при подключённом TOTP нужен ещё код из приложения или код восстановления
+//   - повышенные права действуют auth.reauth.elevated_ttl, но не дольше самого токена
+//   - refresh-токен не меняется: после обновления пары права снова обычные
+//   - неверный пароль или код учитываются в счётчике auth.lockout, как при входе:
+//     украденный access-токен не даёт перебирать пароль
+//
+// Ошибки:
+//   - ErrUserIDEmpty
+//   - ErrUnauthorized если токен выдан без сессии
+//   - ErrInvalidInput
+//   - ErrInvalidCredentials если пароль неверен
+//   - ErrMFARequired если подключён TOTP, а код не передан
+//   - ErrInvalidOTP если код неверен
+//   - ErrClientCertRequired если к аккаунту привязан сертификат, а запрос пришёл без него
+//   - *LoginLockedError (errors.Is(err, ErrTooManyAttempts))
+func (s *AuthService) Reauthenticate(ctx context.Context, userID, sessionID uuid.UUID, password, code string) (Elevation, error) {
+	if userID == uuid.Nil {
+		return Elevation{}, serr.ErrUserIDEmpty
+	}
+	if sessionID == uuid.Nil {
+		return Elevation{}, serr.ErrUnauthorized
+	}
+	password = strings.TrimSpace(password)
+	code = strings.TrimSpace(code)
+	if password == "" {
+		return Elevation{}, serr.ErrInvalidInput
+	}
+
+	email, err := s.lockoutEmail(ctx, userID)
+	if err != nil {
+		return Elevation{}, err
+	}
+	if err := s.checkLockout(ctx, email); err != nil {
+		return Elevation{}, err
+	}
+
+	hash, err := s.users.GetPasswordHash(ctx, userID)
+	if err != nil {
+		return Elevation{}, err
+	}
+	ok, err := s.verifyPassword(ctx, password, hash)
+	if err != nil {
+		return Elevation{}, hashingError(err)
+	}
+	if !ok {
+		s.securityEvent(ctx, "reauth_failed", zap.String("user_id", userID.String()))
+		return Elevation{}, s.loginFailed(ctx, email)
+	}
+
+	enabled, err := s.totpEnabled(ctx, userID)
+	if err != nil {
+		return Elevation{}, err
+	}
+	if enabled {
+		if code == "" {
+			return Elevation{}, serr.ErrMFARequired
+		}
+		if err := s.verifySecondFactor(ctx, userID, code); err != nil {
+			s.securityEvent(ctx, "reauth_failed", zap.String("user_id", userID.String()))
+			if errors.Is(err, serr.ErrInvalidOTP) {
+				if err := s.recordFailure(ctx, email); err != nil {
+					return Elevation{}, err
+				}
+			}
+			return Elevation{}, err
+		}
+	}
+
+	thumbprint, err := s.certBinding(ctx, userID)
+	if err != nil {
+		return Elevation{}, err
+	}
+	s.loginSucceeded(ctx, email)
+
+	ttl := s.elevatedTTL
+	if ttl <= 0 || ttl > s.jwt.AccessTTL {
+		ttl = s.jwt.AccessTTL
+	}
+	until := time.Now().Add(ttl)
+	access, err := crypto.NewElevatedAccessToken(userID.String(), sessionID.String(), thumbprint, until, s.jwt)
+	if err != nil {
+		return Elevation{}, serr.ErrInternal
+	}
+
+	s.securityEvent(ctx, "reauthenticated",
+		zap.String("user_id", userID.String()),
+		zap.Time("elevated_until", until),
+	)
+	s.audit.Record(ctx, userID, models.AuditReauth, uuid.Nil)
+	return Elevation{AccessToken: access, ElevatedUntil: until}, nil
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// revocationSkew — запас по времени при выборке отозванных сессий:
+// покрывает расхождение часов приложения и БД, а также транзакции,
+// закоммиченные позже курсора: revoked_at = now() — это время начала
+// транзакции, и такая запись может появиться уже после того, как курсор
+// ушёл дальше.
+const revocationSkew = time.Minute
+
+// SessionRevocations — in-process кэш недавно отозванных сессий.
+//
+// Access-токен содержит sid сессии. Чтобы после logout/отзыва сессии
+// её access-токены перестали приниматься сразу, а не через access_ttl,
+// AuthMiddleware проверяет sid по этому кэшу — без запроса в БД на каждый запрос.
+//
+// Кэш хранит только сессии, отозванные за последний access_ttl:
+// токены более старых сессий и так уже истекли.
+// Refresh догружает новые отзывы инкрементально (по revoked_at),
+// Run вызывает его периодически — так подхватываются отзывы,
+// сделанные другими экземплярами сервера.
+type SessionRevocations struct {
+	sessions  SessionsRepo
+	accessTTL time.Duration
+
+	mu      sync.RWMutex
+	revoked map[uuid.UUID]time.Time
+	cursor  time.Time // revoked_at последней загруженной записи
+
+	refreshMu sync.Mutex
+
+	// Now — источник времени (подменяется в тестах).
+	Now func() time.Time
+}
+
+// NewSessionRevocations создаёт кэш отзыва.
+//
+// accessTTL — срок жизни access-токена: столько помнится каждый отзыв.
+func NewSessionRevocations(sessions SessionsRepo, accessTTL time.Duration) *SessionRevocations {
+	return &SessionRevocations{
+		sessions:  sessions,
+		accessTTL: accessTTL,
+		revoked:   make(map[uuid.UUID]time.Time),
+		Now:       time.Now,
+	}
+}
+
+// IsRevoked сообщает, что сессия отозвана и её access-токены недействительны.
+func (r *SessionRevocations) IsRevoked(sessionID uuid.UUID) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.revoked[sessionID]
+	return ok
+}
+
+// Len возвращает число сессий в кэше.
+func (r *SessionRevocations) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.revoked)
+}
+
+// Refresh догружает сессии, отозванные после последней синхронизации
+// (с запасом revocationSkew до курсора), и удаляет записи старше access_ttl.
+// Повторно загруженные записи просто перезаписываются.
+func (r *SessionRevocations) Refresh(ctx context.Context) error {
+	// параллельные Refresh (периодический и после logout) не должны
+	// грузить одно и то же дважды
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+
+	now := r.Now()
+	horizon := now.Add(-r.accessTTL - revocationSkew)
+
+	r.mu.RLock()
+	since := r.cursor.Add(-revocationSkew)
+	r.mu.RUnlock()
+	if since.Before(horizon) {
+		since = horizon
+	}
+
+	list, err := r.sessions.ListRevokedSince(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range list {
+		r.revoked[s.ID] = s.RevokedAt
+		if s.RevokedAt.After(r.cursor) {
+			r.cursor = s.RevokedAt
+		}
+	}
+	for id, at := range r.revoked {
+		if at.Before(horizon) {
+			delete(r.revoked, id)
+		}
+	}
+	return nil
+}
+
+// Run периодически вызывает Refresh до отмены ctx.
+// onErr (может быть nil) получает ошибки синхронизации.
+func (r *SessionRevocations) Run(ctx context.Context, interval time.Duration, onErr func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Refresh(ctx); err != nil && onErr != nil {
+				onErr(err)
+			}
+		}
+	}
+}
@@ -20,6 +20,7 @@ import (
 type SecretsService struct {
 	repo   SecretsRepo
 	policy config.SecretsConfig
+	audit  *AuditService // журнал аудита (опционально)
 }
 
 // NewSecretsService создаёт новый SecretsService.
@@ -30,6 +31,11 @@ func NewSecretsService(repo SecretsRepo, cfg config.SecretsConfig) *SecretsServi
 	}
 }
 
+// UseAudit подключает журнал аудита: создание, изменение и удаление секретов.
+func (s *SecretsService) UseAudit(a *AuditService) {
+	s.audit = a
+}
+
 // validateType проверяет, разрешён ли тип секрета политикой сервера.
 func (s *SecretsService) validateType(t SecretType) error {
 	for _, allowed := range s.policy.AllowedTypes {
@@ -72,7 +78,12 @@ func (s *SecretsService) Create(ctx context.Context, userID uuid.UUID, typ strin
 		return uuid.Nil, 0, time.Time{}, serr.ErrInvalidInput
 	}
 
-	return s.repo.Create(ctx, userID, st, title, payload, meta)
+	id, version, updatedAt, err := s.repo.Create(ctx, userID, st, title, payload, meta)
+	if err != nil {
+		return uuid.Nil, 0, time.Time{}, err
+	}
+	s.audit.Record(ctx, userID, models.AuditSecretCreated, id)
+	return id, version, updatedAt, nil
 }
 
 // ListSecrets возвращает список всех секретов пользователя.
@@ -115,7 +126,11 @@ func (s *SecretsService) UpdateSecret(ctx context.Context, userID uuid.UUID, sec
 	if userID == uuid.Nil {
 		return serr.ErrUserIDEmpty
 	}
-	return s.repo.UpdateSecret(ctx, userID, secretID, data)
+	if err := s.repo.UpdateSecret(ctx, userID, secretID, data); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, userID, models.AuditSecretUpdated, secretID)
+	return nil
 }
 
 // DeleteSecret удаляет секрет пользователя с проверкой версии (optimistic locking).
@@ -142,5 +157,23 @@ func (s *SecretsService) DeleteSecret(ctx context.Context, userID uuid.UUID, sec
 	if userID == uuid.Nil {
 		return serr.ErrUserIDEmpty
 	}
-	return s.repo.DeleteSecret(ctx, userID, secretID, version)
+	if err := s.repo.DeleteSecret(ctx, userID, secretID, version); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, userID, models.AuditSecretDeleted, secretID)
+	return nil
+}
+
+// SecretType возвращает тип секрета пользователя.
+//
+// Возможные ошибки:
+//   - ErrUserIDEmpty — userID не передан
+//   - ErrNotFound    — секрет не найден
+//   - ErrInternal    — внутренняя ошибка
+func (s *SecretsService) SecretType(ctx context.Context, userID uuid.UUID, secretID uuid.UUID) (string, error) {
+	if userID == uuid.Nil {
+		return "", serr.ErrUserIDEmpty
+	}
+	typ, err := s.repo.GetType(ctx, userID, secretID)
+	return string(typ), err
 }
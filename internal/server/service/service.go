@@ -31,15 +31,24 @@ import (
 // Используется для явного внедрения зависимостей (dependency injection)
 // при сборке сервисов приложения.
 type Repositories struct {
-	Users    UsersRepo
-	Sessions SessionsRepo
-	Secrets  SecretsRepo
+	Users         UsersRepo
+	Sessions      SessionsRepo
+	Secrets       SecretsRepo
+	TwoFactor     TwoFactorRepo     // nil — двухфакторная аутентификация недоступна
+	LoginAttempts LoginAttemptsRepo // nil — блокировка по неудачным входам выключена
+	APITokens     APITokensRepo     // nil — персональные API-токены недоступны
+	DeviceCodes   DeviceCodesRepo   // nil — вход по коду устройства недоступен
+	Invites       InvitesRepo       // nil — регистрация по приглашениям недоступна
+	Audit         AuditRepo         // nil — журнал аудита выключен
+	Admin         AdminRepo         // nil — администрирование и блокировка аккаунтов недоступны
 }
 
 // Services — агрегатор всех сервисов приложения.
 type Services struct {
 	Auth    *AuthService
 	Secrets *SecretsService
+	Audit   *AuditService // nil, если журнал аудита выключен
+	Admin   *AdminService // nil, если администрирование недоступно
 }
 
 // NewServices собирает все сервисы приложения.
@@ -48,16 +57,56 @@ type Services struct {
 //   - JWT-настроек
 //   - TTL токенов и сессий.
 func NewServices(repos Repositories, cfg *config.Config) *Services {
+	auth := NewAuthService(repos.Users, repos.Sessions, cfg)
+	if repos.TwoFactor != nil {
+		auth.UseTwoFactor(repos.TwoFactor)
+	}
+	if repos.LoginAttempts != nil && cfg.Auth.Lockout.Enabled {
+		auth.UseLoginAttempts(repos.LoginAttempts)
+	}
+	if repos.APITokens != nil {
+		auth.UseAPITokens(repos.APITokens)
+	}
+	if repos.DeviceCodes != nil {
+		auth.UseDeviceCodes(repos.DeviceCodes)
+	}
+	if repos.Invites != nil {
+		auth.UseInvites(repos.Invites)
+	}
+	secrets := NewSecretsService(repos.Secrets, cfg.Secrets)
+
+	var audit *AuditService
+	if repos.Audit != nil {
+		audit = NewAuditService(repos.Audit, cfg.Audit)
+		auth.UseAudit(audit)
+		secrets.UseAudit(audit)
+	}
+
+	var admin *AdminService
+	if repos.Admin != nil {
+		auth.UseAdmin(repos.Admin)
+		admin = NewAdminService(repos.Admin, repos.Sessions, auth)
+	}
 	return &Services{
-		Auth:    NewAuthService(repos.Users, repos.Sessions, cfg),
-		Secrets: NewSecretsService(repos.Secrets, cfg.Secrets),
+		Auth:    auth,
+		Secrets: secrets,
+		Audit:   audit,
+		Admin:   admin,
 	}
 }
 
-// UsersRepo — репозиторий описываеющий операции с пользователями (нужен для auth/register/login).
+// UsersRepo — репозиторий описываеющий операции с пользователями (нужен для auth/register/login
+// и удаления аккаунта).
 type UsersRepo interface {
 	Create(ctx context.Context, email, passwordHash string) (uuid.UUID, error)
 	GetByEmail(ctx context.Context, email string) (uuid.UUID, string, error)
+	GetProfile(ctx context.Context, userID uuid.UUID) (models.Profile, error)
+	GetPasswordHash(ctx context.Context, userID uuid.UUID) (string, error)
+	UpdatePasswordHash(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	ScheduleDeletion(ctx context.Context, userID uuid.UUID, deleteAfter time.Time) error
+	GetPendingDeletion(ctx context.Context, email string) (uuid.UUID, string, time.Time, error)
+	CancelDeletion(ctx context.Context, userID uuid.UUID) error
+	PurgeDeleted(ctx context.Context) (int64, error)
 }
 
 // SessionsRepo описывает работу с refresh-сессиями.
@@ -65,14 +114,126 @@ type UsersRepo interface {
 // Используется для:
 //   - refresh access-токенов
 //   - ротации refresh-токенов
-//   - детекта повторного использования
+//   - детекта повторного использования (отзыв семейства ротации)
+//   - ограничения числа активных сессий пользователя
+//   - отзыва access-токенов отозванных сессий
+//   - очистки истёкших сессий (server session purge-expired)
 type SessionsRepo interface {
-	Create(ctx context.Context, userID uuid.UUID, refreshHash []byte, expiresAt time.Time) (uuid.UUID, error)
-	GetByRefreshHash(ctx context.Context, refreshHash []byte) (id uuid.UUID, userID uuid.UUID, expiresAt time.Time, revokedAt *time.Time, replacedBy *uuid.UUID, err error)
+	Create(ctx context.Context, userID, familyID uuid.UUID, refreshHash []byte, expiresAt time.Time, client models.ClientInfo) (uuid.UUID, error)
+	GetByRefreshHash(ctx context.Context, refreshHash []byte) (id uuid.UUID, userID uuid.UUID, expiresAt time.Time, revokedAt *time.Time, replacedBy *uuid.UUID, familyID uuid.UUID, err error)
 	RevokeAndReplace(ctx context.Context, oldID, newID uuid.UUID) error
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
+	Revoke(ctx context.Context, userID, sessionID uuid.UUID) error
+	CountActive(ctx context.Context, userID uuid.UUID) (int, error)
+	ListActive(ctx context.Context, userID uuid.UUID) ([]models.Session, error)
+	RevokeOldestActive(ctx context.Context, userID uuid.UUID, keep int) error
+	ListRevokedSince(ctx context.Context, since time.Time) ([]models.RevokedSession, error)
+	KnownDevice(ctx context.Context, userID uuid.UUID, deviceName, userAgent string) (bool, error)
+	PurgeExpired(ctx context.Context, before time.Time) (int64, error)
+}
+
+// TwoFactorRepo описывает хранение данных двухфакторной аутентификации:
+// TOTP-секретов, кодов восстановления и challenge "mfa required".
+type TwoFactorRepo interface {
+	SaveTOTPSecret(ctx context.Context, userID uuid.UUID, secret string) error
+	GetTOTP(ctx context.Context, userID uuid.UUID) (secret string, confirmed bool, err error)
+	EnableTOTP(ctx context.Context, userID uuid.UUID, step int64, recoveryHashes [][]byte) error
+	UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) error
+	UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash []byte) error
+	CreateChallenge(ctx context.Context, userID uuid.UUID, tokenHash []byte, expiresAt time.Time) error
+	ChallengeAttempt(ctx context.Context, tokenHash []byte, maxAttempts int) (uuid.UUID, error)
+	CompleteChallenge(ctx context.Context, tokenHash []byte) error
+}
+
+// LoginAttemptsRepo хранит счётчики неудачных попыток входа по email
+// для экспоненциальной задержки и временной блокировки.
+type LoginAttemptsRepo interface {
+	Get(ctx context.Context, email string) (failures int, lastFailure time.Time, err error)
+	RecordFailure(ctx context.Context, email string, resetBefore time.Time) (failures int, lastFailure time.Time, err error)
+	Reset(ctx context.Context, email string) error
+	Prune(ctx context.Context, before time.Time) error
+}
+
+// APITokensRepo хранит персональные API-токены (только SHA-256 токена).
+type APITokensRepo interface {
+	Create(ctx context.Context, userID uuid.UUID, name string, tokenHash []byte, scopes, types []string, expiresAt time.Time) (id uuid.UUID, createdAt time.Time, err error)
+	Use(ctx context.Context, tokenHash []byte) (models.APIToken, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.APIToken, error)
+	Revoke(ctx context.Context, userID, tokenID uuid.UUID) error
 	RevokeAllForUser(ctx context.Context, userID uuid.UUID) error
 }
 
+// DeviceCodesRepo хранит запросы входа по коду устройства (RFC 8628).
+type DeviceCodesRepo interface {
+	Create(ctx context.Context, deviceCodeHash []byte, userCode string, interval time.Duration, expiresAt time.Time) error
+	Decide(ctx context.Context, userCode string, userID uuid.UUID, approve bool) error
+	Poll(ctx context.Context, deviceCodeHash []byte) (models.DeviceCode, error)
+	SlowDown(ctx context.Context, id uuid.UUID, step time.Duration) error
+	Consume(ctx context.Context, id uuid.UUID) error
+	Prune(ctx context.Context) error
+}
+
+// ClientCertsRepo хранит отпечатки клиентских сертификатов, привязанных к аккаунтам.
+type ClientCertsRepo interface {
+	Create(ctx context.Context, cert models.ClientCert) (models.ClientCert, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.ClientCert, error)
+	Match(ctx context.Context, userID uuid.UUID, fingerprint []byte) (bound, matched bool, err error)
+	Delete(ctx context.Context, userID, certID uuid.UUID) error
+}
+
+// OIDCIdentitiesRepo хранит привязки пользователей внешнего OpenID Connect провайдера
+// (issuer, sub) к аккаунтам.
+type OIDCIdentitiesRepo interface {
+	GetUser(ctx context.Context, issuer, subject string) (uuid.UUID, error)
+	Link(ctx context.Context, issuer, subject, email string, userID uuid.UUID) error
+	CreateUser(ctx context.Context, issuer, subject, email, passwordHash string) (uuid.UUID, error)
+}
+
+// InvitesRepo хранит коды приглашений (только SHA-256 кода).
+//
+// CreateUser атомарно расходует одно использование кода и создаёт пользователя:
+// если email занят, использование не списывается.
+type InvitesRepo interface {
+	Create(ctx context.Context, createdBy uuid.UUID, codeHash []byte, maxUses int, expiresAt time.Time) (models.Invite, error)
+	Check(ctx context.Context, codeHash []byte) error
+	CreateUser(ctx context.Context, codeHash []byte, email, passwordHash string) (uuid.UUID, error)
+	ListActive(ctx context.Context) ([]models.Invite, error)
+	Delete(ctx context.Context, inviteID uuid.UUID) error
+}
+
+// EmailTokensRepo хранит одноразовые токены из писем (только SHA-256)
+// и отметку о подтверждении email.
+//
+// Create отменяет прежние неиспользованные токены того же назначения
+// и возвращает ErrConflict, если предыдущий выпущен меньше minInterval назад.
+type EmailTokensRepo interface {
+	Create(ctx context.Context, userID uuid.UUID, purpose string, tokenHash []byte, expiresAt time.Time, minInterval time.Duration) error
+	Consume(ctx context.Context, purpose string, tokenHash []byte) (uuid.UUID, error)
+	MarkVerified(ctx context.Context, userID uuid.UUID) error
+	GetEmail(ctx context.Context, userID uuid.UUID) (email string, verified bool, err error)
+	Prune(ctx context.Context) error
+}
+
+// AuditRepo хранит журнал аудита. Записи только добавляются и удаляются
+// по сроку хранения, изменить их нельзя.
+type AuditRepo interface {
+	Append(ctx context.Context, e models.AuditEvent) error
+	List(ctx context.Context, userID uuid.UUID, f models.AuditFilter) ([]models.AuditEvent, error)
+	Prune(ctx context.Context, before time.Time) (int64, error)
+}
+
+// AdminRepo хранит роль администратора и блокировку аккаунтов
+// и отдаёт данные для администрирования.
+type AdminRepo interface {
+	GetStatus(ctx context.Context, userID uuid.UUID) (models.UserStatus, error)
+	RecordLogin(ctx context.Context, userID uuid.UUID) error
+	ListUsers(ctx context.Context, limit, offset int) ([]models.AdminUser, error)
+	SetDisabled(ctx context.Context, userID uuid.UUID, disabled bool) error
+	SetAdmin(ctx context.Context, userID uuid.UUID, isAdmin bool) error
+	StorageStats(ctx context.Context) (models.StorageStats, error)
+}
+
 // SecretType тип секрета
 type SecretType string
 
@@ -89,4 +250,5 @@ type SecretsRepo interface {
 	ListSecrets(ctx context.Context, userID uuid.UUID) ([]sharModels.Secret, error)
 	UpdateSecret(ctx context.Context, userID uuid.UUID, secretID uuid.UUID, data models.UpdateSecretRequest) error
 	DeleteSecret(ctx context.Context, userID uuid.UUID, secretID uuid.UUID, version int) error
+	GetType(ctx context.Context, userID uuid.UUID, secretID uuid.UUID) (SecretType, error)
 }
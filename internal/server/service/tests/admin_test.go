@@ -0,0 +1,289 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+type adminTestEnv struct {
+	auth     *service.AuthService
+	admin    *service.AdminService
+	users    *mocks.MockUsersRepo
+	sessions *mocks.MockSessionsRepo
+	repo     *mocks.MockAdminRepo
+}
+
+// сервисы с подключённым администрированием, собранные как в NewServices
+func newAdminServices(t *testing.T) adminTestEnv {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	env := adminTestEnv{
+		users:    mocks.NewMockUsersRepo(ctrl),
+		sessions: mocks.NewMockSessionsRepo(ctrl),
+		repo:     mocks.NewMockAdminRepo(ctrl),
+	}
+	svc := service.NewServices(service.Repositories{
+		Users:    env.users,
+		Sessions: env.sessions,
+		Admin:    env.repo,
+	}, testConfig())
+	env.auth, env.admin = svc.Auth, svc.Admin
+	return env
+}
+
+func TestAdminService_RequireAdmin(t *testing.T) {
+	ctx := context.Background()
+	env := newAdminServices(t)
+
+	adminID, userID, disabledAdminID := uuid.New(), uuid.New(), uuid.New()
+	env.repo.EXPECT().GetStatus(ctx, adminID).Return(models.UserStatus{IsAdmin: true}, nil)
+	env.repo.EXPECT().GetStatus(ctx, userID).Return(models.UserStatus{}, nil)
+	env.repo.EXPECT().GetStatus(ctx, disabledAdminID).Return(models.UserStatus{IsAdmin: true, Disabled: true}, nil)
+
+	require.NoError(t, env.admin.RequireAdmin(ctx, adminID))
+	require.ErrorIs(t, env.admin.RequireAdmin(ctx, userID), serr.ErrForbidden)
+	require.ErrorIs(t, env.admin.RequireAdmin(ctx, disabledAdminID), serr.ErrForbidden)
+}
+
+// Блокировка сразу отзывает все сессии пользователя
+func TestAdminService_Disable_RevokesSessions(t *testing.T) {
+	ctx := context.Background()
+	env := newAdminServices(t)
+
+	adminID, userID := uuid.New(), uuid.New()
+	gomock.InOrder(
+		env.repo.EXPECT().SetDisabled(ctx, userID, true).Return(nil),
+		env.sessions.EXPECT().RevokeAllForUser(ctx, userID).Return(nil),
+	)
+
+	require.NoError(t, env.admin.SetDisabled(ctx, adminID, userID, true))
+}
+
+// Разблокировка сессии не трогает
+func TestAdminService_Enable(t *testing.T) {
+	ctx := context.Background()
+	env := newAdminServices(t)
+
+	userID := uuid.New()
+	env.repo.EXPECT().SetDisabled(ctx, userID, false).Return(nil)
+
+	require.NoError(t, env.admin.SetDisabled(ctx, uuid.New(), userID, false))
+}
+
+func TestAdminService_Disable_Self(t *testing.T) {
+	env := newAdminServices(t)
+
+	adminID := uuid.New()
+	err := env.admin.SetDisabled(context.Background(), adminID, adminID, true)
+
+	require.ErrorIs(t, err, serr.ErrInvalidInput)
+}
+
+func TestAdminService_RevokeSessions(t *testing.T) {
+	ctx := context.Background()
+	env := newAdminServices(t)
+
+	userID, missingID := uuid.New(), uuid.New()
+	env.repo.EXPECT().GetStatus(ctx, userID).Return(models.UserStatus{}, nil)
+	env.sessions.EXPECT().RevokeAllForUser(ctx, userID).Return(nil)
+	env.repo.EXPECT().GetStatus(ctx, missingID).Return(models.UserStatus{}, serr.ErrNotFound)
+
+	require.NoError(t, env.admin.RevokeSessions(ctx, uuid.New(), userID))
+	require.ErrorIs(t, env.admin.RevokeSessions(ctx, uuid.New(), missingID), serr.ErrNotFound)
+}
+
+func TestAdminService_ListUsers_Limit(t *testing.T) {
+	ctx := context.Background()
+	env := newAdminServices(t)
+
+	env.repo.EXPECT().ListUsers(ctx, 50, 0).Return(nil, nil)
+
+	_, err := env.admin.ListUsers(ctx, 0, 0)
+	require.NoError(t, err)
+
+	_, err = env.admin.ListUsers(ctx, 500, 0)
+	require.ErrorIs(t, err, serr.ErrInvalidInput)
+	_, err = env.admin.ListUsers(ctx, 10, -1)
+	require.ErrorIs(t, err, serr.ErrInvalidInput)
+}
+
+// Верный пароль заблокированного аккаунта не даёт ни токенов, ни challenge 2FA
+func TestAuthService_Login_Disabled(t *testing.T) {
+	ctx := context.Background()
+	env := newAdminServices(t)
+
+	userID := uuid.New()
+	env.users.EXPECT().
+		GetByEmail(ctx, "test@mail.com").
+		Return(userID, hashForTest(t, testConfig(), "strongpassword"), nil)
+	env.repo.EXPECT().GetStatus(ctx, userID).Return(models.UserStatus{Disabled: true}, nil)
+
+	_, err := env.auth.Login(ctx, "test@mail.com", "strongpassword")
+
+	require.ErrorIs(t, err, serr.ErrAccountDisabled)
+}
+
+// Успешный вход запоминает время последнего входа
+func TestAuthService_Login_RecordsLastLogin(t *testing.T) {
+	ctx := context.Background()
+	env := newAdminServices(t)
+
+	userID := uuid.New()
+	env.users.EXPECT().
+		GetByEmail(ctx, "test@mail.com").
+		Return(userID, hashForTest(t, testConfig(), "strongpassword"), nil)
+	env.repo.EXPECT().GetStatus(ctx, userID).Return(models.UserStatus{}, nil).Times(2)
+	env.sessions.EXPECT().
+		Create(gomock.Any(), userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+	env.sessions.EXPECT().RevokeOldestActive(gomock.Any(), userID, gomock.Any()).Return(nil).AnyTimes()
+	env.repo.EXPECT().RecordLogin(ctx, userID).Return(nil)
+
+	_, err := env.auth.Login(ctx, "test@mail.com", "strongpassword")
+
+	require.NoError(t, err)
+}
+
+func TestAuthService_Refresh_Disabled(t *testing.T) {
+	ctx := context.Background()
+	env := newAdminServices(t)
+
+	sessID, userID, familyID := uuid.New(), uuid.New(), uuid.New()
+	env.sessions.EXPECT().
+		GetByRefreshHash(ctx, crypto.HashRefreshToken("refresh-token")).
+		Return(sessID, userID, time.Now().Add(time.Hour), nil, nil, familyID, nil)
+	env.repo.EXPECT().GetStatus(ctx, userID).Return(models.UserStatus{Disabled: true}, nil)
+
+	_, err := env.auth.Refresh(ctx, "refresh-token")
+
+	require.ErrorIs(t, err, serr.ErrAccountDisabled)
+}
+
+// challenge 2FA, полученный до DELETE /me, не даёт войти в помеченный к удалению аккаунт
+func TestAuthService_CompleteMFA_PendingDeletion(t *testing.T) {
+	ctx := context.Background()
+	env := newAdminServices(t)
+	twoFactor := mocks.NewMockTwoFactorRepo(gomock.NewController(t))
+	env.auth.UseTwoFactor(twoFactor)
+
+	userID := uuid.New()
+	secret, _ := crypto.NewTOTPSecret()
+	twoFactor.EXPECT().
+		ChallengeAttempt(ctx, crypto.HashRefreshToken("challenge"), 5).
+		Return(userID, nil)
+	twoFactor.EXPECT().GetTOTP(ctx, userID).Return(secret, true, nil)
+	twoFactor.EXPECT().UseTOTPStep(ctx, userID, gomock.Any()).Return(nil)
+	twoFactor.EXPECT().CompleteChallenge(ctx, gomock.Any()).Return(nil).AnyTimes()
+	env.repo.EXPECT().GetStatus(ctx, userID).Return(models.UserStatus{PendingDeletion: true}, nil)
+
+	_, err := env.auth.CompleteMFA(ctx, "challenge", currentCode(t, secret))
+
+	require.ErrorIs(t, err, serr.ErrUnauthorized)
+}
+
+// Код устройства, подтверждённый до DELETE /me, не обменивается на токены
+func TestAuthService_PollDeviceToken_PendingDeletion(t *testing.T) {
+	ctx := context.Background()
+	env := newAdminServices(t)
+	devices := mocks.NewMockDeviceCodesRepo(gomock.NewController(t))
+	env.auth.UseDeviceCodes(devices)
+
+	id, userID := uuid.New(), uuid.New()
+	devices.EXPECT().
+		Poll(ctx, gomock.Any()).
+		Return(models.DeviceCode{ID: id, UserID: userID, Interval: 5 * time.Second, ExpiresAt: time.Now().Add(time.Minute), Approved: true}, nil)
+	devices.EXPECT().Consume(ctx, id).Return(nil)
+	env.repo.EXPECT().GetStatus(ctx, userID).Return(models.UserStatus{PendingDeletion: true}, nil)
+
+	_, err := env.auth.PollDeviceToken(ctx, "device-code")
+
+	require.ErrorIs(t, err, serr.ErrUnauthorized)
+}
+
+// Администратор сервера выпускает приглашения без записи в auth.invites.admins
+func TestAuthService_Invites_ServerAdmin(t *testing.T) {
+	ctx := context.Background()
+	svc, users, invites := newInvitesAuthService(t, config.RegistrationInvite)
+	repo := mocks.NewMockAdminRepo(gomock.NewController(t))
+	svc.UseAdmin(repo)
+
+	userID := uuid.New()
+	users.EXPECT().GetProfile(ctx, userID).Return(models.Profile{ID: userID, Email: "ops@mail.com"}, nil)
+	repo.EXPECT().GetStatus(ctx, userID).Return(models.UserStatus{IsAdmin: true}, nil)
+	invites.EXPECT().ListActive(ctx).Return(nil, nil)
+
+	_, err := svc.ListInvites(ctx, userID)
+
+	require.NoError(t, err)
+}
+
+// Создание пользователя оператором сервера не зависит от auth.registration
+func TestAdminService_CreateUser(t *testing.T) {
+	ctx := context.Background()
+	env := newAdminServices(t)
+
+	_, err := env.admin.CreateUser(ctx, "not-an-email", "password123", false)
+	require.ErrorIs(t, err, serr.ErrInvalidInput)
+
+	userID := uuid.New()
+	gomock.InOrder(
+		env.users.EXPECT().Create(ctx, "admin@example.com", gomock.Any()).Return(userID, nil),
+		env.repo.EXPECT().SetAdmin(ctx, userID, true).Return(nil),
+	)
+
+	id, err := env.admin.CreateUser(ctx, " Admin@Example.com ", "password123", true)
+	require.NoError(t, err)
+	require.Equal(t, userID, id)
+}
+
+func TestAdminService_ResolveUser(t *testing.T) {
+	ctx := context.Background()
+	env := newAdminServices(t)
+
+	byID, byEmail := uuid.New(), uuid.New()
+	env.repo.EXPECT().GetStatus(ctx, byID).Return(models.UserStatus{}, nil)
+	env.users.EXPECT().GetByEmail(ctx, "user@example.com").Return(byEmail, "hash", nil)
+	env.users.EXPECT().GetByEmail(ctx, "missing@example.com").Return(uuid.Nil, "", serr.ErrNotFound)
+
+	id, err := env.admin.ResolveUser(ctx, byID.String())
+	require.NoError(t, err)
+	require.Equal(t, byID, id)
+
+	id, err = env.admin.ResolveUser(ctx, "User@Example.com")
+	require.NoError(t, err)
+	require.Equal(t, byEmail, id)
+
+	_, err = env.admin.ResolveUser(ctx, "missing@example.com")
+	require.ErrorIs(t, err, serr.ErrNotFound)
+}
+
+func TestAuthService_PurgeExpiredSessions(t *testing.T) {
+	ctx := context.Background()
+	env := newAdminServices(t)
+
+	_, err := env.auth.PurgeExpiredSessions(ctx, -time.Hour)
+	require.ErrorIs(t, err, serr.ErrInvalidInput)
+
+	env.sessions.EXPECT().PurgeExpired(ctx, gomock.Any()).DoAndReturn(
+		func(_ context.Context, before time.Time) (int64, error) {
+			require.WithinDuration(t, time.Now().Add(-24*time.Hour), before, time.Minute)
+			return 3, nil
+		})
+
+	n, err := env.auth.PurgeExpiredSessions(ctx, 24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), n)
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// сервис с подключённым хранилищем API-токенов
+func newAPITokensAuthService(t *testing.T) (*service.AuthService, *mocks.MockAPITokensRepo) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+
+	cfg := testConfig()
+	cfg.Auth.APITokens = config.APITokensConfig{DefaultTTL: 24 * time.Hour, MaxTTL: 48 * time.Hour}
+	cfg.Secrets.AllowedTypes = []string{"text", "binary"}
+
+	svc := service.NewAuthService(mocks.NewMockUsersRepo(ctrl), mocks.NewMockSessionsRepo(ctrl), cfg)
+	tokens := mocks.NewMockAPITokensRepo(ctrl)
+	svc.UseAPITokens(tokens)
+	return svc, tokens
+}
+
+// токен возвращается один раз, в БД уходит только хэш
+func TestAuthService_CreateAPIToken_OK(t *testing.T) {
+	ctx := context.Background()
+	svc, tokens := newAPITokensAuthService(t)
+
+	userID := uuid.New()
+	tokenID := uuid.New()
+
+	var savedHash []byte
+	tokens.EXPECT().
+		Create(ctx, userID, "ci", gomock.Any(), []string{"secrets:read", "secrets:write"}, []string{"text"}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, _ string, h []byte, _, _ []string, exp time.Time) (uuid.UUID, time.Time, error) {
+			savedHash = h
+			// срок по умолчанию
+			require.WithinDuration(t, time.Now().Add(24*time.Hour), exp, 5*time.Second)
+			return tokenID, time.Now(), nil
+		})
+
+	created, err := svc.CreateAPIToken(ctx, userID, " ci ",
+		[]string{"secrets:write", "secrets:read", "secrets:read"}, []string{"text"}, 0)
+
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(created.Token, crypto.APITokenPrefix))
+	require.Equal(t, crypto.HashRefreshToken(created.Token), savedHash)
+	require.Equal(t, tokenID, created.ID)
+	require.Equal(t, "ci", created.Name)
+}
+
+// неизвестный scope или тип, неверный срок, пустое имя
+func TestAuthService_CreateAPIToken_InvalidInput(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newAPITokensAuthService(t)
+
+	userID := uuid.New()
+	read := []string{models.ScopeSecretsRead}
+
+	cases := []struct {
+		name   string
+		scopes []string
+		types  []string
+		ttl    time.Duration
+	}{
+		{"", read, nil, 0},
+		{"ci", nil, nil, 0},
+		{"ci", []string{"admin"}, nil, 0},
+		{"ci", read, []string{"otp"}, 0},
+		{"ci", read, nil, 72 * time.Hour},
+		{"ci", read, nil, -time.Hour},
+	}
+	for _, c := range cases {
+		_, err := svc.CreateAPIToken(ctx, userID, c.name, c.scopes, c.types, c.ttl)
+		require.ErrorIs(t, err, serr.ErrInvalidInput, "%+v", c)
+	}
+}
+
+// проверка токена по хэшу
+func TestAuthService_AuthenticateAPIToken(t *testing.T) {
+	ctx := context.Background()
+	svc, tokens := newAPITokensAuthService(t)
+
+	want := models.APIToken{ID: uuid.New(), UserID: uuid.New(), Scopes: []string{models.ScopeSecretsRead}}
+	tokens.EXPECT().
+		Use(ctx, crypto.HashRefreshToken("gkp_valid")).
+		Return(want, nil)
+
+	got, err := svc.AuthenticateAPIToken(ctx, "gkp_valid")
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	// без префикса в хранилище не ходим
+	_, err = svc.AuthenticateAPIToken(ctx, "refresh-token")
+	require.ErrorIs(t, err, serr.ErrUnauthorized)
+
+	tokens.EXPECT().
+		Use(ctx, gomock.Any()).
+		Return(models.APIToken{}, serr.ErrUnauthorized)
+
+	_, err = svc.AuthenticateAPIToken(ctx, "gkp_revoked")
+	require.ErrorIs(t, err, serr.ErrUnauthorized)
+}
+
+// без хранилища API-токены не принимаются
+func TestAuthService_AuthenticateAPIToken_Disabled(t *testing.T) {
+	svc, _, _ := newAuthService(t)
+
+	_, err := svc.AuthenticateAPIToken(context.Background(), "gkp_valid")
+	require.ErrorIs(t, err, serr.ErrUnauthorized)
+}
+
+func TestAuthService_RevokeAPIToken_NotFound(t *testing.T) {
+	ctx := context.Background()
+	svc, tokens := newAPITokensAuthService(t)
+
+	userID := uuid.New()
+	tokenID := uuid.New()
+	tokens.EXPECT().
+		Revoke(ctx, userID, tokenID).
+		Return(serr.ErrNotFound)
+
+	err := svc.RevokeAPIToken(ctx, userID, tokenID)
+	require.ErrorIs(t, err, serr.ErrNotFound)
+}
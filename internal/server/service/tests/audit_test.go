@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+func newAuditService(t *testing.T) (*service.AuditService, *mocks.MockAuditRepo) {
+	t.Helper()
+	repo := mocks.NewMockAuditRepo(gomock.NewController(t))
+	return service.NewAuditService(repo, config.AuditConfig{Retention: 24 * time.Hour}), repo
+}
+
+// IP и User-Agent берутся из сведений о клиенте
+func TestAuditService_Record_ClientInfo(t *testing.T) {
+	svc, repo := newAuditService(t)
+	ctx := service.WithClientInfo(context.Background(), models.ClientInfo{IP: "10.0.0.1", UserAgent: "gophkeeper-cli"})
+
+	userID, secretID := uuid.New(), uuid.New()
+	repo.EXPECT().
+		Append(ctx, models.AuditEvent{
+			UserID: userID, Type: models.AuditSecretDeleted, IP: "10.0.0.1", UserAgent: "gophkeeper-cli", SecretID: secretID,
+		}).
+		Return(nil)
+
+	svc.Record(ctx, userID, models.AuditSecretDeleted, secretID)
+}
+
+// Сбой журнала не ломает операцию, а уходит в OnError
+func TestAuditService_Record_ErrorReported(t *testing.T) {
+	svc, repo := newAuditService(t)
+	var reported error
+	svc.OnError = func(err error) { reported = err }
+
+	repo.EXPECT().Append(gomock.Any(), gomock.Any()).Return(serr.ErrInternal)
+
+	svc.Record(context.Background(), uuid.New(), models.AuditLogin, uuid.Nil)
+	require.ErrorIs(t, reported, serr.ErrInternal)
+}
+
+// Выключенный журнал (nil) молча игнорирует события
+func TestAuditService_Record_NilService(t *testing.T) {
+	var svc *service.AuditService
+	svc.Record(context.Background(), uuid.New(), models.AuditLogin, uuid.Nil)
+}
+
+func TestAuditService_List_Pagination(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newAuditService(t)
+
+	userID := uuid.New()
+	since := time.Now().Add(-time.Hour)
+	repo.EXPECT().
+		List(ctx, userID, models.AuditFilter{Since: since, Type: models.AuditLogin, Before: 100, Limit: 3}).
+		Return([]models.AuditEvent{{ID: 99}, {ID: 98}, {ID: 97}}, nil)
+
+	events, next, err := svc.List(ctx, userID, models.AuditFilter{Since: since, Type: models.AuditLogin, Before: 100, Limit: 2})
+
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, int64(98), next)
+}
+
+func TestAuditService_List_LastPage(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newAuditService(t)
+
+	userID := uuid.New()
+	// без limit используется страница по умолчанию
+	repo.EXPECT().
+		List(ctx, userID, models.AuditFilter{Limit: 51}).
+		Return([]models.AuditEvent{{ID: 2}, {ID: 1}}, nil)
+
+	events, next, err := svc.List(ctx, userID, models.AuditFilter{})
+
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Zero(t, next)
+}
+
+func TestAuditService_List_LimitCapped(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newAuditService(t)
+
+	userID := uuid.New()
+	repo.EXPECT().List(ctx, userID, models.AuditFilter{Limit: 201}).Return(nil, nil)
+
+	_, _, err := svc.List(ctx, userID, models.AuditFilter{Limit: 10000})
+	require.NoError(t, err)
+}
+
+func TestAuditService_List_InvalidInput(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newAuditService(t)
+
+	_, _, err := svc.List(ctx, uuid.New(), models.AuditFilter{Type: "unknown"})
+	require.ErrorIs(t, err, serr.ErrInvalidInput)
+
+	_, _, err = svc.List(ctx, uuid.New(), models.AuditFilter{Before: -1})
+	require.ErrorIs(t, err, serr.ErrInvalidInput)
+
+	_, _, err = svc.List(ctx, uuid.Nil, models.AuditFilter{})
+	require.ErrorIs(t, err, serr.ErrUserIDEmpty)
+}
+
+func TestAuditService_Prune(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newAuditService(t)
+	now := time.Now()
+	svc.Now = func() time.Time { return now }
+
+	repo.EXPECT().Prune(ctx, now.Add(-24*time.Hour)).Return(int64(3), nil)
+
+	n, err := svc.Prune(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), n)
+}
+
+// Вход и неудачный вход попадают в журнал пользователя
+func TestAuthService_Login_Audited(t *testing.T) {
+	ctx := context.Background()
+	svc, users, sessions := newAuthService(t)
+	audit, repo := newAuditService(t)
+	svc.UseAudit(audit)
+
+	userID := uuid.New()
+	hash := hashForTest(t, testConfig(), "strongpassword")
+	users.EXPECT().GetByEmail(ctx, "test@mail.com").Return(userID, hash, nil).Times(2)
+	sessions.EXPECT().
+		Create(gomock.Any(), userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+
+	gomock.InOrder(
+		repo.EXPECT().Append(ctx, models.AuditEvent{UserID: userID, Type: models.AuditLoginFailed}).Return(nil),
+		repo.EXPECT().Append(ctx, models.AuditEvent{UserID: userID, Type: models.AuditLogin}).Return(nil),
+	)
+
+	_, err := svc.Login(ctx, "test@mail.com", "wrongpassword")
+	require.ErrorIs(t, err, serr.ErrInvalidCredentials)
+
+	_, err = svc.Login(ctx, "test@mail.com", "strongpassword")
+	require.NoError(t, err)
+}
+
+// Повторное предъявление refresh-токена попадает в журнал
+func TestAuthService_Refresh_ReuseAudited(t *testing.T) {
+	ctx := context.Background()
+	svc, _, sessions := newAuthService(t)
+	audit, repo := newAuditService(t)
+	svc.UseAudit(audit)
+
+	userID, familyID := uuid.New(), uuid.New()
+	revokedAt := time.Now().Add(-time.Minute)
+	sessions.EXPECT().
+		GetByRefreshHash(ctx, gomock.Any()).
+		Return(uuid.New(), userID, time.Now().Add(time.Hour), &revokedAt, nil, familyID, nil)
+	sessions.EXPECT().RevokeFamily(ctx, familyID).Return(nil)
+	repo.EXPECT().Append(ctx, models.AuditEvent{UserID: userID, Type: models.AuditRefreshReuse}).Return(nil)
+
+	_, err := svc.Refresh(ctx, "stolen-refresh")
+	require.ErrorIs(t, err, serr.ErrUnauthorized)
+}
+
+// Изменения секретов записываются с ID секрета, неудачные — нет
+func TestSecretsService_DeleteSecret_Audited(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	secrets := mocks.NewMockSecretsRepo(ctrl)
+	svc := service.NewSecretsService(secrets, config.SecretsConfig{})
+	audit, repo := newAuditService(t)
+	svc.UseAudit(audit)
+
+	userID, secretID := uuid.New(), uuid.New()
+	secrets.EXPECT().DeleteSecret(ctx, userID, secretID, 1).Return(serr.ErrConflict)
+	secrets.EXPECT().DeleteSecret(ctx, userID, secretID, 2).Return(nil)
+	repo.EXPECT().
+		Append(ctx, models.AuditEvent{UserID: userID, Type: models.AuditSecretDeleted, SecretID: secretID}).
+		Return(nil)
+
+	require.True(t, errors.Is(svc.DeleteSecret(ctx, userID, secretID, 1), serr.ErrConflict))
+	require.NoError(t, svc.DeleteSecret(ctx, userID, secretID, 2))
+}
@@ -0,0 +1,158 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// сервис с отсрочкой удаления 24h
+func newDeletionAuthService(t *testing.T) (*service.AuthService, *mocks.MockUsersRepo, *mocks.MockSessionsRepo) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUsersRepo(ctrl)
+	sessions := mocks.NewMockSessionsRepo(ctrl)
+
+	cfg := testConfig()
+	cfg.Auth.AccountDeletion.GracePeriod = 24 * time.Hour
+
+	return service.NewAuthService(users, sessions, cfg), users, sessions
+}
+
+// Удаление: аккаунт помечается, все сессии отзываются
+func TestAuthService_RequestDeletion_OK(t *testing.T) {
+	ctx := context.Background()
+	svc, users, sessions := newDeletionAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetPasswordHash(ctx, userID).
+		Return(hashForTest(t, testConfig(), "strongpassword"), nil)
+
+	var scheduled time.Time
+	gomock.InOrder(
+		users.EXPECT().
+			ScheduleDeletion(ctx, userID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, at time.Time) error {
+				scheduled = at
+				return nil
+			}),
+		sessions.EXPECT().
+			RevokeAllForUser(ctx, userID).
+			Return(nil),
+	)
+
+	deleteAfter, err := svc.RequestDeletion(ctx, userID, "strongpassword")
+
+	require.NoError(t, err)
+	require.Equal(t, scheduled, deleteAfter)
+	require.WithinDuration(t, time.Now().Add(24*time.Hour), deleteAfter, 5*time.Second)
+}
+
+// Неверный пароль — аккаунт не трогаем
+func TestAuthService_RequestDeletion_WrongPassword(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _ := newDeletionAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetPasswordHash(ctx, userID).
+		Return(hashForTest(t, testConfig(), "strongpassword"), nil)
+
+	_, err := svc.RequestDeletion(ctx, userID, "wrongpassword")
+
+	require.ErrorIs(t, err, serr.ErrInvalidCredentials)
+}
+
+// Пустой пароль
+func TestAuthService_RequestDeletion_EmptyPassword(t *testing.T) {
+	svc, _, _ := newDeletionAuthService(t)
+
+	_, err := svc.RequestDeletion(context.Background(), uuid.New(), "  ")
+
+	require.ErrorIs(t, err, serr.ErrInvalidInput)
+}
+
+// Восстановление в течение отсрочки
+func TestAuthService_RestoreAccount_OK(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _ := newDeletionAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetPendingDeletion(ctx, "test@mail.com").
+		Return(userID, hashForTest(t, testConfig(), "strongpassword"), time.Now().Add(time.Hour), nil)
+	users.EXPECT().
+		CancelDeletion(ctx, userID).
+		Return(nil)
+
+	require.NoError(t, svc.RestoreAccount(ctx, " Test@Mail.com ", "strongpassword"))
+}
+
+// Неизвестный email, неверный пароль и истёкшая отсрочка неотличимы
+func TestAuthService_RestoreAccount_Rejected(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("not pending", func(t *testing.T) {
+		svc, users, _ := newDeletionAuthService(t)
+		users.EXPECT().
+			GetPendingDeletion(ctx, "test@mail.com").
+			Return(uuid.Nil, "", time.Time{}, serr.ErrNotFound)
+
+		require.ErrorIs(t, svc.RestoreAccount(ctx, "test@mail.com", "strongpassword"), serr.ErrInvalidCredentials)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		svc, users, _ := newDeletionAuthService(t)
+		users.EXPECT().
+			GetPendingDeletion(ctx, "test@mail.com").
+			Return(uuid.New(), hashForTest(t, testConfig(), "strongpassword"), time.Now().Add(time.Hour), nil)
+
+		require.ErrorIs(t, svc.RestoreAccount(ctx, "test@mail.com", "wrongpassword"), serr.ErrInvalidCredentials)
+	})
+
+	t.Run("grace period expired", func(t *testing.T) {
+		svc, users, _ := newDeletionAuthService(t)
+		users.EXPECT().
+			GetPendingDeletion(ctx, "test@mail.com").
+			Return(uuid.New(), hashForTest(t, testConfig(), "strongpassword"), time.Now().Add(-time.Minute), nil)
+
+		require.ErrorIs(t, svc.RestoreAccount(ctx, "test@mail.com", "strongpassword"), serr.ErrInvalidCredentials)
+	})
+}
+
+// Периодическое удаление останавливается по отмене контекста
+func TestAuthService_RunAccountPurge(t *testing.T) {
+	svc, users, _ := newDeletionAuthService(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	users.EXPECT().
+		PurgeDeleted(gomock.Any()).
+		DoAndReturn(func(context.Context) (int64, error) {
+			cancel()
+			return 1, nil
+		}).
+		MinTimes(1)
+
+	go func() {
+		svc.RunAccountPurge(ctx, 10*time.Millisecond, nil)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("RunAccountPurge did not stop")
+	}
+}
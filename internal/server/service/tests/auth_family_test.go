@@ -0,0 +1,310 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/logger"
+)
+
+// memSession — сессия в памяти fakeSessions.
+type memSession struct {
+	id, userID, familyID uuid.UUID
+	hash                 string
+	expiresAt            time.Time
+	revokedAt            *time.Time
+	replacedBy           *uuid.UUID
+}
+
+// fakeSessions — потокобезопасный SessionsRepo в памяти.
+//
+// Нужен для гонок: gomock не воспроизводит атомарность
+// UPDATE ... WHERE revoked_at IS NULL, а здесь она соблюдается под mu.
+type fakeSessions struct {
+	mu       sync.Mutex
+	sessions map[uuid.UUID]*memSession
+
+	// afterGet вызывается после чтения сессии по refresh-хэшу (вне mu)
+	afterGet func()
+}
+
+func newFakeSessions() *fakeSessions {
+	return &fakeSessions{sessions: make(map[uuid.UUID]*memSession)}
+}
+
+// login создаёт сессию нового семейства и возвращает её refresh-токен.
+func (f *fakeSessions) login(t *testing.T, userID uuid.UUID) (string, uuid.UUID) {
+	t.Helper()
+	refresh, err := crypto.NewRefreshToken()
+	require.NoError(t, err)
+	id, err := f.Create(context.Background(), userID, uuid.Nil, crypto.HashRefreshToken(refresh), time.Now().Add(time.Hour), models.ClientInfo{})
+	require.NoError(t, err)
+	return refresh, id
+}
+
+func (f *fakeSessions) isActive(id uuid.UUID) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.sessions[id].revokedAt == nil
+}
+
+// active возвращает id активных сессий пользователя.
+func (f *fakeSessions) active(userID uuid.UUID) []uuid.UUID {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var ids []uuid.UUID
+	for _, s := range f.sessions {
+		if s.userID == userID && s.revokedAt == nil {
+			ids = append(ids, s.id)
+		}
+	}
+	return ids
+}
+
+func (f *fakeSessions) Create(_ context.Context, userID, familyID uuid.UUID, refreshHash []byte, expiresAt time.Time, _ models.ClientInfo) (uuid.UUID, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := uuid.New()
+	if familyID == uuid.Nil {
+		familyID = id
+	}
+	f.sessions[id] = &memSession{id: id, userID: userID, familyID: familyID, hash: string(refreshHash), expiresAt: expiresAt}
+	return id, nil
+}
+
+func (f *fakeSessions) GetByRefreshHash(_ context.Context, refreshHash []byte) (uuid.UUID, uuid.UUID, time.Time, *time.Time, *uuid.UUID, uuid.UUID, error) {
+	f.mu.Lock()
+	var found memSession
+	ok := false
+	for _, s := range f.sessions {
+		if s.hash == string(refreshHash) {
+			found, ok = *s, true
+			break
+		}
+	}
+	f.mu.Unlock()
+
+	if !ok {
+		return uuid.Nil, uuid.Nil, time.Time{}, nil, nil, uuid.Nil, serr.ErrUnauthorized
+	}
+	if f.afterGet != nil {
+		f.afterGet()
+	}
+	return found.id, found.userID, found.expiresAt, found.revokedAt, found.replacedBy, found.familyID, nil
+}
+
+func (f *fakeSessions) RevokeAndReplace(_ context.Context, oldID, newID uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := f.sessions[oldID]
+	if s == nil || s.revokedAt != nil {
+		return serr.ErrConflict
+	}
+	now := time.Now()
+	s.revokedAt, s.replacedBy = &now, &newID
+	return nil
+}
+
+func (f *fakeSessions) RevokeFamily(_ context.Context, familyID uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	for _, s := range f.sessions {
+		if s.familyID == familyID && s.revokedAt == nil {
+			s.revokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (f *fakeSessions) RevokeAllForUser(_ context.Context, userID uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	for _, s := range f.sessions {
+		if s.userID == userID && s.revokedAt == nil {
+			s.revokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (f *fakeSessions) Revoke(_ context.Context, userID, sessionID uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := f.sessions[sessionID]
+	if s == nil || s.userID != userID || s.revokedAt != nil {
+		return serr.ErrNotFound
+	}
+	now := time.Now()
+	s.revokedAt = &now
+	return nil
+}
+
+func (f *fakeSessions) CountActive(_ context.Context, userID uuid.UUID) (int, error) {
+	return len(f.active(userID)), nil
+}
+
+func (f *fakeSessions) ListActive(context.Context, uuid.UUID) ([]models.Session, error) {
+	return nil, nil
+}
+
+func (f *fakeSessions) RevokeOldestActive(context.Context, uuid.UUID, int) error {
+	return nil
+}
+
+func (f *fakeSessions) ListRevokedSince(context.Context, time.Time) ([]models.RevokedSession, error) {
+	return nil, nil
+}
+
+func (f *fakeSessions) KnownDevice(context.Context, uuid.UUID, string, string) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeSessions) PurgeExpired(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+// newFamilyAuthService собирает сервис поверх fakeSessions с журналом безопасности в памяти.
+func newFamilyAuthService(reuseDetection bool) (*service.AuthService, *fakeSessions, *observer.ObservedLogs) {
+	cfg := testConfig()
+	cfg.Auth.Sessions.ReuseDetection = reuseDetection
+
+	sessions := newFakeSessions()
+	svc := service.NewAuthService(nil, sessions, cfg)
+
+	core, logs := observer.New(zap.InfoLevel)
+	svc.UseSecurityLog(&logger.SecurityLogger{Logger: zap.New(core)})
+	return svc, sessions, logs
+}
+
+// refreshConcurrently выполняет n параллельных Refresh одним токеном.
+// Все запросы читают сессию до того, как кто-то из них её заменит.
+func refreshConcurrently(svc *service.AuthService, sessions *fakeSessions, token string, n int) ([]service.TokenPair, []error) {
+	var read sync.WaitGroup
+	read.Add(n)
+	sessions.afterGet = func() {
+		read.Done()
+		read.Wait()
+	}
+	defer func() { sessions.afterGet = nil }()
+
+	pairs := make([]service.TokenPair, n)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			pairs[i], errs[i] = svc.Refresh(context.Background(), token)
+		}(i)
+	}
+	wg.Wait()
+	return pairs, errs
+}
+
+// Повторное использование отзывает только цепочку токена,
+// сессия на другом устройстве продолжает работать
+func TestAuthService_Refresh_Reuse_RevokesOnlyFamily(t *testing.T) {
+	svc, sessions, logs := newFamilyAuthService(true)
+	userID := uuid.New()
+
+	stolen, _ := sessions.login(t, userID)
+	_, otherDevice := sessions.login(t, userID)
+
+	// легитимный клиент ротирует токен дважды
+	pair, err := svc.Refresh(context.Background(), stolen)
+	require.NoError(t, err)
+	pair, err = svc.Refresh(context.Background(), pair.RefreshToken)
+	require.NoError(t, err)
+
+	// атакующий предъявляет старый токен
+	_, err = svc.Refresh(context.Background(), stolen)
+	require.ErrorIs(t, err, serr.ErrUnauthorized)
+
+	// потомки украденного токена отозваны
+	_, err = svc.Refresh(context.Background(), pair.RefreshToken)
+	require.ErrorIs(t, err, serr.ErrUnauthorized)
+
+	// другое семейство не тронуто
+	require.Equal(t, []uuid.UUID{otherDevice}, sessions.active(userID))
+
+	events := logs.FilterField(zap.String("event", "refresh_token_reuse")).All()
+	require.NotEmpty(t, events)
+	require.Equal(t, userID.String(), events[0].ContextMap()["user_id"])
+	require.Equal(t, true, events[0].ContextMap()["family_revoked"])
+}
+
+// Два запроса одновременно с одним токеном: проходит ровно один,
+// второй считается повторным использованием и отзывает семейство
+func TestAuthService_Refresh_ConcurrentSameToken(t *testing.T) {
+	svc, sessions, logs := newFamilyAuthService(true)
+	userID := uuid.New()
+
+	token, _ := sessions.login(t, userID)
+	_, otherDevice := sessions.login(t, userID)
+
+	pairs, errs := refreshConcurrently(svc, sessions, token, 2)
+
+	winners := 0
+	var winner service.TokenPair
+	for i, err := range errs {
+		if err == nil {
+			winners++
+			winner = pairs[i]
+			continue
+		}
+		require.ErrorIs(t, err, serr.ErrUnauthorized)
+	}
+	require.Equal(t, 1, winners)
+
+	// семейство отозвано целиком, включая сессию выигравшего запроса
+	_, err := svc.Refresh(context.Background(), winner.RefreshToken)
+	require.ErrorIs(t, err, serr.ErrUnauthorized)
+	require.Equal(t, []uuid.UUID{otherDevice}, sessions.active(userID))
+
+	require.Len(t, logs.FilterField(zap.String("event", "refresh_token_reuse")).All(), 2)
+}
+
+// Без reuse detection гонка не разлогинивает пользователя:
+// выигравший сохраняет сессию, сессия проигравшего отзывается
+func TestAuthService_Refresh_ConcurrentSameToken_NoReuseDetection(t *testing.T) {
+	svc, sessions, logs := newFamilyAuthService(false)
+	userID := uuid.New()
+
+	token, oldID := sessions.login(t, userID)
+
+	pairs, errs := refreshConcurrently(svc, sessions, token, 2)
+
+	var winner service.TokenPair
+	winners := 0
+	for i, err := range errs {
+		if err == nil {
+			winners++
+			winner = pairs[i]
+		}
+	}
+	require.Equal(t, 1, winners)
+	require.False(t, sessions.isActive(oldID))
+
+	// остаётся ровно одна активная сессия — выигравшего
+	require.Len(t, sessions.active(userID), 1)
+	_, err := svc.Refresh(context.Background(), winner.RefreshToken)
+	require.NoError(t, err)
+
+	events := logs.FilterField(zap.String("event", "refresh_token_reuse")).All()
+	require.Len(t, events, 1)
+	require.Equal(t, false, events[0].ContextMap()["family_revoked"])
+}
@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// бюджет памяти ровно на одно хэширование, хэширование заметно долгое
+func hashingLimitConfig(queueTimeout time.Duration) *config.Config {
+	cfg := testConfig()
+	cfg.Password.Argon2.Time = 10
+	cfg.Password.Limits = config.PasswordLimitsConfig{
+		MemoryBudgetMiB: 64,
+		QueueTimeout:    queueTimeout,
+	}
+	return cfg
+}
+
+func newHashingLimitService(t *testing.T, queueTimeout time.Duration) (*service.AuthService, *mocks.MockUsersRepo) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUsersRepo(ctrl)
+	sessions := mocks.NewMockSessionsRepo(ctrl)
+
+	return service.NewAuthService(users, sessions, hashingLimitConfig(queueTimeout)), users
+}
+
+// занимает весь бюджет регистрацией в фоне, пока тест не завершится
+func holdHashingBudget(t *testing.T, svc *service.AuthService, users *mocks.MockUsersRepo) <-chan struct{} {
+	t.Helper()
+
+	users.EXPECT().
+		Create(gomock.Any(), "first@mail.com", gomock.Any()).
+		Return(uuid.New(), nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = svc.Register(context.Background(), "first@mail.com", "strongpassword", "")
+	}()
+
+	require.Eventually(t, func() bool {
+		return svc.HashingStats().InUseKiB > 0
+	}, 5*time.Second, time.Millisecond)
+	return done
+}
+
+// Без места в бюджете запрос ждёт queue_timeout и получает BusyError
+func TestAuthService_Register_HashingQueueTimeout(t *testing.T) {
+	ctx := context.Background()
+	svc, users := newHashingLimitService(t, 20*time.Millisecond)
+
+	done := holdHashingBudget(t, svc, users)
+
+	_, err := svc.Register(ctx, "second@mail.com", "strongpassword", "")
+
+	require.ErrorIs(t, err, serr.ErrServerBusy)
+	var busyErr *service.BusyError
+	require.True(t, errors.As(err, &busyErr))
+	require.Equal(t, time.Second, busyErr.RetryAfter)
+
+	stats := svc.HashingStats()
+	require.Equal(t, int64(64*1024), stats.CapacityKiB)
+	require.Equal(t, int64(1), stats.Timeouts)
+	require.Equal(t, int64(0), stats.QueueDepth)
+	require.GreaterOrEqual(t, stats.WaitSecondsMax, (20 * time.Millisecond).Seconds())
+
+	<-done
+	require.Equal(t, int64(0), svc.HashingStats().InUseKiB)
+}
+
+// Дождавшийся очереди запрос выполняется, время ожидания учитывается
+func TestAuthService_Register_WaitsForHashingBudget(t *testing.T) {
+	ctx := context.Background()
+	svc, users := newHashingLimitService(t, time.Minute)
+
+	done := holdHashingBudget(t, svc, users)
+
+	users.EXPECT().
+		Create(ctx, "second@mail.com", gomock.Any()).
+		Return(uuid.New(), nil)
+
+	_, err := svc.Register(ctx, "second@mail.com", "strongpassword", "")
+	require.NoError(t, err)
+	<-done
+
+	stats := svc.HashingStats()
+	require.Equal(t, int64(2), stats.Acquired)
+	require.Equal(t, int64(0), stats.Timeouts)
+	require.Greater(t, stats.WaitSecondsTotal, 0.0)
+}
+
+// Отменённый клиентом запрос — не перегрузка
+func TestAuthService_Register_HashingCanceled(t *testing.T) {
+	svc, users := newHashingLimitService(t, time.Minute)
+
+	done := holdHashingBudget(t, svc, users)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := svc.Register(ctx, "second@mail.com", "strongpassword", "")
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NotErrorIs(t, err, serr.ErrServerBusy)
+	require.Equal(t, int64(0), svc.HashingStats().Timeouts)
+	<-done
+}
@@ -0,0 +1,437 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+func lockoutConfig() *config.Config {
+	cfg := testConfig()
+	cfg.Auth.Lockout = config.LockoutConfig{
+		Enabled:     true,
+		MaxFailures: 5,
+		BaseDelay:   time.Second,
+		MaxDelay:    30 * time.Second,
+		Duration:    15 * time.Minute,
+	}
+	return cfg
+}
+
+// сервис со счётчиками неудачных входов
+func newLockoutAuthService(t *testing.T) (*service.AuthService, *mocks.MockUsersRepo, *mocks.MockSessionsRepo, *mocks.MockLoginAttemptsRepo) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUsersRepo(ctrl)
+	sessions := mocks.NewMockSessionsRepo(ctrl)
+	attempts := mocks.NewMockLoginAttemptsRepo(ctrl)
+
+	svc := service.NewAuthService(users, sessions, lockoutConfig())
+	svc.UseLoginAttempts(attempts)
+	return svc, users, sessions, attempts
+}
+
+// Пока идёт пауза после неудачи, пароль не проверяется
+func TestAuthService_Login_BackoffAfterFailures(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, attempts := newLockoutAuthService(t)
+
+	// 3 неудачи подряд — пауза 1s * 2^2 = 4s
+	attempts.EXPECT().
+		Get(ctx, "test@mail.com").
+		Return(3, time.Now(), nil)
+
+	_, err := svc.Login(ctx, "Test@Mail.com", "strongpassword")
+
+	require.ErrorIs(t, err, serr.ErrTooManyAttempts)
+	var lockErr *service.LoginLockedError
+	require.True(t, errors.As(err, &lockErr))
+	require.InDelta(t, (4 * time.Second).Seconds(), lockErr.RetryAfter.Seconds(), 1)
+}
+
+// После max_failures — временная блокировка на duration
+func TestAuthService_Login_LockedOut(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, attempts := newLockoutAuthService(t)
+
+	attempts.EXPECT().
+		Get(ctx, "test@mail.com").
+		Return(5, time.Now().Add(-time.Minute), nil)
+
+	_, err := svc.Login(ctx, "test@mail.com", "strongpassword")
+
+	var lockErr *service.LoginLockedError
+	require.True(t, errors.As(err, &lockErr))
+	require.InDelta(t, (14 * time.Minute).Seconds(), lockErr.RetryAfter.Seconds(), 1)
+}
+
+// Пауза прошла — пароль проверяется, неудача учитывается
+func TestAuthService_Login_WrongPassword_RecordsFailure(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _, attempts := newLockoutAuthService(t)
+
+	attempts.EXPECT().
+		Get(ctx, "test@mail.com").
+		Return(2, time.Now().Add(-time.Minute), nil)
+	users.EXPECT().
+		GetByEmail(ctx, "test@mail.com").
+		Return(uuid.New(), hashForTest(t, testConfig(), "strongpassword"), nil)
+	attempts.EXPECT().
+		RecordFailure(ctx, "test@mail.com", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, resetBefore time.Time) (int, time.Time, error) {
+			require.WithinDuration(t, time.Now().Add(-15*time.Minute), resetBefore, 5*time.Second)
+			return 3, time.Now(), nil
+		})
+
+	_, err := svc.Login(ctx, "test@mail.com", "wrongpassword")
+
+	require.ErrorIs(t, err, serr.ErrInvalidCredentials)
+}
+
+// Неизвестный email: тот же ответ и тот же учёт неудач
+func TestAuthService_Login_UnknownEmail_RecordsFailure(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _, attempts := newLockoutAuthService(t)
+
+	attempts.EXPECT().
+		Get(ctx, "nobody@mail.com").
+		Return(0, time.Time{}, serr.ErrNotFound)
+	users.EXPECT().
+		GetByEmail(ctx, "nobody@mail.com").
+		Return(uuid.Nil, "", serr.ErrNotFound)
+	attempts.EXPECT().
+		RecordFailure(ctx, "nobody@mail.com", gomock.Any()).
+		Return(1, time.Now(), nil)
+
+	_, err := svc.Login(ctx, "nobody@mail.com", "strongpassword")
+
+	require.ErrorIs(t, err, serr.ErrInvalidCredentials)
+}
+
+// Верный пароль сбрасывает счётчик
+func TestAuthService_Login_Success_ResetsFailures(t *testing.T) {
+	ctx := context.Background()
+	svc, users, sessions, attempts := newLockoutAuthService(t)
+
+	userID := uuid.New()
+	attempts.EXPECT().
+		Get(ctx, "test@mail.com").
+		Return(1, time.Now().Add(-time.Minute), nil)
+	users.EXPECT().
+		GetByEmail(ctx, "test@mail.com").
+		Return(userID, hashForTest(t, testConfig(), "strongpassword"), nil)
+	sessions.EXPECT().
+		Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+	users.EXPECT().
+		GetProfile(ctx, userID).
+		Return(models.Profile{ID: userID, Email: "test@mail.com"}, nil)
+	attempts.EXPECT().
+		Reset(ctx, "test@mail.com").
+		Return(nil)
+
+	pair, err := svc.Login(ctx, "test@mail.com", "strongpassword")
+
+	require.NoError(t, err)
+	require.NotEmpty(t, pair.AccessToken)
+}
+
+// Верный пароль без второго фактора счётчик не сбрасывает,
+// а неверные TOTP-коды учитываются: перебор кодов через новые challenge
+// приводит к блокировке
+func TestAuthService_CompleteMFA_WrongCodes_LockAccount(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUsersRepo(ctrl)
+	sessions := mocks.NewMockSessionsRepo(ctrl)
+	attempts := mocks.NewMockLoginAttemptsRepo(ctrl)
+	twoFactor := mocks.NewMockTwoFactorRepo(ctrl)
+
+	// без пауз между попытками, чтобы проверить именно блокировку
+	cfg := lockoutConfig()
+	cfg.Auth.Lockout.BaseDelay = 0
+	cfg.Auth.Lockout.MaxDelay = 0
+	svc := service.NewAuthService(users, sessions, cfg)
+	svc.UseLoginAttempts(attempts)
+	svc.UseTwoFactor(twoFactor)
+
+	userID := uuid.New()
+	secret, _ := crypto.NewTOTPSecret()
+	wrong := "000000"
+	if currentCode(t, secret) == wrong {
+		wrong = "111111"
+	}
+
+	var (
+		failures int
+		last     time.Time
+	)
+	attempts.EXPECT().
+		Get(ctx, "test@mail.com").
+		DoAndReturn(func(context.Context, string) (int, time.Time, error) {
+			if failures == 0 {
+				return 0, time.Time{}, serr.ErrNotFound
+			}
+			return failures, last, nil
+		}).
+		AnyTimes()
+	attempts.EXPECT().
+		RecordFailure(ctx, "test@mail.com", gomock.Any()).
+		DoAndReturn(func(context.Context, string, time.Time) (int, time.Time, error) {
+			failures++
+			last = time.Now()
+			return failures, last, nil
+		}).
+		AnyTimes()
+	// Reset не ожидается: ни один вход не завершён
+
+	users.EXPECT().
+		GetByEmail(ctx, "test@mail.com").
+		Return(userID, hashForTest(t, testConfig(), "strongpassword"), nil).
+		AnyTimes()
+	users.EXPECT().
+		GetProfile(ctx, userID).
+		Return(models.Profile{ID: userID, Email: "test@mail.com"}, nil).
+		AnyTimes()
+	twoFactor.EXPECT().
+		GetTOTP(ctx, userID).
+		Return(secret, true, nil).
+		AnyTimes()
+	twoFactor.EXPECT().
+		CreateChallenge(ctx, userID, gomock.Any(), gomock.Any()).
+		Return(nil).
+		AnyTimes()
+	twoFactor.EXPECT().
+		ChallengeAttempt(ctx, gomock.Any(), 5).
+		Return(userID, nil).
+		AnyTimes()
+
+	var challenge string
+	for i := 0; i < cfg.Auth.Lockout.MaxFailures; i++ {
+		_, err := svc.Login(ctx, "test@mail.com", "strongpassword")
+		var mfaErr *service.MFARequiredError
+		require.True(t, errors.As(err, &mfaErr), "attempt %d: %v", i+1, err)
+		challenge = mfaErr.Challenge.Token
+
+		_, err = svc.CompleteMFA(ctx, challenge, wrong)
+		require.ErrorIs(t, err, serr.ErrInvalidOTP)
+	}
+	require.Equal(t, cfg.Auth.Lockout.MaxFailures, failures)
+
+	// новый challenge не выдаётся
+	_, err := svc.Login(ctx, "test@mail.com", "strongpassword")
+	require.ErrorIs(t, err, serr.ErrTooManyAttempts)
+
+	// и уже выданный не принимает даже верный код
+	_, err = svc.CompleteMFA(ctx, challenge, currentCode(t, secret))
+	require.ErrorIs(t, err, serr.ErrTooManyAttempts)
+}
+
+// Для неизвестного email выполняется dummy-проверка Argon2:
+// ответ занимает сопоставимое время
+func TestAuthService_Login_UnknownEmail_DummyVerify(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _ := newAuthService(t)
+
+	users.EXPECT().
+		GetByEmail(ctx, "nobody@mail.com").
+		Return(uuid.Nil, "", serr.ErrNotFound).
+		Times(2)
+
+	// первый вызов считает dummy-хэш
+	_, err := svc.Login(ctx, "nobody@mail.com", "strongpassword")
+	require.ErrorIs(t, err, serr.ErrInvalidCredentials)
+
+	cfg := testConfig()
+	hash := hashForTest(t, cfg, "strongpassword")
+	start := time.Now()
+	_, _ = crypto.VerifyPassword("strongpassword", hash)
+	verify := time.Since(start)
+
+	start = time.Now()
+	_, err = svc.Login(ctx, "nobody@mail.com", "strongpassword")
+	elapsed := time.Since(start)
+
+	require.ErrorIs(t, err, serr.ErrInvalidCredentials)
+	require.Greater(t, elapsed, verify/4)
+}
+
+// Восстановление аккаунта проверяет пароль — та же блокировка, что и при входе
+func TestAuthService_RestoreAccount_LockedOut(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, attempts := newLockoutAuthService(t)
+
+	attempts.EXPECT().
+		Get(ctx, "test@mail.com").
+		Return(5, time.Now().Add(-time.Minute), nil)
+
+	err := svc.RestoreAccount(ctx, "test@mail.com", "strongpassword")
+
+	require.ErrorIs(t, err, serr.ErrTooManyAttempts)
+}
+
+func TestAuthService_RestoreAccount_WrongPassword_RecordsFailure(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _, attempts := newLockoutAuthService(t)
+
+	attempts.EXPECT().
+		Get(ctx, "test@mail.com").
+		Return(0, time.Time{}, serr.ErrNotFound)
+	users.EXPECT().
+		GetPendingDeletion(ctx, "test@mail.com").
+		Return(uuid.New(), hashForTest(t, testConfig(), "strongpassword"), time.Now().Add(time.Hour), nil)
+	attempts.EXPECT().
+		RecordFailure(ctx, "test@mail.com", gomock.Any()).
+		Return(1, time.Now(), nil)
+
+	err := svc.RestoreAccount(ctx, "test@mail.com", "wrongpassword")
+
+	require.ErrorIs(t, err, serr.ErrInvalidCredentials)
+}
+
+// Аккаунт не ждёт удаления: тоже dummy-проверка, время ответа не выдаёт,
+// какие аккаунты помечены к удалению
+func TestAuthService_RestoreAccount_NotPending_DummyVerify(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _ := newAuthService(t)
+
+	users.EXPECT().
+		GetPendingDeletion(ctx, "nobody@mail.com").
+		Return(uuid.Nil, "", time.Time{}, serr.ErrNotFound).
+		Times(2)
+
+	// первый вызов считает dummy-хэш
+	require.ErrorIs(t, svc.RestoreAccount(ctx, "nobody@mail.com", "strongpassword"), serr.ErrInvalidCredentials)
+
+	hash := hashForTest(t, testConfig(), "strongpassword")
+	start := time.Now()
+	_, _ = crypto.VerifyPassword("strongpassword", hash)
+	verify := time.Since(start)
+
+	start = time.Now()
+	err := svc.RestoreAccount(ctx, "nobody@mail.com", "strongpassword")
+	elapsed := time.Since(start)
+
+	require.ErrorIs(t, err, serr.ErrInvalidCredentials)
+	require.Greater(t, elapsed, verify/4)
+}
+
+// С украденным access-токеном пароль через reauth не перебрать
+func TestAuthService_Reauthenticate_LockedOut(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _, attempts := newLockoutAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetProfile(ctx, userID).
+		Return(models.Profile{ID: userID, Email: "test@mail.com"}, nil)
+	attempts.EXPECT().
+		Get(ctx, "test@mail.com").
+		Return(5, time.Now().Add(-time.Minute), nil)
+
+	_, err := svc.Reauthenticate(ctx, userID, uuid.New(), "strongpassword", "")
+
+	require.ErrorIs(t, err, serr.ErrTooManyAttempts)
+}
+
+func TestAuthService_Reauthenticate_WrongPassword_RecordsFailure(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _, attempts := newLockoutAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetProfile(ctx, userID).
+		Return(models.Profile{ID: userID, Email: "test@mail.com"}, nil)
+	attempts.EXPECT().
+		Get(ctx, "test@mail.com").
+		Return(0, time.Time{}, serr.ErrNotFound)
+	users.EXPECT().
+		GetPasswordHash(ctx, userID).
+		Return(hashForTest(t, testConfig(), "strongpassword"), nil)
+	attempts.EXPECT().
+		RecordFailure(ctx, "test@mail.com", gomock.Any()).
+		Return(1, time.Now(), nil)
+
+	_, err := svc.Reauthenticate(ctx, userID, uuid.New(), "wrongpassword", "")
+
+	require.ErrorIs(t, err, serr.ErrInvalidCredentials)
+}
+
+// Украденный access-токен не даёт перебирать пароль через смену пароля:
+// после max_failures неудач старый пароль больше не проверяется
+func TestAuthService_ChangePassword_WrongPasswords_LockAccount(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _, attempts := newLockoutAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetProfile(ctx, userID).
+		Return(models.Profile{ID: userID, Email: "test@mail.com"}, nil).
+		Times(2)
+	gomock.InOrder(
+		attempts.EXPECT().
+			Get(ctx, "test@mail.com").
+			Return(4, time.Now().Add(-time.Minute), nil),
+		attempts.EXPECT().
+			Get(ctx, "test@mail.com").
+			Return(5, time.Now(), nil),
+	)
+	users.EXPECT().
+		GetPasswordHash(ctx, userID).
+		Return(hashForTest(t, testConfig(), "strongpassword"), nil)
+	attempts.EXPECT().
+		RecordFailure(ctx, "test@mail.com", gomock.Any()).
+		Return(5, time.Now(), nil)
+
+	_, err := svc.ChangePassword(ctx, userID, "wrongpassword", "NewPass123")
+	require.ErrorIs(t, err, serr.ErrInvalidCredentials)
+
+	_, err = svc.ChangePassword(ctx, userID, "strongpassword", "NewPass123")
+	require.ErrorIs(t, err, serr.ErrTooManyAttempts)
+}
+
+// То же для удаления аккаунта
+func TestAuthService_RequestDeletion_WrongPasswords_LockAccount(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _, attempts := newLockoutAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetProfile(ctx, userID).
+		Return(models.Profile{ID: userID, Email: "test@mail.com"}, nil).
+		Times(2)
+	gomock.InOrder(
+		attempts.EXPECT().
+			Get(ctx, "test@mail.com").
+			Return(4, time.Now().Add(-time.Minute), nil),
+		attempts.EXPECT().
+			Get(ctx, "test@mail.com").
+			Return(5, time.Now(), nil),
+	)
+	users.EXPECT().
+		GetPasswordHash(ctx, userID).
+		Return(hashForTest(t, testConfig(), "strongpassword"), nil)
+	attempts.EXPECT().
+		RecordFailure(ctx, "test@mail.com", gomock.Any()).
+		Return(5, time.Now(), nil)
+
+	_, err := svc.RequestDeletion(ctx, userID, "wrongpassword")
+	require.ErrorIs(t, err, serr.ErrInvalidCredentials)
+
+	_, err = svc.RequestDeletion(ctx, userID, "strongpassword")
+	require.ErrorIs(t, err, serr.ErrTooManyAttempts)
+}
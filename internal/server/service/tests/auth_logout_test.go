@@ -0,0 +1,81 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// Logout текущей сессии
+func TestAuthService_Logout_Current(t *testing.T) {
+	ctx := context.Background()
+	svc, _, sessions := newAuthService(t)
+
+	sessID := uuid.New()
+	userID := uuid.New()
+
+	sessions.EXPECT().
+		GetByRefreshHash(ctx, gomock.Any()).
+		Return(sessID, userID, time.Now().Add(time.Hour), nil, nil, uuid.New(), nil)
+	sessions.EXPECT().
+		Revoke(ctx, userID, sessID).
+		Return(nil)
+
+	require.NoError(t, svc.Logout(ctx, "refresh-token", false))
+}
+
+// Logout со всех устройств
+func TestAuthService_Logout_All(t *testing.T) {
+	ctx := context.Background()
+	svc, _, sessions := newAuthService(t)
+
+	userID := uuid.New()
+
+	sessions.EXPECT().
+		GetByRefreshHash(ctx, gomock.Any()).
+		Return(uuid.New(), userID, time.Now().Add(time.Hour), nil, nil, uuid.New(), nil)
+	sessions.EXPECT().
+		RevokeAllForUser(ctx, userID).
+		Return(nil)
+
+	require.NoError(t, svc.Logout(ctx, "refresh-token", true))
+}
+
+// Повторный logout отозванным токеном — не ошибка
+func TestAuthService_Logout_AlreadyRevoked(t *testing.T) {
+	ctx := context.Background()
+	svc, _, sessions := newAuthService(t)
+
+	revoked := time.Now().Add(-time.Minute)
+	sessions.EXPECT().
+		GetByRefreshHash(ctx, gomock.Any()).
+		Return(uuid.New(), uuid.New(), time.Now().Add(time.Hour), &revoked, nil, uuid.New(), nil)
+
+	require.NoError(t, svc.Logout(ctx, "refresh-token", false))
+}
+
+// Отозванным токеном нельзя разлогинить все устройства
+func TestAuthService_Logout_AllWithRevokedToken(t *testing.T) {
+	ctx := context.Background()
+	svc, _, sessions := newAuthService(t)
+
+	revoked := time.Now().Add(-time.Minute)
+	sessions.EXPECT().
+		GetByRefreshHash(ctx, gomock.Any()).
+		Return(uuid.New(), uuid.New(), time.Now().Add(time.Hour), &revoked, nil, uuid.New(), nil)
+
+	require.ErrorIs(t, svc.Logout(ctx, "refresh-token", true), serr.ErrUnauthorized)
+}
+
+// Пустой токен
+func TestAuthService_Logout_EmptyToken(t *testing.T) {
+	svc, _, _ := newAuthService(t)
+
+	require.ErrorIs(t, svc.Logout(context.Background(), "  ", false), serr.ErrInvalidInput)
+}
@@ -0,0 +1,37 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// Сводка по аккаунту берётся из репозитория пользователей
+func TestAuthService_Me(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _ := newAuthService(t)
+
+	userID := uuid.New()
+	want := models.Profile{ID: userID, Email: "a@b.c", SecretCount: 2, StorageBytes: 100, ActiveSessions: 1}
+
+	users.EXPECT().
+		GetProfile(ctx, userID).
+		Return(want, nil)
+
+	got, err := svc.Me(ctx, userID)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+// Пустой userID
+func TestAuthService_Me_EmptyUser(t *testing.T) {
+	svc, _, _ := newAuthService(t)
+
+	_, err := svc.Me(context.Background(), uuid.Nil)
+	require.ErrorIs(t, err, serr.ErrUserIDEmpty)
+}
@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// Смена пароля: новый хэш, отзыв всех сессий, новая пара токенов
+func TestAuthService_ChangePassword_OK(t *testing.T) {
+	ctx := context.Background()
+	svc, users, sessions := newAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetPasswordHash(ctx, userID).
+		Return(hashForTest(t, testConfig(), "oldpassword"), nil)
+
+	var newHash string
+	gomock.InOrder(
+		users.EXPECT().
+			UpdatePasswordHash(ctx, userID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uuid.UUID, h string) error {
+				newHash = h
+				return nil
+			}),
+		sessions.EXPECT().
+			RevokeAllForUser(ctx, userID).
+			Return(nil),
+		sessions.EXPECT().
+			Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(uuid.New(), nil),
+	)
+
+	pair, err := svc.ChangePassword(ctx, userID, "oldpassword", "newpassword")
+
+	require.NoError(t, err)
+	require.NotEmpty(t, pair.AccessToken)
+	require.NotEmpty(t, pair.RefreshToken)
+
+	ok, err := crypto.VerifyPassword("newpassword", newHash)
+	require.NoError(t, err)
+	require.True(t, ok)
+}
+
+// Смена пароля отзывает и API-токены
+func TestAuthService_ChangePassword_RevokesAPITokens(t *testing.T) {
+	ctx := context.Background()
+	svc, users, sessions := newAuthService(t)
+	apiTokens := mocks.NewMockAPITokensRepo(gomock.NewController(t))
+	svc.UseAPITokens(apiTokens)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetPasswordHash(ctx, userID).
+		Return(hashForTest(t, testConfig(), "oldpassword"), nil)
+	gomock.InOrder(
+		users.EXPECT().
+			UpdatePasswordHash(ctx, userID, gomock.Any()).
+			Return(nil),
+		sessions.EXPECT().
+			RevokeAllForUser(ctx, userID).
+			Return(nil),
+		apiTokens.EXPECT().
+			RevokeAllForUser(ctx, userID).
+			Return(nil),
+		sessions.EXPECT().
+			Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(uuid.New(), nil),
+	)
+
+	_, err := svc.ChangePassword(ctx, userID, "oldpassword", "newpassword")
+
+	require.NoError(t, err)
+}
+
+// Неверный старый пароль — ничего не меняется
+func TestAuthService_ChangePassword_WrongOldPassword(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _ := newAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetPasswordHash(ctx, userID).
+		Return(hashForTest(t, testConfig(), "oldpassword"), nil)
+
+	_, err := svc.ChangePassword(ctx, userID, "wrongpassword", "newpassword")
+
+	require.ErrorIs(t, err, serr.ErrInvalidCredentials)
+}
+
+// Новый пароль проверяется по правилам регистрации
+func TestAuthService_ChangePassword_InvalidInput(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newAuthService(t)
+
+	cases := []struct {
+		name                     string
+		oldPassword, newPassword string
+	}{
+		{"short new", "oldpassword", "short"},
+		{"blank new", "oldpassword", "        "},
+		{"empty old", "", "newpassword"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := svc.ChangePassword(ctx, uuid.New(), c.oldPassword, c.newPassword)
+			require.ErrorIs(t, err, serr.ErrInvalidInput)
+		})
+	}
+
+	_, err := svc.ChangePassword(ctx, uuid.Nil, "oldpassword", "newpassword")
+	require.ErrorIs(t, err, serr.ErrUserIDEmpty)
+}
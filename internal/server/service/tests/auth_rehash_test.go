@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	crypt "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// Хэш bcrypt при текущем argon2id заменяется после успешного логина
+func TestAuthService_Login_RehashesOutdatedHash(t *testing.T) {
+	ctx := context.Background()
+	svc, users, sessions := newAuthService(t)
+
+	userID := uuid.New()
+	oldHash, err := crypt.BcryptHasher{Cost: 4}.Hash("password123")
+	require.NoError(t, err)
+
+	users.EXPECT().
+		GetByEmail(ctx, "test@mail.com").
+		Return(userID, oldHash, nil)
+	users.EXPECT().
+		UpdatePasswordHash(ctx, userID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, hash string) error {
+			require.True(t, strings.HasPrefix(hash, "argon2id$"), "unexpected hash %q", hash)
+			ok, err := crypt.VerifyPassword("password123", hash)
+			require.NoError(t, err)
+			require.True(t, ok)
+			return nil
+		})
+	sessions.EXPECT().
+		Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+
+	_, err = svc.Login(ctx, "test@mail.com", "password123")
+	require.NoError(t, err)
+}
+
+// Хэш с актуальными параметрами не перезаписывается
+func TestAuthService_Login_NoRehashForCurrentHash(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	users := mocks.NewMockUsersRepo(ctrl)
+	sessions := mocks.NewMockSessionsRepo(ctrl)
+
+	cfg := testConfig()
+	cfg.Password.Hasher = "bcrypt"
+	cfg.Password.Bcrypt.Cost = 4
+	svc := service.NewAuthService(users, sessions, cfg)
+
+	userID := uuid.New()
+	hash, err := crypt.BcryptHasher{Cost: 4}.Hash("password123")
+	require.NoError(t, err)
+
+	users.EXPECT().
+		GetByEmail(ctx, "test@mail.com").
+		Return(userID, hash, nil)
+	sessions.EXPECT().
+		Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+
+	_, err = svc.Login(ctx, "test@mail.com", "password123")
+	require.NoError(t, err)
+}
+
+// bcrypt без pepper не принимает пароль длиннее 72 байт:
+// это ошибка ввода, а не внутренняя ошибка сервера
+func TestAuthService_Bcrypt_PasswordTooLong(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	users := mocks.NewMockUsersRepo(ctrl)
+	sessions := mocks.NewMockSessionsRepo(ctrl)
+
+	cfg := testConfig()
+	cfg.Password.Hasher = "bcrypt"
+	cfg.Password.Bcrypt.Cost = 4
+	svc := service.NewAuthService(users, sessions, cfg)
+
+	long := strings.Repeat("a", crypt.BcryptMaxPasswordLen+1)
+
+	_, err := svc.Register(ctx, "test@mail.com", long, "")
+	require.ErrorIs(t, err, serr.ErrInvalidInput)
+
+	_, err = svc.ChangePassword(ctx, uuid.New(), "password123", long)
+	require.ErrorIs(t, err, serr.ErrInvalidInput)
+
+	// 72 байта ещё допустимы
+	users.EXPECT().
+		Create(ctx, "test@mail.com", gomock.Any()).
+		Return(uuid.New(), nil)
+	_, err = svc.Register(ctx, "test@mail.com", long[1:], "")
+	require.NoError(t, err)
+}
+
+// С pepper в bcrypt уходит HMAC, и длинный пароль допустим
+func TestAuthService_BcryptWithPepper_LongPassword(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	users := mocks.NewMockUsersRepo(ctrl)
+	sessions := mocks.NewMockSessionsRepo(ctrl)
+
+	cfg := testConfig()
+	cfg.Password.Hasher = "bcrypt"
+	cfg.Password.Bcrypt.Cost = 4
+	cfg.Password.Pepper = "pepperpepperpepperpepperpepper123"
+	svc := service.NewAuthService(users, sessions, cfg)
+
+	users.EXPECT().
+		Create(ctx, "test@mail.com", gomock.Any()).
+		Return(uuid.New(), nil)
+
+	_, err := svc.Register(ctx, "test@mail.com", strings.Repeat("a", 100), "")
+	require.NoError(t, err)
+}
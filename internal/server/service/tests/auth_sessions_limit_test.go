@@ -0,0 +1,111 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// сервис с лимитом сессий
+func newLimitedAuthService(t *testing.T, policy string) (*service.AuthService, *mocks.MockUsersRepo, *mocks.MockSessionsRepo) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUsersRepo(ctrl)
+	sessions := mocks.NewMockSessionsRepo(ctrl)
+
+	cfg := testConfig()
+	cfg.Auth.Sessions.MaxSessionsPerUser = 2
+	cfg.Auth.Sessions.LimitPolicy = policy
+
+	return service.NewAuthService(users, sessions, cfg), users, sessions
+}
+
+func hashForTest(t *testing.T, cfg *config.Config, password string) string {
+	t.Helper()
+
+	hash, err := crypto.HashPassword(password, crypto.Argon2Params{
+		Time:      cfg.Password.Argon2.Time,
+		MemoryKiB: cfg.Password.Argon2.MemoryKiB,
+		Threads:   cfg.Password.Argon2.Threads,
+		KeyLen:    cfg.Password.Argon2.KeyLen,
+		SaltLen:   cfg.Password.Argon2.SaltLen,
+	})
+	require.NoError(t, err)
+	return hash
+}
+
+// revoke_oldest: после создания сессии лишние отзываются
+func TestAuthService_Login_SessionLimit_RevokesOldest(t *testing.T) {
+	ctx := context.Background()
+	svc, users, sessions := newLimitedAuthService(t, "revoke_oldest")
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetByEmail(ctx, "test@mail.com").
+		Return(userID, hashForTest(t, testConfig(), "strongpassword"), nil)
+
+	gomock.InOrder(
+		sessions.EXPECT().
+			Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(uuid.New(), nil),
+		sessions.EXPECT().
+			RevokeOldestActive(ctx, userID, 2).
+			Return(nil),
+	)
+
+	tokens, err := svc.Login(ctx, "test@mail.com", "strongpassword")
+
+	require.NoError(t, err)
+	require.NotEmpty(t, tokens.RefreshToken)
+}
+
+// reject: при достижении лимита логин отклоняется, сессия не создаётся
+func TestAuthService_Login_SessionLimit_Reject(t *testing.T) {
+	ctx := context.Background()
+	svc, users, sessions := newLimitedAuthService(t, "reject")
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetByEmail(ctx, "test@mail.com").
+		Return(userID, hashForTest(t, testConfig(), "strongpassword"), nil)
+
+	sessions.EXPECT().
+		CountActive(ctx, userID).
+		Return(2, nil)
+
+	_, err := svc.Login(ctx, "test@mail.com", "strongpassword")
+
+	require.ErrorIs(t, err, serr.ErrTooManySessions)
+}
+
+// reject: ниже лимита логин проходит
+func TestAuthService_Login_SessionLimit_RejectBelowLimit(t *testing.T) {
+	ctx := context.Background()
+	svc, users, sessions := newLimitedAuthService(t, "reject")
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetByEmail(ctx, "test@mail.com").
+		Return(userID, hashForTest(t, testConfig(), "strongpassword"), nil)
+
+	sessions.EXPECT().
+		CountActive(ctx, userID).
+		Return(1, nil)
+	sessions.EXPECT().
+		Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+
+	_, err := svc.Login(ctx, "test@mail.com", "strongpassword")
+
+	require.NoError(t, err)
+}
@@ -0,0 +1,51 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// Список активных сессий пользователя
+func TestAuthService_ListSessions(t *testing.T) {
+	ctx := context.Background()
+	svc, _, sessions := newAuthService(t)
+
+	userID := uuid.New()
+	want := []models.Session{{ID: uuid.New(), DeviceName: "laptop"}}
+
+	sessions.EXPECT().
+		ListActive(ctx, userID).
+		Return(want, nil)
+
+	got, err := svc.ListSessions(ctx, userID)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+// Отзыв чужой или несуществующей сессии — ErrNotFound
+func TestAuthService_RevokeSession_NotFound(t *testing.T) {
+	ctx := context.Background()
+	svc, _, sessions := newAuthService(t)
+
+	userID := uuid.New()
+	sessID := uuid.New()
+
+	sessions.EXPECT().
+		Revoke(ctx, userID, sessID).
+		Return(serr.ErrNotFound)
+
+	require.ErrorIs(t, svc.RevokeSession(ctx, userID, sessID), serr.ErrNotFound)
+}
+
+// Пустой userID — ошибка без обращения к репозиторию
+func TestAuthService_RevokeSession_EmptyUser(t *testing.T) {
+	svc, _, _ := newAuthService(t)
+
+	require.ErrorIs(t, svc.RevokeSession(context.Background(), uuid.Nil, uuid.New()), serr.ErrUserIDEmpty)
+}
@@ -56,7 +56,7 @@ func TestAuthService_Login_OK(t *testing.T) {
 		Return(userID, hash, nil)
 
 	sessions.EXPECT().
-		Create(gomock.Any(), userID, gomock.Any(), gomock.Any()).
+		Create(gomock.Any(), userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(uuid.New(), nil)
 
 	tokens, err := svc.Login(ctx, "test@mail.com", password)
@@ -119,15 +119,16 @@ func TestAuthService_Refresh_Rotate_OK(t *testing.T) {
 	oldSessID := uuid.New()
 	newSessID := uuid.New()
 	userID := uuid.New()
+	familyID := uuid.New()
 
 	expires := time.Now().Add(time.Hour)
 
 	sessions.EXPECT().
 		GetByRefreshHash(ctx, gomock.Any()).
-		Return(oldSessID, userID, expires, nil, nil, nil)
+		Return(oldSessID, userID, expires, nil, nil, familyID, nil)
 
 	sessions.EXPECT().
-		Create(ctx, userID, gomock.Any(), gomock.Any()).
+		Create(ctx, userID, familyID, gomock.Any(), gomock.Any(), gomock.Any()).
 		Return(newSessID, nil)
 
 	sessions.EXPECT().
@@ -141,21 +142,22 @@ func TestAuthService_Refresh_Rotate_OK(t *testing.T) {
 	require.NotEmpty(t, tokens.RefreshToken)
 }
 
-// Повторное использование refresh
+// Повторное использование refresh: отзывается только семейство токена
 func TestAuthService_Refresh_ReusedToken(t *testing.T) {
 	ctx := context.Background()
 	svc, _, sessions := newAuthService(t)
 
 	userID := uuid.New()
+	familyID := uuid.New()
 	now := time.Now()
 	revoked := now.Add(-time.Minute)
 
 	sessions.EXPECT().
 		GetByRefreshHash(ctx, gomock.Any()).
-		Return(uuid.New(), userID, now.Add(time.Hour), &revoked, nil, nil)
+		Return(uuid.New(), userID, now.Add(time.Hour), &revoked, nil, familyID, nil)
 
 	sessions.EXPECT().
-		RevokeAllForUser(ctx, userID).
+		RevokeFamily(ctx, familyID).
 		Return(nil)
 
 	_, err := svc.Refresh(ctx, "reused-token")
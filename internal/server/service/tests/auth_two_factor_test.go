@@ -0,0 +1,298 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// сервис с подключённым хранилищем 2FA
+func newTwoFactorAuthService(t *testing.T) (*service.AuthService, *mocks.MockUsersRepo, *mocks.MockSessionsRepo, *mocks.MockTwoFactorRepo) {
+	t.Helper()
+
+	svc, users, sessions := newAuthService(t)
+	twoFactor := mocks.NewMockTwoFactorRepo(gomock.NewController(t))
+	svc.UseTwoFactor(twoFactor)
+	return svc, users, sessions, twoFactor
+}
+
+func currentCode(t *testing.T, secret string) string {
+	t.Helper()
+	code, err := crypto.TOTPCode(secret, crypto.TOTPStep(time.Now()))
+	require.NoError(t, err)
+	return code
+}
+
+// При подключённом TOTP логин возвращает challenge, сессия не создаётся
+func TestAuthService_Login_TOTPEnabled_ReturnsChallenge(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _, twoFactor := newTwoFactorAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetByEmail(ctx, "test@mail.com").
+		Return(userID, hashForTest(t, testConfig(), "strongpassword"), nil)
+	twoFactor.EXPECT().
+		GetTOTP(ctx, userID).
+		Return("SECRET", true, nil)
+
+	var savedHash []byte
+	twoFactor.EXPECT().
+		CreateChallenge(ctx, userID, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, h []byte, exp time.Time) error {
+			savedHash = h
+			require.WithinDuration(t, time.Now().Add(5*time.Minute), exp, 5*time.Second)
+			return nil
+		})
+
+	pair, err := svc.Login(ctx, "test@mail.com", "strongpassword")
+
+	require.ErrorIs(t, err, serr.ErrMFARequired)
+	require.Empty(t, pair.AccessToken)
+
+	var mfaErr *service.MFARequiredError
+	require.True(t, errors.As(err, &mfaErr))
+	require.NotEmpty(t, mfaErr.Challenge.Token)
+	// в БД только хэш challenge
+	require.Equal(t, crypto.HashRefreshToken(mfaErr.Challenge.Token), savedHash)
+}
+
+// Неподтверждённый TOTP не мешает входу по паролю
+func TestAuthService_Login_TOTPPending_IssuesTokens(t *testing.T) {
+	ctx := context.Background()
+	svc, users, sessions, twoFactor := newTwoFactorAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetByEmail(ctx, "test@mail.com").
+		Return(userID, hashForTest(t, testConfig(), "strongpassword"), nil)
+	twoFactor.EXPECT().
+		GetTOTP(ctx, userID).
+		Return("SECRET", false, nil)
+	sessions.EXPECT().
+		Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+
+	pair, err := svc.Login(ctx, "test@mail.com", "strongpassword")
+
+	require.NoError(t, err)
+	require.NotEmpty(t, pair.AccessToken)
+}
+
+// Верный TOTP-код завершает вход
+func TestAuthService_CompleteMFA_TOTP_OK(t *testing.T) {
+	ctx := context.Background()
+	svc, _, sessions, twoFactor := newTwoFactorAuthService(t)
+
+	userID := uuid.New()
+	secret, _ := crypto.NewTOTPSecret()
+
+	twoFactor.EXPECT().
+		ChallengeAttempt(ctx, crypto.HashRefreshToken("challenge"), 5).
+		Return(userID, nil)
+	twoFactor.EXPECT().
+		GetTOTP(ctx, userID).
+		Return(secret, true, nil)
+	twoFactor.EXPECT().
+		UseTOTPStep(ctx, userID, gomock.Any()).
+		Return(nil)
+	twoFactor.EXPECT().
+		CompleteChallenge(ctx, crypto.HashRefreshToken("challenge")).
+		Return(nil)
+	sessions.EXPECT().
+		Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+
+	pair, err := svc.CompleteMFA(ctx, "challenge", currentCode(t, secret))
+
+	require.NoError(t, err)
+	require.NotEmpty(t, pair.AccessToken)
+	require.NotEmpty(t, pair.RefreshToken)
+}
+
+// Неверный код — токены не выдаются, challenge не гасится
+func TestAuthService_CompleteMFA_WrongCode(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, twoFactor := newTwoFactorAuthService(t)
+
+	userID := uuid.New()
+	secret, _ := crypto.NewTOTPSecret()
+	code := currentCode(t, secret)
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+
+	twoFactor.EXPECT().
+		ChallengeAttempt(ctx, gomock.Any(), 5).
+		Return(userID, nil)
+	twoFactor.EXPECT().
+		GetTOTP(ctx, userID).
+		Return(secret, true, nil)
+
+	_, err := svc.CompleteMFA(ctx, "challenge", wrong)
+
+	require.ErrorIs(t, err, serr.ErrInvalidOTP)
+}
+
+// Повторный ввод того же TOTP-кода отклоняется
+func TestAuthService_CompleteMFA_ReplayedCode(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, twoFactor := newTwoFactorAuthService(t)
+
+	userID := uuid.New()
+	secret, _ := crypto.NewTOTPSecret()
+
+	twoFactor.EXPECT().
+		ChallengeAttempt(ctx, gomock.Any(), 5).
+		Return(userID, nil)
+	twoFactor.EXPECT().
+		GetTOTP(ctx, userID).
+		Return(secret, true, nil)
+	twoFactor.EXPECT().
+		UseTOTPStep(ctx, userID, gomock.Any()).
+		Return(serr.ErrConflict)
+
+	_, err := svc.CompleteMFA(ctx, "challenge", currentCode(t, secret))
+
+	require.ErrorIs(t, err, serr.ErrInvalidOTP)
+}
+
+// Код восстановления гасится по хэшу
+func TestAuthService_CompleteMFA_RecoveryCode(t *testing.T) {
+	ctx := context.Background()
+	svc, _, sessions, twoFactor := newTwoFactorAuthService(t)
+
+	userID := uuid.New()
+
+	twoFactor.EXPECT().
+		ChallengeAttempt(ctx, gomock.Any(), 5).
+		Return(userID, nil)
+	twoFactor.EXPECT().
+		UseRecoveryCode(ctx, userID, crypto.HashRecoveryCode("abcde-fghjk")).
+		Return(nil)
+	twoFactor.EXPECT().
+		CompleteChallenge(ctx, gomock.Any()).
+		Return(nil)
+	sessions.EXPECT().
+		Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+
+	_, err := svc.CompleteMFA(ctx, "challenge", "ABCDE-FGHJK")
+
+	require.NoError(t, err)
+}
+
+// Истёкший/использованный challenge или исчерпанные попытки
+func TestAuthService_CompleteMFA_ChallengeRejected(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, twoFactor := newTwoFactorAuthService(t)
+
+	twoFactor.EXPECT().
+		ChallengeAttempt(ctx, gomock.Any(), 5).
+		Return(uuid.Nil, serr.ErrUnauthorized)
+
+	_, err := svc.CompleteMFA(ctx, "challenge", "123456")
+
+	require.ErrorIs(t, err, serr.ErrUnauthorized)
+}
+
+// Подключение: секрет сохраняется, URI содержит email
+func TestAuthService_EnrollTOTP(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _, twoFactor := newTwoFactorAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetProfile(ctx, userID).
+		Return(models.Profile{ID: userID, Email: "user@example.com"}, nil)
+
+	var saved string
+	twoFactor.EXPECT().
+		SaveTOTPSecret(ctx, userID, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, secret string) error {
+			saved = secret
+			return nil
+		})
+
+	enr, err := svc.EnrollTOTP(ctx, userID)
+
+	require.NoError(t, err)
+	require.Equal(t, saved, enr.Secret)
+	require.True(t, strings.HasPrefix(enr.URI, "otpauth://totp/test:user@example.com?"))
+	require.Contains(t, enr.URI, "secret="+saved)
+}
+
+// Подтверждение: коды восстановления возвращаются, в БД уходят их хэши
+func TestAuthService_ConfirmTOTP_ReturnsRecoveryCodes(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, twoFactor := newTwoFactorAuthService(t)
+
+	userID := uuid.New()
+	secret, _ := crypto.NewTOTPSecret()
+
+	twoFactor.EXPECT().
+		GetTOTP(ctx, userID).
+		Return(secret, false, nil)
+
+	var saved [][]byte
+	twoFactor.EXPECT().
+		EnableTOTP(ctx, userID, crypto.TOTPStep(time.Now()), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, _ int64, hashes [][]byte) error {
+			saved = hashes
+			return nil
+		})
+
+	codes, err := svc.ConfirmTOTP(ctx, userID, currentCode(t, secret))
+
+	require.NoError(t, err)
+	require.Len(t, codes, 10)
+	require.Len(t, saved, 10)
+	for i, c := range codes {
+		require.Equal(t, crypto.HashRecoveryCode(c), saved[i])
+	}
+}
+
+// Подтверждение неверным кодом
+func TestAuthService_ConfirmTOTP_WrongCode(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, twoFactor := newTwoFactorAuthService(t)
+
+	userID := uuid.New()
+	twoFactor.EXPECT().
+		GetTOTP(ctx, userID).
+		Return(rfcSecretForService, false, nil)
+
+	_, err := svc.ConfirmTOTP(ctx, userID, "abc")
+
+	require.ErrorIs(t, err, serr.ErrInvalidOTP)
+}
+
+// Уже подключён
+func TestAuthService_ConfirmTOTP_AlreadyEnabled(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, twoFactor := newTwoFactorAuthService(t)
+
+	userID := uuid.New()
+	twoFactor.EXPECT().
+		GetTOTP(ctx, userID).
+		Return(rfcSecretForService, true, nil)
+
+	_, err := svc.ConfirmTOTP(ctx, userID, "123456")
+
+	require.ErrorIs(t, err, serr.ErrAlreadyExists)
+}
+
+const rfcSecretForService = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
@@ -0,0 +1,276 @@
+package tests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// сервис с подключёнными клиентскими сертификатами и CA, которым выпущен clientPEM
+func newClientCertAuthService(t *testing.T) (*service.AuthService, *mocks.MockUsersRepo, *mocks.MockSessionsRepo, *mocks.MockClientCertsRepo, []byte) {
+	t.Helper()
+
+	svc, users, sessions := newAuthService(t)
+	certs := mocks.NewMockClientCertsRepo(gomock.NewController(t))
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, _ := x509.ParseCertificate(caDER)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "laptop"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	svc.UseClientCerts(certs, roots)
+
+	return svc, users, sessions, certs, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// контекст запроса с предъявленным клиентским сертификатом
+func withCert(fp []byte) context.Context {
+	return service.WithClientInfo(context.Background(), models.ClientInfo{CertFingerprint: fp})
+}
+
+// cnf.x5t#S256 из access-токена
+func tokenThumbprint(t *testing.T, token string) string {
+	t.Helper()
+
+	claims := &crypto.AccessClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return []byte(testConfig().Auth.JWT.SigningKey), nil
+	})
+	require.NoError(t, err)
+	if claims.Confirmation == nil {
+		return ""
+	}
+	return claims.Confirmation.CertThumbprint
+}
+
+// Привязка: в БД уходит отпечаток, имя по умолчанию — CN
+func TestAuthService_BindClientCert_OK(t *testing.T) {
+	svc, _, _, certs, clientPEM := newClientCertAuthService(t)
+
+	userID := uuid.New()
+	block, _ := pem.Decode(clientPEM)
+	cert, _ := x509.ParseCertificate(block.Bytes)
+	ctx := withCert(crypto.CertFingerprint(cert))
+
+	certs.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, c models.ClientCert) (models.ClientCert, error) {
+			require.Equal(t, userID, c.UserID)
+			require.Equal(t, "laptop", c.Name)
+			require.Equal(t, crypto.CertFingerprint(cert), c.Fingerprint)
+			c.ID = uuid.New()
+			return c, nil
+		})
+
+	bound, err := svc.BindClientCert(ctx, userID, string(clientPEM), "  ")
+
+	require.NoError(t, err)
+	require.NotEqual(t, uuid.Nil, bound.ID)
+}
+
+// Сертификат из тела не предъявлен в TLS-рукопожатии — владение ключом не доказано
+func TestAuthService_BindClientCert_NotPresented(t *testing.T) {
+	svc, _, _, _, clientPEM := newClientCertAuthService(t)
+	_, _, _, _, otherPEM := newClientCertAuthService(t)
+
+	block, _ := pem.Decode(otherPEM)
+	other, _ := x509.ParseCertificate(block.Bytes)
+
+	for name, ctx := range map[string]context.Context{
+		"no mtls":    context.Background(),
+		"other cert": withCert(crypto.CertFingerprint(other)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.BindClientCert(ctx, uuid.New(), string(clientPEM), "laptop")
+			require.ErrorIs(t, err, serr.ErrClientCertRequired)
+		})
+	}
+}
+
+// Сертификат не от доверенного CA
+func TestAuthService_BindClientCert_Untrusted(t *testing.T) {
+	svc, _, _, _, _ := newClientCertAuthService(t)
+	_, _, _, _, foreignPEM := newClientCertAuthService(t)
+
+	_, err := svc.BindClientCert(context.Background(), uuid.New(), string(foreignPEM), "laptop")
+
+	require.ErrorIs(t, err, serr.ErrInvalidInput)
+}
+
+// mTLS на сервере выключен
+func TestAuthService_BindClientCert_Disabled(t *testing.T) {
+	svc, _, _ := newAuthService(t)
+
+	_, err := svc.BindClientCert(context.Background(), uuid.New(), "pem", "laptop")
+
+	require.ErrorIs(t, err, serr.ErrNotFound)
+}
+
+// Сертификат привязан, а вход без него — токены не выдаются
+func TestAuthService_Login_ClientCertRequired(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _, certs, _ := newClientCertAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetByEmail(ctx, "test@mail.com").
+		Return(userID, hashForTest(t, testConfig(), "strongpassword"), nil)
+	certs.EXPECT().
+		Match(ctx, userID, []byte(nil)).
+		Return(true, false, nil)
+
+	_, err := svc.Login(ctx, "test@mail.com", "strongpassword")
+
+	require.ErrorIs(t, err, serr.ErrClientCertRequired)
+}
+
+// Вход с привязанным сертификатом — access-токен привязан к нему
+func TestAuthService_Login_BoundCertificate(t *testing.T) {
+	fp := []byte("fingerprint")
+	ctx := withCert(fp)
+	svc, users, sessions, certs, _ := newClientCertAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetByEmail(ctx, "test@mail.com").
+		Return(userID, hashForTest(t, testConfig(), "strongpassword"), nil)
+	certs.EXPECT().
+		Match(ctx, userID, fp).
+		Return(true, true, nil)
+	sessions.EXPECT().
+		Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+
+	pair, err := svc.Login(ctx, "test@mail.com", "strongpassword")
+
+	require.NoError(t, err)
+	require.Equal(t, crypto.CertThumbprint(fp), tokenThumbprint(t, pair.AccessToken))
+}
+
+// Без привязанных сертификатов токен не привязывается, даже если сертификат предъявлен
+func TestAuthService_Login_NoBoundCertificates(t *testing.T) {
+	ctx := withCert([]byte("fingerprint"))
+	svc, users, sessions, certs, _ := newClientCertAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetByEmail(ctx, "test@mail.com").
+		Return(userID, hashForTest(t, testConfig(), "strongpassword"), nil)
+	certs.EXPECT().
+		Match(ctx, userID, gomock.Any()).
+		Return(false, false, nil)
+	sessions.EXPECT().
+		Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+
+	pair, err := svc.Login(ctx, "test@mail.com", "strongpassword")
+
+	require.NoError(t, err)
+	require.Empty(t, tokenThumbprint(t, pair.AccessToken))
+}
+
+// Сессия, созданная до привязки, без сертификата не продлевается
+func TestAuthService_Refresh_ClientCertRequired(t *testing.T) {
+	ctx := context.Background()
+	svc, _, sessions, certs, _ := newClientCertAuthService(t)
+
+	userID := uuid.New()
+	sessions.EXPECT().
+		GetByRefreshHash(ctx, gomock.Any()).
+		Return(uuid.New(), userID, time.Now().Add(time.Hour), nil, nil, uuid.New(), nil)
+	certs.EXPECT().
+		Match(ctx, userID, gomock.Any()).
+		Return(true, false, nil)
+
+	_, err := svc.Refresh(ctx, "refresh-token")
+
+	require.ErrorIs(t, err, serr.ErrClientCertRequired)
+}
+
+// Отвязка чужого сертификата
+func TestAuthService_UnbindClientCert_NotFound(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, certs, _ := newClientCertAuthService(t)
+
+	userID, certID := uuid.New(), uuid.New()
+	certs.EXPECT().
+		Delete(ctx, userID, certID).
+		Return(serr.ErrNotFound)
+
+	require.ErrorIs(t, svc.UnbindClientCert(ctx, userID, certID), serr.ErrNotFound)
+}
+
+// API-токен не обходит привязку сертификата: без него запрос отклоняется
+func TestAuthService_AuthenticateAPIToken_ClientCertRequired(t *testing.T) {
+	svc, _, _, certs, _ := newClientCertAuthService(t)
+	tokens := mocks.NewMockAPITokensRepo(gomock.NewController(t))
+	svc.UseAPITokens(tokens)
+
+	userID := uuid.New()
+	tokens.EXPECT().
+		Use(gomock.Any(), crypto.HashRefreshToken("gkp_valid")).
+		Return(models.APIToken{ID: uuid.New(), UserID: userID}, nil).
+		Times(2)
+
+	// без сертификата
+	ctx := context.Background()
+	certs.EXPECT().
+		Match(ctx, userID, []byte(nil)).
+		Return(true, false, nil)
+
+	_, err := svc.AuthenticateAPIToken(ctx, "gkp_valid")
+	require.ErrorIs(t, err, serr.ErrClientCertRequired)
+
+	// с привязанным сертификатом
+	fp := []byte("fingerprint")
+	ctx = withCert(fp)
+	certs.EXPECT().
+		Match(ctx, userID, fp).
+		Return(true, true, nil)
+
+	got, err := svc.AuthenticateAPIToken(ctx, "gkp_valid")
+	require.NoError(t, err)
+	require.Equal(t, userID, got.UserID)
+}
@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// сервис с подключённым хранилищем кодов устройств
+func newDeviceAuthService(t *testing.T) (*service.AuthService, *mocks.MockSessionsRepo, *mocks.MockDeviceCodesRepo) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+
+	cfg := testConfig()
+	cfg.Auth.Device = config.DeviceConfig{CodeTTL: 10 * time.Minute, PollInterval: 5 * time.Second}
+
+	sessions := mocks.NewMockSessionsRepo(ctrl)
+	svc := service.NewAuthService(mocks.NewMockUsersRepo(ctrl), sessions, cfg)
+	devices := mocks.NewMockDeviceCodesRepo(ctrl)
+	svc.UseDeviceCodes(devices)
+	return svc, sessions, devices
+}
+
+// device_code возвращается клиенту, в БД — только его хэш
+func TestAuthService_StartDeviceAuthorization(t *testing.T) {
+	ctx := context.Background()
+	svc, _, devices := newDeviceAuthService(t)
+
+	var savedHash []byte
+	var savedCode string
+	devices.EXPECT().
+		Create(ctx, gomock.Any(), gomock.Any(), 5*time.Second, gomock.Any()).
+		DoAndReturn(func(_ context.Context, h []byte, code string, _ time.Duration, exp time.Time) error {
+			savedHash, savedCode = h, code
+			require.WithinDuration(t, time.Now().Add(10*time.Minute), exp, 5*time.Second)
+			return nil
+		})
+
+	auth, err := svc.StartDeviceAuthorization(ctx)
+
+	require.NoError(t, err)
+	require.Equal(t, crypto.HashRefreshToken(auth.DeviceCode), savedHash)
+	require.Equal(t, savedCode, auth.UserCode)
+	require.Equal(t, 5*time.Second, auth.Interval)
+}
+
+// совпавший user_code генерируется заново
+func TestAuthService_StartDeviceAuthorization_RetriesUserCode(t *testing.T) {
+	ctx := context.Background()
+	svc, _, devices := newDeviceAuthService(t)
+
+	gomock.InOrder(
+		devices.EXPECT().
+			Create(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(serr.ErrAlreadyExists),
+		devices.EXPECT().
+			Create(ctx, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil),
+	)
+
+	_, err := svc.StartDeviceAuthorization(ctx)
+	require.NoError(t, err)
+}
+
+// код вводится в любом регистре и без дефиса
+func TestAuthService_DecideDevice(t *testing.T) {
+	ctx := context.Background()
+	svc, _, devices := newDeviceAuthService(t)
+
+	userID := uuid.New()
+	devices.EXPECT().
+		Decide(ctx, "BCDF-GHJK", userID, true).
+		Return(nil)
+
+	require.NoError(t, svc.DecideDevice(ctx, userID, "bcdfghjk", true))
+
+	err := svc.DecideDevice(ctx, userID, "not-a-code", true)
+	require.ErrorIs(t, err, serr.ErrInvalidInput)
+}
+
+// состояния опроса по RFC 8628
+func TestAuthService_PollDeviceToken_States(t *testing.T) {
+	ctx := context.Background()
+	svc, _, devices := newDeviceAuthService(t)
+
+	future := time.Now().Add(time.Minute)
+	longAgo := time.Now().Add(-time.Minute)
+	justNow := time.Now()
+	id := uuid.New()
+
+	cases := []struct {
+		name string
+		code models.DeviceCode
+		want error
+	}{
+		{"pending", models.DeviceCode{ID: id, Interval: 5 * time.Second, ExpiresAt: future, LastPolledAt: &longAgo}, serr.ErrAuthorizationPending},
+		{"first poll", models.DeviceCode{ID: id, Interval: 5 * time.Second, ExpiresAt: future}, serr.ErrAuthorizationPending},
+		{"denied", models.DeviceCode{ID: id, Interval: 5 * time.Second, ExpiresAt: future, Denied: true}, serr.ErrAccessDenied},
+		{"expired", models.DeviceCode{ID: id, Interval: 5 * time.Second, ExpiresAt: longAgo, Approved: true}, serr.ErrExpiredToken},
+		{"used", models.DeviceCode{ID: id, Interval: 5 * time.Second, ExpiresAt: future, Approved: true, Used: true}, serr.ErrInvalidGrant},
+	}
+	for _, c := range cases {
+		devices.EXPECT().
+			Poll(ctx, crypto.HashRefreshToken("device-code")).
+			Return(c.code, nil)
+
+		_, err := svc.PollDeviceToken(ctx, "device-code")
+		require.ErrorIs(t, err, c.want, c.name)
+	}
+
+	// слишком частый опрос увеличивает интервал
+	devices.EXPECT().
+		Poll(ctx, gomock.Any()).
+		Return(models.DeviceCode{ID: id, Interval: 5 * time.Second, ExpiresAt: future, LastPolledAt: &justNow}, nil)
+	devices.EXPECT().
+		SlowDown(ctx, id, 5*time.Second).
+		Return(nil)
+
+	_, err := svc.PollDeviceToken(ctx, "device-code")
+	require.ErrorIs(t, err, serr.ErrSlowDown)
+
+	// неизвестный код
+	devices.EXPECT().
+		Poll(ctx, gomock.Any()).
+		Return(models.DeviceCode{}, serr.ErrNotFound)
+
+	_, err = svc.PollDeviceToken(ctx, "device-code")
+	require.ErrorIs(t, err, serr.ErrInvalidGrant)
+}
+
+// подтверждённый код обменивается на токены один раз
+func TestAuthService_PollDeviceToken_Approved(t *testing.T) {
+	ctx := context.Background()
+	svc, sessions, devices := newDeviceAuthService(t)
+
+	id := uuid.New()
+	userID := uuid.New()
+	devices.EXPECT().
+		Poll(ctx, gomock.Any()).
+		Return(models.DeviceCode{ID: id, UserID: userID, Interval: 5 * time.Second, ExpiresAt: time.Now().Add(time.Minute), Approved: true}, nil)
+	devices.EXPECT().
+		Consume(ctx, id).
+		Return(nil)
+	sessions.EXPECT().
+		Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+
+	pair, err := svc.PollDeviceToken(ctx, "device-code")
+
+	require.NoError(t, err)
+	require.NotEmpty(t, pair.AccessToken)
+	require.NotEmpty(t, pair.RefreshToken)
+
+	// параллельный опрос успел раньше
+	devices.EXPECT().
+		Poll(ctx, gomock.Any()).
+		Return(models.DeviceCode{ID: id, UserID: userID, Interval: 5 * time.Second, ExpiresAt: time.Now().Add(time.Minute), Approved: true}, nil)
+	devices.EXPECT().
+		Consume(ctx, id).
+		Return(serr.ErrNotFound)
+
+	_, err = svc.PollDeviceToken(ctx, "device-code")
+	require.ErrorIs(t, err, serr.ErrInvalidGrant)
+}
@@ -0,0 +1,360 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/mailer"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// memMailer запоминает отправленные письма.
+type memMailer struct {
+	mu   sync.Mutex
+	sent []mailer.Message
+	err  error
+}
+
+func (m *memMailer) Send(_ context.Context, msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return m.err
+}
+
+var tokenInMail = regexp.MustCompile(`--token (\S+)`)
+
+// token извлекает токен из единственного отправленного письма.
+func (m *memMailer) token(t *testing.T) string {
+	t.Helper()
+	require.Len(t, m.sent, 1)
+	match := tokenInMail.FindStringSubmatch(m.sent[0].Body)
+	require.Len(t, match, 2, "no token in %q", m.sent[0].Body)
+	return match[1]
+}
+
+type mailTestEnv struct {
+	svc      *service.AuthService
+	users    *mocks.MockUsersRepo
+	sessions *mocks.MockSessionsRepo
+	tokens   *mocks.MockEmailTokensRepo
+	mail     *memMailer
+}
+
+// newMailAuthService собирает AuthService с подключёнными письмами.
+func newMailAuthService(t *testing.T) mailTestEnv {
+	t.Helper()
+	cfg := testConfig()
+	cfg.Mail.VerifyTTL = 24 * time.Hour
+	cfg.Mail.ResetTTL = 30 * time.Minute
+	cfg.Mail.ResendInterval = time.Minute
+
+	ctrl := gomock.NewController(t)
+	env := mailTestEnv{
+		users:    mocks.NewMockUsersRepo(ctrl),
+		sessions: mocks.NewMockSessionsRepo(ctrl),
+		tokens:   mocks.NewMockEmailTokensRepo(ctrl),
+		mail:     &memMailer{},
+	}
+	env.svc = service.NewAuthService(env.users, env.sessions, cfg)
+	env.svc.UseMailer(env.tokens, env.mail, func(err error) { t.Logf("mail error: %v", err) })
+	return env
+}
+
+// Регистрация отправляет письмо подтверждения с токеном, в БД — только его хэш
+func TestAuthService_Register_SendsVerification(t *testing.T) {
+	ctx := context.Background()
+	env := newMailAuthService(t)
+
+	userID := uuid.New()
+	var storedHash []byte
+	env.users.EXPECT().Create(ctx, "user@example.com", gomock.Any()).Return(userID, nil)
+	env.tokens.EXPECT().
+		Create(ctx, userID, models.EmailTokenVerify, gomock.Any(), gomock.Any(), time.Minute).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, _ string, hash []byte, expiresAt time.Time, _ time.Duration) error {
+			storedHash = hash
+			require.WithinDuration(t, time.Now().Add(24*time.Hour), expiresAt, time.Minute)
+			return nil
+		})
+
+	id, err := env.svc.Register(ctx, "User@Example.com", "strongpassword", "")
+
+	require.NoError(t, err)
+	require.Equal(t, userID, id)
+	require.Equal(t, "user@example.com", env.mail.sent[0].To)
+	require.Equal(t, crypto.HashRefreshToken(env.mail.token(t)), storedHash)
+}
+
+// Сбой почты не отменяет регистрацию
+func TestAuthService_Register_MailFailureIgnored(t *testing.T) {
+	ctx := context.Background()
+	env := newMailAuthService(t)
+	env.mail.err = errors.New("smtp down")
+
+	userID := uuid.New()
+	env.users.EXPECT().Create(ctx, "user@example.com", gomock.Any()).Return(userID, nil)
+	env.tokens.EXPECT().Create(ctx, userID, models.EmailTokenVerify, gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err := env.svc.Register(ctx, "user@example.com", "strongpassword", "")
+	require.NoError(t, err)
+}
+
+func TestAuthService_VerifyEmail(t *testing.T) {
+	ctx := context.Background()
+	env := newMailAuthService(t)
+
+	userID := uuid.New()
+	gomock.InOrder(
+		env.tokens.EXPECT().Consume(ctx, models.EmailTokenVerify, crypto.HashRefreshToken("good")).Return(userID, nil),
+		env.tokens.EXPECT().MarkVerified(ctx, userID).Return(nil),
+	)
+	env.tokens.EXPECT().Consume(ctx, models.EmailTokenVerify, crypto.HashRefreshToken("used")).Return(uuid.Nil, serr.ErrNotFound)
+
+	require.NoError(t, env.svc.VerifyEmail(ctx, "good"))
+	require.ErrorIs(t, env.svc.VerifyEmail(ctx, "used"), serr.ErrInvalidEmailToken)
+	require.ErrorIs(t, env.svc.VerifyEmail(ctx, " "), serr.ErrInvalidInput)
+}
+
+// Без почты подтверждение и сброс недоступны
+func TestAuthService_Email_Disabled(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newAuthService(t)
+
+	require.ErrorIs(t, svc.VerifyEmail(ctx, "token"), serr.ErrNotFound)
+	require.ErrorIs(t, svc.ResendVerification(ctx, "user@example.com"), serr.ErrNotFound)
+	require.ErrorIs(t, svc.RequestPasswordReset(ctx, "user@example.com"), serr.ErrNotFound)
+	require.ErrorIs(t, svc.ResetPassword(ctx, "token", "newstrongpassword"), serr.ErrNotFound)
+}
+
+func TestAuthService_ResendVerification(t *testing.T) {
+	ctx := context.Background()
+	env := newMailAuthService(t)
+
+	verifiedID, pendingID := uuid.New(), uuid.New()
+	env.users.EXPECT().GetByEmail(gomock.Any(), "nobody@example.com").Return(uuid.Nil, "", serr.ErrNotFound)
+	env.users.EXPECT().GetByEmail(gomock.Any(), "done@example.com").Return(verifiedID, "hash", nil)
+	env.users.EXPECT().GetByEmail(gomock.Any(), "pending@example.com").Return(pendingID, "hash", nil)
+	env.tokens.EXPECT().GetEmail(gomock.Any(), verifiedID).Return("done@example.com", true, nil)
+	env.tokens.EXPECT().GetEmail(gomock.Any(), pendingID).Return("pending@example.com", false, nil)
+	env.tokens.EXPECT().Create(gomock.Any(), pendingID, models.EmailTokenVerify, gomock.Any(), gomock.Any(), time.Minute).Return(nil)
+
+	// ответ одинаковый, письмо — только неподтверждённому адресу
+	require.NoError(t, env.svc.ResendVerification(ctx, "nobody@example.com"))
+	require.NoError(t, env.svc.ResendVerification(ctx, "done@example.com"))
+	require.NoError(t, env.svc.ResendVerification(ctx, "pending@example.com"))
+	env.svc.WaitMail()
+	require.Len(t, env.mail.sent, 1)
+	require.Equal(t, "pending@example.com", env.mail.sent[0].To)
+}
+
+// Для неизвестного email ответ тот же, но письмо не отправляется
+func TestAuthService_RequestPasswordReset_UnknownEmail(t *testing.T) {
+	ctx := context.Background()
+	env := newMailAuthService(t)
+
+	env.users.EXPECT().GetByEmail(gomock.Any(), "nobody@example.com").Return(uuid.Nil, "", serr.ErrNotFound)
+
+	require.NoError(t, env.svc.RequestPasswordReset(ctx, "nobody@example.com"))
+	env.svc.WaitMail()
+	require.Empty(t, env.mail.sent)
+}
+
+// Повторный запрос раньше mail.resend_interval письма не отправляет
+func TestAuthService_RequestPasswordReset_Throttled(t *testing.T) {
+	ctx := context.Background()
+	env := newMailAuthService(t)
+
+	userID := uuid.New()
+	env.users.EXPECT().GetByEmail(gomock.Any(), "user@example.com").Return(userID, "hash", nil)
+	env.tokens.EXPECT().Create(gomock.Any(), userID, models.EmailTokenReset, gomock.Any(), gomock.Any(), time.Minute).Return(serr.ErrConflict)
+
+	require.NoError(t, env.svc.RequestPasswordReset(ctx, "user@example.com"))
+	env.svc.WaitMail()
+	require.Empty(t, env.mail.sent)
+}
+
+// Полный сброс: токен из письма, новый пароль, все сессии отозваны
+func TestAuthService_ResetPassword_OK(t *testing.T) {
+	ctx := context.Background()
+	env := newMailAuthService(t)
+
+	userID := uuid.New()
+	var tokenHash []byte
+	env.users.EXPECT().GetByEmail(gomock.Any(), "user@example.com").Return(userID, "hash", nil)
+	env.tokens.EXPECT().
+		Create(gomock.Any(), userID, models.EmailTokenReset, gomock.Any(), gomock.Any(), time.Minute).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, _ string, hash []byte, expiresAt time.Time, _ time.Duration) error {
+			tokenHash = hash
+			require.WithinDuration(t, time.Now().Add(30*time.Minute), expiresAt, time.Minute)
+			return nil
+		})
+
+	require.NoError(t, env.svc.RequestPasswordReset(ctx, "user@example.com"))
+	env.svc.WaitMail()
+	token := env.mail.token(t)
+	require.Contains(t, env.mail.sent[0].Body, "does NOT recover your secrets")
+
+	env.tokens.EXPECT().Consume(ctx, models.EmailTokenReset, tokenHash).Return(userID, nil)
+	gomock.InOrder(
+		env.users.EXPECT().UpdatePasswordHash(ctx, userID, gomock.Any()).Return(nil),
+		env.sessions.EXPECT().RevokeAllForUser(ctx, userID).Return(nil),
+		env.tokens.EXPECT().MarkVerified(ctx, userID).Return(nil),
+	)
+	env.tokens.EXPECT().GetEmail(ctx, userID).Return("user@example.com", true, nil)
+
+	require.NoError(t, env.svc.ResetPassword(ctx, token, "newstrongpassword"))
+	// второе письмо — уведомление о сбросе
+	require.Len(t, env.mail.sent, 2)
+}
+
+// Сброс пароля отзывает и API-токены: токен, выпущенный
+// тем, кто завладел аккаунтом, не переживает сброс
+func TestAuthService_ResetPassword_RevokesAPITokens(t *testing.T) {
+	ctx := context.Background()
+	env := newMailAuthService(t)
+	apiTokens := mocks.NewMockAPITokensRepo(gomock.NewController(t))
+	env.svc.UseAPITokens(apiTokens)
+
+	userID := uuid.New()
+	env.tokens.EXPECT().Consume(ctx, models.EmailTokenReset, gomock.Any()).Return(userID, nil)
+	gomock.InOrder(
+		env.users.EXPECT().UpdatePasswordHash(ctx, userID, gomock.Any()).Return(nil),
+		env.sessions.EXPECT().RevokeAllForUser(ctx, userID).Return(nil),
+		apiTokens.EXPECT().RevokeAllForUser(ctx, userID).Return(nil),
+		env.tokens.EXPECT().MarkVerified(ctx, userID).Return(nil),
+	)
+	env.tokens.EXPECT().GetEmail(ctx, userID).Return("user@example.com", true, nil)
+
+	require.NoError(t, env.svc.ResetPassword(ctx, "token", "newstrongpassword"))
+	require.Contains(t, env.mail.sent[0].Body, "API tokens were revoked")
+}
+
+// Время ответа не зависит от того, есть ли аккаунт: письмо отправляется в фоне
+func TestAuthService_RequestPasswordReset_Background(t *testing.T) {
+	ctx := context.Background()
+	env := newMailAuthService(t)
+
+	release := make(chan struct{})
+	env.users.EXPECT().
+		GetByEmail(gomock.Any(), "user@example.com").
+		DoAndReturn(func(context.Context, string) (uuid.UUID, string, error) {
+			<-release
+			return uuid.Nil, "", serr.ErrNotFound
+		})
+
+	done := make(chan error, 1)
+	go func() { done <- env.svc.RequestPasswordReset(ctx, "user@example.com") }()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("RequestPasswordReset waited for the account lookup")
+	}
+	close(release)
+	env.svc.WaitMail()
+}
+
+// Повторная отправка тоже не ждёт поиска аккаунта и SMTP
+func TestAuthService_ResendVerification_Background(t *testing.T) {
+	ctx := context.Background()
+	env := newMailAuthService(t)
+
+	release := make(chan struct{})
+	env.users.EXPECT().
+		GetByEmail(gomock.Any(), "user@example.com").
+		DoAndReturn(func(context.Context, string) (uuid.UUID, string, error) {
+			<-release
+			return uuid.Nil, "", serr.ErrNotFound
+		})
+
+	done := make(chan error, 1)
+	go func() { done <- env.svc.ResendVerification(ctx, "user@example.com") }()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("ResendVerification waited for the account lookup")
+	}
+	close(release)
+	env.svc.WaitMail()
+}
+
+func TestAuthService_ResetPassword_InvalidToken(t *testing.T) {
+	ctx := context.Background()
+	env := newMailAuthService(t)
+
+	env.tokens.EXPECT().Consume(ctx, models.EmailTokenReset, gomock.Any()).Return(uuid.Nil, serr.ErrNotFound)
+
+	require.ErrorIs(t, env.svc.ResetPassword(ctx, "expired", "newstrongpassword"), serr.ErrInvalidEmailToken)
+	// короткий пароль отклоняется, не расходуя токен
+	require.ErrorIs(t, env.svc.ResetPassword(ctx, "token", "short"), serr.ErrInvalidInput)
+}
+
+// mail.require_verified: без подтверждённого email вход по паролю запрещён
+func TestAuthService_Login_RequireVerified(t *testing.T) {
+	ctx := context.Background()
+	cfg := testConfig()
+	cfg.Mail.RequireVerified = true
+
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUsersRepo(ctrl)
+	tokens := mocks.NewMockEmailTokensRepo(ctrl)
+	svc := service.NewAuthService(users, mocks.NewMockSessionsRepo(ctrl), cfg)
+	svc.UseMailer(tokens, &memMailer{}, nil)
+
+	userID := uuid.New()
+	users.EXPECT().GetByEmail(ctx, "user@example.com").Return(userID, hashForTest(t, cfg, "strongpassword"), nil)
+	tokens.EXPECT().GetEmail(ctx, userID).Return("user@example.com", false, nil)
+
+	_, err := svc.Login(ctx, "user@example.com", "strongpassword")
+	require.ErrorIs(t, err, serr.ErrEmailNotVerified)
+}
+
+// mail.notify_new_device: письмо только о входе с незнакомого устройства
+func TestAuthService_Login_NotifiesNewDevice(t *testing.T) {
+	cfg := testConfig()
+	cfg.Mail.NotifyNewDevice = true
+
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUsersRepo(ctrl)
+	sessions := mocks.NewMockSessionsRepo(ctrl)
+	tokens := mocks.NewMockEmailTokensRepo(ctrl)
+	mail := &memMailer{}
+	svc := service.NewAuthService(users, sessions, cfg)
+	svc.UseMailer(tokens, mail, nil)
+
+	ctx := service.WithClientInfo(context.Background(), models.ClientInfo{DeviceName: "laptop", UserAgent: "gophkeeper-cli", IP: "10.0.0.1"})
+	userID := uuid.New()
+	hash := hashForTest(t, cfg, "strongpassword")
+
+	users.EXPECT().GetByEmail(ctx, "user@example.com").Return(userID, hash, nil).Times(2)
+	sessions.EXPECT().Create(gomock.Any(), userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).Return(uuid.New(), nil).Times(2)
+	gomock.InOrder(
+		sessions.EXPECT().KnownDevice(ctx, userID, "laptop", "gophkeeper-cli").Return(false, nil),
+		sessions.EXPECT().KnownDevice(ctx, userID, "laptop", "gophkeeper-cli").Return(true, nil),
+	)
+	tokens.EXPECT().GetEmail(ctx, userID).Return("user@example.com", true, nil)
+
+	_, err := svc.Login(ctx, "user@example.com", "strongpassword")
+	require.NoError(t, err)
+	_, err = svc.Login(ctx, "user@example.com", "strongpassword")
+	require.NoError(t, err)
+
+	require.Len(t, mail.sent, 1)
+	require.Contains(t, mail.sent[0].Body, "10.0.0.1")
+	require.Contains(t, mail.sent[0].Body, "laptop")
+}
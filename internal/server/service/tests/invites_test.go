@@ -0,0 +1,166 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// сервис в заданном режиме регистрации; администратор приглашений — admin@mail.com
+func newInvitesAuthService(t *testing.T, registration string) (*service.AuthService, *mocks.MockUsersRepo, *mocks.MockInvitesRepo) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUsersRepo(ctrl)
+	invites := mocks.NewMockInvitesRepo(ctrl)
+
+	cfg := testConfig()
+	cfg.Auth.Registration = registration
+	cfg.Auth.Invites = config.InvitesConfig{
+		Admins:     []string{"admin@mail.com"},
+		DefaultTTL: 24 * time.Hour,
+		MaxTTL:     48 * time.Hour,
+		MaxUses:    10,
+	}
+
+	svc := service.NewAuthService(users, mocks.NewMockSessionsRepo(ctrl), cfg)
+	svc.UseInvites(invites)
+	return svc, users, invites
+}
+
+// Код проверяется и расходуется вместе с созданием пользователя
+func TestAuthService_Register_Invite_OK(t *testing.T) {
+	ctx := context.Background()
+	svc, _, invites := newInvitesAuthService(t, config.RegistrationInvite)
+
+	userID := uuid.New()
+	codeHash := crypto.HashRefreshToken("invite-code")
+	invites.EXPECT().Check(ctx, codeHash).Return(nil)
+	invites.EXPECT().
+		CreateUser(ctx, codeHash, "new@mail.com", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ []byte, _, hash string) (uuid.UUID, error) {
+			require.NotEmpty(t, hash)
+			return userID, nil
+		})
+
+	got, err := svc.Register(ctx, "New@Mail.com", "strongpassword", " invite-code ")
+
+	require.NoError(t, err)
+	require.Equal(t, userID, got)
+}
+
+// Неверный код отклоняется до хэширования пароля, без кода — сразу
+func TestAuthService_Register_Invite_Invalid(t *testing.T) {
+	ctx := context.Background()
+	svc, _, invites := newInvitesAuthService(t, config.RegistrationInvite)
+
+	invites.EXPECT().
+		Check(ctx, crypto.HashRefreshToken("bad")).
+		Return(serr.ErrInvalidInvite)
+
+	_, err := svc.Register(ctx, "new@mail.com", "strongpassword", "bad")
+	require.ErrorIs(t, err, serr.ErrInvalidInvite)
+
+	_, err = svc.Register(ctx, "new@mail.com", "strongpassword", "")
+	require.ErrorIs(t, err, serr.ErrInvalidInvite)
+}
+
+// Код исчерпан между проверкой и регистрацией
+func TestAuthService_Register_Invite_ExhaustedConcurrently(t *testing.T) {
+	ctx := context.Background()
+	svc, _, invites := newInvitesAuthService(t, config.RegistrationInvite)
+
+	invites.EXPECT().Check(ctx, gomock.Any()).Return(nil)
+	invites.EXPECT().
+		CreateUser(ctx, gomock.Any(), "new@mail.com", gomock.Any()).
+		Return(uuid.Nil, serr.ErrInvalidInvite)
+
+	_, err := svc.Register(ctx, "new@mail.com", "strongpassword", "invite-code")
+
+	require.ErrorIs(t, err, serr.ErrInvalidInvite)
+}
+
+// В режиме open код не нужен и не расходуется
+func TestAuthService_Register_Open_IgnoresInvite(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _ := newInvitesAuthService(t, config.RegistrationOpen)
+
+	userID := uuid.New()
+	users.EXPECT().
+		Create(ctx, "new@mail.com", gomock.Any()).
+		Return(userID, nil)
+
+	got, err := svc.Register(ctx, "new@mail.com", "strongpassword", "whatever")
+
+	require.NoError(t, err)
+	require.Equal(t, userID, got)
+}
+
+func TestAuthService_Register_Closed(t *testing.T) {
+	svc, _, _ := newInvitesAuthService(t, config.RegistrationClosed)
+
+	_, err := svc.Register(context.Background(), "new@mail.com", "strongpassword", "invite-code")
+
+	require.ErrorIs(t, err, serr.ErrRegistrationClosed)
+}
+
+func TestAuthService_CreateInvite(t *testing.T) {
+	ctx := context.Background()
+	svc, users, invites := newInvitesAuthService(t, config.RegistrationInvite)
+
+	adminID := uuid.New()
+	users.EXPECT().
+		GetProfile(ctx, adminID).
+		Return(models.Profile{ID: adminID, Email: "Admin@Mail.com"}, nil).
+		AnyTimes()
+
+	var stored []byte
+	invites.EXPECT().
+		Create(ctx, adminID, gomock.Any(), 1, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, codeHash []byte, maxUses int, expiresAt time.Time) (models.Invite, error) {
+			stored = codeHash
+			require.WithinDuration(t, time.Now().Add(24*time.Hour), expiresAt, time.Minute)
+			return models.Invite{ID: uuid.New(), MaxUses: maxUses, ExpiresAt: expiresAt}, nil
+		})
+
+	// по умолчанию — одно использование и default_ttl
+	inv, err := svc.CreateInvite(ctx, adminID, 0, 0)
+	require.NoError(t, err)
+	require.NotEmpty(t, inv.Code)
+	// в хранилище попадает только хэш
+	require.Equal(t, crypto.HashRefreshToken(inv.Code), stored)
+
+	_, err = svc.CreateInvite(ctx, adminID, 11, 0)
+	require.ErrorIs(t, err, serr.ErrInvalidInput)
+	_, err = svc.CreateInvite(ctx, adminID, 1, 72*time.Hour)
+	require.ErrorIs(t, err, serr.ErrInvalidInput)
+}
+
+// Управлять приглашениями могут только auth.invites.admins
+func TestAuthService_Invites_NotAdmin(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _ := newInvitesAuthService(t, config.RegistrationInvite)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetProfile(ctx, userID).
+		Return(models.Profile{ID: userID, Email: "user@mail.com"}, nil).
+		Times(3)
+
+	_, err := svc.CreateInvite(ctx, userID, 1, 0)
+	require.ErrorIs(t, err, serr.ErrForbidden)
+	_, err = svc.ListInvites(ctx, userID)
+	require.ErrorIs(t, err, serr.ErrForbidden)
+	require.ErrorIs(t, svc.RevokeInvite(ctx, userID, uuid.New()), serr.ErrForbidden)
+}
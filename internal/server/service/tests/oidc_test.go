@@ -0,0 +1,202 @@
+package tests
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// stubIdP — локальный OpenID Connect провайдер (Ed25519), выпускающий ID-токены
+type stubIdP struct {
+	srv *httptest.Server
+	key ed25519.PrivateKey
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	idp := &stubIdP{key: priv}
+	idp.srv = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(crypto.JWKS{Keys: []crypto.JWK{{
+			Kty: "OKP", Crv: "Ed25519", Kid: "k1", Alg: "EdDSA", Use: "sig",
+			X: base64.RawURLEncoding.EncodeToString(pub),
+		}}})
+	}))
+	t.Cleanup(idp.srv.Close)
+	return idp
+}
+
+func (idp *stubIdP) idToken(t *testing.T, subject, email string, verified bool) string {
+	t.Helper()
+
+	tok := jwt.NewWithClaims(jwt.SigningMethodEdDSA, crypto.IDTokenClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    idp.srv.URL,
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{"gophkeeper"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+		Email:         email,
+		EmailVerified: verified,
+	})
+	tok.Header["kid"] = "k1"
+	raw, err := tok.SignedString(idp.key)
+	require.NoError(t, err)
+	return raw
+}
+
+// сервис с подключённым входом через stub-провайдер
+func newOIDCAuthService(t *testing.T, linkByEmail bool) (*service.AuthService, *mocks.MockUsersRepo, *mocks.MockSessionsRepo, *mocks.MockOIDCIdentitiesRepo, *stubIdP) {
+	t.Helper()
+
+	svc, users, sessions := newAuthService(t)
+	identities := mocks.NewMockOIDCIdentitiesRepo(gomock.NewController(t))
+	idp := newStubIdP(t)
+	svc.UseOIDC(identities, crypto.NewOIDCVerifier(crypto.OIDCConfig{
+		Issuer:     idp.srv.URL,
+		ClientID:   "gophkeeper",
+		JWKSURL:    idp.srv.URL,
+		CacheTTL:   time.Hour,
+		HTTPClient: idp.srv.Client(),
+	}), linkByEmail)
+	return svc, users, sessions, identities, idp
+}
+
+// Уже привязанный пользователь провайдера получает токены
+func TestAuthService_LoginOIDC_KnownIdentity(t *testing.T) {
+	ctx := context.Background()
+	svc, _, sessions, identities, idp := newOIDCAuthService(t, false)
+
+	userID := uuid.New()
+	identities.EXPECT().
+		GetUser(ctx, idp.srv.URL, "sub-1").
+		Return(userID, nil)
+	sessions.EXPECT().
+		Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+
+	pair, err := svc.LoginOIDC(ctx, idp.idToken(t, "sub-1", "alice@corp.example", true))
+
+	require.NoError(t, err)
+	require.NotEmpty(t, pair.AccessToken)
+	require.NotEmpty(t, pair.RefreshToken)
+}
+
+// Первый вход: аккаунт создаётся по подтверждённому email (в нижнем регистре)
+func TestAuthService_LoginOIDC_CreatesUser(t *testing.T) {
+	ctx := context.Background()
+	svc, users, sessions, identities, idp := newOIDCAuthService(t, false)
+
+	userID := uuid.New()
+	identities.EXPECT().
+		GetUser(ctx, idp.srv.URL, "sub-1").
+		Return(uuid.Nil, serr.ErrNotFound)
+	users.EXPECT().
+		GetByEmail(ctx, "alice@corp.example").
+		Return(uuid.Nil, "", serr.ErrNotFound)
+	identities.EXPECT().
+		CreateUser(ctx, idp.srv.URL, "sub-1", "alice@corp.example", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _, _, hash string) (uuid.UUID, error) {
+			require.NotEmpty(t, hash)
+			return userID, nil
+		})
+	sessions.EXPECT().
+		Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+
+	_, err := svc.LoginOIDC(ctx, idp.idToken(t, "sub-1", "Alice@Corp.Example", true))
+
+	require.NoError(t, err)
+}
+
+// Email занят аккаунтом с паролем, привязка по email выключена
+func TestAuthService_LoginOIDC_EmailTaken(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _, identities, idp := newOIDCAuthService(t, false)
+
+	identities.EXPECT().
+		GetUser(ctx, idp.srv.URL, "sub-1").
+		Return(uuid.Nil, serr.ErrNotFound)
+	users.EXPECT().
+		GetByEmail(ctx, "alice@corp.example").
+		Return(uuid.New(), "hash", nil)
+
+	_, err := svc.LoginOIDC(ctx, idp.idToken(t, "sub-1", "alice@corp.example", true))
+
+	require.ErrorIs(t, err, serr.ErrAlreadyExists)
+}
+
+// link_by_email: существующий аккаунт привязывается к провайдеру
+func TestAuthService_LoginOIDC_LinkByEmail(t *testing.T) {
+	ctx := context.Background()
+	svc, users, sessions, identities, idp := newOIDCAuthService(t, true)
+
+	userID := uuid.New()
+	identities.EXPECT().
+		GetUser(ctx, idp.srv.URL, "sub-1").
+		Return(uuid.Nil, serr.ErrNotFound)
+	users.EXPECT().
+		GetByEmail(ctx, "alice@corp.example").
+		Return(userID, "hash", nil)
+	identities.EXPECT().
+		Link(ctx, idp.srv.URL, "sub-1", "alice@corp.example", userID).
+		Return(nil)
+	sessions.EXPECT().
+		Create(ctx, userID, uuid.Nil, gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(uuid.New(), nil)
+
+	_, err := svc.LoginOIDC(ctx, idp.idToken(t, "sub-1", "alice@corp.example", true))
+
+	require.NoError(t, err)
+}
+
+// Неподтверждённый email не создаёт и не привязывает аккаунт
+func TestAuthService_LoginOIDC_UnverifiedEmail(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _, identities, idp := newOIDCAuthService(t, true)
+
+	identities.EXPECT().
+		GetUser(ctx, idp.srv.URL, "sub-1").
+		Return(uuid.Nil, serr.ErrNotFound)
+
+	_, err := svc.LoginOIDC(ctx, idp.idToken(t, "sub-1", "alice@corp.example", false))
+
+	require.ErrorIs(t, err, serr.ErrUnauthorized)
+}
+
+// Токен, подписанный не провайдером
+func TestAuthService_LoginOIDC_InvalidToken(t *testing.T) {
+	svc, _, _, _, _ := newOIDCAuthService(t, false)
+	other := newStubIdP(t)
+
+	_, err := svc.LoginOIDC(context.Background(), other.idToken(t, "sub-1", "alice@corp.example", true))
+
+	require.ErrorIs(t, err, serr.ErrUnauthorized)
+}
+
+// Вход через провайдер не включён
+func TestAuthService_LoginOIDC_Disabled(t *testing.T) {
+	svc, _, _ := newAuthService(t)
+
+	_, err := svc.LoginOIDC(context.Background(), "id-token")
+
+	require.ErrorIs(t, err, serr.ErrNotFound)
+}
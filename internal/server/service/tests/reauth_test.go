@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// parseAccessClaims разбирает access-токен, подписанный ключом из testConfig
+func parseAccessClaims(t *testing.T, token string) *crypto.AccessClaims {
+	t.Helper()
+	claims := &crypto.AccessClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return []byte(testConfig().Auth.JWT.SigningKey), nil
+	})
+	require.NoError(t, err)
+	return claims
+}
+
+// Верный пароль — токен той же сессии с elevated_until, новая сессия не создаётся
+func TestAuthService_Reauthenticate_OK(t *testing.T) {
+	ctx := context.Background()
+	cfg := testConfig()
+	cfg.Auth.Reauth.ElevatedTTL = 30 * time.Second
+
+	ctrl := gomock.NewController(t)
+	users := mocks.NewMockUsersRepo(ctrl)
+	svc := service.NewAuthService(users, mocks.NewMockSessionsRepo(ctrl), cfg)
+
+	userID, sessionID := uuid.New(), uuid.New()
+	users.EXPECT().
+		GetPasswordHash(ctx, userID).
+		Return(hashForTest(t, cfg, "strongpassword"), nil)
+
+	elev, err := svc.Reauthenticate(ctx, userID, sessionID, "strongpassword", "")
+
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(30*time.Second), elev.ElevatedUntil, 2*time.Second)
+
+	claims := parseAccessClaims(t, elev.AccessToken)
+	require.Equal(t, sessionID.String(), claims.SessionID)
+	require.NotNil(t, claims.ElevatedUntil)
+	require.Equal(t, elev.ElevatedUntil.Unix(), claims.ElevatedUntil.Unix())
+}
+
+// Повышенные права не переживают сам access-токен
+func TestAuthService_Reauthenticate_CappedByAccessTTL(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _ := newAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetPasswordHash(ctx, userID).
+		Return(hashForTest(t, testConfig(), "strongpassword"), nil)
+
+	elev, err := svc.Reauthenticate(ctx, userID, uuid.New(), "strongpassword", "")
+
+	require.NoError(t, err)
+	claims := parseAccessClaims(t, elev.AccessToken)
+	require.False(t, claims.ElevatedUntil.After(claims.ExpiresAt.Time))
+}
+
+func TestAuthService_Reauthenticate_WrongPassword(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _ := newAuthService(t)
+
+	userID := uuid.New()
+	users.EXPECT().
+		GetPasswordHash(ctx, userID).
+		Return(hashForTest(t, testConfig(), "strongpassword"), nil)
+
+	_, err := svc.Reauthenticate(ctx, userID, uuid.New(), "wrongpassword", "")
+
+	require.ErrorIs(t, err, serr.ErrInvalidCredentials)
+}
+
+func TestAuthService_Reauthenticate_InvalidInput(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newAuthService(t)
+
+	_, err := svc.Reauthenticate(ctx, uuid.New(), uuid.New(), "   ", "")
+	require.ErrorIs(t, err, serr.ErrInvalidInput)
+
+	// токен без сессии повысить нельзя
+	_, err = svc.Reauthenticate(ctx, uuid.New(), uuid.Nil, "strongpassword", "")
+	require.ErrorIs(t, err, serr.ErrUnauthorized)
+}
+
+// При подключённом TOTP одного пароля мало
+func TestAuthService_Reauthenticate_TOTPRequiresCode(t *testing.T) {
+	ctx := context.Background()
+	svc, users, _, twoFactor := newTwoFactorAuthService(t)
+
+	userID := uuid.New()
+	secret, _ := crypto.NewTOTPSecret()
+	users.EXPECT().
+		GetPasswordHash(ctx, userID).
+		Return(hashForTest(t, testConfig(), "strongpassword"), nil).
+		Times(2)
+	twoFactor.EXPECT().
+		GetTOTP(ctx, userID).
+		Return(secret, true, nil).
+		Times(3)
+	twoFactor.EXPECT().
+		UseTOTPStep(ctx, userID, gomock.Any()).
+		Return(nil)
+
+	_, err := svc.Reauthenticate(ctx, userID, uuid.New(), "strongpassword", "")
+	require.ErrorIs(t, err, serr.ErrMFARequired)
+
+	elev, err := svc.Reauthenticate(ctx, userID, uuid.New(), "strongpassword", currentCode(t, secret))
+	require.NoError(t, err)
+	require.NotEmpty(t, elev.AccessToken)
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/mocks"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+)
+
+// Кэш догружает отзывы инкрементально и забывает отзывы старше access_ttl
+func TestSessionRevocations_RefreshAndPrune(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	sessions := mocks.NewMockSessionsRepo(ctrl)
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	r := service.NewSessionRevocations(sessions, 15*time.Minute)
+	r.Now = func() time.Time { return now }
+
+	first := uuid.New()
+	firstAt := now.Add(-time.Minute)
+
+	// первая загрузка — за access_ttl + запас
+	sessions.EXPECT().
+		ListRevokedSince(ctx, now.Add(-16*time.Minute)).
+		Return([]models.RevokedSession{{ID: first, RevokedAt: firstAt}}, nil)
+	require.NoError(t, r.Refresh(ctx))
+	require.True(t, r.IsRevoked(first))
+
+	// следующая загрузка — с курсора минус запас
+	second := uuid.New()
+	sessions.EXPECT().
+		ListRevokedSince(ctx, firstAt.Add(-time.Minute)).
+		Return([]models.RevokedSession{{ID: second, RevokedAt: now}}, nil)
+	require.NoError(t, r.Refresh(ctx))
+	require.True(t, r.IsRevoked(second))
+	require.False(t, r.IsRevoked(uuid.New()))
+
+	// через access_ttl (+ запас) токены first истекли — запись удаляется
+	now = now.Add(16 * time.Minute)
+	sessions.EXPECT().
+		ListRevokedSince(ctx, gomock.Any()).
+		Return(nil, nil)
+	require.NoError(t, r.Refresh(ctx))
+	require.False(t, r.IsRevoked(first))
+	require.True(t, r.IsRevoked(second))
+	require.Equal(t, 1, r.Len())
+}
+
+// Отзыв, закоммиченный позже курсора, но с более ранним revoked_at
+// (долгая транзакция или другой экземпляр), всё равно подгружается
+func TestSessionRevocations_LateCommit(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	sessions := mocks.NewMockSessionsRepo(ctrl)
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	r := service.NewSessionRevocations(sessions, 15*time.Minute)
+	r.Now = func() time.Time { return now }
+
+	first := uuid.New()
+	sessions.EXPECT().
+		ListRevokedSince(ctx, gomock.Any()).
+		Return([]models.RevokedSession{{ID: first, RevokedAt: now}}, nil)
+	require.NoError(t, r.Refresh(ctx))
+
+	// транзакция началась за секунду до курсора, а закоммитилась после загрузки
+	late := uuid.New()
+	now = now.Add(5 * time.Second)
+	sessions.EXPECT().
+		ListRevokedSince(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, since time.Time) ([]models.RevokedSession, error) {
+			lateAt := now.Add(-6 * time.Second)
+			require.True(t, since.Before(lateAt), "since %s must cover %s", since, lateAt)
+			return []models.RevokedSession{
+				{ID: first, RevokedAt: now.Add(-5 * time.Second)},
+				{ID: late, RevokedAt: lateAt},
+			}, nil
+		})
+	require.NoError(t, r.Refresh(ctx))
+
+	require.True(t, r.IsRevoked(late))
+	require.True(t, r.IsRevoked(first))
+	require.Equal(t, 2, r.Len())
+}
+
+// Logout сразу обновляет кэш, не дожидаясь периодической синхронизации
+func TestAuthService_Logout_SyncsRevocations(t *testing.T) {
+	ctx := context.Background()
+	svc, _, sessions := newAuthService(t)
+
+	r := service.NewSessionRevocations(sessions, time.Minute)
+	svc.UseRevocations(r)
+
+	sessID := uuid.New()
+	userID := uuid.New()
+
+	sessions.EXPECT().
+		GetByRefreshHash(ctx, gomock.Any()).
+		Return(sessID, userID, time.Now().Add(time.Hour), nil, nil, uuid.New(), nil)
+	sessions.EXPECT().
+		Revoke(ctx, userID, sessID).
+		Return(nil)
+	sessions.EXPECT().
+		ListRevokedSince(ctx, gomock.Any()).
+		Return([]models.RevokedSession{{ID: sessID, RevokedAt: time.Now()}}, nil)
+
+	require.NoError(t, svc.Logout(ctx, "refresh-token", false))
+	require.True(t, r.IsRevoked(sessID))
+}
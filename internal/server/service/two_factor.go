@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/config"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/crypto"
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/server/service/models"
+	serr "github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/errors"
+)
+
+// Значения по умолчанию, если auth.mfa не задан (например, в тестовом конфиге).
+const (
+	defaultMFAChallengeTTL  = 5 * time.Minute
+	defaultMFAMaxAttempts   = 5
+	defaultMFARecoveryCodes = 10
+)
+
+// mfaSettings — параметры 2FA из auth.mfa.
+type mfaSettings struct {
+	challengeTTL  time.Duration
+	maxAttempts   int
+	recoveryCodes int
+}
+
+func newMFASettings(cfg config.MFAConfig) mfaSettings {
+	m := mfaSettings{
+		challengeTTL:  cfg.ChallengeTTL,
+		maxAttempts:   cfg.MaxAttempts,
+		recoveryCodes: cfg.RecoveryCodes,
+	}
+	if m.challengeTTL <= 0 {
+		m.challengeTTL = defaultMFAChallengeTTL
+	}
+	if m.maxAttempts <= 0 {
+		m.maxAttempts = defaultMFAMaxAttempts
+	}
+	if m.recoveryCodes <= 0 {
+		m.recoveryCodes = defaultMFARecoveryCodes
+	}
+	return m
+}
+
+// MFAChallenge — challenge "mfa required", выдаваемый после верного пароля.
+//
+// Token одноразовый и короткоживущий: его нужно вместе с кодом
+// передать в CompleteMFA до ExpiresAt.
+type MFAChallenge struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// MFARequiredError возвращается из Login, когда для входа нужен второй фактор.
+//
+// errors.Is(err, serr.ErrMFARequired) == true.
+type MFARequiredError struct {
+	Challenge MFAChallenge
+}
+
+func (e *MFARequiredError) Error() string { return serr.ErrMFARequired.Error() }
+
+// Unwrap позволяет сравнивать ошибку с serr.ErrMFARequired.
+func (e *MFARequiredError) Unwrap() error { return serr.ErrMFARequired }
+
+// TOTPEnrollment — данные для подключения TOTP в приложении-аутентификаторе.
+type TOTPEnrollment struct {
+	Secret string
+	URI    string // otpauth://totp/...
+}
+
+// UseTwoFactor подключает хранилище данных двухфакторной аутентификации.
+//
+// Без вызова 2FA недоступна: Login выдаёт токены по паролю,
+// а методы подключения TOTP возвращают ErrInternal.
+func (s *AuthService) UseTwoFactor(repo TwoFactorRepo) {
+	s.twoFactor = repo
+}
+
+// totpEnabled сообщает, подключён ли (и подтверждён) TOTP у пользователя.
+func (s *AuthService) totpEnabled(ctx context.Context, userID uuid.UUID) (bool, error) {
+	if s.twoFactor == nil {
+		return false, nil
+	}
+	_, confirmed, err := s.twoFactor.GetTOTP(ctx, userID)
+	if errors.Is(err, serr.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return confirmed, nil
+}
+
+// newMFAChallenge создаёт challenge для второго шага входа.
+// В БД сохраняется только хэш токена, как и для refresh-токенов.
+func (s *AuthService) newMFAChallenge(ctx context.Context, userID uuid.UUID) (MFAChallenge, error) {
+	token, err := crypto.NewRefreshToken()
+	if err != nil {
+		return MFAChallenge{}, serr.ErrInternal
+	}
+	expiresAt := time.Now().Add(s.mfa.challengeTTL)
+	if err := s.twoFactor.CreateChallenge(ctx, userID, crypto.HashRefreshToken(token), expiresAt); err != nil {
+		return MFAChallenge{}, err
+	}
+	return MFAChallenge{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// EnrollTOTP начинает подключение TOTP: генерирует секрет и otpauth URI.
+//
+// До подтверждения кодом (ConfirmTOTP) вход по-прежнему выполняется по паролю,
+// повторный вызов заменяет неподтверждённый секрет.
+//
+// Ошибки:
+//   - ErrUserIDEmpty
+//   - ErrAlreadyExists если TOTP уже подключён
+func (s *AuthService) EnrollTOTP(ctx context.Context, userID uuid.UUID) (TOTPEnrollment, error) {
+	if userID == uuid.Nil {
+		return TOTPEnrollment{}, serr.ErrUserIDEmpty
+	}
+	if s.twoFactor == nil {
+		return TOTPEnrollment{}, serr.ErrInternal
+	}
+
+	profile, err := s.users.GetProfile(ctx, userID)
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+
+	secret, err := crypto.NewTOTPSecret()
+	if err != nil {
+		return TOTPEnrollment{}, serr.ErrInternal
+	}
+	if err := s.twoFactor.SaveTOTPSecret(ctx, userID, secret); err != nil {
+		return TOTPEnrollment{}, err
+	}
+
+	issuer := s.jwt.Issuer
+	if issuer == "" {
+		issuer = "GophKeeper"
+	}
+	return TOTPEnrollment{Secret: secret, URI: crypto.TOTPURI(issuer, profile.Email, secret)}, nil
+}
+
+// ConfirmTOTP завершает подключение TOTP кодом из приложения
+// и возвращает одноразовые коды восстановления.
+//
+// Коды показываются один раз: в БД сохраняются только их хэши.
+//
+// Ошибки:
+//   - ErrUserIDEmpty
+//   - ErrNotFound если подключение не начато (не было EnrollTOTP)
+//   - ErrAlreadyExists если TOTP уже подтверждён
+//   - ErrInvalidOTP если код неверный
+func (s *AuthService) ConfirmTOTP(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	if userID == uuid.Nil {
+		return nil, serr.ErrUserIDEmpty
+	}
+	if s.twoFactor == nil {
+		return nil, serr.ErrInternal
+	}
+
+	secret, confirmed, err := s.twoFactor.GetTOTP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if confirmed {
+		return nil, serr.ErrAlreadyExists
+	}
+
+	step, ok := crypto.ValidateTOTP(secret, code, time.Now())
+	if !ok {
+		return nil, serr.ErrInvalidOTP
+	}
+
+	codes, err := crypto.NewRecoveryCodes(s.mfa.recoveryCodes)
+	if err != nil {
+		return nil, serr.ErrInternal
+	}
+	hashes := make([][]byte, 0, len(codes))
+	for _, c := range codes {
+		hashes = append(hashes, crypto.HashRecoveryCode(c))
+	}
+
+	if err := s.twoFactor.EnableTOTP(ctx, userID, step, hashes); err != nil {
+		// подтверждено параллельным запросом
+		if errors.Is(err, serr.ErrConflict) {
+			return nil, serr.ErrAlreadyExists
+		}
+		return nil, err
+	}
+
+	s.securityEvent(ctx, "totp_enabled", zap.String("user_id", userID.String()))
+	return codes, nil
+}
+
+// CompleteMFA завершает вход со вторым фактором и выдаёт пару токенов.
+//
+// code — 6-значный TOTP-код или код восстановления (гасится после использования).
+// Число попыток на один challenge ограничено auth.mfa.max_attempts,
+// один и тот же TOTP-код дважды не принимается.
+// Неверный код учитывается в счётчике auth.lockout наравне с неверным паролем.
+//
+// Ошибки:
+//   - ErrInvalidInput
+//   - ErrUnauthorized если challenge неизвестен, истёк, использован или попытки исчерпаны
+//   - ErrInvalidOTP если код неверный
+//   - *LoginLockedError (errors.Is(err, ErrTooManyAttempts))
+//   - ErrTooManySessions
+//   - ErrClientCertRequired
+func (s *AuthService) CompleteMFA(ctx context.Context, challengeToken, code string) (TokenPair, error) {
+	challengeToken = strings.TrimSpace(challengeToken)
+	code = strings.TrimSpace(code)
+	if challengeToken == "" || code == "" {
+		return TokenPair{}, serr.ErrInvalidInput
+	}
+	if s.twoFactor == nil {
+		return TokenPair{}, serr.ErrUnauthorized
+	}
+
+	tokenHash := crypto.HashRefreshToken(challengeToken)
+	userID, err := s.twoFactor.ChallengeAttempt(ctx, tokenHash, s.mfa.maxAttempts)
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	// неудачи второго фактора учитываются в том же счётчике, что и неверный пароль
+	email, err := s.lockoutEmail(ctx, userID)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	if err := s.checkLockout(ctx, email); err != nil {
+		return TokenPair{}, err
+	}
+
+	if err := s.verifySecondFactor(ctx, userID, code); err != nil {
+		if errors.Is(err, serr.ErrInvalidOTP) {
+			s.securityEvent(ctx, "mfa_failed", zap.String("user_id", userID.String()))
+			s.audit.Record(ctx, userID, models.AuditLoginFailed, uuid.Nil)
+			if err := s.recordFailure(ctx, email); err != nil {
+				return TokenPair{}, err
+			}
+		}
+		return TokenPair{}, err
+	}
+
+	// challenge одноразовый: при гонке двух верных кодов токены получит один запрос
+	if err := s.twoFactor.CompleteChallenge(ctx, tokenHash); err != nil {
+		return TokenPair{}, err
+	}
+
+	return s.completeLogin(ctx, userID)
+}
+
+// verifySecondFactor проверяет TOTP-код или код восстановления.
+func (s *AuthService) verifySecondFactor(ctx context.Context, userID uuid.UUID, code string) error {
+	if len(code) == crypto.TOTPDigits && isDigits(code) {
+		secret, confirmed, err := s.twoFactor.GetTOTP(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			return serr.ErrUnauthorized
+		}
+		step, ok := crypto.ValidateTOTP(secret, code, time.Now())
+		if !ok {
+			return serr.ErrInvalidOTP
+		}
+		// код этого интервала уже использован
+		if err := s.twoFactor.UseTOTPStep(ctx, userID, step); err != nil {
+			if errors.Is(err, serr.ErrConflict) {
+				return serr.ErrInvalidOTP
+			}
+			return err
+		}
+		return nil
+	}
+
+	err := s.twoFactor.UseRecoveryCode(ctx, userID, crypto.HashRecoveryCode(code))
+	if errors.Is(err, serr.ErrNotFound) {
+		return serr.ErrInvalidOTP
+	}
+	if err != nil {
+		return err
+	}
+	s.securityEvent(ctx, "recovery_code_used", zap.String("user_id", userID.String()))
+	return nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -28,6 +28,50 @@ var (
 	ErrExpectedError = errors.New("expected error")
 	// неожидаемая ошибка
 	ErrUnexpectedError = errors.New("unexpected error")
+	// превышен лимит активных сессий пользователя
+	ErrTooManySessions = errors.New("too many active sessions")
+	// пароль верный, но для входа нужен второй фактор (TOTP или код восстановления)
+	ErrMFARequired = errors.New("mfa required")
+	// неверный одноразовый код (TOTP или код восстановления)
+	ErrInvalidOTP = errors.New("invalid one-time code")
+	// слишком много неудачных попыток входа: вход временно недоступен
+	ErrTooManyAttempts = errors.New("too many failed login attempts")
+	// сервер перегружен (очередь хэширования паролей), запрос можно повторить позже
+	ErrServerBusy = errors.New("server is busy, try again later")
+	// недостаточно прав (например, у API-токена нет нужного scope)
+	ErrForbidden = errors.New("forbidden")
+	// к аккаунту привязаны клиентские сертификаты, а запрос пришёл без привязанного сертификата
+	ErrClientCertRequired = errors.New("client certificate required")
+	// регистрация на сервере закрыта (auth.registration=closed)
+	ErrRegistrationClosed = errors.New("registration is closed")
+	// код приглашения неизвестен, истёк или уже использован
+	ErrInvalidInvite = errors.New("invalid or expired invite code")
+	// сервер под нагрузкой: регистрация и вход требуют решения proof-of-work
+	ErrPoWRequired = errors.New("proof of work required")
+	// решение proof-of-work неверно, challenge истёк или уже использован
+	ErrInvalidPoW = errors.New("invalid proof of work")
+	// операция требует недавнего повторного ввода пароля (POST /auth/reauth)
+	ErrReauthRequired = errors.New("reauthentication required")
+	// вход по паролю требует подтверждённого email (mail.require_verified)
+	ErrEmailNotVerified = errors.New("email not verified")
+	// токен из письма неизвестен, истёк или уже использован
+	ErrInvalidEmailToken = errors.New("invalid or expired token")
+	// аккаунт заблокирован администратором
+	ErrAccountDisabled = errors.New("account disabled")
+)
+
+// вход по коду устройства (RFC 8628); текст ошибок — коды error из RFC
+var (
+	// пользователь ещё не подтвердил код
+	ErrAuthorizationPending = errors.New("authorization_pending")
+	// клиент опрашивает сервер чаще допустимого интервала
+	ErrSlowDown = errors.New("slow_down")
+	// пользователь отклонил запрос
+	ErrAccessDenied = errors.New("access_denied")
+	// срок действия кода истёк
+	ErrExpiredToken = errors.New("expired_token")
+	// device_code неизвестен или уже обменян на токены
+	ErrInvalidGrant = errors.New("invalid_grant")
 )
 
 // только для секретов
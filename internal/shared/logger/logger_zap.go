@@ -27,10 +27,15 @@ type HTTPLogger struct {
 // Для файлов включена ротация (MaxSize/MaxBackups/MaxAge) и сжатие архивов.
 // Формат времени: "HH:MM:SS DD.MM.YYYY".
 func NewHTTPLogger() *HTTPLogger {
+	return &HTTPLogger{Logger: newFileLogger("http.log")}
+}
+
+// newFileLogger создаёт zap-логгер, пишущий в runtime/logs/<name> с ротацией.
+func newFileLogger(name string) *zap.Logger {
 	logDir := filepath.Join("runtime", "logs")
 	_ = os.MkdirAll(logDir, 0755)
 
-	logFile := filepath.Join(logDir, "http.log")
+	logFile := filepath.Join(logDir, name)
 
 	// lumberjack отвечает за ротацию файлов
 	writer := zapcore.AddSync(&lumberjack.Logger{
@@ -52,9 +57,7 @@ func NewHTTPLogger() *HTTPLogger {
 		zap.InfoLevel,
 	)
 
-	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
-
-	return &HTTPLogger{Logger: logger}
+	return zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
 }
 
 // LogRequest записывает структурированный лог об HTTP-запросе.
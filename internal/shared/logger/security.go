@@ -0,0 +1,23 @@
+package logger
+
+import "go.uber.org/zap"
+
+// SecurityLogger пишет события безопасности (повторное использование
+// refresh-токена, блокировки и т.п.) в отдельный файл runtime/logs/security.log,
+// чтобы их было удобно отслеживать отдельно от HTTP-логов.
+type SecurityLogger struct {
+	*zap.Logger
+}
+
+// NewSecurityLogger создаёт файловый логгер событий безопасности.
+func NewSecurityLogger() *SecurityLogger {
+	return &SecurityLogger{Logger: newFileLogger("security.log")}
+}
+
+// Event записывает событие безопасности.
+//
+// event — короткое имя события (например, "refresh_token_reuse"),
+// fields — подробности: пользователь, сессия, IP и т.п.
+func (l *SecurityLogger) Event(event string, fields ...zap.Field) {
+	l.Warn("security event", append([]zap.Field{zap.String("event", event)}, fields...)...)
+}
@@ -0,0 +1,64 @@
+// Package pow — proof-of-work в стиле hashcash, общий для сервера и агента.
+//
+// Решение — десятичное число n, при котором SHA-256(challenge + ":" + n)
+// начинается с difficulty нулевых бит. Проверка стоит один хэш,
+// поиск — в среднем 2^difficulty хэшей.
+package pow
+
+import (
+	"context"
+	"crypto/sha256"
+	"math/bits"
+	"strconv"
+)
+
+// Заголовки, в которых клиент передаёт challenge и его решение.
+const (
+	HeaderChallenge = "X-PoW-Challenge"
+	HeaderSolution  = "X-PoW-Solution"
+)
+
+// MaxDifficulty — предел сложности: больше не имеет смысла для CLI-клиента.
+const MaxDifficulty = 32
+
+// Valid проверяет, что solution решает challenge со сложностью difficulty.
+func Valid(challenge, solution string, difficulty int) bool {
+	if difficulty <= 0 {
+		return true
+	}
+	if difficulty > MaxDifficulty || solution == "" {
+		return false
+	}
+	sum := sha256.Sum256([]byte(challenge + ":" + solution))
+	return leadingZeroBits(sum[:]) >= difficulty
+}
+
+// Solve перебирает решения, пока не найдёт подходящее или не отменят ctx.
+func Solve(ctx context.Context, challenge string, difficulty int) (string, error) {
+	if difficulty > MaxDifficulty {
+		difficulty = MaxDifficulty
+	}
+	for n := uint64(0); ; n++ {
+		// проверяем отмену не на каждой итерации — хэш дешевле select
+		if n&0xffff == 0 {
+			if err := ctx.Err(); err != nil {
+				return "", err
+			}
+		}
+		solution := strconv.FormatUint(n, 10)
+		if Valid(challenge, solution, difficulty) {
+			return solution, nil
+		}
+	}
+}
+
+func leadingZeroBits(b []byte) int {
+	n := 0
+	for _, x := range b {
+		if x != 0 {
+			return n + bits.LeadingZeros8(x)
+		}
+		n += 8
+	}
+	return n
+}
@@ -0,0 +1,32 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/IvanChernomyrdin/go-yandex-gophkeeper/internal/shared/pow"
+)
+
+func TestSolve_Valid(t *testing.T) {
+	solution, err := pow.Solve(context.Background(), "challenge-1", 12)
+
+	require.NoError(t, err)
+	require.True(t, pow.Valid("challenge-1", solution, 12))
+}
+
+func TestValid_Rejects(t *testing.T) {
+	require.True(t, pow.Valid("challenge", "", 0))
+	require.False(t, pow.Valid("challenge", "", 8))
+	require.False(t, pow.Valid("challenge", "1", pow.MaxDifficulty+1))
+}
+
+func TestSolve_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := pow.Solve(ctx, "challenge", pow.MaxDifficulty)
+
+	require.ErrorIs(t, err, context.Canceled)
+}
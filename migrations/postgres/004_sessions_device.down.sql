@@ -0,0 +1,5 @@
+-- удаляем информацию об устройствах из сессий
+ALTER TABLE sessions
+    DROP COLUMN IF EXISTS device_name,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS ip;
@@ -0,0 +1,6 @@
+-- Информация об устройстве, с которого открыта сессия.
+-- Нужна, чтобы пользователь видел свои сессии и мог отозвать потерянное устройство.
+ALTER TABLE sessions
+    ADD COLUMN IF NOT EXISTS device_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS user_agent  TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ip          TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- Кэш отзыва access-токенов периодически выбирает недавно отозванные сессии
+CREATE INDEX IF NOT EXISTS idx_sessions_revoked_at ON sessions(revoked_at) WHERE revoked_at IS NOT NULL;
@@ -0,0 +1,22 @@
+-- Семейство refresh-токенов: все сессии, полученные ротацией от одного логина.
+-- При повторном использовании токена отзывается только его семейство.
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS family_id UUID;
+
+-- Существующие цепочки ротации: семейство = id первой сессии цепочки
+WITH RECURSIVE chain AS (
+    SELECT s.id, s.replaced_by, s.id AS family_id
+      FROM sessions s
+     WHERE NOT EXISTS (SELECT 1 FROM sessions p WHERE p.replaced_by = s.id)
+    UNION ALL
+    SELECT s.id, s.replaced_by, c.family_id
+      FROM sessions s
+      JOIN chain c ON s.id = c.replaced_by
+)
+UPDATE sessions s
+   SET family_id = chain.family_id
+  FROM chain
+ WHERE s.id = chain.id;
+
+ALTER TABLE sessions ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_sessions_family ON sessions(family_id) WHERE revoked_at IS NULL;
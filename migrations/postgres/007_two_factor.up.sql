@@ -0,0 +1,32 @@
+-- TOTP-секрет пользователя. confirmed_at IS NULL — подключение начато, но не подтверждено кодом.
+-- last_step — последний принятый 30-секундный интервал (защита от повторного ввода кода).
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id       UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret        TEXT NOT NULL,
+    confirmed_at  TIMESTAMPTZ NULL,
+    last_step     BIGINT NOT NULL DEFAULT 0,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Одноразовые коды восстановления (хранятся только хэши)
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id       UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash     BYTEA NOT NULL,
+    used_at       TIMESTAMPTZ NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, code_hash)
+);
+
+-- Challenge "mfa required": выдаётся после верного пароля, обменивается на токены по коду
+CREATE TABLE IF NOT EXISTS mfa_challenges (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id       UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash    BYTEA NOT NULL UNIQUE,
+    expires_at    TIMESTAMPTZ NOT NULL,
+    attempts      INTEGER NOT NULL DEFAULT 0,
+    used_at       TIMESTAMPTZ NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfa_challenges_expires ON mfa_challenges(expires_at);
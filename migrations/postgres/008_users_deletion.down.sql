@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_delete_after;
+
+ALTER TABLE users DROP COLUMN IF EXISTS delete_after;
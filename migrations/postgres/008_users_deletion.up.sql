@@ -0,0 +1,6 @@
+-- Удаление аккаунта с отсрочкой: пока delete_after не наступил,
+-- вход заблокирован, но аккаунт можно восстановить.
+-- Секреты, сессии и данные 2FA удаляются вместе с пользователем (ON DELETE CASCADE).
+ALTER TABLE users ADD COLUMN IF NOT EXISTS delete_after TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_users_delete_after ON users(delete_after) WHERE delete_after IS NOT NULL;
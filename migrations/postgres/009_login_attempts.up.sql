@@ -0,0 +1,9 @@
+-- Неудачные попытки входа по email (в том числе несуществующему):
+-- экспоненциальная задержка и временная блокировка перебора пароля.
+CREATE TABLE IF NOT EXISTS login_attempts (
+    email            TEXT PRIMARY KEY,
+    failures         INT NOT NULL DEFAULT 0,
+    last_failure_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_last_failure ON login_attempts(last_failure_at);
@@ -0,0 +1,17 @@
+-- Персональные API-токены для CI и автоматизации.
+-- Хранится только SHA-256 токена; scopes и types — списки через запятую
+-- (пустой types — доступны секреты любого типа).
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id       UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name          TEXT NOT NULL,
+    token_hash    BYTEA NOT NULL UNIQUE,
+    scopes        TEXT NOT NULL,
+    types         TEXT NOT NULL DEFAULT '',
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at    TIMESTAMPTZ NOT NULL,
+    last_used_at  TIMESTAMPTZ NULL,
+    revoked_at    TIMESTAMPTZ NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user ON api_tokens(user_id);
@@ -0,0 +1,19 @@
+-- Вход по коду устройства (RFC 8628) для машин без браузера.
+-- device_code знает только опрашивающий клиент, поэтому хранится SHA-256;
+-- user_code вводится на авторизованном устройстве, живёт несколько минут.
+-- user_id заполняется при подтверждении или отказе.
+CREATE TABLE IF NOT EXISTS device_codes (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    device_code_hash  BYTEA NOT NULL UNIQUE,
+    user_code         TEXT NOT NULL UNIQUE,
+    user_id           UUID NULL REFERENCES users(id) ON DELETE CASCADE,
+    interval_seconds  INTEGER NOT NULL,
+    approved_at       TIMESTAMPTZ NULL,
+    denied_at         TIMESTAMPTZ NULL,
+    used_at           TIMESTAMPTZ NULL,
+    last_polled_at    TIMESTAMPTZ NULL,
+    expires_at        TIMESTAMPTZ NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_device_codes_expires ON device_codes(expires_at);
@@ -0,0 +1,13 @@
+-- Клиентские сертификаты (mTLS), привязанные к аккаунту как второй фактор.
+-- Хранится SHA-256 DER-кодировки сертификата; один сертификат — один аккаунт.
+CREATE TABLE IF NOT EXISTS client_certificates (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name         TEXT NOT NULL,
+    subject      TEXT NOT NULL,
+    fingerprint  BYTEA NOT NULL UNIQUE,
+    not_after    TIMESTAMPTZ NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_certificates_user ON client_certificates(user_id);
@@ -0,0 +1,13 @@
+-- Учётные записи внешнего OpenID Connect провайдера, привязанные к аккаунтам.
+-- Пользователь провайдера однозначно определяется парой (issuer, subject);
+-- у одного аккаунта может быть несколько привязок.
+CREATE TABLE IF NOT EXISTS oidc_identities (
+    issuer      TEXT NOT NULL,
+    subject     TEXT NOT NULL,
+    user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email       TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (issuer, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_oidc_identities_user ON oidc_identities(user_id);
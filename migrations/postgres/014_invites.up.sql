@@ -0,0 +1,11 @@
+-- Коды приглашений для регистрации в режиме auth.registration=invite.
+-- Хранится только SHA-256 кода; код можно использовать max_uses раз до expires_at.
+CREATE TABLE IF NOT EXISTS invites (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code_hash   BYTEA NOT NULL UNIQUE,
+    created_by  UUID REFERENCES users(id) ON DELETE SET NULL,
+    max_uses    INTEGER NOT NULL CHECK (max_uses > 0),
+    uses        INTEGER NOT NULL DEFAULT 0,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
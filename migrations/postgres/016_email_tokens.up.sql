@@ -0,0 +1,20 @@
+-- Подтверждение email и восстановление доступа по email.
+-- email_verified_at заполняется после перехода по токену подтверждения
+-- (или сброса пароля — он тоже доказывает владение адресом).
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+-- Одноразовые токены из писем: purpose = verify (подтверждение email)
+-- или reset (сброс пароля). Хранится только SHA-256 токена;
+-- новый токен того же назначения отменяет предыдущие.
+CREATE TABLE IF NOT EXISTS email_tokens (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose     TEXT NOT NULL CHECK (purpose IN ('verify', 'reset')),
+    token_hash  BYTEA NOT NULL UNIQUE,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    used_at     TIMESTAMPTZ NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_email_tokens_user ON email_tokens(user_id, purpose, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_email_tokens_expires ON email_tokens(expires_at);
@@ -0,0 +1,7 @@
+-- Администрирование: роль администратора, блокировка аккаунта и время последнего входа.
+-- Первого администратора назначают вручную:
+--   UPDATE users SET is_admin = true WHERE email = 'admin@example.com';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_admin BOOLEAN NOT NULL DEFAULT false;
+-- заблокированный аккаунт не может войти и обновить токены
+ALTER TABLE users ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMPTZ;